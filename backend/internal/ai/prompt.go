@@ -28,7 +28,7 @@ func BuildPrompt(c *Conversation, userMsg string, suggestions []dto.RecommendSug
 				parts = append(parts, fmt.Sprintf("(%s)", sugg.Brand))
 			}
 			if sugg.Price > 0 {
-				parts = append(parts, fmt.Sprintf("- R$ %.2f", sugg.Price))
+				parts = append(parts, fmt.Sprintf("- R$ %s", sugg.Price))
 			}
 			if sugg.Reason != "" {
 				parts = append(parts, fmt.Sprintf("- %s", sugg.Reason))
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// GuestCookieName holds the signed guest session ID of shoppers who are not signed in.
+	GuestCookieName      = "guest_session"
+	guestSessionDuration = 30 * 24 * time.Hour // 30 days
+	guestLookupBytes     = 32                  // 256 bits
+)
+
+// GuestSessionMiddleware identifies anonymous shoppers by a signed cookie so they can keep a cart
+// and check out without an account. Signed-in requests are left alone. A missing or tampered cookie
+// is replaced by a new guest session; the guest ID is injected into the context as "guestID".
+func GuestSessionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("userID") != "" {
+			c.Next()
+			return
+		}
+
+		guestID := ""
+		if raw, err := c.Cookie(GuestCookieName); err == nil {
+			guestID, _ = parseGuestCookie(raw)
+		}
+		if guestID == "" {
+			guestID = uuid.New().String()
+			value, err := signGuestID(guestID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_error"})
+				c.Abort()
+				return
+			}
+			setGuestCookie(c, value, int(guestSessionDuration.Seconds()))
+		}
+
+		c.Set("guestID", guestID)
+		c.Next()
+	}
+}
+
+// ShopperAuthMiddleware serves both signed-in users and guests. A request with an Authorization
+// header must carry a valid JWT, so an expired session still gets a 401 and can refresh instead of
+// silently turning into a guest; requests without one get a guest session.
+func ShopperAuthMiddleware() gin.HandlerFunc {
+	jwtAuth := JWTAuthMiddleware()
+	guestSession := GuestSessionMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			jwtAuth(c)
+			return
+		}
+		guestSession(c)
+	}
+}
+
+// GuestIDFromCookie returns the guest session of a request, if it carries a valid guest cookie.
+// Signed-in users use it to claim what they did before creating an account.
+func GuestIDFromCookie(c *gin.Context) string {
+	raw, err := c.Cookie(GuestCookieName)
+	if err != nil {
+		return ""
+	}
+	guestID, _ := parseGuestCookie(raw)
+	return guestID
+}
+
+// ClearGuestCookie removes the guest session cookie
+func ClearGuestCookie(c *gin.Context) {
+	setGuestCookie(c, "", -1)
+}
+
+// ShopperID returns the signed-in user's public ID or, for anonymous shoppers, the guest session ID.
+func ShopperID(c *gin.Context) string {
+	if userID := c.GetString("userID"); userID != "" {
+		return userID
+	}
+	return c.GetString("guestID")
+}
+
+// GenerateGuestLookupToken creates the secret a guest uses to look up an order without an account.
+// Returns the raw token (to send to the guest) and its hash (to store).
+func GenerateGuestLookupToken() (string, string, error) {
+	bytes := make([]byte, guestLookupBytes)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(bytes)
+	return token, HashGuestLookupToken(token), nil
+}
+
+// HashGuestLookupToken creates a SHA-256 hash of a guest lookup token for DB storage.
+func HashGuestLookupToken(token string) string {
+	hash := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(hash[:])
+}
+
+func setGuestCookie(c *gin.Context, value string, maxAge int) {
+	cookie := &http.Cookie{
+		Name:     GuestCookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteNoneMode,
+	}
+	http.SetCookie(c.Writer, cookie)
+}
+
+// signGuestID returns the cookie value "<guest id>.<signature>".
+func signGuestID(guestID string) (string, error) {
+	sig, err := guestSignature(guestID)
+	if err != nil {
+		return "", err
+	}
+	return guestID + "." + sig, nil
+}
+
+// parseGuestCookie verifies the signature of a guest cookie and returns its guest ID.
+func parseGuestCookie(value string) (string, bool) {
+	guestID, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return "", false
+	}
+	if _, err := uuid.Parse(guestID); err != nil {
+		return "", false
+	}
+	expected, err := guestSignature(guestID)
+	if err != nil || !hmac.Equal([]byte(sig), []byte(expected)) {
+		return "", false
+	}
+	return guestID, true
+}
+
+func guestSignature(guestID string) (string, error) {
+	sec, err := loadSecret()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, sec)
+	mac.Write([]byte("guest_session:" + guestID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
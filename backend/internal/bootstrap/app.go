@@ -16,8 +16,12 @@ import (
 	"github.com/leoferamos/aroma-sense/internal/rate"
 	"github.com/leoferamos/aroma-sense/internal/repository"
 	serviceadmin "github.com/leoferamos/aroma-sense/internal/service/admin"
+	servicecart "github.com/leoferamos/aroma-sense/internal/service/cart"
 	servicelgpd "github.com/leoferamos/aroma-sense/internal/service/lgpd"
 	servicelog "github.com/leoferamos/aroma-sense/internal/service/log"
+	serviceorder "github.com/leoferamos/aroma-sense/internal/service/order"
+	servicepayment "github.com/leoferamos/aroma-sense/internal/service/payment"
+	servicewishlist "github.com/leoferamos/aroma-sense/internal/service/wishlist"
 	"github.com/leoferamos/aroma-sense/internal/storage"
 	"gorm.io/gorm"
 )
@@ -25,6 +29,8 @@ import (
 // AppHandlers contains all initialized handlers
 type AppHandlers struct {
 	UserHandler              *userhandler.UserHandler
+	AddressHandler           *userhandler.AddressHandler
+	WishlistHandler          *userhandler.WishlistHandler
 	AdminUserHandler         *admin.AdminUserHandler
 	ProductHandler           *product.ProductHandler
 	CartHandler              *carthandler.CartHandler
@@ -37,19 +43,32 @@ type AppHandlers struct {
 	AuditLogHandler          *loghandler.AuditLogHandler
 	AdminContestationHandler *admin.AdminContestationHandler
 	AdminReviewReportHandler *admin.AdminReviewReportHandler
+	AdminPromotionHandler    *admin.AdminPromotionHandler
+	AdminCartRecoveryHandler *admin.AdminCartRecoveryHandler
 	PaymentHandler           *paymenthandler.PaymentHandler
 }
 
 // AppServices contains service instances needed for jobs
 type AppServices struct {
-	AdminUserService serviceadmin.AdminUserService
-	AuditLogService  servicelog.AuditLogService
-	LgpdService      servicelgpd.LgpdService
+	AdminUserService    serviceadmin.AdminUserService
+	CartRecoveryService servicecart.CartRecoveryService
+	WishlistService     servicewishlist.WishlistService
+	AuditLogService     servicelog.AuditLogService
+	LgpdService         servicelgpd.LgpdService
+	OrderService        serviceorder.OrderService
+	OrderConfig         serviceorder.Config
+	PaymentService      servicepayment.PaymentService
+	PaymentConfig       servicepayment.Config
 }
 
 // AppRepos contains repository instances needed for jobs
 type AppRepos struct {
-	UserRepo repository.UserRepository
+	UserRepo           repository.UserRepository
+	OrderRepo          repository.OrderRepository
+	PaymentRepo        repository.PaymentRepository
+	WebhookEventRepo   repository.WebhookEventRepository
+	IdempotencyKeyRepo repository.IdempotencyKeyRepository
+	CartRepo           repository.CartRepository
 }
 
 // AppComponents contains all initialized application components
@@ -69,19 +88,30 @@ func InitializeApp(db *gorm.DB, storageClient storage.ImageStorage) *AppComponen
 	integrations := initializeIntegrations()
 
 	// Initialize services in dependency order
-	services := initializeServices(repositories, integrations, storageClient)
+	services := initializeServices(repositories, integrations, storageClient, rateLimiter)
 
 	// Initialize handlers
 	handlers := initializeHandlers(services, rateLimiter)
 
 	appServices := &AppServices{
-		AdminUserService: services.adminUser,
-		AuditLogService:  services.auditLog,
-		LgpdService:      services.lgpd,
+		AdminUserService:    services.adminUser,
+		CartRecoveryService: services.cartRecovery,
+		WishlistService:     services.wishlist,
+		AuditLogService:     services.auditLog,
+		LgpdService:         services.lgpd,
+		OrderService:        services.order,
+		OrderConfig:         services.orderConfig,
+		PaymentService:      services.payment,
+		PaymentConfig:       services.paymentConfig,
 	}
 
 	appRepos := &AppRepos{
-		UserRepo: repositories.user,
+		UserRepo:           repositories.user,
+		OrderRepo:          repositories.order,
+		PaymentRepo:        repositories.payment,
+		WebhookEventRepo:   repositories.webhookEvent,
+		IdempotencyKeyRepo: repositories.idempotencyKey,
+		CartRepo:           repositories.cart,
 	}
 
 	return &AppComponents{
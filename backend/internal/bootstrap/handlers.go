@@ -21,18 +21,22 @@ func initializeHandlers(services *services, rateLimiter rate.RateLimiter) *AppHa
 
 	return &AppHandlers{
 		UserHandler:              userhandler.NewUserHandler(services.auth, services.userProfile, services.lgpd, services.chat),
+		AddressHandler:           userhandler.NewAddressHandler(services.address),
+		WishlistHandler:          userhandler.NewWishlistHandler(services.wishlist),
 		AdminUserHandler:         admin.NewAdminUserHandler(services.adminUser),
 		ProductHandler:           product.NewProductHandler(services.product, services.review, services.userProfile),
 		CartHandler:              carthandler.NewCartHandler(services.cart),
 		OrderHandler:             orderhandler.NewOrderHandler(services.order),
 		PasswordResetHandler:     auth.NewPasswordResetHandler(services.passwordReset, rateLimiter),
-		ShippingHandler:          shipping.NewShippingHandler(services.shipping),
+		ShippingHandler:          shipping.NewShippingHandler(services.shipping, services.address),
 		ReviewHandler:            reviewhandler.NewReviewHandler(services.review, services.reviewReport, services.userProfile, services.product, services.auditLog, rateLimiter),
 		AIHandler:                aihandler.NewAIHandler(services.ai, rateLimiter),
 		ChatHandler:              chathandler.NewChatHandler(services.chat, rateLimiter),
 		AuditLogHandler:          loghandler.NewAuditLogHandler(services.auditLog),
 		AdminContestationHandler: admin.NewAdminContestationHandler(services.userContestation),
 		AdminReviewReportHandler: admin.NewAdminReviewReportHandler(services.reviewReport),
+		AdminPromotionHandler:    admin.NewAdminPromotionHandler(services.promotion),
+		AdminCartRecoveryHandler: admin.NewAdminCartRecoveryHandler(services.cartRecovery),
 		PaymentHandler:           paymenthandler.NewPaymentHandler(services.payment),
 	}
 }
@@ -3,14 +3,19 @@ package bootstrap
 import (
 	"context"
 	"log"
+	"os"
 	"time"
 
 	"github.com/leoferamos/aroma-sense/internal/email"
 	"github.com/leoferamos/aroma-sense/internal/integrations/ai/config"
 	"github.com/leoferamos/aroma-sense/internal/integrations/ai/embeddings"
 	"github.com/leoferamos/aroma-sense/internal/integrations/ai/llm"
+	"github.com/leoferamos/aroma-sense/internal/integrations/payment/boleto"
+	"github.com/leoferamos/aroma-sense/internal/integrations/payment/fake"
+	"github.com/leoferamos/aroma-sense/internal/integrations/payment/pix"
 	gatewaypayment "github.com/leoferamos/aroma-sense/internal/integrations/payment/stripe"
 	shippingprovider "github.com/leoferamos/aroma-sense/internal/integrations/shipping"
+	"github.com/leoferamos/aroma-sense/internal/model"
 	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
 	shippingservice "github.com/leoferamos/aroma-sense/internal/service/shipping"
 )
@@ -40,7 +45,7 @@ type aiIntegration struct {
 }
 
 type paymentIntegration struct {
-	provider paymentservice.PaymentProvider
+	providers *paymentservice.Providers
 }
 
 // initializeIntegrations creates all external integration instances
@@ -156,13 +161,51 @@ func initializeAIIntegration() *aiIntegration {
 	}
 }
 
-// initializePaymentIntegration configures Stripe provider if env is present.
+// initializePaymentIntegration configures the default gateway plus the Pix and boleto gateways, each if its env is present.
+// The default is Stripe, or the in-process fake gateway when PAYMENT_PROVIDER=fake.
 func initializePaymentIntegration() *paymentIntegration {
-	cfg, err := gatewaypayment.LoadConfigFromEnv()
-	if err != nil {
-		log.Printf("Stripe payment configuration not available: %v", err)
+	var providers *paymentservice.Providers
+	switch name := os.Getenv("PAYMENT_PROVIDER"); name {
+	case fake.ProviderName:
+		cfg, err := fake.LoadConfigFromEnv()
+		if err != nil {
+			log.Fatalf("Fake payment configuration error: %v", err)
+		}
+		log.Printf("Using the fake payment gateway: no real charges are made, simulate outcomes via POST /admin/payments/:intentID/simulate")
+		providers = paymentservice.NewProviders(fake.ProviderName, fake.NewProvider(cfg))
+	default:
+		if name != "" && name != "stripe" {
+			log.Printf("Unknown PAYMENT_PROVIDER=%q, using stripe", name)
+		}
+		var stripeProvider paymentservice.PaymentProvider
+		if cfg, err := gatewaypayment.LoadConfigFromEnv(); err != nil {
+			log.Printf("Stripe payment configuration not available: %v", err)
+		} else {
+			stripeProvider = gatewaypayment.NewProvider(cfg)
+		}
+		providers = paymentservice.NewProviders("stripe", stripeProvider)
+	}
+
+	if cfg, err := pix.LoadConfigFromEnv(); err != nil {
+		log.Printf("Pix payment configuration not available: %v", err)
+	} else {
+		if cfg.LocalWebhookURL != "" {
+			log.Printf("Pix payments are confirmed by the local PSP stand-in at %s", cfg.LocalWebhookURL)
+		}
+		providers.WithMethod(model.PaymentMethodPix, pix.ProviderName, pix.NewProvider(cfg))
+	}
+
+	if cfg, err := boleto.LoadConfigFromEnv(); err != nil {
+		log.Printf("Boleto payment configuration not available: %v", err)
+	} else {
+		if cfg.LocalWebhookURL != "" {
+			log.Printf("Boletos are settled by the local PSP stand-in at %s", cfg.LocalWebhookURL)
+		}
+		providers.WithMethod(model.PaymentMethodBoleto, boleto.ProviderName, boleto.NewProvider(cfg))
+	}
+
+	if providers.Empty() {
 		return nil
 	}
-	provider := gatewaypayment.NewProvider(cfg)
-	return &paymentIntegration{provider: provider}
+	return &paymentIntegration{providers: providers}
 }
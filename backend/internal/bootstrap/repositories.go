@@ -8,10 +8,18 @@ import (
 // repositories holds all repository instances
 type repositories struct {
 	user             repository.UserRepository
+	address          repository.AddressRepository
 	product          repository.ProductRepository
 	cart             repository.CartRepository
 	order            repository.OrderRepository
+	orderEvent       repository.OrderEventRepository
 	payment          repository.PaymentRepository
+	refund           repository.RefundRepository
+	webhookEvent     repository.WebhookEventRepository
+	promotion        repository.PromotionRepository
+	cartReminder     repository.CartReminderRepository
+	wishlist         repository.WishlistRepository
+	idempotencyKey   repository.IdempotencyKeyRepository
 	resetToken       repository.ResetTokenRepository
 	review           repository.ReviewRepository
 	reviewReport     repository.ReviewReportRepository
@@ -23,10 +31,18 @@ type repositories struct {
 func initializeRepositories(db *gorm.DB) *repositories {
 	return &repositories{
 		user:             repository.NewUserRepository(db),
+		address:          repository.NewAddressRepository(db),
 		product:          repository.NewProductRepository(db),
 		cart:             repository.NewCartRepository(db),
 		order:            repository.NewOrderRepository(db),
+		orderEvent:       repository.NewOrderEventRepository(db),
 		payment:          repository.NewPaymentRepository(db),
+		refund:           repository.NewRefundRepository(db),
+		webhookEvent:     repository.NewWebhookEventRepository(db),
+		promotion:        repository.NewPromotionRepository(db),
+		cartReminder:     repository.NewCartReminderRepository(db),
+		wishlist:         repository.NewWishlistRepository(db),
+		idempotencyKey:   repository.NewIdempotencyKeyRepository(db),
 		resetToken:       repository.NewResetTokenRepository(db),
 		review:           repository.NewReviewRepository(db),
 		reviewReport:     repository.NewReviewReportRepository(db),
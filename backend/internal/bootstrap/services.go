@@ -2,8 +2,10 @@ package bootstrap
 
 import (
 	"os"
+	"strings"
 
 	"github.com/leoferamos/aroma-sense/internal/notification"
+	"github.com/leoferamos/aroma-sense/internal/rate"
 	serviceadmin "github.com/leoferamos/aroma-sense/internal/service/admin"
 	authservice "github.com/leoferamos/aroma-sense/internal/service/auth"
 	cartservice "github.com/leoferamos/aroma-sense/internal/service/cart"
@@ -13,9 +15,11 @@ import (
 	orderservice "github.com/leoferamos/aroma-sense/internal/service/order"
 	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
 	productservice "github.com/leoferamos/aroma-sense/internal/service/product"
+	promotionservice "github.com/leoferamos/aroma-sense/internal/service/promotion"
 	reviewservice "github.com/leoferamos/aroma-sense/internal/service/review"
 	shippingservice "github.com/leoferamos/aroma-sense/internal/service/shipping"
 	userservice "github.com/leoferamos/aroma-sense/internal/service/user"
+	wishlistservice "github.com/leoferamos/aroma-sense/internal/service/wishlist"
 	"github.com/leoferamos/aroma-sense/internal/storage"
 )
 
@@ -24,11 +28,17 @@ type services struct {
 	adminUser        serviceadmin.AdminUserService
 	auth             authservice.AuthService
 	userProfile      userservice.UserProfileService
+	address          userservice.AddressService
+	wishlist         wishlistservice.WishlistService
 	lgpd             lgpdservice.LgpdService
 	product          productservice.ProductService
 	cart             cartservice.CartService
+	cartRecovery     cartservice.CartRecoveryService
+	promotion        promotionservice.PromotionService
 	order            orderservice.OrderService
+	orderConfig      orderservice.Config
 	payment          paymentservice.PaymentService
+	paymentConfig    paymentservice.Config
 	passwordReset    authservice.PasswordResetService
 	review           reviewservice.ReviewService
 	reviewReport     reviewservice.ReviewReportService
@@ -40,9 +50,9 @@ type services struct {
 }
 
 // initializeServices creates all service instances with proper dependencies
-func initializeServices(repos *repositories, integrations *integrations, storageClient storage.ImageStorage) *services {
+func initializeServices(repos *repositories, integrations *integrations, storageClient storage.ImageStorage, rateLimiter rate.RateLimiter) *services {
 	frontend := os.Getenv("FRONTEND_URL")
-	notifier := notification.NewNotifier(integrations.email, frontend)
+	notifier := notification.NewNotifier(integrations.email, frontend, adminAlertRecipients())
 
 	// Optional integrations
 	if integrations.shipping != nil && integrations.shipping.provider != nil {
@@ -53,32 +63,45 @@ func initializeServices(repos *repositories, integrations *integrations, storage
 	auditLogService := logservice.NewAuditLogService(repos.auditLog)
 	aiService := chatservice.NewAIService(repos.product)
 	productService := productservice.NewProductService(repos.product, storageClient, integrations.ai.embProvider)
-	cartService := cartservice.NewCartService(repos.cart, productService)
+	promotionService := promotionservice.NewPromotionService(repos.promotion, auditLogService)
+	cartService := cartservice.NewCartService(repos.cart, productService, promotionService)
+	cartRecoveryService := cartservice.NewCartRecoveryService(repos.cartReminder, repos.cart, notifier, cartservice.LoadRecoveryConfigFromEnv())
 	adminUserService := serviceadmin.NewAdminUserService(repos.user, auditLogService, notifier)
 	userContestationService := userservice.NewUserContestationService(repos.userContestation, repos.user, adminUserService)
 	lgpdService := lgpdservice.NewLgpdService(repos.user, repos.userContestation, auditLogService, notifier)
 	reviewService := reviewservice.NewReviewService(repos.review, repos.order, repos.product)
 	reviewReportService := reviewservice.NewReviewReportService(repos.reviewReport, repos.review, repos.user, adminUserService)
 	chatService := chatservice.NewChatService(repos.product, integrations.ai.llmProvider, integrations.ai.embProvider)
-	orderService := orderservice.NewOrderService(repos.order, repos.cart, repos.product, integrations.shipping.service)
-	passwordResetService := authservice.NewPasswordResetService(repos.resetToken, repos.user, notifier)
-	userProfileService := userservice.NewUserProfileService(repos.user, auditLogService)
-	authService := authservice.NewAuthService(repos.user, cartService, auditLogService)
 
 	var paymentSvc paymentservice.PaymentService
-	if integrations.payment != nil && integrations.payment.provider != nil {
-		paymentSvc = paymentservice.NewPaymentService(repos.cart, repos.product, repos.order, repos.payment, integrations.shipping.service, integrations.payment.provider)
+	paymentConfig := paymentservice.LoadConfigFromEnv()
+	if integrations.payment != nil && !integrations.payment.providers.Empty() {
+		paymentSvc = paymentservice.NewPaymentService(repos.cart, repos.product, repos.order, repos.orderEvent, repos.payment, repos.refund, repos.webhookEvent, integrations.shipping.service, auditLogService, notifier, promotionService, integrations.payment.providers)
 	}
 
+	orderConfig := orderservice.LoadConfigFromEnv()
+	orderService := orderservice.NewOrderService(repos.order, repos.orderEvent, repos.cart, repos.product, repos.user, repos.address, integrations.shipping.service, auditLogService, paymentSvc, notifier, promotionService, orderConfig)
+	passwordResetService := authservice.NewPasswordResetService(repos.resetToken, repos.user, notifier)
+	userProfileService := userservice.NewUserProfileService(repos.user, auditLogService)
+	addressService := userservice.NewAddressService(repos.address)
+	wishlistService := wishlistservice.NewWishlistService(repos.wishlist, repos.product, notifier, rateLimiter, wishlistservice.LoadConfigFromEnv())
+	authService := authservice.NewAuthService(repos.user, cartService, auditLogService)
+
 	return &services{
 		adminUser:        adminUserService,
 		auth:             authService,
 		userProfile:      userProfileService,
+		address:          addressService,
+		wishlist:         wishlistService,
 		lgpd:             lgpdService,
 		product:          productService,
 		cart:             cartService,
+		cartRecovery:     cartRecoveryService,
+		promotion:        promotionService,
 		order:            orderService,
+		orderConfig:      orderConfig,
 		payment:          paymentSvc,
+		paymentConfig:    paymentConfig,
 		passwordReset:    passwordResetService,
 		review:           reviewService,
 		reviewReport:     reviewReportService,
@@ -89,3 +112,14 @@ func initializeServices(repos *repositories, integrations *integrations, storage
 		userContestation: userContestationService,
 	}
 }
+
+// adminAlertRecipients reads the comma-separated ADMIN_ALERT_EMAILS list
+func adminAlertRecipients() []string {
+	var recipients []string
+	for _, addr := range strings.Split(os.Getenv("ADMIN_ALERT_EMAILS"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			recipients = append(recipients, addr)
+		}
+	}
+	return recipients
+}
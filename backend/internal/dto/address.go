@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+)
+
+// AddressRequest represents the payload to create or replace an address book entry
+type AddressRequest struct {
+	Recipient    string `json:"recipient" binding:"required,max=120" example:"Maria Silva"`
+	CEP          string `json:"cep" binding:"required" example:"01310-100"`
+	Street       string `json:"street" binding:"required,max=255" example:"Avenida Paulista"`
+	Number       string `json:"number" binding:"required,max=20" example:"1578"`
+	Complement   string `json:"complement,omitempty" binding:"max=120" example:"Apto 42"`
+	Neighborhood string `json:"neighborhood" binding:"required,max=120" example:"Bela Vista"`
+	City         string `json:"city" binding:"required,max=120" example:"São Paulo"`
+	UF           string `json:"uf" binding:"required,len=2" example:"SP"`
+	IsDefault    bool   `json:"is_default" example:"true"`
+}
+
+// PostalAddressResponse represents a structured Brazilian address
+type PostalAddressResponse struct {
+	Recipient    string `json:"recipient" example:"Maria Silva"`
+	CEP          string `json:"cep" example:"01310-100"`
+	Street       string `json:"street" example:"Avenida Paulista"`
+	Number       string `json:"number" example:"1578"`
+	Complement   string `json:"complement,omitempty" example:"Apto 42"`
+	Neighborhood string `json:"neighborhood" example:"Bela Vista"`
+	City         string `json:"city" example:"São Paulo"`
+	UF           string `json:"uf" example:"SP"`
+}
+
+// AddressResponse represents an address book entry returned to the client
+type AddressResponse struct {
+	PublicID string `json:"public_id"`
+	PostalAddressResponse
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PostalAddressResponseFromModel maps a structured address to its response
+func PostalAddressResponseFromModel(a model.PostalAddress) PostalAddressResponse {
+	return PostalAddressResponse{
+		Recipient:    a.Recipient,
+		CEP:          a.FormattedCEP(),
+		Street:       a.Street,
+		Number:       a.Number,
+		Complement:   a.Complement,
+		Neighborhood: a.Neighborhood,
+		City:         a.City,
+		UF:           a.UF,
+	}
+}
+
+// AddressResponseFromModel maps an address book entry to its response
+func AddressResponseFromModel(m *model.Address) AddressResponse {
+	return AddressResponse{
+		PublicID:              m.PublicID,
+		PostalAddressResponse: PostalAddressResponseFromModel(m.PostalAddress),
+		IsDefault:             m.IsDefault,
+		CreatedAt:             m.CreatedAt,
+		UpdatedAt:             m.UpdatedAt,
+	}
+}
@@ -1,15 +1,20 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
 
 // AdminOrderItem represents the data for an order shown in admin listings
 type AdminOrderItem struct {
-	ID          uint      `json:"id" example:"1"`
-	PublicID    string    `json:"public_id" example:"uuid"`
-	UserID      string    `json:"user_id" example:"uuid"`
-	TotalAmount float64   `json:"total_amount" example:"123.45"`
-	Status      string    `json:"status" example:"pending"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID                  uint         `json:"id" example:"1"`
+	PublicID            string       `json:"public_id" example:"uuid"`
+	UserID              string       `json:"user_id" example:"uuid"`
+	TotalAmount         money.Amount `json:"total_amount" example:"123.45"`
+	Status              string       `json:"status" example:"pending"`
+	PaymentReviewReason string       `json:"payment_review_reason,omitempty" example:"payment_underpaid"`
+	CreatedAt           time.Time    `json:"created_at"`
 }
 
 // PaginationMeta contains pagination information
@@ -22,8 +27,8 @@ type PaginationMeta struct {
 
 // StatsMeta contains aggregated statistics for the listing
 type StatsMeta struct {
-	TotalRevenue      float64 `json:"total_revenue" example:"12345.67"`
-	AverageOrderValue float64 `json:"average_order_value" example:"49.95"`
+	TotalRevenue      money.Amount `json:"total_revenue" example:"12345.67"`
+	AverageOrderValue money.Amount `json:"average_order_value" example:"49.95"`
 }
 
 // AdminOrdersResponse is the response returned by GET /admin/orders
@@ -34,3 +39,13 @@ type AdminOrdersResponse struct {
 		Stats      StatsMeta      `json:"stats"`
 	} `json:"meta"`
 }
+
+// AdminOrderDetailResponse is the response returned by GET /admin/orders/:publicID
+type AdminOrderDetailResponse struct {
+	OrderResponse
+	UserID              string               `json:"user_id,omitempty" example:"uuid"`
+	GuestEmail          string               `json:"guest_email,omitempty" example:"guest@example.com"`
+	PaymentReviewReason string               `json:"payment_review_reason,omitempty" example:"payment_underpaid"`
+	PaymentReviewAt     *time.Time           `json:"payment_review_at,omitempty"`
+	Timeline            []OrderEventResponse `json:"timeline"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "github.com/leoferamos/aroma-sense/internal/money"
+
 // RecommendRequest is the input payload for the AI recommend endpoint.
 type RecommendRequest struct {
 	Message string   `json:"message"`
@@ -9,13 +11,13 @@ type RecommendRequest struct {
 
 // RecommendSuggestion is a compact product card to show inside the chat.
 type RecommendSuggestion struct {
-	ID           uint    `json:"id"`
-	Name         string  `json:"name"`
-	Brand        string  `json:"brand"`
-	Slug         string  `json:"slug"`
-	ThumbnailURL string  `json:"thumbnail_url"`
-	Price        float64 `json:"price"`
-	Reason       string  `json:"reason"`
+	ID           uint         `json:"id"`
+	Name         string       `json:"name"`
+	Brand        string       `json:"brand"`
+	Slug         string       `json:"slug"`
+	ThumbnailURL string       `json:"thumbnail_url"`
+	Price        money.Amount `json:"price"`
+	Reason       string       `json:"reason"`
 }
 
 // RecommendResponse bundles suggestions and lightweight reasoning.
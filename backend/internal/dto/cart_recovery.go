@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
+
+// CartRecoveryMetricsResponse reports how abandoned cart reminders converted into orders
+type CartRecoveryMetricsResponse struct {
+	Days             int          `json:"days" example:"30"`
+	Since            time.Time    `json:"since"`
+	RemindersSent    int64        `json:"reminders_sent" example:"420"`
+	CartsReminded    int64        `json:"carts_reminded" example:"300"`
+	CartsRecovered   int64        `json:"carts_recovered" example:"36"`
+	ConversionRate   float64      `json:"conversion_rate" example:"0.12"`
+	RecoveredRevenue money.Amount `json:"recovered_revenue" example:"5400.00"`
+}
@@ -1,27 +1,60 @@
 package dto
 
+import "github.com/leoferamos/aroma-sense/internal/money"
+
 // CartResponse represents the cart data returned to the client
 type CartResponse struct {
-	Items     []CartItemResponse `json:"items"`
-	Total     float64            `json:"total"`
-	ItemCount int                `json:"item_count"`
+	Items       []CartItemResponse     `json:"items"`
+	Subtotal    money.Amount           `json:"subtotal"`
+	Discount    money.Amount           `json:"discount"`
+	Total       money.Amount           `json:"total"`
+	ItemCount   int                    `json:"item_count"`
+	HasWarnings bool                   `json:"has_warnings"`
+	Promotion   *CartPromotionResponse `json:"promotion,omitempty"`
 }
 
-// CartItemResponse represents a cart item returned to the client
+// CartItemResponse represents a cart item returned to the client. Price is the price captured when
+// the item was added; Warnings lists what changed in the product since then.
 type CartItemResponse struct {
-	Product  *ProductResponse `json:"product,omitempty"`
-	Quantity int              `json:"quantity"`
-	Price    float64          `json:"price"`
-	Total    float64          `json:"total"`
+	Product  *ProductResponse        `json:"product,omitempty"`
+	Variant  *ProductVariantResponse `json:"variant,omitempty"`
+	Quantity int                     `json:"quantity"`
+	Price    money.Amount            `json:"price"`
+	Total    money.Amount            `json:"total"`
+	Warnings []CartItemWarning       `json:"warnings,omitempty"`
+}
+
+// Cart line warning codes
+const (
+	CartWarningPriceChanged      = "price_changed"
+	CartWarningInsufficientStock = "insufficient_stock"
+	CartWarningOutOfStock        = "out_of_stock"
+	CartWarningProductRemoved    = "product_removed"
+)
+
+// CartItemWarning describes a difference between a cart line and the current product
+type CartItemWarning struct {
+	Code          string        `json:"code" example:"price_changed"`
+	PreviousPrice *money.Amount `json:"previous_price,omitempty" example:"129.9"`
+	CurrentPrice  *money.Amount `json:"current_price,omitempty" example:"139.9"`
+	Available     *int          `json:"available,omitempty" example:"2"`
 }
 
 // AddToCartRequest represents the payload for adding an item to cart
 type AddToCartRequest struct {
 	ProductSlug string `json:"product_slug" binding:"required"`
-	Quantity    int    `json:"quantity" binding:"required,min=1"`
+	// VariantSKU picks the size and concentration; the product's default variant when omitted
+	VariantSKU string `json:"variant_sku,omitempty"`
+	Quantity   int    `json:"quantity" binding:"required,min=1"`
 }
 
 // UpdateCartItemRequest represents the payload for updating cart item quantity
 type UpdateCartItemRequest struct {
 	Quantity int `json:"quantity" binding:"required,min=0"`
 }
+
+// ReconcileCartRequest chooses how cart warnings are resolved. An empty body does both.
+type ReconcileCartRequest struct {
+	AcceptPrices     *bool `json:"accept_prices,omitempty" example:"true"`
+	AdjustQuantities *bool `json:"adjust_quantities,omitempty" example:"true"`
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
 
 // CartSummaryResponse represents a summary of the cart
 type CartSummaryResponse struct {
@@ -10,8 +14,8 @@ type CartSummaryResponse struct {
 
 // CartSummary provides additional cart information
 type CartSummary struct {
-	ItemCount   int       `json:"item_count"`
-	TotalAmount float64   `json:"total_amount"`
-	UniqueItems int       `json:"unique_items"`
-	LastUpdated time.Time `json:"last_updated"`
+	ItemCount   int          `json:"item_count"`
+	TotalAmount money.Amount `json:"total_amount"`
+	UniqueItems int          `json:"unique_items"`
+	LastUpdated time.Time    `json:"last_updated"`
 }
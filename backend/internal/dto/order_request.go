@@ -1,12 +1,38 @@
 package dto
 
-// CreateOrderFromCartRequest represents the payload for creating an order from the entire cart
+import "github.com/leoferamos/aroma-sense/internal/money"
+
+// CreateOrderFromCartRequest represents the payload for creating an order from the entire cart.
+// The delivery address is either an address book entry (address_id) or free text (shipping_address).
 type CreateOrderFromCartRequest struct {
-	ShippingAddress   string             `json:"shipping_address" binding:"required" example:"Rua Example, 123, São Paulo - SP, 01234-567"`
+	ShippingAddress   string             `json:"shipping_address" binding:"required_without=AddressID" example:"Rua Example, 123, São Paulo - SP, 01234-567"`
+	AddressID         string             `json:"address_id,omitempty" example:"3f0c6d7e-8a51-4f0b-9a57-1c2d3e4f5a6b"`
 	PaymentMethod     string             `json:"payment_method" binding:"required,oneof=credit_card debit_card pix boleto" example:"pix"`
 	ShippingSelection *ShippingSelection `json:"shipping_selection,omitempty"`
 }
 
+// CreateGuestOrderRequest represents the payload for checking out the guest session's cart without an account.
+// The delivery address is either structured (address) or free text (shipping_address).
+type CreateGuestOrderRequest struct {
+	Email             string             `json:"email" binding:"required,email,max=255" example:"guest@example.com" format:"email"`
+	Address           *AddressRequest    `json:"address,omitempty"`
+	ShippingAddress   string             `json:"shipping_address" binding:"required_without=Address" example:"Rua Example, 123, São Paulo - SP, 01234-567"`
+	PaymentMethod     string             `json:"payment_method" binding:"required,oneof=credit_card debit_card pix boleto" example:"pix"`
+	ShippingSelection *ShippingSelection `json:"shipping_selection,omitempty"`
+}
+
+// TrackOrderRequest represents the payload a guest sends to look up an order
+type TrackOrderRequest struct {
+	Email string `json:"email" binding:"required,email" example:"guest@example.com" format:"email"`
+	Token string `json:"token" binding:"required,max=128"`
+}
+
+// ClaimGuestOrdersRequest represents the lookup tokens of guest orders to move into the signed-in account.
+// Orders placed from the current guest session are claimed without a token.
+type ClaimGuestOrdersRequest struct {
+	Tokens []string `json:"tokens,omitempty" binding:"max=20,dive,max=128"`
+}
+
 // CreateOrderDirectRequest represents the payload for buying a single product directly
 type CreateOrderDirectRequest struct {
 	ProductID       uint   `json:"product_id" binding:"required" example:"5"`
@@ -17,14 +43,27 @@ type CreateOrderDirectRequest struct {
 
 // UpdateOrderStatusRequest represents the payload for updating order status
 type UpdateOrderStatusRequest struct {
-	Status string `json:"status" binding:"required,oneof=pending processing shipped delivered cancelled" example:"processing"`
+	Status         string `json:"status" binding:"required,oneof=pending processing shipped delivered cancelled" example:"shipped"`
+	TrackingNumber string `json:"tracking_number,omitempty" binding:"max=255" example:"BR123456789BR"`
+	Reason         string `json:"reason,omitempty" binding:"max=500" example:"customer requested cancellation"`
+}
+
+// CancelOrderRequest represents the optional payload a customer sends when cancelling an order
+type CancelOrderRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=500" example:"ordered the wrong size"`
+}
+
+// AdminRefundRequest represents the payload for refunding an order. Omitting amount refunds everything still captured
+type AdminRefundRequest struct {
+	Amount money.Amount `json:"amount,omitempty" binding:"omitempty,gt=0" example:"49.90"`
+	Reason string       `json:"reason" binding:"required,max=500" example:"damaged item returned"`
 }
 
 // ShippingSelection represents the client's chosen shipping option.
 type ShippingSelection struct {
-	Carrier       string  `json:"carrier" binding:"required" example:"Correios"`
-	ServiceCode   string  `json:"service_code" binding:"required" example:"SEDEX"`
-	Price         float64 `json:"price" binding:"required" example:"24.90"`
-	EstimatedDays int     `json:"estimated_days" example:"2"`
-	QuoteID       string  `json:"quote_id,omitempty" example:"q_abc123"`
+	Carrier       string       `json:"carrier" binding:"required" example:"Correios"`
+	ServiceCode   string       `json:"service_code" binding:"required" example:"SEDEX"`
+	Price         money.Amount `json:"price" binding:"required" example:"24.90"`
+	EstimatedDays int          `json:"estimated_days" example:"2"`
+	QuoteID       string       `json:"quote_id,omitempty" example:"q_abc123"`
 }
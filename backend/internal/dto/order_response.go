@@ -1,32 +1,88 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
 
 // OrderResponse represents the order data returned to the client
 type OrderResponse struct {
-	PublicID                  string              `json:"public_id"`
-	TotalAmount               float64             `json:"total_amount"`
-	Status                    string              `json:"status"`
-	ShippingAddress           string              `json:"shipping_address"`
-	PaymentMethod             string              `json:"payment_method"`
-	ShippingPrice             float64             `json:"shipping_price"`
-	ShippingCarrier           string              `json:"shipping_carrier,omitempty"`
-	ShippingServiceCode       string              `json:"shipping_service_code,omitempty"`
-	ShippingEstimatedDelivery *time.Time          `json:"shipping_estimated_delivery,omitempty"`
-	ShippingTracking          string              `json:"shipping_tracking,omitempty"`
-	ShippingStatus            string              `json:"shipping_status,omitempty"`
-	Items                     []OrderItemResponse `json:"items"`
-	ItemCount                 int                 `json:"item_count"`
-	CreatedAt                 time.Time           `json:"created_at"`
-	UpdatedAt                 time.Time           `json:"updated_at"`
+	PublicID                  string                 `json:"public_id"`
+	TotalAmount               money.Amount           `json:"total_amount"`
+	Status                    string                 `json:"status"`
+	ShippingAddress           string                 `json:"shipping_address"`
+	ShippingDetails           *PostalAddressResponse `json:"shipping_details,omitempty"`
+	PaymentMethod             string                 `json:"payment_method"`
+	ShippingPrice             money.Amount           `json:"shipping_price"`
+	DiscountAmount            money.Amount           `json:"discount_amount"`
+	PromotionCode             string                 `json:"promotion_code,omitempty"`
+	ShippingCarrier           string                 `json:"shipping_carrier,omitempty"`
+	ShippingServiceCode       string                 `json:"shipping_service_code,omitempty"`
+	ShippingEstimatedDelivery *time.Time             `json:"shipping_estimated_delivery,omitempty"`
+	ShippingTracking          string                 `json:"shipping_tracking,omitempty"`
+	ShippingStatus            string                 `json:"shipping_status,omitempty"`
+	ReservationExpiresAt      *time.Time             `json:"reservation_expires_at,omitempty"`
+	Items                     []OrderItemResponse    `json:"items"`
+	ItemCount                 int                    `json:"item_count"`
+	CreatedAt                 time.Time              `json:"created_at"`
+	UpdatedAt                 time.Time              `json:"updated_at"`
 }
 
 // OrderItemResponse represents an order item returned to the client
 type OrderItemResponse struct {
-	ProductSlug     string  `json:"product_slug"`
-	ProductName     string  `json:"product_name,omitempty"`
-	ProductImageURL string  `json:"product_image_url,omitempty"`
-	Quantity        int     `json:"quantity"`
-	PriceAtPurchase float64 `json:"price_at_purchase"`
-	Subtotal        float64 `json:"subtotal"`
+	ProductSlug     string       `json:"product_slug"`
+	ProductName     string       `json:"product_name,omitempty"`
+	ProductImageURL string       `json:"product_image_url,omitempty"`
+	VariantSKU      string       `json:"variant_sku,omitempty"`
+	SizeML          int          `json:"size_ml,omitempty"`
+	Concentration   string       `json:"concentration,omitempty"`
+	Quantity        int          `json:"quantity"`
+	PriceAtPurchase money.Amount `json:"price_at_purchase"`
+	Subtotal        money.Amount `json:"subtotal"`
+}
+
+// OrderEventResponse represents a single entry in an order's timeline
+type OrderEventResponse struct {
+	Type       string    `json:"type" example:"status_changed"`
+	FromStatus string    `json:"from_status,omitempty" example:"processing"`
+	ToStatus   string    `json:"to_status,omitempty" example:"shipped"`
+	Actor      string    `json:"actor" example:"admin"`
+	ActorID    string    `json:"actor_id,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OrderDetailResponse represents a single order together with its timeline
+type OrderDetailResponse struct {
+	OrderResponse
+	Timeline []OrderEventResponse `json:"timeline"`
+}
+
+// GuestOrderResponse represents an order placed through guest checkout. The lookup token is only
+// returned here; together with the email it is needed to track the order.
+type GuestOrderResponse struct {
+	OrderResponse
+	GuestEmail  string `json:"guest_email" example:"guest@example.com"`
+	LookupToken string `json:"lookup_token"`
+}
+
+// ClaimGuestOrdersResponse lists the guest orders moved into the account
+type ClaimGuestOrdersResponse struct {
+	ClaimedOrders []string `json:"claimed_orders"`
+}
+
+// OrderCancellationResponse represents a cancelled order and the outcome of its refund
+type OrderCancellationResponse struct {
+	OrderResponse
+	RefundStatus   string       `json:"refund_status" example:"refunded"`
+	RefundedAmount money.Amount `json:"refunded_amount" example:"129.90"`
+}
+
+// RefundResponse represents the result of an admin refund
+type RefundResponse struct {
+	OrderPublicID   string       `json:"order_public_id"`
+	Status          string       `json:"status" example:"partially_refunded"`
+	RefundedAmount  money.Amount `json:"refunded_amount" example:"49.90"`
+	RemainingAmount money.Amount `json:"remaining_amount" example:"80.00"`
 }
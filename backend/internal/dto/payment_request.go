@@ -3,11 +3,18 @@ package dto
 import "strings"
 
 // CreatePaymentIntentRequest represents payload to start payment.
+// PaymentMethod picks the gateway for cart checkouts; intents for an existing order use the order's method.
 type CreatePaymentIntentRequest struct {
 	ShippingAddress   string             `json:"shipping_address" binding:"required"`
 	ShippingSelection *ShippingSelection `json:"shipping_selection,omitempty"`
 	CustomerEmail     string             `json:"customer_email,omitempty"`
 	OrderPublicID     string             `json:"order_public_id,omitempty"`
+	PaymentMethod     string             `json:"payment_method,omitempty" binding:"omitempty,oneof=credit_card debit_card pix boleto"`
+}
+
+// SimulatePaymentRequest picks the outcome to simulate for an intent of an in-process gateway.
+type SimulatePaymentRequest struct {
+	Outcome string `json:"outcome" binding:"required,oneof=succeeded failed processing canceled"`
 }
 
 // ShippingPostalCode attempts to extract the CEP digits from the shipping address.
@@ -0,0 +1,52 @@
+package dto
+
+import "github.com/leoferamos/aroma-sense/internal/money"
+
+// ProductFilter holds the structured catalog filters. Values of one attribute are alternatives
+// (any may match) and attributes are combined, so brand=Dior&accords=woody&accords=citrus matches
+// Dior products with a woody or citrus accord.
+type ProductFilter struct {
+	Brands      []string
+	Genders     []string
+	Accords     []string
+	Occasions   []string
+	Seasons     []string
+	Intensities []string
+	PriceRanges []string
+	Categories  []string
+	MinPrice    *money.Amount
+	MaxPrice    *money.Amount
+}
+
+// IsEmpty reports whether no filter is set
+func (f ProductFilter) IsEmpty() bool {
+	return len(f.Brands) == 0 && len(f.Genders) == 0 && len(f.Accords) == 0 &&
+		len(f.Occasions) == 0 && len(f.Seasons) == 0 && len(f.Intensities) == 0 &&
+		len(f.PriceRanges) == 0 && len(f.Categories) == 0 && f.MinPrice == nil && f.MaxPrice == nil
+}
+
+// FacetCount is the number of matching products with a given attribute value
+type FacetCount struct {
+	Value string `json:"value" example:"Dior"`
+	Count int    `json:"count" example:"12"`
+}
+
+// PriceBounds is the lowest and highest price among the matching products
+type PriceBounds struct {
+	Min money.Amount `json:"min" example:"89.90"`
+	Max money.Amount `json:"max" example:"1299.00"`
+}
+
+// ProductFacets holds the facet counts of a catalog search. The counts of an attribute ignore the
+// filter on that attribute itself, so the sidebar can show how many products each alternative adds.
+type ProductFacets struct {
+	Brands      []FacetCount `json:"brands"`
+	Genders     []FacetCount `json:"genders"`
+	Accords     []FacetCount `json:"accords"`
+	Occasions   []FacetCount `json:"occasions"`
+	Seasons     []FacetCount `json:"seasons"`
+	Intensities []FacetCount `json:"intensities"`
+	PriceRanges []FacetCount `json:"price_ranges"`
+	Categories  []FacetCount `json:"categories"`
+	Price       *PriceBounds `json:"price,omitempty"`
+}
@@ -3,6 +3,8 @@ package dto
 import (
 	"fmt"
 	"io"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
 )
 
 // FileUpload represents an uploaded file with its metadata.
@@ -30,3 +32,55 @@ func (f *FileUpload) Validate() error {
 
 	return fmt.Errorf("invalid image type: %s", f.ContentType)
 }
+
+// ProductImageFormDTO represents the form fields sent with a gallery image upload
+type ProductImageFormDTO struct {
+	AltText   string `form:"alt_text" binding:"max=255"`
+	IsPrimary bool   `form:"is_primary"`
+}
+
+// UpdateProductImageRequest represents the payload for updating a gallery image.
+// Setting IsPrimary to true makes the image the product's primary image.
+type UpdateProductImageRequest struct {
+	AltText   *string `json:"alt_text,omitempty" binding:"omitempty,max=255" example:"Sauvage bottle, side view"`
+	IsPrimary *bool   `json:"is_primary,omitempty" example:"true"`
+}
+
+// ReorderProductImagesRequest lists every image of a product gallery in the new display order
+type ReorderProductImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required,min=1" example:"3,1,2"`
+}
+
+// ProductImageResponse represents an image in a product gallery
+type ProductImageResponse struct {
+	ID           uint   `json:"id" example:"1"`
+	URL          string `json:"url" example:"https://example.com/image.jpg"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty" example:"https://example.com/image_thumb.jpg"`
+	AltText      string `json:"alt_text" example:"Sauvage bottle, front view"`
+	Position     int    `json:"position" example:"0"`
+	IsPrimary    bool   `json:"is_primary" example:"true"`
+}
+
+// ProductImageResponseFromModel maps a gallery image to its response
+func ProductImageResponseFromModel(i model.ProductImage) ProductImageResponse {
+	return ProductImageResponse{
+		ID:           i.ID,
+		URL:          i.ImageURL,
+		ThumbnailURL: i.ThumbnailURL,
+		AltText:      i.AltText,
+		Position:     i.Position,
+		IsPrimary:    i.IsPrimary,
+	}
+}
+
+// ProductImageResponsesFromModel maps a product gallery, which is ordered by position
+func ProductImageResponsesFromModel(images []model.ProductImage) []ProductImageResponse {
+	if len(images) == 0 {
+		return nil
+	}
+	resp := make([]ProductImageResponse, len(images))
+	for i, img := range images {
+		resp[i] = ProductImageResponseFromModel(img)
+	}
+	return resp
+}
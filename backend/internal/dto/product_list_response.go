@@ -6,4 +6,6 @@ type ProductListResponse struct {
 	Total int               `json:"total"`
 	Page  int               `json:"page"`
 	Limit int               `json:"limit"`
+	// Facets is set for searches and filtered listings
+	Facets *ProductFacets `json:"facets,omitempty"`
 }
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"github.com/leoferamos/aroma-sense/internal/money"
 	"github.com/lib/pq"
 )
 
@@ -10,7 +11,7 @@ type ProductFormDTO struct {
 	Brand         string         `form:"brand" binding:"required"`
 	Weight        float64        `form:"weight" binding:"required"`
 	Description   string         `form:"description"`
-	Price         float64        `form:"price" binding:"required"`
+	Price         money.Amount   `form:"price" binding:"required"`
 	Category      string         `form:"category" binding:"required"`
 	StockQuantity int            `form:"stock_quantity" binding:"required,gte=0"`
 	Accords       pq.StringArray `form:"accords"`
@@ -22,16 +23,24 @@ type ProductFormDTO struct {
 	NotesTop      pq.StringArray `form:"notes_top"`
 	NotesHeart    pq.StringArray `form:"notes_heart"`
 	NotesBase     pq.StringArray `form:"notes_base"`
+	// Default variant; the SKU is generated and the concentration defaults to edp when omitted
+	SKU           string `form:"sku" binding:"max=64"`
+	SizeML        int    `form:"size_ml" binding:"gte=0"`
+	Concentration string `form:"concentration" binding:"omitempty,oneof=edt edp parfum"`
+	Barcode       string `form:"barcode" binding:"max=32"`
+	// Alt text of the uploaded image, which becomes the primary gallery image
+	ImageAltText string `form:"image_alt_text" binding:"max=255"`
 }
 
 // UpdateProductRequest represents the payload for updating a product.
+// Weight, Price and StockQuantity apply to the product's default variant.
 // @Description Product update request
 type UpdateProductRequest struct {
 	Name          *string         `json:"name,omitempty" example:"Sauvage Elixir"`
 	Brand         *string         `json:"brand,omitempty" example:"Dior"`
 	Weight        *float64        `json:"weight,omitempty" example:"60.0"`
 	Description   *string         `json:"description,omitempty" example:"An intense and spicy fragrance"`
-	Price         *float64        `json:"price,omitempty" example:"399.99"`
+	Price         *money.Amount   `json:"price,omitempty" example:"399.99"`
 	Category      *string         `json:"category,omitempty" example:"Eau de Parfum"`
 	StockQuantity *int            `json:"stock_quantity,omitempty" example:"25"`
 	Accords       *pq.StringArray `json:"accords,omitempty"`
@@ -44,3 +53,28 @@ type UpdateProductRequest struct {
 	NotesHeart    *pq.StringArray `json:"notes_heart,omitempty"`
 	NotesBase     *pq.StringArray `json:"notes_base,omitempty"`
 }
+
+// ProductVariantRequest represents the payload for adding a variant to a product.
+// @Description Product variant creation request
+type ProductVariantRequest struct {
+	SKU           string       `json:"sku" binding:"required,max=64" example:"DIOR-SAUVAGE-EDP-100"`
+	SizeML        int          `json:"size_ml" binding:"required,gt=0" example:"100"`
+	Concentration string       `json:"concentration" binding:"required,oneof=edt edp parfum" example:"edp"`
+	Price         money.Amount `json:"price" binding:"required" example:"899.90"`
+	StockQuantity int          `json:"stock_quantity" binding:"gte=0" example:"12"`
+	Weight        float64      `json:"weight" binding:"required,gt=0" example:"0.35"`
+	Barcode       string       `json:"barcode,omitempty" binding:"max=32" example:"3348901250153"`
+	Position      int          `json:"position" binding:"gte=0" example:"1"`
+}
+
+// UpdateProductVariantRequest represents the payload for updating a product variant.
+// @Description Product variant update request
+type UpdateProductVariantRequest struct {
+	SizeML        *int          `json:"size_ml,omitempty" binding:"omitempty,gt=0" example:"100"`
+	Concentration *string       `json:"concentration,omitempty" binding:"omitempty,oneof=edt edp parfum" example:"edp"`
+	Price         *money.Amount `json:"price,omitempty" example:"899.90"`
+	StockQuantity *int          `json:"stock_quantity,omitempty" binding:"omitempty,gte=0" example:"12"`
+	Weight        *float64      `json:"weight,omitempty" binding:"omitempty,gt=0" example:"0.35"`
+	Barcode       *string       `json:"barcode,omitempty" binding:"omitempty,max=32" example:"3348901250153"`
+	Position      *int          `json:"position,omitempty" binding:"omitempty,gte=0" example:"0"`
+}
@@ -1,31 +1,82 @@
 package dto
 
-import "time"
+import (
+	"time"
 
-// ProductResponse represents the product data returned to the client
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
+
+// ProductResponse represents the product data returned to the client. Price is the lowest variant
+// price and StockQuantity the stock of all variants; Variants lists them, default first.
+// ImageURL is the primary image of the gallery listed in Images.
 type ProductResponse struct {
-	ID                 *uint     `json:"id,omitempty" example:"1"`
-	Name               string    `json:"name" example:"Sauvage"`
-	Brand              string    `json:"brand" example:"Dior"`
-	Weight             float64   `json:"weight" example:"100.0"`
-	Description        string    `json:"description" example:"A fresh and woody fragrance"`
-	Price              float64   `json:"price" example:"299.99"`
-	ImageURL           string    `json:"image_url" example:"https://example.com/image.jpg"`
-	ThumbnailURL       string    `json:"thumbnail_url,omitempty" example:"https://example.com/image_thumb.jpg"`
-	Slug               string    `json:"slug,omitempty" example:"dior-sauvage"`
-	Accords            []string  `json:"accords,omitempty" example:"[\"woody\",\"citrus\"]"`
-	Occasions          []string  `json:"occasions,omitempty" example:"[\"work\",\"night out\"]"`
-	Seasons            []string  `json:"seasons,omitempty" example:"[\"summer\",\"spring\"]"`
-	Intensity          string    `json:"intensity,omitempty" example:"moderate"`
-	Gender             string    `json:"gender,omitempty" example:"unisex"`
-	PriceRange         string    `json:"price_range,omitempty" example:"premium"`
-	NotesTop           []string  `json:"notes_top,omitempty" example:"[\"bergamot\"]"`
-	NotesHeart         []string  `json:"notes_heart,omitempty" example:"[\"lavender\"]"`
-	NotesBase          []string  `json:"notes_base,omitempty" example:"[\"ambroxan\"]"`
-	Category           string    `json:"category" example:"Eau de Parfum"`
-	StockQuantity      int       `json:"stock_quantity" example:"50"`
-	CreatedAt          time.Time `json:"created_at,omitempty" example:"2025-09-28T10:00:00Z"`
-	UpdatedAt          time.Time `json:"updated_at,omitempty" example:"2025-09-28T10:00:00Z"`
-	CanReview          *bool     `json:"can_review"`
-	CannotReviewReason *string   `json:"cannot_review_reason,omitempty"`
+	ID                 *uint                    `json:"id,omitempty" example:"1"`
+	Name               string                   `json:"name" example:"Sauvage"`
+	Brand              string                   `json:"brand" example:"Dior"`
+	Weight             float64                  `json:"weight" example:"100.0"`
+	Description        string                   `json:"description" example:"A fresh and woody fragrance"`
+	Price              money.Amount             `json:"price" example:"299.99"`
+	ImageURL           string                   `json:"image_url" example:"https://example.com/image.jpg"`
+	ThumbnailURL       string                   `json:"thumbnail_url,omitempty" example:"https://example.com/image_thumb.jpg"`
+	Slug               string                   `json:"slug,omitempty" example:"dior-sauvage"`
+	Accords            []string                 `json:"accords,omitempty" example:"[\"woody\",\"citrus\"]"`
+	Occasions          []string                 `json:"occasions,omitempty" example:"[\"work\",\"night out\"]"`
+	Seasons            []string                 `json:"seasons,omitempty" example:"[\"summer\",\"spring\"]"`
+	Intensity          string                   `json:"intensity,omitempty" example:"moderate"`
+	Gender             string                   `json:"gender,omitempty" example:"unisex"`
+	PriceRange         string                   `json:"price_range,omitempty" example:"premium"`
+	NotesTop           []string                 `json:"notes_top,omitempty" example:"[\"bergamot\"]"`
+	NotesHeart         []string                 `json:"notes_heart,omitempty" example:"[\"lavender\"]"`
+	NotesBase          []string                 `json:"notes_base,omitempty" example:"[\"ambroxan\"]"`
+	Category           string                   `json:"category" example:"Eau de Parfum"`
+	StockQuantity      int                      `json:"stock_quantity" example:"50"`
+	Variants           []ProductVariantResponse `json:"variants,omitempty"`
+	Images             []ProductImageResponse   `json:"images,omitempty"`
+	CreatedAt          time.Time                `json:"created_at,omitempty" example:"2025-09-28T10:00:00Z"`
+	UpdatedAt          time.Time                `json:"updated_at,omitempty" example:"2025-09-28T10:00:00Z"`
+	CanReview          *bool                    `json:"can_review"`
+	CannotReviewReason *string                  `json:"cannot_review_reason,omitempty"`
+}
+
+// ProductVariantResponse represents a purchasable size and concentration of a product
+type ProductVariantResponse struct {
+	// ID is for server-side lookups; clients address variants by SKU
+	ID            uint         `json:"-"`
+	SKU           string       `json:"sku" example:"DIOR-SAUVAGE-EDP-100"`
+	SizeML        int          `json:"size_ml,omitempty" example:"100"`
+	Concentration string       `json:"concentration" example:"edp"`
+	Price         money.Amount `json:"price" example:"899.90"`
+	StockQuantity int          `json:"stock_quantity" example:"12"`
+	Weight        float64      `json:"weight" example:"0.35"`
+	Barcode       string       `json:"barcode,omitempty" example:"3348901250153"`
+}
+
+// ProductVariantResponseFromModel maps a variant to its response
+func ProductVariantResponseFromModel(v model.ProductVariant) ProductVariantResponse {
+	resp := ProductVariantResponse{
+		ID:            v.ID,
+		SKU:           v.SKU,
+		SizeML:        v.SizeML,
+		Concentration: string(v.Concentration),
+		Price:         v.Price,
+		StockQuantity: v.StockQuantity,
+		Weight:        v.Weight,
+	}
+	if v.Barcode != nil {
+		resp.Barcode = *v.Barcode
+	}
+	return resp
+}
+
+// ProductVariantResponsesFromModel maps a product's variants, which are ordered default first
+func ProductVariantResponsesFromModel(variants []model.ProductVariant) []ProductVariantResponse {
+	if len(variants) == 0 {
+		return nil
+	}
+	resp := make([]ProductVariantResponse, len(variants))
+	for i, v := range variants {
+		resp[i] = ProductVariantResponseFromModel(v)
+	}
+	return resp
 }
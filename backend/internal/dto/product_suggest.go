@@ -0,0 +1,18 @@
+package dto
+
+// ProductSuggestion is a product offered while the user types in the search box
+type ProductSuggestion struct {
+	Name         string `json:"name" example:"Sauvage"`
+	Brand        string `json:"brand" example:"Dior"`
+	Slug         string `json:"slug" example:"dior-sauvage"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty" example:"https://example.com/image_thumb.jpg"`
+}
+
+// SuggestResponse groups typeahead suggestions. Each group is ranked by prefix match first and
+// trigram similarity second.
+type SuggestResponse struct {
+	Products []ProductSuggestion `json:"products"`
+	Brands   []string            `json:"brands"`
+	Accords  []string            `json:"accords"`
+	Notes    []string            `json:"notes"`
+}
@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
+
+// PromotionRequest represents the payload to create or replace a promotion
+type PromotionRequest struct {
+	Code           string       `json:"code" binding:"required,min=3,max=64" example:"WELCOME10"`
+	Name           string       `json:"name" binding:"required,max=128" example:"Welcome discount"`
+	Description    string       `json:"description,omitempty" binding:"max=2000"`
+	Type           string       `json:"type" binding:"required,oneof=percentage fixed_amount free_shipping buy_x_get_y" example:"percentage"`
+	PercentOff     int          `json:"percent_off,omitempty" binding:"omitempty,min=1,max=100" example:"10"`
+	AmountOff      money.Amount `json:"amount_off,omitempty" example:"25"`
+	BuyQuantity    int          `json:"buy_quantity,omitempty" binding:"omitempty,min=1" example:"2"`
+	GetQuantity    int          `json:"get_quantity,omitempty" binding:"omitempty,min=1" example:"1"`
+	Brand          string       `json:"brand,omitempty" binding:"max=64"`
+	Category       string       `json:"category,omitempty" binding:"max=64"`
+	MinSubtotal    money.Amount `json:"min_subtotal,omitempty" example:"150"`
+	MaxUses        *int         `json:"max_uses,omitempty" binding:"omitempty,min=1" example:"500"`
+	MaxUsesPerUser *int         `json:"max_uses_per_user,omitempty" binding:"omitempty,min=1" example:"1"`
+	StartsAt       *time.Time   `json:"starts_at,omitempty"`
+	EndsAt         *time.Time   `json:"ends_at,omitempty"`
+	Active         *bool        `json:"active,omitempty" example:"true"`
+}
+
+// PromotionResponse represents a promotion returned to admins
+type PromotionResponse struct {
+	ID             uint         `json:"id"`
+	Code           string       `json:"code" example:"WELCOME10"`
+	Name           string       `json:"name"`
+	Description    string       `json:"description,omitempty"`
+	Type           string       `json:"type" example:"percentage"`
+	PercentOff     int          `json:"percent_off,omitempty"`
+	AmountOff      money.Amount `json:"amount_off,omitempty"`
+	BuyQuantity    int          `json:"buy_quantity,omitempty"`
+	GetQuantity    int          `json:"get_quantity,omitempty"`
+	Brand          string       `json:"brand,omitempty"`
+	Category       string       `json:"category,omitempty"`
+	MinSubtotal    money.Amount `json:"min_subtotal,omitempty"`
+	MaxUses        *int         `json:"max_uses,omitempty"`
+	MaxUsesPerUser *int         `json:"max_uses_per_user,omitempty"`
+	UsedCount      int          `json:"used_count"`
+	StartsAt       *time.Time   `json:"starts_at,omitempty"`
+	EndsAt         *time.Time   `json:"ends_at,omitempty"`
+	Active         bool         `json:"active"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// PromotionListResponse represents a page of promotions
+type PromotionListResponse struct {
+	Promotions []PromotionResponse `json:"promotions"`
+	Meta       struct {
+		Pagination PaginationMeta `json:"pagination"`
+	} `json:"meta"`
+}
+
+// ApplyPromotionRequest represents a coupon code entered at the cart
+type ApplyPromotionRequest struct {
+	Code string `json:"code" binding:"required,max=64" example:"WELCOME10"`
+}
+
+// CartPromotionResponse describes the coupon applied to a cart. When the coupon no longer applies,
+// Error carries the reason and no discount is given.
+type CartPromotionResponse struct {
+	Code         string       `json:"code" example:"WELCOME10"`
+	Name         string       `json:"name,omitempty"`
+	Type         string       `json:"type,omitempty" example:"percentage"`
+	Discount     money.Amount `json:"discount"`
+	FreeShipping bool         `json:"free_shipping,omitempty"`
+	Error        string       `json:"error,omitempty" example:"promotion_min_subtotal_not_met"`
+}
+
+// PromotionResponseFromModel maps a promotion to its admin representation
+func PromotionResponseFromModel(p *model.Promotion) PromotionResponse {
+	return PromotionResponse{
+		ID:             p.ID,
+		Code:           p.Code,
+		Name:           p.Name,
+		Description:    p.Description,
+		Type:           string(p.Type),
+		PercentOff:     p.PercentOff,
+		AmountOff:      p.AmountOff,
+		BuyQuantity:    p.BuyQuantity,
+		GetQuantity:    p.GetQuantity,
+		Brand:          p.Brand,
+		Category:       p.Category,
+		MinSubtotal:    p.MinSubtotal,
+		MaxUses:        p.MaxUses,
+		MaxUsesPerUser: p.MaxUsesPerUser,
+		UsedCount:      p.UsedCount,
+		StartsAt:       p.StartsAt,
+		EndsAt:         p.EndsAt,
+		Active:         p.Active,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
@@ -1,9 +1,11 @@
 package dto
 
+import "github.com/leoferamos/aroma-sense/internal/money"
+
 // ShippingOption represents a shipping option returned to the client.
 type ShippingOption struct {
-	Carrier       string  `json:"carrier"`
-	ServiceCode   string  `json:"service_code"`
-	Price         float64 `json:"price"`
-	EstimatedDays int     `json:"estimated_days"`
+	Carrier       string       `json:"carrier"`
+	ServiceCode   string       `json:"service_code"`
+	Price         money.Amount `json:"price"`
+	EstimatedDays int          `json:"estimated_days"`
 }
@@ -6,12 +6,16 @@ import "time"
 type CreateUserRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"user@example.com" format:"email"`
 	Password string `json:"password" binding:"required,min=8"`
+	// GuestID is the guest session whose cart moves into the new account; set from the guest cookie
+	GuestID string `json:"-" swaggerignore:"true"`
 }
 
 // LoginRequest represents the expected payload for user login.
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"user@example.com" format:"email"`
 	Password string `json:"password" binding:"required"`
+	// GuestID is the guest session whose cart moves into the account; set from the guest cookie
+	GuestID string `json:"-" swaggerignore:"true"`
 }
 
 // UpdateProfileRequest represents the payload to update user's profile fields.
@@ -19,6 +23,11 @@ type UpdateProfileRequest struct {
 	DisplayName string `json:"display_name" binding:"required,min=2,max=50" example:"João Santos"`
 }
 
+// UpdatePreferencesRequest represents the payload to update the user's communication preferences.
+type UpdatePreferencesRequest struct {
+	MarketingEmails *bool `json:"marketing_emails" binding:"required" example:"true"`
+}
+
 // AdminDeactivateUserRequest represents the payload for admin user deactivation with enhanced LGPD compliance
 type AdminDeactivateUserRequest struct {
 	Reason          string     `json:"reason" binding:"required,oneof=violation_of_terms privacy_violation fraud_suspicion account_compromise underage_user duplicate_account" example:"violation_of_terms"`
@@ -21,11 +21,12 @@ type LoginResponse struct {
 
 // ProfileResponse represents the current user's profile data
 type ProfileResponse struct {
-	PublicID    string    `json:"public_id"`
-	Email       string    `json:"email"`
-	Role        string    `json:"role"`
-	DisplayName *string   `json:"display_name,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
+	PublicID        string    `json:"public_id"`
+	Email           string    `json:"email"`
+	Role            string    `json:"role"`
+	DisplayName     *string   `json:"display_name,omitempty"`
+	MarketingEmails bool      `json:"marketing_emails"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 // UserExportResponse represents all user data for GDPR export
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// WebhookEventResponse is an entry of the payment webhook inbox as shown to admins
+type WebhookEventResponse struct {
+	ID             uint       `json:"id" example:"1"`
+	Provider       string     `json:"provider" example:"stripe"`
+	EventID        string     `json:"event_id" example:"evt_1Nv0"`
+	EventType      string     `json:"event_type,omitempty" example:"payment_intent.succeeded"`
+	Status         string     `json:"status" example:"failed"`
+	SignatureValid bool       `json:"signature_valid" example:"true"`
+	Attempts       int        `json:"attempts" example:"3"`
+	LastError      string     `json:"last_error,omitempty"`
+	Payload        string     `json:"payload"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	ProcessedAt    *time.Time `json:"processed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// AdminWebhookEventsResponse is the response returned by GET /admin/webhook-events
+type AdminWebhookEventsResponse struct {
+	Events []WebhookEventResponse `json:"events"`
+	Meta   struct {
+		Pagination PaginationMeta `json:"pagination"`
+	} `json:"meta"`
+}
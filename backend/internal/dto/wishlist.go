@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
+
+// WishlistItemResponse represents a saved product with its current price and stock
+type WishlistItemResponse struct {
+	ProductSlug  string       `json:"product_slug" example:"sauvage-edt-100ml"`
+	Name         string       `json:"name" example:"Sauvage EDT 100ml"`
+	Brand        string       `json:"brand" example:"Dior"`
+	ThumbnailURL string       `json:"thumbnail_url"`
+	Price        money.Amount `json:"price" example:"549.90"`
+	SavedPrice   money.Amount `json:"saved_price" example:"599.90"`
+	PriceDropped bool         `json:"price_dropped" example:"true"`
+	InStock      bool         `json:"in_stock" example:"true"`
+	SavedAt      time.Time    `json:"saved_at"`
+}
+
+// WishlistResponse represents the current user's wishlist
+type WishlistResponse struct {
+	Items []WishlistItemResponse `json:"items"`
+	Total int                    `json:"total" example:"3"`
+}
+
+// WishlistItemResponseFromModel maps a wishlist entry with its product to its response
+func WishlistItemResponseFromModel(item model.WishlistItem) WishlistItemResponse {
+	resp := WishlistItemResponse{
+		SavedPrice: item.SavedPrice,
+		SavedAt:    item.CreatedAt,
+	}
+	if item.Product != nil {
+		resp.ProductSlug = item.Product.Slug
+		resp.Name = item.Product.Name
+		resp.Brand = item.Product.Brand
+		resp.ThumbnailURL = item.Product.ThumbnailURL
+		resp.Price = item.Product.Price
+		resp.PriceDropped = item.Product.Price < item.SavedPrice
+		resp.InStock = item.Product.StockQuantity > 0
+	}
+	return resp
+}
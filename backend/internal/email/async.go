@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
 )
 
 // AsyncEmailService is a wrapper that enqueues email send operations and processes them in background workers.
@@ -83,6 +84,14 @@ func (a *AsyncEmailService) SendOrderConfirmation(to string, order *model.Order)
 	a.enqueue(func() { _ = a.svc.SendOrderConfirmation(to, order) })
 	return nil
 }
+func (a *AsyncEmailService) SendGuestOrderConfirmation(to string, order *model.Order, lookupToken, trackLink string) error {
+	a.enqueue(func() { _ = a.svc.SendGuestOrderConfirmation(to, order, lookupToken, trackLink) })
+	return nil
+}
+func (a *AsyncEmailService) SendOrderCancelled(to string, order *model.Order, refundedAmount money.Amount) error {
+	a.enqueue(func() { _ = a.svc.SendOrderCancelled(to, order, refundedAmount) })
+	return nil
+}
 func (a *AsyncEmailService) SendWelcomeEmail(to, name string) error {
 	a.enqueue(func() { _ = a.svc.SendWelcomeEmail(to, name) })
 	return nil
@@ -116,6 +125,11 @@ func (a *AsyncEmailService) SendDataAnonymized(to string) error {
 	return nil
 }
 
+func (a *AsyncEmailService) SendAdminAlert(to, subject, message string) error {
+	a.enqueue(func() { _ = a.svc.SendAdminAlert(to, subject, message) })
+	return nil
+}
+
 func (a *AsyncEmailService) SendDeletionCancelled(to string) error {
 	a.enqueue(func() { _ = a.svc.SendDeletionCancelled(to) })
 	return nil
@@ -1,6 +1,9 @@
 package email
 
-import "github.com/leoferamos/aroma-sense/internal/model"
+import (
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
 
 // EmailService defines the interface for sending emails.
 type EmailService interface {
@@ -10,6 +13,12 @@ type EmailService interface {
 	// SendOrderConfirmation sends order confirmation email to customer
 	SendOrderConfirmation(to string, order *model.Order) error
 
+	// SendGuestOrderConfirmation sends a guest the order confirmation with the lookup token to track it
+	SendGuestOrderConfirmation(to string, order *model.Order, lookupToken, trackLink string) error
+
+	// SendOrderCancelled notifies the customer that their order was cancelled and any refund issued
+	SendOrderCancelled(to string, order *model.Order, refundedAmount money.Amount) error
+
 	// SendWelcomeEmail sends welcome email to new users
 	SendWelcomeEmail(to, name string) error
 
@@ -36,4 +45,7 @@ type EmailService interface {
 
 	// SendDataAnonymized notifies user that their personal data has been anonymized
 	SendDataAnonymized(to string) error
+
+	// SendAdminAlert notifies an operator about an event that needs manual attention
+	SendAdminAlert(to, subject, message string) error
 }
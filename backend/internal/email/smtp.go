@@ -5,6 +5,7 @@ import (
 	"net/smtp"
 
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
 )
 
 // SMTPEmailService implements EmailService using SMTP protocol
@@ -36,6 +37,22 @@ func (s *SMTPEmailService) SendOrderConfirmation(to string, order *model.Order)
 	return s.sendEmail(to, subject, htmlBody)
 }
 
+// SendGuestOrderConfirmation sends a guest order confirmation email with its lookup token
+func (s *SMTPEmailService) SendGuestOrderConfirmation(to string, order *model.Order, lookupToken, trackLink string) error {
+	subject := "Order Confirmation - Aroma Sense"
+	htmlBody := GuestOrderConfirmationTemplate(fmt.Sprintf("#%d", order.ID), lookupToken, trackLink)
+
+	return s.sendEmail(to, subject, htmlBody)
+}
+
+// SendOrderCancelled sends order cancellation email
+func (s *SMTPEmailService) SendOrderCancelled(to string, order *model.Order, refundedAmount money.Amount) error {
+	subject := "Order Cancelled - Aroma Sense"
+	htmlBody := OrderCancelledTemplate(fmt.Sprintf("#%d", order.ID), refundedAmount)
+
+	return s.sendEmail(to, subject, htmlBody)
+}
+
 // SendWelcomeEmail sends welcome email to new users
 func (s *SMTPEmailService) SendWelcomeEmail(to, name string) error {
 	subject := "Welcome to Aroma Sense!"
@@ -125,6 +142,12 @@ func (s *SMTPEmailService) SendDataAnonymized(to string) error {
 	return s.sendEmail(to, subject, htmlBody)
 }
 
+// SendAdminAlert sends an operational alert to an administrator
+func (s *SMTPEmailService) SendAdminAlert(to, subject, message string) error {
+	htmlBody := AdminAlertTemplate(subject, message)
+	return s.sendEmail(to, "[Aroma Sense] "+subject, htmlBody)
+}
+
 // SendDeletionCancelled notifies the user that their deletion request was cancelled
 func (s *SMTPEmailService) SendDeletionCancelled(to string) error {
 	subject := "Solicitação de exclusão cancelada — Aroma Sense"
@@ -1,6 +1,13 @@
 package email
 
-import "fmt"
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
 
 // PasswordResetTemplate generates the HTML email body for password reset
 func PasswordResetTemplate(code string) string {
@@ -126,6 +133,203 @@ func OrderConfirmationTemplate(orderID string) string {
 `, orderID)
 }
 
+// OrderCancelledTemplate generates the HTML email body for order cancellation
+func OrderCancelledTemplate(orderID string, refundedAmount money.Amount) string {
+	refundLine := ""
+	if refundedAmount > 0 {
+		refundLine = fmt.Sprintf(`
+                            <p style="color: #666666; font-size: 16px;">
+                                A refund of <strong>R$ %s</strong> was issued to your original payment method.
+                            </p>`, refundedAmount)
+	}
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Order Cancelled</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: Arial, sans-serif; background-color: #f4f4f4;">
+    <table role="presentation" style="width: 100%%; border-collapse: collapse;">
+        <tr>
+            <td align="center" style="padding: 40px 0;">
+                <table role="presentation" style="width: 600px; border-collapse: collapse; background-color: #ffffff; border-radius: 8px;">
+                    <tr>
+                        <td style="padding: 40px; text-align: center;">
+                            <h1 style="color: #2563eb;">Order Cancelled</h1>
+                            <p style="color: #666666; font-size: 16px;">
+                                Your order <strong>%s</strong> has been cancelled.
+                            </p>%s
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>
+`, orderID, refundLine)
+}
+
+// GuestOrderConfirmationTemplate generates the HTML email body confirming a guest order with the
+// lookup token needed to track it, since guests have no account to find the order in.
+func GuestOrderConfirmationTemplate(orderID, lookupToken, trackLink string) string {
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Order Confirmation</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: Arial, sans-serif; background-color: #f4f4f4;">
+    <table role="presentation" style="width: 100%%; border-collapse: collapse;">
+        <tr>
+            <td align="center" style="padding: 40px 0;">
+                <table role="presentation" style="width: 600px; border-collapse: collapse; background-color: #ffffff; border-radius: 8px;">
+                    <tr>
+                        <td style="padding: 40px; text-align: center;">
+                            <h1 style="color: #2563eb;">Order Confirmed!</h1>
+                            <p style="color: #666666; font-size: 16px;">
+                                Thank you for your order. Your order ID is: <strong>%s</strong>
+                            </p>
+                            <p style="color: #666666; font-size: 16px;">
+                                Use this email address and the lookup code below to track your order:
+                            </p>
+                            <p style="font-size: 18px; font-family: monospace; color: #111111;">%s</p>
+                            <a href="%s" style="display: inline-block; padding: 12px 24px; background-color: #2563eb; color: #ffffff; text-decoration: none; border-radius: 6px;">Track your order</a>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>
+`, html.EscapeString(orderID), html.EscapeString(lookupToken), html.EscapeString(trackLink))
+}
+
+// AbandonedCartTemplate generates the HTML email body reminding a shopper of the items left in their cart
+func AbandonedCartTemplate(items []model.CartItem, cartLink string) string {
+	var rows strings.Builder
+	for _, item := range items {
+		if item.Product == nil {
+			continue
+		}
+		image := item.Product.ThumbnailURL
+		if image == "" {
+			image = item.Product.ImageURL
+		}
+		thumbnail := ""
+		if image != "" {
+			thumbnail = fmt.Sprintf(`<img src="%s" alt="%s" width="64" height="64" style="display: block; border-radius: 4px;">`,
+				html.EscapeString(image), html.EscapeString(item.Product.Name))
+		}
+		fmt.Fprintf(&rows, `
+                                <tr>
+                                    <td style="padding: 8px; width: 64px;">%s</td>
+                                    <td style="padding: 8px; color: #333333; font-size: 15px;">%s &times; %d</td>
+                                    <td style="padding: 8px; color: #333333; font-size: 15px; text-align: right;">R$ %s</td>
+                                </tr>`, thumbnail, html.EscapeString(item.Product.Name), item.Quantity, item.Price.Mul(item.Quantity))
+	}
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Your cart is waiting</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: Arial, sans-serif; background-color: #f4f4f4;">
+    <table role="presentation" style="width: 100%%; border-collapse: collapse;">
+        <tr>
+            <td align="center" style="padding: 40px 0;">
+                <table role="presentation" style="width: 600px; border-collapse: collapse; background-color: #ffffff; border-radius: 8px;">
+                    <tr>
+                        <td style="padding: 40px; text-align: center;">
+                            <h1 style="color: #2563eb;">Your cart is waiting</h1>
+                            <p style="color: #666666; font-size: 16px;">
+                                You left these items in your cart. They are still there whenever you are ready.
+                            </p>
+                            <table role="presentation" style="width: 100%%; border-collapse: collapse; margin: 24px 0;">%s
+                            </table>
+                            <a href="%s" style="display: inline-block; padding: 12px 24px; background-color: #2563eb; color: #ffffff; text-decoration: none; border-radius: 4px; font-size: 16px;">Return to my cart</a>
+                            <p style="color: #999999; font-size: 12px; margin-top: 32px;">
+                                You are receiving this email because you opted in to marketing emails. You can turn them off in your account settings.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>
+`, rows.String(), html.EscapeString(cartLink))
+}
+
+// WishlistAlertTemplate generates the HTML email body announcing price drops and restocks of wishlisted products
+func WishlistAlertTemplate(alerts []model.WishlistAlert, productBase string) string {
+	var rows strings.Builder
+	for _, alert := range alerts {
+		image := alert.Product.ThumbnailURL
+		if image == "" {
+			image = alert.Product.ImageURL
+		}
+		thumbnail := ""
+		if image != "" {
+			thumbnail = fmt.Sprintf(`<img src="%s" alt="%s" width="64" height="64" style="display: block; border-radius: 4px;">`,
+				html.EscapeString(image), html.EscapeString(alert.Product.Name))
+		}
+		var status []string
+		if alert.PriceDropped {
+			status = append(status, fmt.Sprintf("Now R$ %s, was R$ %s when you saved it", alert.Product.Price, alert.SavedPrice))
+		}
+		if alert.BackInStock {
+			status = append(status, "Back in stock")
+		}
+		link := productBase + "/" + alert.Product.Slug
+		fmt.Fprintf(&rows, `
+                                <tr>
+                                    <td style="padding: 8px; width: 64px;">%s</td>
+                                    <td style="padding: 8px; text-align: left;">
+                                        <a href="%s" style="color: #2563eb; font-size: 15px; text-decoration: none;">%s</a>
+                                        <p style="margin: 4px 0 0; color: #666666; font-size: 14px;">%s</p>
+                                    </td>
+                                </tr>`, thumbnail, html.EscapeString(link), html.EscapeString(alert.Product.Name), html.EscapeString(strings.Join(status, ". ")))
+	}
+	return fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>News about your wishlist</title>
+</head>
+<body style="margin: 0; padding: 0; font-family: Arial, sans-serif; background-color: #f4f4f4;">
+    <table role="presentation" style="width: 100%%; border-collapse: collapse;">
+        <tr>
+            <td align="center" style="padding: 40px 0;">
+                <table role="presentation" style="width: 600px; border-collapse: collapse; background-color: #ffffff; border-radius: 8px;">
+                    <tr>
+                        <td style="padding: 40px; text-align: center;">
+                            <h1 style="color: #2563eb;">News about your wishlist</h1>
+                            <p style="color: #666666; font-size: 16px;">
+                                Some products you saved for later have changed.
+                            </p>
+                            <table role="presentation" style="width: 100%%; border-collapse: collapse; margin: 24px 0;">%s
+                            </table>
+                            <p style="color: #999999; font-size: 12px; margin-top: 32px;">
+                                You are receiving this email because these products are in your wishlist. Remove them to stop these alerts.
+                            </p>
+                        </td>
+                    </tr>
+                </table>
+            </td>
+        </tr>
+    </table>
+</body>
+</html>
+`, rows.String())
+}
+
 // AccountDeactivatedTemplate generates the HTML body for account deactivation notification
 func AccountDeactivatedTemplate(reason string, contestationDeadline string) string {
 	return fmt.Sprintf(`
@@ -209,3 +413,12 @@ func DeletionCancelledTemplate(name, cancelledAt string) string {
 <p>Atenciosamente,<br>Equipe Aroma Sense</p>
 `, name, cancelledAt)
 }
+
+// AdminAlertTemplate generates the HTML body for operational alerts sent to administrators
+func AdminAlertTemplate(subject, message string) string {
+	return fmt.Sprintf(`
+<h2>%s</h2>
+<p>%s</p>
+<p>Esta é uma mensagem automática do Aroma Sense.</p>
+`, html.EscapeString(subject), html.EscapeString(message))
+}
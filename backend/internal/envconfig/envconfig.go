@@ -0,0 +1,39 @@
+// Package envconfig reads optional tuning values from the environment. A missing variable yields
+// the fallback; an invalid one is logged and also yields the fallback, so a typo never stops the
+// server from starting.
+package envconfig
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Duration reads a positive Go duration such as "45m" or "24h".
+func Duration(key string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Printf("invalid %s=%q, using default %s", key, raw, fallback)
+		return fallback
+	}
+	return d
+}
+
+// Count reads a positive integer.
+func Count(key string, fallback int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		log.Printf("invalid %s=%q, using default %d", key, raw, fallback)
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,34 @@
+package envconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuration(t *testing.T) {
+	t.Setenv("TEST_DURATION", "")
+	assert.Equal(t, time.Hour, Duration("TEST_DURATION", time.Hour))
+
+	t.Setenv("TEST_DURATION", "45m")
+	assert.Equal(t, 45*time.Minute, Duration("TEST_DURATION", time.Hour))
+
+	for _, raw := range []string{"soon", "0s", "-5m"} {
+		t.Setenv("TEST_DURATION", raw)
+		assert.Equal(t, time.Hour, Duration("TEST_DURATION", time.Hour), raw)
+	}
+}
+
+func TestCount(t *testing.T) {
+	t.Setenv("TEST_COUNT", "")
+	assert.Equal(t, 2, Count("TEST_COUNT", 2))
+
+	t.Setenv("TEST_COUNT", "5")
+	assert.Equal(t, 5, Count("TEST_COUNT", 2))
+
+	for _, raw := range []string{"many", "0", "-1"} {
+		t.Setenv("TEST_COUNT", raw)
+		assert.Equal(t, 2, Count("TEST_COUNT", 2), raw)
+	}
+}
@@ -0,0 +1,111 @@
+// Package febraban builds and renders boleto bancário codes following the FEBRABAN layout.
+package febraban
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// currencyReal is the currency code of boletos issued in BRL.
+const currencyReal = "9"
+
+// dueFactorBase is day zero of the due date factor; the factor wraps from 9999 back to 1000.
+var dueFactorBase = time.Date(1997, 10, 7, 0, 0, 0, 0, time.UTC)
+
+// Boleto holds the fields encoded in the 44-digit barcode.
+type Boleto struct {
+	BankCode    string
+	DueDate     time.Time
+	AmountCents int64
+	// FreeField is the 25-digit bank-specific block, usually agreement and "nosso número".
+	FreeField string
+}
+
+// Barcode returns the 44-digit barcode, including its general check digit at position 5.
+func (b Boleto) Barcode() (string, error) {
+	if len(b.BankCode) != 3 || !isDigits(b.BankCode) {
+		return "", fmt.Errorf("bank code must have 3 digits")
+	}
+	if len(b.FreeField) != 25 || !isDigits(b.FreeField) {
+		return "", fmt.Errorf("free field must have 25 digits")
+	}
+	if b.AmountCents < 0 || b.AmountCents > 9999999999 {
+		return "", fmt.Errorf("amount out of range")
+	}
+
+	withoutDV := b.BankCode + currencyReal + DueFactor(b.DueDate) + fmt.Sprintf("%010d", b.AmountCents) + b.FreeField
+	dv := mod11(withoutDV)
+	return withoutDV[:4] + dv + withoutDV[4:], nil
+}
+
+// DueFactor returns the 4-digit number of days between the base date and the due date.
+func DueFactor(due time.Time) string {
+	day := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(day.Sub(dueFactorBase).Hours() / 24)
+	if days > 9999 {
+		days = (days-10000)%9000 + 1000
+	}
+	return fmt.Sprintf("%04d", days)
+}
+
+// DigitableLine converts a barcode to the 47-digit line customers type into their bank app,
+// formatted as "AAAAA.AAAAA BBBBB.BBBBBB CCCCC.CCCCCC D EEEEEEEEEEEEEE".
+func DigitableLine(barcode string) (string, error) {
+	if len(barcode) != 44 || !isDigits(barcode) {
+		return "", fmt.Errorf("barcode must have 44 digits")
+	}
+	f1 := barcode[0:4] + barcode[19:24]
+	f2 := barcode[24:34]
+	f3 := barcode[34:44]
+	f1 += mod10(f1)
+	f2 += mod10(f2)
+	f3 += mod10(f3)
+	return strings.Join([]string{
+		f1[:5] + "." + f1[5:],
+		f2[:5] + "." + f2[5:],
+		f3[:5] + "." + f3[5:],
+		barcode[4:5],
+		barcode[5:19],
+	}, " "), nil
+}
+
+// mod10 is the check digit of each digitable line field: weights 2,1,2,1... from the right,
+// summing the digits of each product.
+func mod10(digits string) string {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		p := int(digits[i]-'0') * weight
+		sum += p/10 + p%10
+		weight = 3 - weight
+	}
+	return fmt.Sprintf("%d", (10-sum%10)%10)
+}
+
+// mod11 is the general barcode check digit: weights 2..9 from the right, with 0, 10 and 11 mapped to 1.
+func mod11(digits string) string {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	dv := 11 - sum%11
+	if dv == 0 || dv == 10 || dv == 11 {
+		dv = 1
+	}
+	return fmt.Sprintf("%d", dv)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,59 @@
+package febraban
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBarcode(t *testing.T) {
+	b := Boleto{
+		BankCode:    "001",
+		DueDate:     time.Date(2007, 12, 31, 0, 0, 0, 0, time.UTC),
+		AmountCents: 100,
+		FreeField:   "0500940144816060680935031",
+	}
+
+	barcode, err := b.Barcode()
+	assert.NoError(t, err)
+	assert.Equal(t, "00193373700000001000500940144816060680935031", barcode)
+
+	line, err := DigitableLine(barcode)
+	assert.NoError(t, err)
+	assert.Equal(t, "00190.50095 40144.816069 06809.350314 3 37370000000100", line)
+
+	_, err = Boleto{BankCode: "1", FreeField: b.FreeField}.Barcode()
+	assert.Error(t, err)
+}
+
+func TestDueFactor(t *testing.T) {
+	assert.Equal(t, "9999", DueFactor(time.Date(2025, 2, 21, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "1000", DueFactor(time.Date(2025, 2, 22, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "1297", DueFactor(time.Date(2025, 12, 16, 15, 0, 0, 0, time.UTC)))
+}
+
+func TestRenderHTML(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderHTML(&buf, Document{
+		Beneficiary:   "Aroma Sense <Ltda>",
+		Payer:         "ana@example.com",
+		OurNumber:     "00000000000000123",
+		AmountCents:   12990,
+		DueDate:       time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC),
+		DigitableLine: "00190.50095 40144.816069 06809.350314 3 37370000000100",
+		Barcode:       "00193373700000001000500940144816060680935031",
+	})
+	assert.NoError(t, err)
+	html := buf.String()
+	assert.Contains(t, html, "R$ 129,90")
+	assert.Contains(t, html, "19/12/2025")
+	assert.Contains(t, html, "Aroma Sense &lt;Ltda&gt;")
+	// start (2) + 22 pairs of 5 + stop (2)
+	assert.Equal(t, 2+22*5+2, strings.Count(html, "<rect"))
+
+	err = RenderHTML(&buf, Document{Barcode: "123"})
+	assert.Error(t, err)
+}
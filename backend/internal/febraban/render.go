@@ -0,0 +1,120 @@
+package febraban
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// Document is the printable boleto shown to the customer.
+type Document struct {
+	Beneficiary   string
+	Payer         string
+	OurNumber     string
+	AmountCents   int64
+	DueDate       time.Time
+	DigitableLine string
+	Barcode       string
+	Instructions  string
+}
+
+// interleaved2of5 holds the narrow (n) and wide (w) widths of each digit.
+var interleaved2of5 = [10]string{"nnwwn", "wnnnw", "nwnnw", "wwnnn", "nnwnw", "wnwnn", "nwwnn", "nnnww", "wnnwn", "nwnwn"}
+
+// bar is one dark stripe of the barcode.
+type bar struct {
+	X     int
+	Width int
+}
+
+const (
+	narrowWidth = 1
+	wideWidth   = 3
+)
+
+// barcodeBars lays out an Interleaved 2 of 5 barcode: a start pattern, digit pairs where the
+// first digit drives the bars and the second the spaces, and a stop pattern.
+func barcodeBars(digits string) ([]bar, int, error) {
+	if len(digits)%2 != 0 || !isDigits(digits) {
+		return nil, 0, fmt.Errorf("interleaved 2 of 5 needs an even number of digits")
+	}
+	width := func(c byte) int {
+		if c == 'w' {
+			return wideWidth
+		}
+		return narrowWidth
+	}
+
+	var bars []bar
+	x := 0
+	draw := func(w int) {
+		bars = append(bars, bar{X: x, Width: w})
+		x += w
+	}
+
+	draw(narrowWidth)
+	x += narrowWidth
+	draw(narrowWidth)
+	x += narrowWidth
+	for i := 0; i < len(digits); i += 2 {
+		dark := interleaved2of5[digits[i]-'0']
+		light := interleaved2of5[digits[i+1]-'0']
+		for j := 0; j < 5; j++ {
+			draw(width(dark[j]))
+			x += width(light[j])
+		}
+	}
+	draw(wideWidth)
+	x += narrowWidth
+	draw(narrowWidth)
+	return bars, x, nil
+}
+
+var documentTemplate = template.Must(template.New("boleto").Funcs(template.FuncMap{
+	"money": func(cents int64) string { return fmt.Sprintf("R$ %d,%02d", cents/100, cents%100) },
+	"date":  func(t time.Time) string { return t.Format("02/01/2006") },
+}).Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>Boleto {{.OurNumber}}</title>
+<style>
+body { font-family: Arial, sans-serif; max-width: 720px; margin: 24px auto; color: #222; }
+table { width: 100%; border-collapse: collapse; }
+td { border: 1px solid #444; padding: 6px 8px; font-size: 13px; vertical-align: top; }
+td small { display: block; color: #666; font-size: 10px; }
+.line { font-family: monospace; font-size: 16px; letter-spacing: 1px; text-align: right; margin: 12px 0; }
+.barcode { margin-top: 16px; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<div class="line">{{.DigitableLine}}</div>
+<table>
+<tr><td colspan="2"><small>Beneficiário</small>{{.Beneficiary}}</td><td><small>Vencimento</small>{{date .DueDate}}</td></tr>
+<tr><td colspan="2"><small>Pagador</small>{{.Payer}}</td><td><small>Nosso número</small>{{.OurNumber}}</td></tr>
+<tr><td colspan="2"><small>Instruções</small>{{.Instructions}}</td><td><small>Valor do documento</small>{{money .AmountCents}}</td></tr>
+</table>
+<div class="barcode">
+<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="50" viewBox="0 0 {{.Units}} 50" preserveAspectRatio="none" role="img" aria-label="{{.Barcode}}">
+{{range .Bars}}<rect x="{{.X}}" y="0" width="{{.Width}}" height="50"/>{{end}}
+</svg>
+</div>
+</body>
+</html>
+`))
+
+// RenderHTML writes a printable HTML boleto; browsers can save it as PDF.
+func RenderHTML(w io.Writer, doc Document) error {
+	bars, units, err := barcodeBars(doc.Barcode)
+	if err != nil {
+		return err
+	}
+	return documentTemplate.Execute(w, struct {
+		Document
+		Bars  []bar
+		Units int
+		Width int
+	}{Document: doc, Bars: bars, Units: units, Width: 640})
+}
@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	handlererrors "github.com/leoferamos/aroma-sense/internal/handler/errors"
+	cartservice "github.com/leoferamos/aroma-sense/internal/service/cart"
+)
+
+// AdminCartRecoveryHandler reports on abandoned cart reminders
+type AdminCartRecoveryHandler struct {
+	service cartservice.CartRecoveryService
+}
+
+func NewAdminCartRecoveryHandler(s cartservice.CartRecoveryService) *AdminCartRecoveryHandler {
+	return &AdminCartRecoveryHandler{service: s}
+}
+
+// GetAbandonedCartMetrics reports how abandoned cart reminders converted into orders
+//
+// @Summary      Abandoned cart recovery metrics
+// @Description  Counts reminders sent in the last days and the reminded carts whose owner ordered afterwards. conversion_rate is carts_recovered / carts_reminded.
+// @Tags         admin-metrics
+// @Produce      json
+// @Param        days  query    int  false  "Period in days (1-365)"  default(30)
+// @Success      200  {object}  dto.CartRecoveryMetricsResponse
+// @Failure      400  {object}  dto.ErrorResponse "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse "Error code: unauthorized"
+// @Failure      500  {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /admin/metrics/abandoned-carts [get]
+// @Security     BearerAuth
+func (h *AdminCartRecoveryHandler) GetAbandonedCartMetrics(c *gin.Context) {
+	days := 30
+	if d := c.Query("days"); d != "" {
+		v, err := strconv.Atoi(d)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+			return
+		}
+		days = v
+	}
+
+	resp, err := h.service.GetRecoveryMetrics(days)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,69 @@
+package admin_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/handler/admin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCartRecoveryService struct {
+	days   int
+	result *dto.CartRecoveryMetricsResponse
+	err    error
+}
+
+func (m *mockCartRecoveryService) SendReminders() (int, error) { return 0, nil }
+
+func (m *mockCartRecoveryService) GetRecoveryMetrics(days int) (*dto.CartRecoveryMetricsResponse, error) {
+	m.days = days
+	return m.result, m.err
+}
+
+func setupAdminCartRecoveryRouter(svc *mockCartRecoveryService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handler := admin.NewAdminCartRecoveryHandler(svc)
+	r.GET("/admin/metrics/abandoned-carts", handler.GetAbandonedCartMetrics)
+	return r
+}
+
+func TestAdminCartRecoveryHandler_GetAbandonedCartMetrics(t *testing.T) {
+	t.Run("defaults to 30 days", func(t *testing.T) {
+		svc := &mockCartRecoveryService{result: &dto.CartRecoveryMetricsResponse{Days: 30, CartsReminded: 10, CartsRecovered: 2, ConversionRate: 0.2}}
+		r := setupAdminCartRecoveryRouter(svc)
+
+		req, _ := http.NewRequest("GET", "/admin/metrics/abandoned-carts", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 30, svc.days)
+		assert.Contains(t, w.Body.String(), `"conversion_rate":0.2`)
+	})
+
+	t.Run("invalid days", func(t *testing.T) {
+		r := setupAdminCartRecoveryRouter(&mockCartRecoveryService{})
+
+		req, _ := http.NewRequest("GET", "/admin/metrics/abandoned-carts?days=abc", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("out of range days", func(t *testing.T) {
+		r := setupAdminCartRecoveryRouter(&mockCartRecoveryService{err: apperror.NewCodeMessage("invalid_request", "days must be between 1 and 365")})
+
+		req, _ := http.NewRequest("GET", "/admin/metrics/abandoned-carts?days=900", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
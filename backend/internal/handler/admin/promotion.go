@@ -0,0 +1,211 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	handlererrors "github.com/leoferamos/aroma-sense/internal/handler/errors"
+	promotionservice "github.com/leoferamos/aroma-sense/internal/service/promotion"
+)
+
+// maxPromotionsPerPage caps admin promotion listings.
+const maxPromotionsPerPage = 100
+
+// AdminPromotionHandler handles admin promotion management
+type AdminPromotionHandler struct {
+	service promotionservice.PromotionService
+}
+
+func NewAdminPromotionHandler(s promotionservice.PromotionService) *AdminPromotionHandler {
+	return &AdminPromotionHandler{service: s}
+}
+
+// ListPromotions lists promotions, newest first
+//
+// @Summary      List promotions
+// @Description  List coupon promotions with pagination
+// @Tags         admin-promotions
+// @Produce      json
+// @Param        page      query    int  false  "Page"      default(1)
+// @Param        per_page  query    int  false  "Per page"  default(25)
+// @Success      200  {object}  dto.PromotionListResponse
+// @Failure      400  {object}  dto.ErrorResponse "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse "Error code: unauthorized"
+// @Failure      500  {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /admin/promotions [get]
+// @Security     BearerAuth
+func (h *AdminPromotionHandler) ListPromotions(c *gin.Context) {
+	page := 1
+	perPage := 25
+	if p := c.Query("page"); p != "" {
+		if v, err := strconv.Atoi(p); err == nil && v > 0 {
+			page = v
+		} else {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+			return
+		}
+	}
+	if pp := c.Query("per_page"); pp != "" {
+		if v, err := strconv.Atoi(pp); err == nil && v > 0 {
+			perPage = v
+		} else {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+			return
+		}
+	}
+	if perPage > maxPromotionsPerPage {
+		perPage = maxPromotionsPerPage
+	}
+
+	resp, err := h.service.ListPromotions(page, perPage)
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetPromotion returns a promotion
+//
+// @Summary      Get promotion
+// @Description  Get a coupon promotion by ID, including how many times it was redeemed
+// @Tags         admin-promotions
+// @Produce      json
+// @Param        id   path      int  true  "Promotion ID"
+// @Success      200  {object}  dto.PromotionResponse
+// @Failure      400  {object}  dto.ErrorResponse "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse "Error code: unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse "Error code: promotion_not_found"
+// @Failure      500  {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /admin/promotions/{id} [get]
+// @Security     BearerAuth
+func (h *AdminPromotionHandler) GetPromotion(c *gin.Context) {
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.GetPromotion(id)
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreatePromotion creates a promotion
+//
+// @Summary      Create promotion
+// @Description  Create a coupon promotion. Codes are stored upper-case. Type decides which of percent_off, amount_off or buy_quantity/get_quantity is required; brand and category restrict the eligible products.
+// @Tags         admin-promotions
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.PromotionRequest  true  "Promotion"
+// @Success      201   {object}  dto.PromotionResponse
+// @Failure      400   {object}  dto.ErrorResponse "Error code: invalid_request or invalid_promotion"
+// @Failure      401   {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      403   {object}  dto.ErrorResponse "Error code: unauthorized"
+// @Failure      409   {object}  dto.ErrorResponse "Error code: promotion_code_taken"
+// @Failure      500   {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /admin/promotions [post]
+// @Security     BearerAuth
+func (h *AdminPromotionHandler) CreatePromotion(c *gin.Context) {
+	var req dto.PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	resp, err := h.service.CreatePromotion(&req, c.GetString("userID"))
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// UpdatePromotion replaces a promotion's settings
+//
+// @Summary      Update promotion
+// @Description  Replace the settings of a coupon promotion. Its redemption count is kept; max_uses cannot go below it.
+// @Tags         admin-promotions
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                   true  "Promotion ID"
+// @Param        body  body      dto.PromotionRequest  true  "Promotion"
+// @Success      200   {object}  dto.PromotionResponse
+// @Failure      400   {object}  dto.ErrorResponse "Error code: invalid_request or invalid_promotion"
+// @Failure      401   {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      403   {object}  dto.ErrorResponse "Error code: unauthorized"
+// @Failure      404   {object}  dto.ErrorResponse "Error code: promotion_not_found"
+// @Failure      409   {object}  dto.ErrorResponse "Error code: promotion_code_taken"
+// @Failure      500   {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /admin/promotions/{id} [put]
+// @Security     BearerAuth
+func (h *AdminPromotionHandler) UpdatePromotion(c *gin.Context) {
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+	var req dto.PromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	resp, err := h.service.UpdatePromotion(id, &req, c.GetString("userID"))
+	if err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeletePromotion deletes a promotion that was never redeemed
+//
+// @Summary      Delete promotion
+// @Description  Delete a coupon promotion. Redeemed promotions cannot be deleted; deactivate them instead.
+// @Tags         admin-promotions
+// @Param        id   path  int  true  "Promotion ID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  dto.ErrorResponse "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse "Error code: unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse "Error code: promotion_not_found"
+// @Failure      409  {object}  dto.ErrorResponse "Error code: promotion_in_use"
+// @Failure      500  {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /admin/promotions/{id} [delete]
+// @Security     BearerAuth
+func (h *AdminPromotionHandler) DeletePromotion(c *gin.Context) {
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePromotion(id, c.GetString("userID")); err != nil {
+		respondPromotionError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func promotionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondPromotionError(c *gin.Context, err error) {
+	if status, code, ok := handlererrors.MapServiceError(err); ok {
+		c.JSON(status, dto.ErrorResponse{Error: code})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+}
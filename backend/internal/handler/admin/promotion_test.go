@@ -0,0 +1,182 @@
+package admin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/handler/admin"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	promotionservice "github.com/leoferamos/aroma-sense/internal/service/promotion"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPromotionService struct {
+	created    *dto.PromotionRequest
+	createdBy  string
+	result     *dto.PromotionResponse
+	err        error
+	listPage   int
+	listPer    int
+	deletedID  uint
+	updatedID  uint
+	listResult *dto.PromotionListResponse
+}
+
+func (m *mockPromotionService) Evaluate(code string, customer promotionservice.Customer, lines []promotionservice.Line, shipping money.Amount) (*promotionservice.Discount, error) {
+	return nil, nil
+}
+
+func (m *mockPromotionService) CreatePromotion(req *dto.PromotionRequest, adminPublicID string) (*dto.PromotionResponse, error) {
+	m.created = req
+	m.createdBy = adminPublicID
+	return m.result, m.err
+}
+
+func (m *mockPromotionService) UpdatePromotion(id uint, req *dto.PromotionRequest, adminPublicID string) (*dto.PromotionResponse, error) {
+	m.updatedID = id
+	return m.result, m.err
+}
+
+func (m *mockPromotionService) DeletePromotion(id uint, adminPublicID string) error {
+	m.deletedID = id
+	return m.err
+}
+
+func (m *mockPromotionService) GetPromotion(id uint) (*dto.PromotionResponse, error) {
+	return m.result, m.err
+}
+
+func (m *mockPromotionService) ListPromotions(page int, perPage int) (*dto.PromotionListResponse, error) {
+	m.listPage, m.listPer = page, perPage
+	return m.listResult, m.err
+}
+
+func setupAdminPromotionRouter(svc promotionservice.PromotionService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	// Add middleware to simulate authentication
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", "admin-123")
+		c.Next()
+	})
+
+	handler := admin.NewAdminPromotionHandler(svc)
+	r.GET("/admin/promotions", handler.ListPromotions)
+	r.POST("/admin/promotions", handler.CreatePromotion)
+	r.GET("/admin/promotions/:id", handler.GetPromotion)
+	r.PUT("/admin/promotions/:id", handler.UpdatePromotion)
+	r.DELETE("/admin/promotions/:id", handler.DeletePromotion)
+	return r
+}
+
+func TestAdminPromotionHandler_CreatePromotion(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc := &mockPromotionService{result: &dto.PromotionResponse{ID: 1, Code: "WELCOME10", Type: "percentage", PercentOff: 10, Active: true}}
+		r := setupAdminPromotionRouter(svc)
+
+		body := `{"code":"welcome10","name":"Welcome","type":"percentage","percent_off":10,"min_subtotal":150,"max_uses_per_user":1}`
+		req, _ := http.NewRequest("POST", "/admin/promotions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "admin-123", svc.createdBy)
+		assert.Equal(t, money.FromCents(15000), svc.created.MinSubtotal)
+		assert.Equal(t, 1, *svc.created.MaxUsesPerUser)
+
+		var resp dto.PromotionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "WELCOME10", resp.Code)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		r := setupAdminPromotionRouter(&mockPromotionService{})
+
+		req, _ := http.NewRequest("POST", "/admin/promotions", strings.NewReader(`{"code":"X10","name":"x","type":"bogus"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("code taken", func(t *testing.T) {
+		r := setupAdminPromotionRouter(&mockPromotionService{err: apperror.NewCodeMessage("promotion_code_taken", "promotion code already exists")})
+
+		req, _ := http.NewRequest("POST", "/admin/promotions", strings.NewReader(`{"code":"X10","name":"x","type":"free_shipping"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "promotion_code_taken")
+	})
+}
+
+func TestAdminPromotionHandler_ListPromotions(t *testing.T) {
+	svc := &mockPromotionService{listResult: &dto.PromotionListResponse{Promotions: []dto.PromotionResponse{}}}
+	r := setupAdminPromotionRouter(svc)
+
+	req, _ := http.NewRequest("GET", "/admin/promotions?page=2&per_page=500", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2, svc.listPage)
+	assert.Equal(t, 100, svc.listPer)
+}
+
+func TestAdminPromotionHandler_UpdatePromotion(t *testing.T) {
+	svc := &mockPromotionService{result: &dto.PromotionResponse{ID: 4}}
+	r := setupAdminPromotionRouter(svc)
+
+	req, _ := http.NewRequest("PUT", "/admin/promotions/4", strings.NewReader(`{"code":"X10","name":"x","type":"free_shipping","active":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uint(4), svc.updatedID)
+}
+
+func TestAdminPromotionHandler_DeletePromotion(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc := &mockPromotionService{}
+		r := setupAdminPromotionRouter(svc)
+
+		req, _ := http.NewRequest("DELETE", "/admin/promotions/5", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, uint(5), svc.deletedID)
+	})
+
+	t.Run("redeemed promotion", func(t *testing.T) {
+		r := setupAdminPromotionRouter(&mockPromotionService{err: apperror.NewCodeMessage("promotion_in_use", "promotion has been redeemed")})
+
+		req, _ := http.NewRequest("DELETE", "/admin/promotions/5", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		r := setupAdminPromotionRouter(&mockPromotionService{})
+
+		req, _ := http.NewRequest("DELETE", "/admin/promotions/abc", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
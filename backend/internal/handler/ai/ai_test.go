@@ -13,6 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/handler/ai"
+	"github.com/leoferamos/aroma-sense/internal/money"
 	"github.com/leoferamos/aroma-sense/internal/rate"
 	"github.com/stretchr/testify/assert"
 )
@@ -54,7 +55,7 @@ func TestAIHandler_Recommend(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		svc := &mockAIService{
 			recommendResult: []dto.RecommendSuggestion{
-				{ID: 1, Name: "Perfume 1", Slug: "perfume-1", Price: 29.99, Reason: "floral scent"},
+				{ID: 1, Name: "Perfume 1", Slug: "perfume-1", Price: money.FromCents(2999), Reason: "floral scent"},
 			},
 			recommendReason: "based on preferences",
 		}
@@ -17,10 +17,38 @@ func NewCartHandler(cartService cartservice.CartService) *CartHandler {
 	return &CartHandler{cartService: cartService}
 }
 
+// shopperID returns the owner of the cart: the signed-in user or, for guest checkout, the guest
+// session. It writes the error response when there is neither.
+func (h *CartHandler) shopperID(c *gin.Context) (string, bool) {
+	if userID := c.GetString("userID"); userID != "" {
+		return userID, true
+	}
+	guestID := c.GetString("guestID")
+	if guestID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return "", false
+	}
+	return guestID, true
+}
+
+// cartOwnerID is shopperID for requests that change the cart. A guest's cart is created on the
+// first change, so anonymous visitors that only look at the cart do not leave one behind.
+func (h *CartHandler) cartOwnerID(c *gin.Context) (string, bool) {
+	ownerID, ok := h.shopperID(c)
+	if !ok || c.GetString("userID") != "" {
+		return ownerID, ok
+	}
+	if err := h.cartService.CreateCartForGuest(ownerID); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "cart_create_failed"})
+		return "", false
+	}
+	return ownerID, true
+}
+
 // GetCart retrieves the current user's cart
 //
 // @Summary      Get current user's cart
-// @Description  Retrieves the shopping cart for the authenticated user with items, quantities and totals
+// @Description  Retrieves the shopping cart for the authenticated user, or for the guest session cookie when not signed in, with items, quantities and totals. Each line is checked against the current product and carries warnings (price_changed, insufficient_stock, out_of_stock, product_removed) when it differs.
 // @Tags         cart
 // @Accept       json
 // @Produce      json
@@ -32,14 +60,19 @@ func NewCartHandler(cartService cartservice.CartService) *CartHandler {
 // @Security     BearerAuth
 func (h *CartHandler) GetCart(c *gin.Context) {
 
-	userIDStr := c.GetString("userID")
-	if userIDStr == "" {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+	userIDStr, ok := h.shopperID(c)
+	if !ok {
 		return
 	}
 
-	// Get user's cart
-	cartResponse, err := h.cartService.GetCartResponse(userIDStr)
+	// Get user's cart; a guest that has not added anything yet sees an empty one
+	var cartResponse *dto.CartResponse
+	var err error
+	if c.GetString("userID") != "" {
+		cartResponse, err = h.cartService.GetCartResponse(userIDStr)
+	} else {
+		cartResponse, err = h.cartService.GetGuestCartResponse(userIDStr)
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "cart not found"})
 		return
@@ -51,24 +84,23 @@ func (h *CartHandler) GetCart(c *gin.Context) {
 // AddItem adds an item to the user's cart
 //
 // @Summary      Add item to cart
-// @Description  Adds a product to the user's shopping cart. If item already exists, increases quantity.
+// @Description  Adds a product variant to the user's shopping cart, the default variant unless variant_sku is given. If the variant is already in the cart, increases quantity.
 // @Tags         cart
 // @Accept       json
 // @Produce      json
-// @Param        request        body    dto.AddToCartRequest  true   "Product slug and quantity to add"
+// @Param        request        body    dto.AddToCartRequest  true   "Product slug, optional variant SKU and quantity to add"
 // @Success      200  {object}  dto.CartResponse    "Updated cart with new item"
 // @Failure      400  {object}  dto.ErrorResponse   "Error code: invalid_request"
 // @Failure      401  {object}  dto.ErrorResponse   "Error code: unauthenticated"
-// @Failure      404  {object}  dto.ErrorResponse   "Error code: product_not_found"
+// @Failure      404  {object}  dto.ErrorResponse   "Error code: product_not_found, variant_not_found"
 // @Failure      409  {object}  dto.ErrorResponse   "Error code: insufficient_stock"
 // @Failure      500  {object}  dto.ErrorResponse   "Error code: internal_error"
 // @Router       /cart [post]
 // @Security     BearerAuth
 func (h *CartHandler) AddItem(c *gin.Context) {
 
-	userIDStr := c.GetString("userID")
-	if userIDStr == "" {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+	userIDStr, ok := h.cartOwnerID(c)
+	if !ok {
 		return
 	}
 
@@ -79,7 +111,7 @@ func (h *CartHandler) AddItem(c *gin.Context) {
 	}
 
 	// Add item to cart
-	cartResponse, err := h.cartService.AddItemToCart(userIDStr, req.ProductSlug, req.Quantity)
+	cartResponse, err := h.cartService.AddItemToCart(userIDStr, req.ProductSlug, req.VariantSKU, req.Quantity)
 	if err != nil {
 		if status, code, ok := handlererrors.MapServiceError(err); ok {
 			c.JSON(status, dto.ErrorResponse{Error: code})
@@ -100,6 +132,7 @@ func (h *CartHandler) AddItem(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        productSlug    path    string                       true   "Product slug"
+// @Param        variant        query   string                       false  "Variant SKU, required when the product is in the cart in several variants"
 // @Param        request        body    dto.UpdateCartItemRequest    true   "New quantity (0 to remove item)"
 // @Success      200  {object}  dto.CartResponse    "Updated cart"
 // @Failure      400  {object}  dto.ErrorResponse   "Error code: invalid_request"
@@ -111,9 +144,8 @@ func (h *CartHandler) AddItem(c *gin.Context) {
 // @Security     BearerAuth
 func (h *CartHandler) UpdateItemQuantity(c *gin.Context) {
 
-	userIDStr := c.GetString("userID")
-	if userIDStr == "" {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+	userIDStr, ok := h.cartOwnerID(c)
+	if !ok {
 		return
 	}
 
@@ -131,7 +163,7 @@ func (h *CartHandler) UpdateItemQuantity(c *gin.Context) {
 	}
 
 	// Update item quantity
-	cartResponse, err := h.cartService.UpdateItemQuantityBySlug(userIDStr, productSlug, req.Quantity)
+	cartResponse, err := h.cartService.UpdateItemQuantityBySlug(userIDStr, productSlug, c.Query("variant"), req.Quantity)
 	if err != nil {
 		if status, code, ok := handlererrors.MapServiceError(err); ok {
 			c.JSON(status, dto.ErrorResponse{Error: code})
@@ -152,6 +184,7 @@ func (h *CartHandler) UpdateItemQuantity(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        productSlug    path    string true   "Product slug"
+// @Param        variant        query   string false  "Variant SKU, required when the product is in the cart in several variants"
 // @Success      200  {object}  dto.CartResponse    "Updated cart after item removal"
 // @Failure      400  {object}  dto.ErrorResponse   "Error code: invalid_request"
 // @Failure      401  {object}  dto.ErrorResponse   "Error code: unauthenticated"
@@ -161,9 +194,8 @@ func (h *CartHandler) UpdateItemQuantity(c *gin.Context) {
 // @Security     BearerAuth
 func (h *CartHandler) RemoveItem(c *gin.Context) {
 
-	userIDStr := c.GetString("userID")
-	if userIDStr == "" {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+	userIDStr, ok := h.cartOwnerID(c)
+	if !ok {
 		return
 	}
 
@@ -175,7 +207,7 @@ func (h *CartHandler) RemoveItem(c *gin.Context) {
 	}
 
 	// Remove item from cart
-	cartResponse, err := h.cartService.RemoveItemBySlug(userIDStr, productSlug)
+	cartResponse, err := h.cartService.RemoveItemBySlug(userIDStr, productSlug, c.Query("variant"))
 	if err != nil {
 		if status, code, ok := handlererrors.MapServiceError(err); ok {
 			c.JSON(status, dto.ErrorResponse{Error: code})
@@ -203,9 +235,8 @@ func (h *CartHandler) RemoveItem(c *gin.Context) {
 // @Security     BearerAuth
 func (h *CartHandler) ClearCart(c *gin.Context) {
 
-	userIDStr := c.GetString("userID")
-	if userIDStr == "" {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+	userIDStr, ok := h.cartOwnerID(c)
+	if !ok {
 		return
 	}
 
@@ -222,3 +253,123 @@ func (h *CartHandler) ClearCart(c *gin.Context) {
 
 	c.JSON(http.StatusOK, cartResponse)
 }
+
+// ReconcileCart applies current prices and stock to the cart
+//
+// @Summary      Resolve cart warnings
+// @Description  Accepts the current price of every line and/or lowers quantities to the available stock, removing products that are sold out or no longer exist. An empty body does both.
+// @Tags         cart
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.ReconcileCartRequest  false  "What to resolve"
+// @Success      200  {object}  dto.CartResponse    "Updated cart"
+// @Failure      400  {object}  dto.ErrorResponse   "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse   "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse   "Error code: cart_not_found"
+// @Failure      500  {object}  dto.ErrorResponse   "Error code: internal_error"
+// @Router       /cart/reconcile [post]
+// @Security     BearerAuth
+func (h *CartHandler) ReconcileCart(c *gin.Context) {
+	var req dto.ReconcileCartRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+			return
+		}
+	}
+	acceptPrices := req.AcceptPrices == nil || *req.AcceptPrices
+	adjustQuantities := req.AdjustQuantities == nil || *req.AdjustQuantities
+	if !acceptPrices && !adjustQuantities {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	userIDStr, ok := h.cartOwnerID(c)
+	if !ok {
+		return
+	}
+
+	cartResponse, err := h.cartService.ReconcileCart(userIDStr, acceptPrices, adjustQuantities)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, cartResponse)
+}
+
+// ApplyPromotion applies a coupon code to the cart
+//
+// @Summary      Apply coupon
+// @Description  Validates a coupon code against the cart and keeps it on the cart. The discount is shown in the cart and applied at checkout and payment.
+// @Tags         cart
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.ApplyPromotionRequest  true  "Coupon code"
+// @Success      200  {object}  dto.CartResponse    "Updated cart"
+// @Failure      400  {object}  dto.ErrorResponse   "Error code: invalid_request, cart_empty, promotion_not_active, promotion_min_subtotal_not_met or promotion_not_applicable"
+// @Failure      401  {object}  dto.ErrorResponse   "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse   "Error code: promotion_not_found"
+// @Failure      409  {object}  dto.ErrorResponse   "Error code: promotion_usage_limit_reached"
+// @Failure      500  {object}  dto.ErrorResponse   "Error code: internal_error"
+// @Router       /cart/promotion [put]
+// @Security     BearerAuth
+func (h *CartHandler) ApplyPromotion(c *gin.Context) {
+	var req dto.ApplyPromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	userIDStr, ok := h.cartOwnerID(c)
+	if !ok {
+		return
+	}
+
+	cartResponse, err := h.cartService.ApplyPromotion(userIDStr, req.Code)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, cartResponse)
+}
+
+// RemovePromotion removes the coupon from the cart
+//
+// @Summary      Remove coupon
+// @Description  Takes the coupon code off the cart
+// @Tags         cart
+// @Produce      json
+// @Success      200  {object}  dto.CartResponse    "Updated cart"
+// @Failure      401  {object}  dto.ErrorResponse   "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse   "Error code: cart_not_found"
+// @Failure      500  {object}  dto.ErrorResponse   "Error code: internal_error"
+// @Router       /cart/promotion [delete]
+// @Security     BearerAuth
+func (h *CartHandler) RemovePromotion(c *gin.Context) {
+	userIDStr, ok := h.cartOwnerID(c)
+	if !ok {
+		return
+	}
+
+	cartResponse, err := h.cartService.RemovePromotion(userIDStr)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, cartResponse)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/handler/cart"
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
 	cartservice "github.com/leoferamos/aroma-sense/internal/service/cart"
 	"github.com/stretchr/testify/assert"
 )
@@ -33,14 +34,48 @@ type mockCartService struct {
 	removeItemErr                  error
 	removeItemBySlugResult         *dto.CartResponse
 	removeItemBySlugErr            error
+	variantSKU                     string
 	clearCartResult                *dto.CartResponse
 	clearCartErr                   error
+	createdGuestCart               string
+	guestCartRead                  string
+	reconcileResult                *dto.CartResponse
+	reconcileErr                   error
+	acceptedPrices                 bool
+	adjustedQuantities             bool
+	promotionResult                *dto.CartResponse
+	promotionErr                   error
+	appliedCode                    string
 }
 
 func (m *mockCartService) CreateCartForUser(userID string) error {
 	return m.createCartForUserResult
 }
 
+func (m *mockCartService) CreateCartForGuest(guestID string) error {
+	m.createdGuestCart = guestID
+	return m.createCartForUserResult
+}
+
+func (m *mockCartService) MergeGuestCart(guestID string, userID string) error {
+	return nil
+}
+
+func (m *mockCartService) ReconcileCart(userID string, acceptPrices bool, adjustQuantities bool) (*dto.CartResponse, error) {
+	m.acceptedPrices = acceptPrices
+	m.adjustedQuantities = adjustQuantities
+	return m.reconcileResult, m.reconcileErr
+}
+
+func (m *mockCartService) ApplyPromotion(userID string, code string) (*dto.CartResponse, error) {
+	m.appliedCode = code
+	return m.promotionResult, m.promotionErr
+}
+
+func (m *mockCartService) RemovePromotion(userID string) (*dto.CartResponse, error) {
+	return m.promotionResult, m.promotionErr
+}
+
 func (m *mockCartService) GetCartByUserID(userID string) (*model.Cart, error) {
 	return m.getCartByUserIDResult, m.getCartByUserIDErr
 }
@@ -49,7 +84,13 @@ func (m *mockCartService) GetCartResponse(userID string) (*dto.CartResponse, err
 	return m.getCartResponseResult, m.getCartResponseErr
 }
 
-func (m *mockCartService) AddItemToCart(userID string, productSlug string, quantity int) (*dto.CartResponse, error) {
+func (m *mockCartService) GetGuestCartResponse(guestID string) (*dto.CartResponse, error) {
+	m.guestCartRead = guestID
+	return m.getCartResponseResult, m.getCartResponseErr
+}
+
+func (m *mockCartService) AddItemToCart(userID string, productSlug string, variantSKU string, quantity int) (*dto.CartResponse, error) {
+	m.variantSKU = variantSKU
 	return m.addItemToCartResult, m.addItemToCartErr
 }
 
@@ -57,7 +98,8 @@ func (m *mockCartService) UpdateItemQuantity(userID string, itemID uint, quantit
 	return m.updateItemQuantityResult, m.updateItemQuantityErr
 }
 
-func (m *mockCartService) UpdateItemQuantityBySlug(userID string, productSlug string, quantity int) (*dto.CartResponse, error) {
+func (m *mockCartService) UpdateItemQuantityBySlug(userID string, productSlug string, variantSKU string, quantity int) (*dto.CartResponse, error) {
+	m.variantSKU = variantSKU
 	return m.updateItemQuantityBySlugResult, m.updateItemQuantityBySlugErr
 }
 
@@ -65,7 +107,8 @@ func (m *mockCartService) RemoveItem(userID string, itemID uint) (*dto.CartRespo
 	return m.removeItemResult, m.removeItemErr
 }
 
-func (m *mockCartService) RemoveItemBySlug(userID string, productSlug string) (*dto.CartResponse, error) {
+func (m *mockCartService) RemoveItemBySlug(userID string, productSlug string, variantSKU string) (*dto.CartResponse, error) {
+	m.variantSKU = variantSKU
 	return m.removeItemBySlugResult, m.removeItemBySlugErr
 }
 
@@ -89,6 +132,9 @@ func setupCartRouter(svc cartservice.CartService) *gin.Engine {
 	r.PATCH("/cart/items/:productSlug", handler.UpdateItemQuantity)
 	r.DELETE("/cart/items/:productSlug", handler.RemoveItem)
 	r.DELETE("/cart", handler.ClearCart)
+	r.POST("/cart/reconcile", handler.ReconcileCart)
+	r.PUT("/cart/promotion", handler.ApplyPromotion)
+	r.DELETE("/cart/promotion", handler.RemovePromotion)
 	return r
 }
 
@@ -99,14 +145,14 @@ func createTestCartResponse() *dto.CartResponse {
 				Product: &dto.ProductResponse{
 					Slug:  "test-product",
 					Name:  "Test Product",
-					Price: 29.99,
+					Price: money.FromCents(2999),
 				},
 				Quantity: 2,
-				Price:    29.99,
-				Total:    59.98,
+				Price:    money.FromCents(2999),
+				Total:    money.FromCents(5998),
 			},
 		},
-		Total:     59.98,
+		Total:     money.FromCents(5998),
 		ItemCount: 1,
 	}
 }
@@ -130,7 +176,7 @@ func TestCartHandler_GetCart(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.Len(t, response.Items, 1)
-		assert.Equal(t, 59.98, response.Total)
+		assert.Equal(t, money.FromCents(5998), response.Total)
 		assert.Equal(t, 1, response.ItemCount)
 	})
 
@@ -170,6 +216,45 @@ func TestCartHandler_GetCart(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "unauthenticated", response.Error)
 	})
+
+	t.Run("guest session reads without creating a cart", func(t *testing.T) {
+		svc := &mockCartService{getCartResponseResult: &dto.CartResponse{Items: []dto.CartItemResponse{}}}
+		r := setupGuestCartRouter(svc)
+
+		req, _ := http.NewRequest("GET", "/cart", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "guest-123", svc.guestCartRead)
+		assert.Empty(t, svc.createdGuestCart)
+	})
+
+	t.Run("guest cart is created on the first write", func(t *testing.T) {
+		svc := &mockCartService{addItemToCartResult: createTestCartResponse()}
+		r := setupGuestCartRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/cart", strings.NewReader(`{"product_slug": "test-product", "quantity": 1}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "guest-123", svc.createdGuestCart)
+	})
+}
+
+func setupGuestCartRouter(svc *mockCartService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("guestID", "guest-123")
+		c.Next()
+	})
+	handler := cart.NewCartHandler(svc)
+	r.GET("/cart", handler.GetCart)
+	r.POST("/cart", handler.AddItem)
+	return r
 }
 
 func TestCartHandler_AddItem(t *testing.T) {
@@ -314,6 +399,34 @@ func TestCartHandler_UpdateItemQuantity(t *testing.T) {
 		assert.Equal(t, 5, response.Items[0].Quantity)
 	})
 
+	t.Run("passes the variant SKU", func(t *testing.T) {
+		svc := &mockCartService{updateItemQuantityBySlugResult: createTestCartResponse()}
+		r := setupCartRouter(svc)
+
+		req, _ := http.NewRequest("PATCH", "/cart/items/test-product?variant=TEST-EDP-100", strings.NewReader(`{"quantity": 1}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "TEST-EDP-100", svc.variantSKU)
+	})
+
+	t.Run("product in the cart in several variants", func(t *testing.T) {
+		svc := &mockCartService{updateItemQuantityBySlugErr: apperror.NewCodeMessage("variant_required", "variant required")}
+		r := setupCartRouter(svc)
+
+		req, _ := http.NewRequest("PATCH", "/cart/items/test-product", strings.NewReader(`{"quantity": 1}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "variant_required")
+	})
+
 	t.Run("invalid product slug", func(t *testing.T) {
 		svc := &mockCartService{}
 		r := setupCartRouter(svc)
@@ -505,7 +618,7 @@ func TestCartHandler_ClearCart(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.Len(t, response.Items, 0)
-		assert.Equal(t, float64(0), response.Total)
+		assert.Equal(t, money.Amount(0), response.Total)
 		assert.Equal(t, 0, response.ItemCount)
 	})
 
@@ -545,3 +658,92 @@ func TestCartHandler_ClearCart(t *testing.T) {
 		assert.Equal(t, "internal_error", response.Error)
 	})
 }
+
+func TestCartHandler_ReconcileCart(t *testing.T) {
+	t.Run("empty body resolves everything", func(t *testing.T) {
+		svc := &mockCartService{reconcileResult: createTestCartResponse()}
+		r := setupCartRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/cart/reconcile", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, svc.acceptedPrices)
+		assert.True(t, svc.adjustedQuantities)
+	})
+
+	t.Run("accept prices only", func(t *testing.T) {
+		svc := &mockCartService{reconcileResult: createTestCartResponse()}
+		r := setupCartRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/cart/reconcile", strings.NewReader(`{"adjust_quantities": false}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, svc.acceptedPrices)
+		assert.False(t, svc.adjustedQuantities)
+	})
+
+	t.Run("nothing to resolve", func(t *testing.T) {
+		r := setupCartRouter(&mockCartService{})
+
+		req, _ := http.NewRequest("POST", "/cart/reconcile", strings.NewReader(`{"accept_prices": false, "adjust_quantities": false}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCartHandler_ApplyPromotion(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc := &mockCartService{promotionResult: createTestCartResponse()}
+		r := setupCartRouter(svc)
+
+		req, _ := http.NewRequest("PUT", "/cart/promotion", strings.NewReader(`{"code": "welcome10"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "welcome10", svc.appliedCode)
+	})
+
+	t.Run("missing code", func(t *testing.T) {
+		r := setupCartRouter(&mockCartService{})
+
+		req, _ := http.NewRequest("PUT", "/cart/promotion", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("coupon used up", func(t *testing.T) {
+		svc := &mockCartService{promotionErr: apperror.NewCodeMessage("promotion_usage_limit_reached", "promotion usage limit reached")}
+		r := setupCartRouter(svc)
+
+		req, _ := http.NewRequest("PUT", "/cart/promotion", strings.NewReader(`{"code": "GONE"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "promotion_usage_limit_reached")
+	})
+}
+
+func TestCartHandler_RemovePromotion(t *testing.T) {
+	r := setupCartRouter(&mockCartService{promotionResult: createTestCartResponse()})
+
+	req, _ := http.NewRequest("DELETE", "/cart/promotion", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
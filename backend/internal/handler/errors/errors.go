@@ -76,6 +76,50 @@ var domainCodeStatus = map[string]int{
 	"suspension_until_past":          http.StatusBadRequest,
 	"user_not_deactivated":           http.StatusBadRequest,
 	"invalid_webhook":                http.StatusBadRequest,
+	"order_not_found":                http.StatusNotFound,
+	"invalid_order_status":           http.StatusBadRequest,
+	"invalid_status_transition":      http.StatusConflict,
+	"order_status_conflict":          http.StatusConflict,
+	"tracking_number_required":       http.StatusBadRequest,
+	"reservation_active":             http.StatusConflict,
+	"order_not_cancellable":          http.StatusConflict,
+	"cancellation_window_expired":    http.StatusConflict,
+	"payment_in_progress":            http.StatusConflict,
+	"order_not_payable":              http.StatusConflict,
+	"invalid_amount":                 http.StatusBadRequest,
+	"refund_exceeds_captured":        http.StatusBadRequest,
+	"nothing_to_refund":              http.StatusConflict,
+	"refund_failed":                  http.StatusBadGateway,
+	"payment_method_unavailable":     http.StatusUnprocessableEntity,
+	"payment_not_found":              http.StatusNotFound,
+	"boleto_not_found":               http.StatusNotFound,
+	"webhook_event_not_found":        http.StatusNotFound,
+	"webhook_event_not_replayable":   http.StatusConflict,
+	"simulation_unsupported":         http.StatusUnprocessableEntity,
+	"simulation_failed":              http.StatusUnprocessableEntity,
+	"address_not_found":              http.StatusNotFound,
+	"address_limit_reached":          http.StatusConflict,
+	"invalid_address":                http.StatusBadRequest,
+	"invalid_uf":                     http.StatusBadRequest,
+	"promotion_not_found":            http.StatusNotFound,
+	"promotion_not_active":           http.StatusBadRequest,
+	"promotion_usage_limit_reached":  http.StatusConflict,
+	"promotion_min_subtotal_not_met": http.StatusBadRequest,
+	"promotion_not_applicable":       http.StatusBadRequest,
+	"invalid_promotion":              http.StatusBadRequest,
+	"promotion_code_taken":           http.StatusConflict,
+	"promotion_in_use":               http.StatusConflict,
+	"wishlist_item_not_found":        http.StatusNotFound,
+	"wishlist_limit_reached":         http.StatusConflict,
+	"variant_not_found":              http.StatusNotFound,
+	"variant_required":               http.StatusBadRequest,
+	"variant_sku_taken":              http.StatusConflict,
+	"last_variant":                   http.StatusConflict,
+	"invalid_image":                  http.StatusBadRequest,
+	"image_not_found":                http.StatusNotFound,
+	"invalid_image_order":            http.StatusBadRequest,
+	"primary_image_required":         http.StatusConflict,
+	"last_image":                     http.StatusConflict,
 	"internal_error":                 http.StatusInternalServerError,
 }
 
@@ -50,6 +50,14 @@ func (m *mockAuditLogService) LogSystemAction(action model.AuditAction, resource
 	return nil
 }
 
+func (m *mockAuditLogService) LogSystemAlert(action model.AuditAction, resource, resourceID, severity string, details map[string]interface{}) error {
+	return nil
+}
+
+func (m *mockAuditLogService) LogOrderAction(actorID *uint, orderPublicID string, action model.AuditAction, details map[string]interface{}, oldValues, newValues map[string]interface{}) error {
+	return nil
+}
+
 func (m *mockAuditLogService) LogDataAccess(userID uint, resource string, resourceID string) error {
 	return nil
 }
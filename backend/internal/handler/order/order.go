@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/auth"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	handlererrors "github.com/leoferamos/aroma-sense/internal/handler/errors"
 	"github.com/leoferamos/aroma-sense/internal/model"
@@ -62,6 +63,118 @@ func (h *OrderHandler) CreateOrderFromCart(c *gin.Context) {
 	c.JSON(http.StatusCreated, orderResp)
 }
 
+// CreateGuestOrder handles checkout of the guest session's cart without an account
+//
+// @Summary      Create a guest order
+// @Description  Creates an order from the guest session's cart. The response carries a lookup token that, together with the email, is needed to track the order.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        order  body  dto.CreateGuestOrderRequest  true  "Order data (email, shipping address, payment method)"
+// @Success      201  {object}  dto.GuestOrderResponse  "Order created successfully"
+// @Failure      400  {object}  dto.ErrorResponse      "Error code: invalid_request or cart_empty or insufficient_stock or invalid_postal_code"
+// @Failure      401  {object}  dto.ErrorResponse      "Error code: unauthenticated"
+// @Failure      500  {object}  dto.ErrorResponse      "Error code: internal_error"
+// @Router       /orders/guest [post]
+func (h *OrderHandler) CreateGuestOrder(c *gin.Context) {
+	var req dto.CreateGuestOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	guestID := c.GetString("guestID")
+	if guestID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	orderResp, err := h.orderService.CreateGuestOrderFromCart(guestID, &req)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, orderResp)
+}
+
+// TrackOrder returns a guest order with its timeline given the order email and lookup token
+// @Summary      Track guest order
+// @Description  Looks up an order placed through guest checkout by the email used at checkout and the lookup token returned with the order
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.TrackOrderRequest  true  "Email and lookup token"
+// @Success      200  {object}  dto.OrderDetailResponse
+// @Failure      400  {object}  dto.ErrorResponse "Error code: invalid_request"
+// @Failure      404  {object}  dto.ErrorResponse "Error code: order_not_found"
+// @Failure      500  {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /orders/track [post]
+func (h *OrderHandler) TrackOrder(c *gin.Context) {
+	var req dto.TrackOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	resp, err := h.orderService.TrackGuestOrder(&req)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ClaimGuestOrders moves guest orders into the authenticated user's account
+// @Summary      Claim guest orders
+// @Description  Attaches guest orders placed with the account's email to the account. Orders from the current guest session are claimed automatically; others need their lookup token.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dto.ClaimGuestOrdersRequest  false  "Lookup tokens of orders placed from other devices"
+// @Success      200  {object}  dto.ClaimGuestOrdersResponse
+// @Failure      400  {object}  dto.ErrorResponse "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      500  {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /orders/guest/claim [post]
+// @Security     BearerAuth
+func (h *OrderHandler) ClaimGuestOrders(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	var req dto.ClaimGuestOrdersRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+			return
+		}
+	}
+
+	resp, err := h.orderService.ClaimGuestOrders(userID, auth.GuestIDFromCookie(c), &req)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
 // ListOrders allows admin to list orders with filters, pagination and stats
 // @Summary      List all orders
 // @Description  Returns paginated list of orders with optional filters (status, date range)
@@ -184,3 +297,199 @@ func (h *OrderHandler) ListUserOrders(c *gin.Context) {
 
 	c.JSON(http.StatusOK, orders)
 }
+
+// UpdateOrderStatus allows admin to move an order through its fulfillment lifecycle
+// @Summary      Update order status
+// @Description  Moves an order to a new status following the allowed lifecycle (pending → processing → shipped → delivered, or cancelled). A tracking number is required when shipping.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        publicID  path      string                        true  "Order public ID"
+// @Param        body      body      dto.UpdateOrderStatusRequest  true  "New status"
+// @Success      200  {object}  dto.OrderResponse
+// @Failure      400  {object}  dto.ErrorResponse "Error code: invalid_request or invalid_order_status or tracking_number_required"
+// @Failure      401  {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse "Error code: order_not_found"
+// @Failure      409  {object}  dto.ErrorResponse "Error code: invalid_status_transition or payment_in_progress or order_status_conflict"
+// @Failure      500  {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /admin/orders/{publicID}/status [patch]
+// @Security     BearerAuth
+func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
+	adminID := c.GetString("userID")
+	if adminID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	publicID := c.Param("publicID")
+	if publicID == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	var req dto.UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	resp, err := h.orderService.UpdateOrderStatus(c.Request.Context(), publicID, &req, adminID)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AdminRefundOrder allows admin to refund all or part of an order's captured payments
+// @Summary      Refund order
+// @Description  Refunds the given amount, or everything still captured when amount is omitted, through the payment provider. The reason and acting admin are recorded.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        publicID  path      string                  true  "Order public ID"
+// @Param        body      body      dto.AdminRefundRequest  true  "Refund amount and reason"
+// @Success      201  {object}  dto.RefundResponse
+// @Failure      400  {object}  dto.ErrorResponse "Error code: invalid_request or invalid_amount or refund_exceeds_captured"
+// @Failure      401  {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse "Error code: order_not_found"
+// @Failure      409  {object}  dto.ErrorResponse "Error code: nothing_to_refund"
+// @Failure      502  {object}  dto.ErrorResponse "Error code: refund_failed"
+// @Failure      503  {object}  dto.ErrorResponse "Error code: provider_unavailable"
+// @Failure      500  {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /admin/orders/{publicID}/refunds [post]
+// @Security     BearerAuth
+func (h *OrderHandler) AdminRefundOrder(c *gin.Context) {
+	adminID := c.GetString("userID")
+	if adminID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	var req dto.AdminRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	resp, err := h.orderService.AdminRefundOrder(c.Request.Context(), c.Param("publicID"), &req, adminID)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetUserOrder returns a single order of the authenticated user with its timeline
+// @Summary      Get user's order
+// @Description  Returns an order owned by the authenticated user, including the history of status, payment and shipping changes
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        publicID  path      string  true  "Order public ID"
+// @Success      200  {object}  dto.OrderDetailResponse
+// @Failure      401  {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse "Error code: order_not_found"
+// @Failure      500  {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /orders/{publicID} [get]
+// @Security     BearerAuth
+func (h *OrderHandler) GetUserOrder(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	resp, err := h.orderService.GetOrderByPublicID(userID, c.Param("publicID"))
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CancelOrder lets the authenticated user cancel one of their orders
+// @Summary      Cancel order
+// @Description  Cancels an order while its status and age allow it, restocks its items and refunds any captured payment
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        publicID  path      string                  true   "Order public ID"
+// @Param        request   body      dto.CancelOrderRequest  false  "Optional cancellation reason"
+// @Success      200  {object}  dto.OrderCancellationResponse
+// @Failure      400  {object}  dto.ErrorResponse "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse "Error code: order_not_found"
+// @Failure      409  {object}  dto.ErrorResponse "Error code: order_not_cancellable or cancellation_window_expired or payment_in_progress or order_status_conflict"
+// @Failure      500  {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /orders/{publicID}/cancel [post]
+// @Security     BearerAuth
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	var req dto.CancelOrderRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+			return
+		}
+	}
+
+	resp, err := h.orderService.CancelOrder(c.Request.Context(), userID, c.Param("publicID"), req.Reason)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AdminGetOrder returns any order with its full timeline
+// @Summary      Get order details
+// @Description  Returns an order with its items and the full history of status, payment and shipping changes
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        publicID  path      string  true  "Order public ID"
+// @Success      200  {object}  dto.AdminOrderDetailResponse
+// @Failure      401  {object}  dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse "Error code: order_not_found"
+// @Failure      500  {object}  dto.ErrorResponse "Error code: internal_error"
+// @Router       /admin/orders/{publicID} [get]
+// @Security     BearerAuth
+func (h *OrderHandler) AdminGetOrder(c *gin.Context) {
+	resp, err := h.orderService.AdminGetOrder(c.Param("publicID"))
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
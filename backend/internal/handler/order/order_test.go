@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -10,7 +11,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/apperror"
 	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/money"
 	orderservice "github.com/leoferamos/aroma-sense/internal/service/order"
 	"github.com/stretchr/testify/assert"
 )
@@ -22,6 +25,26 @@ type mockOrderService struct {
 	adminListOrdersErr        error
 	getOrdersByUserResult     []dto.OrderResponse
 	getOrdersByUserErr        error
+	updateStatusResult        *dto.OrderResponse
+	updateStatusErr           error
+	getOrderResult            *dto.OrderDetailResponse
+	getOrderErr               error
+	adminGetOrderResult       *dto.AdminOrderDetailResponse
+	adminGetOrderErr          error
+	cancelResult              *dto.OrderCancellationResponse
+	cancelErr                 error
+	cancelReason              string
+	refundResult              *dto.RefundResponse
+	refundErr                 error
+	guestOrderResult          *dto.GuestOrderResponse
+	guestOrderErr             error
+	guestOrderGuestID         string
+	trackResult               *dto.OrderDetailResponse
+	trackErr                  error
+	claimResult               *dto.ClaimGuestOrdersResponse
+	claimErr                  error
+	claimGuestID              string
+	claimTokens               []string
 }
 
 func (m *mockOrderService) CreateOrderFromCart(userID string, req *dto.CreateOrderFromCartRequest) (*dto.OrderResponse, error) {
@@ -36,6 +59,50 @@ func (m *mockOrderService) GetOrdersByUser(userID string) ([]dto.OrderResponse,
 	return m.getOrdersByUserResult, m.getOrdersByUserErr
 }
 
+func (m *mockOrderService) UpdateOrderStatus(ctx context.Context, publicID string, req *dto.UpdateOrderStatusRequest, adminPublicID string) (*dto.OrderResponse, error) {
+	return m.updateStatusResult, m.updateStatusErr
+}
+
+func (m *mockOrderService) GetOrderByPublicID(userID string, publicID string) (*dto.OrderDetailResponse, error) {
+	return m.getOrderResult, m.getOrderErr
+}
+
+func (m *mockOrderService) AdminGetOrder(publicID string) (*dto.AdminOrderDetailResponse, error) {
+	return m.adminGetOrderResult, m.adminGetOrderErr
+}
+
+func (m *mockOrderService) ReleaseExpiredReservation(publicID string) error {
+	return nil
+}
+
+func (m *mockOrderService) CancelUnpaidOrder(publicID string, note string) error {
+	return nil
+}
+
+func (m *mockOrderService) CancelOrder(ctx context.Context, userID string, publicID string, reason string) (*dto.OrderCancellationResponse, error) {
+	m.cancelReason = reason
+	return m.cancelResult, m.cancelErr
+}
+
+func (m *mockOrderService) CreateGuestOrderFromCart(guestID string, req *dto.CreateGuestOrderRequest) (*dto.GuestOrderResponse, error) {
+	m.guestOrderGuestID = guestID
+	return m.guestOrderResult, m.guestOrderErr
+}
+
+func (m *mockOrderService) TrackGuestOrder(req *dto.TrackOrderRequest) (*dto.OrderDetailResponse, error) {
+	return m.trackResult, m.trackErr
+}
+
+func (m *mockOrderService) ClaimGuestOrders(userID string, guestID string, req *dto.ClaimGuestOrdersRequest) (*dto.ClaimGuestOrdersResponse, error) {
+	m.claimGuestID = guestID
+	m.claimTokens = req.Tokens
+	return m.claimResult, m.claimErr
+}
+
+func (m *mockOrderService) AdminRefundOrder(ctx context.Context, publicID string, req *dto.AdminRefundRequest, adminPublicID string) (*dto.RefundResponse, error) {
+	return m.refundResult, m.refundErr
+}
+
 func setupOrderRouter(svc orderservice.OrderService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -49,7 +116,13 @@ func setupOrderRouter(svc orderservice.OrderService) *gin.Engine {
 	handler := NewOrderHandler(svc)
 	r.POST("/orders", handler.CreateOrderFromCart)
 	r.GET("/orders", handler.ListUserOrders)
+	r.GET("/orders/:publicID", handler.GetUserOrder)
+	r.POST("/orders/:publicID/cancel", handler.CancelOrder)
+	r.POST("/orders/guest/claim", handler.ClaimGuestOrders)
 	r.GET("/admin/orders", handler.ListOrders)
+	r.GET("/admin/orders/:publicID", handler.AdminGetOrder)
+	r.PATCH("/admin/orders/:publicID/status", handler.UpdateOrderStatus)
+	r.POST("/admin/orders/:publicID/refunds", handler.AdminRefundOrder)
 	return r
 }
 
@@ -60,13 +133,31 @@ func setupOrderRouterUnauthenticated(svc orderservice.OrderService) *gin.Engine
 	handler := NewOrderHandler(svc)
 	r.POST("/orders", handler.CreateOrderFromCart)
 	r.GET("/orders", handler.ListUserOrders)
+	r.GET("/orders/:publicID", handler.GetUserOrder)
+	r.POST("/orders/:publicID/cancel", handler.CancelOrder)
+	r.POST("/orders/track", handler.TrackOrder)
 	r.GET("/admin/orders", handler.ListOrders)
 	return r
 }
 
+func setupOrderRouterGuest(svc orderservice.OrderService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	// Add middleware to simulate a guest session
+	r.Use(func(c *gin.Context) {
+		c.Set("guestID", "guest-123")
+		c.Next()
+	})
+
+	handler := NewOrderHandler(svc)
+	r.POST("/orders/guest", handler.CreateGuestOrder)
+	return r
+}
+
 func TestOrderHandler_CreateOrderFromCart(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		orderResp := &dto.OrderResponse{PublicID: "order-123", Status: "pending", TotalAmount: 100.0}
+		orderResp := &dto.OrderResponse{PublicID: "order-123", Status: "pending", TotalAmount: money.FromCents(10000)}
 		svc := &mockOrderService{
 			createOrderFromCartResult: orderResp,
 		}
@@ -163,8 +254,8 @@ func TestOrderHandler_CreateOrderFromCart(t *testing.T) {
 func TestOrderHandler_ListUserOrders(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		orders := []dto.OrderResponse{
-			{PublicID: "order-1", Status: "delivered", TotalAmount: 50.0},
-			{PublicID: "order-2", Status: "pending", TotalAmount: 75.0},
+			{PublicID: "order-1", Status: "delivered", TotalAmount: money.FromCents(5000)},
+			{PublicID: "order-2", Status: "pending", TotalAmount: money.FromCents(7500)},
 		}
 		svc := &mockOrderService{
 			getOrdersByUserResult: orders,
@@ -226,14 +317,14 @@ func TestOrderHandler_ListOrders(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		adminResp := &dto.AdminOrdersResponse{
 			Orders: []dto.AdminOrderItem{
-				{ID: 1, PublicID: "order-123", UserID: "user-456", TotalAmount: 100.0, Status: "pending", CreatedAt: time.Now()},
+				{ID: 1, PublicID: "order-123", UserID: "user-456", TotalAmount: money.FromCents(10000), Status: "pending", CreatedAt: time.Now()},
 			},
 			Meta: struct {
 				Pagination dto.PaginationMeta `json:"pagination"`
 				Stats      dto.StatsMeta      `json:"stats"`
 			}{
 				Pagination: dto.PaginationMeta{Page: 1, PerPage: 25, TotalPages: 1, TotalCount: 1},
-				Stats:      dto.StatsMeta{TotalRevenue: 100.0, AverageOrderValue: 100.0},
+				Stats:      dto.StatsMeta{TotalRevenue: money.FromCents(10000), AverageOrderValue: money.FromCents(10000)},
 			},
 		}
 		svc := &mockOrderService{
@@ -304,3 +395,359 @@ func TestOrderHandler_ListOrders(t *testing.T) {
 		assert.Equal(t, "internal_error", response.Error)
 	})
 }
+
+func TestOrderHandler_UpdateOrderStatus(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc := &mockOrderService{
+			updateStatusResult: &dto.OrderResponse{PublicID: "order-123", Status: "shipped", ShippingTracking: "BR123"},
+		}
+		r := setupOrderRouter(svc)
+
+		reqBody := `{"status": "shipped", "tracking_number": "BR123"}`
+		req, _ := http.NewRequest("PATCH", "/admin/orders/order-123/status", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.OrderResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "shipped", response.Status)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		svc := &mockOrderService{}
+		r := setupOrderRouter(svc)
+
+		reqBody := `{"status": "lost"}`
+		req, _ := http.NewRequest("PATCH", "/admin/orders/order-123/status", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("illegal transition", func(t *testing.T) {
+		svc := &mockOrderService{
+			updateStatusErr: apperror.NewCodeMessage("invalid_status_transition", "order status transition not allowed"),
+		}
+		r := setupOrderRouter(svc)
+
+		reqBody := `{"status": "delivered"}`
+		req, _ := http.NewRequest("PATCH", "/admin/orders/order-123/status", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var response dto.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "invalid_status_transition", response.Error)
+	})
+}
+
+func TestOrderHandler_GetUserOrder(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc := &mockOrderService{
+			getOrderResult: &dto.OrderDetailResponse{
+				OrderResponse: dto.OrderResponse{PublicID: "order-123", Status: "processing"},
+				Timeline:      []dto.OrderEventResponse{{Type: "created", ToStatus: "pending", Actor: "user"}},
+			},
+		}
+		r := setupOrderRouter(svc)
+
+		req, _ := http.NewRequest("GET", "/orders/order-123", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.OrderDetailResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "order-123", response.PublicID)
+		assert.Len(t, response.Timeline, 1)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := &mockOrderService{
+			getOrderErr: apperror.NewCodeMessage("order_not_found", "order not found"),
+		}
+		r := setupOrderRouter(svc)
+
+		req, _ := http.NewRequest("GET", "/orders/order-123", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		svc := &mockOrderService{}
+		r := setupOrderRouterUnauthenticated(svc)
+
+		req, _ := http.NewRequest("GET", "/orders/order-123", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestOrderHandler_CancelOrder(t *testing.T) {
+	t.Run("success with reason", func(t *testing.T) {
+		svc := &mockOrderService{
+			cancelResult: &dto.OrderCancellationResponse{
+				OrderResponse:  dto.OrderResponse{PublicID: "order-123", Status: "cancelled"},
+				RefundStatus:   "refunded",
+				RefundedAmount: money.FromCents(10000),
+			},
+		}
+		r := setupOrderRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/orders/order-123/cancel", strings.NewReader(`{"reason": "changed my mind"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "changed my mind", svc.cancelReason)
+
+		var response dto.OrderCancellationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "cancelled", response.Status)
+		assert.Equal(t, "refunded", response.RefundStatus)
+	})
+
+	t.Run("success without body", func(t *testing.T) {
+		svc := &mockOrderService{
+			cancelResult: &dto.OrderCancellationResponse{
+				OrderResponse: dto.OrderResponse{PublicID: "order-123", Status: "cancelled"},
+				RefundStatus:  "none",
+			},
+		}
+		r := setupOrderRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/orders/order-123/cancel", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("window expired", func(t *testing.T) {
+		svc := &mockOrderService{
+			cancelErr: apperror.NewCodeMessage("cancellation_window_expired", "cancellation window has expired"),
+		}
+		r := setupOrderRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/orders/order-123/cancel", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "cancellation_window_expired")
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		svc := &mockOrderService{}
+		r := setupOrderRouterUnauthenticated(svc)
+
+		req, _ := http.NewRequest("POST", "/orders/order-123/cancel", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestOrderHandler_AdminRefundOrder(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc := &mockOrderService{
+			refundResult: &dto.RefundResponse{OrderPublicID: "order-123", Status: "partially_refunded", RefundedAmount: money.FromCents(1000), RemainingAmount: money.FromCents(9000)},
+		}
+		r := setupOrderRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/admin/orders/order-123/refunds", strings.NewReader(`{"amount": 10, "reason": "damaged"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), "partially_refunded")
+	})
+
+	t.Run("reason is required", func(t *testing.T) {
+		r := setupOrderRouter(&mockOrderService{})
+
+		req, _ := http.NewRequest("POST", "/admin/orders/order-123/refunds", strings.NewReader(`{"amount": 10}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("amount above captured", func(t *testing.T) {
+		svc := &mockOrderService{
+			refundErr: apperror.NewCodeMessage("refund_exceeds_captured", "refund amount exceeds the captured amount"),
+		}
+		r := setupOrderRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/admin/orders/order-123/refunds", strings.NewReader(`{"amount": 1000, "reason": "damaged"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "refund_exceeds_captured")
+	})
+}
+
+func TestOrderHandler_AdminGetOrder(t *testing.T) {
+	svc := &mockOrderService{
+		adminGetOrderResult: &dto.AdminOrderDetailResponse{
+			OrderResponse: dto.OrderResponse{PublicID: "order-123"},
+			UserID:        "user-1",
+		},
+	}
+	r := setupOrderRouter(svc)
+
+	req, _ := http.NewRequest("GET", "/admin/orders/order-123", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.AdminOrderDetailResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", response.UserID)
+}
+
+func TestOrderHandler_CreateGuestOrder(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc := &mockOrderService{guestOrderResult: &dto.GuestOrderResponse{
+			OrderResponse: dto.OrderResponse{PublicID: "order-123", Status: "pending"},
+			GuestEmail:    "guest@example.com",
+			LookupToken:   "token-abc",
+		}}
+		r := setupOrderRouterGuest(svc)
+
+		reqBody := `{
+			"email": "guest@example.com",
+			"shipping_address": "Rua Teste, 123, São Paulo - SP, 01234-567",
+			"payment_method": "pix"
+		}`
+		req, _ := http.NewRequest("POST", "/orders/guest", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "guest-123", svc.guestOrderGuestID)
+
+		var response dto.GuestOrderResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "order-123", response.PublicID)
+		assert.Equal(t, "token-abc", response.LookupToken)
+	})
+
+	t.Run("missing email", func(t *testing.T) {
+		r := setupOrderRouterGuest(&mockOrderService{})
+
+		reqBody := `{"shipping_address": "Rua Teste, 123", "payment_method": "pix"}`
+		req, _ := http.NewRequest("POST", "/orders/guest", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("cart empty", func(t *testing.T) {
+		svc := &mockOrderService{guestOrderErr: apperror.NewCodeMessage("cart_empty", "cart is empty")}
+		r := setupOrderRouterGuest(svc)
+
+		reqBody := `{"email": "guest@example.com", "shipping_address": "Rua Teste, 123", "payment_method": "pix"}`
+		req, _ := http.NewRequest("POST", "/orders/guest", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestOrderHandler_TrackOrder(t *testing.T) {
+	t.Run("success without authentication", func(t *testing.T) {
+		svc := &mockOrderService{trackResult: &dto.OrderDetailResponse{OrderResponse: dto.OrderResponse{PublicID: "order-123"}}}
+		r := setupOrderRouterUnauthenticated(svc)
+
+		reqBody := `{"email": "guest@example.com", "token": "token-abc"}`
+		req, _ := http.NewRequest("POST", "/orders/track", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := &mockOrderService{trackErr: apperror.NewCodeMessage("order_not_found", "order not found")}
+		r := setupOrderRouterUnauthenticated(svc)
+
+		reqBody := `{"email": "guest@example.com", "token": "wrong"}`
+		req, _ := http.NewRequest("POST", "/orders/track", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestOrderHandler_ClaimGuestOrders(t *testing.T) {
+	t.Run("success with tokens", func(t *testing.T) {
+		svc := &mockOrderService{claimResult: &dto.ClaimGuestOrdersResponse{ClaimedOrders: []string{"order-123"}}}
+		r := setupOrderRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/orders/guest/claim", strings.NewReader(`{"tokens": ["token-abc"]}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"token-abc"}, svc.claimTokens)
+		assert.Equal(t, "", svc.claimGuestID)
+
+		var response dto.ClaimGuestOrdersResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"order-123"}, response.ClaimedOrders)
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		svc := &mockOrderService{claimResult: &dto.ClaimGuestOrdersResponse{ClaimedOrders: []string{}}}
+		r := setupOrderRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/orders/guest/claim", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
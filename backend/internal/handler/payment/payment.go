@@ -1,12 +1,19 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/base64"
 	"io"
+	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/auth"
 	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/febraban"
 	handlererrors "github.com/leoferamos/aroma-sense/internal/handler/errors"
+	"github.com/leoferamos/aroma-sense/internal/model"
 	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
 )
 
@@ -20,6 +27,7 @@ func NewPaymentHandler(paymentService paymentservice.PaymentService) *PaymentHan
 }
 
 // CreateIntent creates a PaymentIntent and returns the client secret.
+// Shoppers who are not signed in pay as their guest session.
 func (h *PaymentHandler) CreateIntent(c *gin.Context) {
 	var req dto.CreatePaymentIntentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -27,13 +35,16 @@ func (h *PaymentHandler) CreateIntent(c *gin.Context) {
 		return
 	}
 
-	userID := c.GetString("userID")
-	if userID == "" {
+	var res *paymentservice.PaymentIntentResult
+	var err error
+	if userID := c.GetString("userID"); userID != "" {
+		res, err = h.paymentService.CreateIntent(c.Request.Context(), userID, &req)
+	} else if guestID := c.GetString("guestID"); guestID != "" {
+		res, err = h.paymentService.CreateGuestIntent(c.Request.Context(), guestID, &req)
+	} else {
 		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
 		return
 	}
-
-	res, err := h.paymentService.CreateIntent(c.Request.Context(), userID, &req)
 	if err != nil {
 		if status, code, ok := handlererrors.MapServiceError(err); ok {
 			c.JSON(status, dto.ErrorResponse{Error: code})
@@ -43,15 +54,80 @@ func (h *PaymentHandler) CreateIntent(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	resp := gin.H{
 		"payment_intent_id": res.ID,
 		"client_secret":     res.ClientSecret,
-	})
+	}
+	if res.PixCode != "" {
+		resp["pix_code"] = res.PixCode
+		resp["pix_qr_code_png"] = base64.StdEncoding.EncodeToString(res.PixQRCodePNG)
+	}
+	if res.BoletoBarcode != "" {
+		resp["boleto_barcode"] = res.BoletoBarcode
+		resp["boleto_digitable_line"] = res.BoletoDigitableLine
+		resp["boleto_url"] = "/payments/" + res.ID + "/boleto"
+	}
+	if res.DueDate != nil {
+		resp["due_date"] = res.DueDate.Format("2006-01-02")
+	}
+	if res.ExpiresAt != nil {
+		resp["expires_at"] = res.ExpiresAt
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetBoleto renders the customer's boleto as printable HTML.
+func (h *PaymentHandler) GetBoleto(c *gin.Context) {
+	userID := auth.ShopperID(c)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	payment, err := h.paymentService.GetBoleto(c.Request.Context(), userID, c.Param("intentID"))
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	doc := febraban.Document{
+		OurNumber:     payment.IntentID,
+		AmountCents:   payment.AmountCents,
+		DigitableLine: payment.BoletoDigitableLine,
+		Barcode:       payment.BoletoBarcode,
+		Instructions:  "Não receber após o vencimento do prazo de compensação.",
+	}
+	if v, ok := payment.Metadata["boleto_beneficiary"].(string); ok {
+		doc.Beneficiary = v
+	}
+	if v, ok := payment.Metadata["boleto_payer"].(string); ok {
+		doc.Payer = v
+	}
+	if payment.DueDate != nil {
+		doc.DueDate = *payment.DueDate
+	}
+
+	var buf bytes.Buffer
+	if err := febraban.RenderHTML(&buf, doc); err != nil {
+		log.Printf("boleto: failed to render %s: %v", payment.IntentID, err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
 }
 
-// HandleWebhook will validate and process payment webhooks (stub for now).
+// HandleWebhook validates and processes payment webhooks. The provider path parameter selects the
+// gateway; without it the default gateway is used. Stripe signs with Stripe-Signature, other
+// gateways with an X-Webhook-Signature HMAC.
 func (h *PaymentHandler) HandleWebhook(c *gin.Context) {
 	signature := c.GetHeader("Stripe-Signature")
+	if signature == "" {
+		signature = c.GetHeader("X-Webhook-Signature")
+	}
 	if signature == "" {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "missing_signature"})
 		return
@@ -63,7 +139,9 @@ func (h *PaymentHandler) HandleWebhook(c *gin.Context) {
 		return
 	}
 
-	if _, err := h.paymentService.HandleWebhook(c.Request.Context(), body, signature); err != nil {
+	// Duplicate deliveries and event types we do not act on are acknowledged as well,
+	// so the gateway stops sending them.
+	if _, err := h.paymentService.HandleWebhook(c.Request.Context(), c.Param("provider"), body, signature); err != nil {
 		if status, code, ok := handlererrors.MapServiceError(err); ok {
 			c.JSON(status, dto.ErrorResponse{Error: code})
 			return
@@ -74,3 +152,100 @@ func (h *PaymentHandler) HandleWebhook(c *gin.Context) {
 
 	c.Status(http.StatusOK)
 }
+
+// maxWebhookEventsPerPage caps admin inbox listings.
+const maxWebhookEventsPerPage = 100
+
+// AdminListWebhookEvents lists payment webhook inbox entries, failed ones by default; status=all lists every entry.
+func (h *PaymentHandler) AdminListWebhookEvents(c *gin.Context) {
+	status := c.DefaultQuery("status", string(model.WebhookEventFailed))
+	switch model.WebhookEventStatus(status) {
+	case model.WebhookEventPending, model.WebhookEventProcessing, model.WebhookEventProcessed,
+		model.WebhookEventFailed, model.WebhookEventRejected, model.WebhookEventIgnored:
+	case "all":
+		status = ""
+	default:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	page := 1
+	perPage := 25
+	if p := c.Query("page"); p != "" {
+		if v, err := strconv.Atoi(p); err == nil && v > 0 {
+			page = v
+		} else {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+			return
+		}
+	}
+	if pp := c.Query("per_page"); pp != "" {
+		if v, err := strconv.Atoi(pp); err == nil && v > 0 {
+			perPage = v
+		} else {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+			return
+		}
+	}
+	if perPage > maxWebhookEventsPerPage {
+		perPage = maxWebhookEventsPerPage
+	}
+
+	resp, err := h.paymentService.ListWebhookEvents(status, page, perPage)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AdminReplayWebhookEvent processes a failed webhook event again and returns its new state.
+func (h *PaymentHandler) AdminReplayWebhookEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	resp, err := h.paymentService.ReplayWebhookEvent(c.Request.Context(), uint(id))
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AdminSimulatePayment delivers a simulated gateway event for an intent of the fake gateway,
+// so checkout can be exercised end to end without a real gateway.
+func (h *PaymentHandler) AdminSimulatePayment(c *gin.Context) {
+	var req dto.SimulatePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	res, err := h.paymentService.SimulatePayment(c.Request.Context(), c.Param("intentID"), req.Outcome)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	resp := gin.H{"payment_intent_id": c.Param("intentID"), "outcome": req.Outcome}
+	if res != nil {
+		resp["event_id"] = res.EventID
+		resp["status"] = res.Status
+	}
+	c.JSON(http.StatusOK, resp)
+}
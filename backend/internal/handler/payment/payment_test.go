@@ -9,9 +9,13 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/apperror"
 	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	orderservice "github.com/leoferamos/aroma-sense/internal/service/order"
 	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
 	"github.com/stretchr/testify/assert"
 )
@@ -19,18 +23,79 @@ import (
 type mockPaymentService struct {
 	createIntentResult  *paymentservice.PaymentIntentResult
 	createIntentErr     error
+	guestID             string
 	handleWebhookResult *paymentservice.PaymentWebhookPayload
 	handleWebhookErr    error
+	webhookProvider     string
+	boleto              *model.Payment
+	boletoErr           error
+	webhookEvents       *dto.AdminWebhookEventsResponse
+	listStatus          string
+	replayed            *dto.WebhookEventResponse
+	replayErr           error
+	replayID            uint
+	simulated           *paymentservice.PaymentWebhookPayload
+	simulateErr         error
+	simulatedOutcome    string
 }
 
 func (m *mockPaymentService) CreateIntent(ctx context.Context, userID string, req *dto.CreatePaymentIntentRequest) (*paymentservice.PaymentIntentResult, error) {
 	return m.createIntentResult, m.createIntentErr
 }
 
-func (m *mockPaymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) (*paymentservice.PaymentWebhookPayload, error) {
+func (m *mockPaymentService) CreateGuestIntent(ctx context.Context, guestID string, req *dto.CreatePaymentIntentRequest) (*paymentservice.PaymentIntentResult, error) {
+	m.guestID = guestID
+	return m.createIntentResult, m.createIntentErr
+}
+
+func (m *mockPaymentService) HandleWebhook(ctx context.Context, provider string, payload []byte, signature string) (*paymentservice.PaymentWebhookPayload, error) {
+	m.webhookProvider = provider
 	return m.handleWebhookResult, m.handleWebhookErr
 }
 
+func (m *mockPaymentService) CancelOrderIntents(ctx context.Context, orderPublicID string, reason string, message string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockPaymentService) RefundOrder(ctx context.Context, orderPublicID string, req orderservice.RefundRequest) (*orderservice.RefundOutcome, error) {
+	return &orderservice.RefundOutcome{}, nil
+}
+
+func (m *mockPaymentService) ExpireIntent(ctx context.Context, intentID string, reason string) error {
+	return nil
+}
+
+func (m *mockPaymentService) GetBoleto(ctx context.Context, userID string, intentID string) (*model.Payment, error) {
+	return m.boleto, m.boletoErr
+}
+
+func (m *mockPaymentService) ProcessWebhookEvent(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (m *mockPaymentService) ListWebhookEvents(status string, page int, perPage int) (*dto.AdminWebhookEventsResponse, error) {
+	m.listStatus = status
+	return m.webhookEvents, nil
+}
+
+func (m *mockPaymentService) ReplayWebhookEvent(ctx context.Context, id uint) (*dto.WebhookEventResponse, error) {
+	m.replayID = id
+	return m.replayed, m.replayErr
+}
+
+func (m *mockPaymentService) ReconcileIntent(ctx context.Context, intentID string) (model.PaymentStatus, error) {
+	return "", nil
+}
+
+func (m *mockPaymentService) DiscrepancyReport(from, to time.Time) (*paymentservice.DiscrepancyReport, error) {
+	return &paymentservice.DiscrepancyReport{}, nil
+}
+
+func (m *mockPaymentService) SimulatePayment(ctx context.Context, intentID string, outcome string) (*paymentservice.PaymentWebhookPayload, error) {
+	m.simulatedOutcome = outcome
+	return m.simulated, m.simulateErr
+}
+
 func setupPaymentRouter(svc paymentservice.PaymentService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -44,6 +109,11 @@ func setupPaymentRouter(svc paymentservice.PaymentService) *gin.Engine {
 	handler := NewPaymentHandler(svc)
 	r.POST("/payments/intent", handler.CreateIntent)
 	r.POST("/payments/webhook", handler.HandleWebhook)
+	r.POST("/payments/webhook/:provider", handler.HandleWebhook)
+	r.GET("/payments/:intentID/boleto", handler.GetBoleto)
+	r.GET("/admin/webhook-events", handler.AdminListWebhookEvents)
+	r.POST("/admin/webhook-events/:id/replay", handler.AdminReplayWebhookEvent)
+	r.POST("/admin/payments/:intentID/simulate", handler.AdminSimulatePayment)
 	return r
 }
 
@@ -76,6 +146,35 @@ func TestPaymentHandler_CreateIntent(t *testing.T) {
 		assert.Equal(t, "secret_123", response["client_secret"])
 	})
 
+	t.Run("pix charge", func(t *testing.T) {
+		expiresAt := time.Date(2025, 12, 16, 12, 30, 0, 0, time.UTC)
+		svc := &mockPaymentService{
+			createIntentResult: &paymentservice.PaymentIntentResult{
+				ID:           "TX123",
+				PixCode:      "000201010212",
+				PixQRCodePNG: []byte("png"),
+				ExpiresAt:    &expiresAt,
+			},
+		}
+		r := setupPaymentRouter(svc)
+
+		reqBody := `{"shipping_address": "Rua Teste, 123", "payment_method": "pix"}`
+		req, _ := http.NewRequest("POST", "/payments/intent", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]string
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "000201010212", response["pix_code"])
+		assert.Equal(t, "cG5n", response["pix_qr_code_png"])
+		assert.Equal(t, "2025-12-16T12:30:00Z", response["expires_at"])
+	})
+
 	t.Run("invalid json", func(t *testing.T) {
 		svc := &mockPaymentService{}
 		r := setupPaymentRouter(svc)
@@ -169,6 +268,20 @@ func TestPaymentHandler_HandleWebhook(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 
+	t.Run("provider route with hmac signature", func(t *testing.T) {
+		svc := &mockPaymentService{handleWebhookResult: &paymentservice.PaymentWebhookPayload{IntentID: "TX123"}}
+		r := setupPaymentRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/payments/webhook/pix", bytes.NewReader([]byte(`{"pix":[]}`)))
+		req.Header.Set("X-Webhook-Signature", "abc123")
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "pix", svc.webhookProvider)
+	})
+
 	t.Run("missing signature", func(t *testing.T) {
 		svc := &mockPaymentService{}
 		r := setupPaymentRouter(svc)
@@ -208,3 +321,152 @@ func TestPaymentHandler_HandleWebhook(t *testing.T) {
 		assert.Equal(t, "internal_error", response.Error)
 	})
 }
+
+func TestPaymentHandler_GetBoleto(t *testing.T) {
+	t.Run("renders html", func(t *testing.T) {
+		due := time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC)
+		svc := &mockPaymentService{boleto: &model.Payment{
+			IntentID:            "00000000000000123",
+			AmountCents:         12990,
+			DueDate:             &due,
+			BoletoBarcode:       "00193373700000001000500940144816060680935031",
+			BoletoDigitableLine: "00190.50095 40144.816069 06809.350314 3 37370000000100",
+			Metadata:            map[string]interface{}{"boleto_beneficiary": "Aroma Sense", "boleto_payer": "ana@example.com"},
+		}}
+		r := setupPaymentRouter(svc)
+
+		req, _ := http.NewRequest("GET", "/payments/00000000000000123/boleto", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+		assert.Contains(t, w.Body.String(), "00190.50095 40144.816069 06809.350314 3 37370000000100")
+		assert.Contains(t, w.Body.String(), "ana@example.com")
+		assert.Contains(t, w.Body.String(), "19/12/2025")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc := &mockPaymentService{boletoErr: apperror.NewCodeMessage("boleto_not_found", "boleto not found")}
+		r := setupPaymentRouter(svc)
+
+		req, _ := http.NewRequest("GET", "/payments/missing/boleto", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPaymentHandler_AdminListWebhookEvents(t *testing.T) {
+	t.Run("defaults to failed events", func(t *testing.T) {
+		resp := &dto.AdminWebhookEventsResponse{Events: []dto.WebhookEventResponse{{ID: 7, Provider: "stripe", Status: "failed"}}}
+		svc := &mockPaymentService{webhookEvents: resp}
+		r := setupPaymentRouter(svc)
+
+		req, _ := http.NewRequest("GET", "/admin/webhook-events", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "failed", svc.listStatus)
+		assert.Contains(t, w.Body.String(), `"provider":"stripe"`)
+	})
+
+	t.Run("all statuses", func(t *testing.T) {
+		svc := &mockPaymentService{webhookEvents: &dto.AdminWebhookEventsResponse{}}
+		r := setupPaymentRouter(svc)
+
+		req, _ := http.NewRequest("GET", "/admin/webhook-events?status=all", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "", svc.listStatus)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		r := setupPaymentRouter(&mockPaymentService{})
+
+		req, _ := http.NewRequest("GET", "/admin/webhook-events?status=lost", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPaymentHandler_AdminReplayWebhookEvent(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc := &mockPaymentService{replayed: &dto.WebhookEventResponse{ID: 7, Status: "processed"}}
+		r := setupPaymentRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/admin/webhook-events/7/replay", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, uint(7), svc.replayID)
+		assert.Contains(t, w.Body.String(), `"status":"processed"`)
+	})
+
+	t.Run("not replayable", func(t *testing.T) {
+		svc := &mockPaymentService{replayErr: apperror.NewCodeMessage("webhook_event_not_replayable", "only failed webhook events can be replayed")}
+		r := setupPaymentRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/admin/webhook-events/7/replay", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		r := setupPaymentRouter(&mockPaymentService{})
+
+		req, _ := http.NewRequest("POST", "/admin/webhook-events/abc/replay", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPaymentHandler_AdminSimulatePayment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		svc := &mockPaymentService{simulated: &paymentservice.PaymentWebhookPayload{EventID: "evt_pi_fake_1", Status: "succeeded"}}
+		r := setupPaymentRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/admin/payments/pi_fake_1/simulate", strings.NewReader(`{"outcome":"succeeded"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "succeeded", svc.simulatedOutcome)
+		assert.Contains(t, w.Body.String(), `"event_id":"evt_pi_fake_1"`)
+	})
+
+	t.Run("unknown outcome", func(t *testing.T) {
+		r := setupPaymentRouter(&mockPaymentService{})
+
+		req, _ := http.NewRequest("POST", "/admin/payments/pi_fake_1/simulate", strings.NewReader(`{"outcome":"disputed"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("gateway cannot simulate", func(t *testing.T) {
+		svc := &mockPaymentService{simulateErr: apperror.NewCodeMessage("simulation_unsupported", "payment provider cannot simulate events")}
+		r := setupPaymentRouter(svc)
+
+		req, _ := http.NewRequest("POST", "/admin/payments/pi_123/simulate", strings.NewReader(`{"outcome":"failed"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
@@ -0,0 +1,88 @@
+package product
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
+
+// maxFilterValues bounds the alternatives accepted for one attribute
+const maxFilterValues = 20
+
+// parseProductFilter reads the catalog filters from the query string. Multi-select attributes
+// accept repeated parameters and comma-separated values (accords=woody&accords=citrus or
+// accords=woody,citrus); min_price and max_price take decimal amounts.
+func parseProductFilter(c *gin.Context) (dto.ProductFilter, error) {
+	var f dto.ProductFilter
+	lists := []struct {
+		param string
+		dst   *[]string
+	}{
+		{"brand", &f.Brands},
+		{"gender", &f.Genders},
+		{"accords", &f.Accords},
+		{"occasions", &f.Occasions},
+		{"seasons", &f.Seasons},
+		{"intensity", &f.Intensities},
+		{"price_range", &f.PriceRanges},
+		{"category", &f.Categories},
+	}
+	for _, l := range lists {
+		values, err := filterValues(c.QueryArray(l.param))
+		if err != nil {
+			return dto.ProductFilter{}, err
+		}
+		*l.dst = values
+	}
+
+	var err error
+	if f.MinPrice, err = priceParam(c, "min_price"); err != nil {
+		return dto.ProductFilter{}, err
+	}
+	if f.MaxPrice, err = priceParam(c, "max_price"); err != nil {
+		return dto.ProductFilter{}, err
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return dto.ProductFilter{}, errors.New("min_price is greater than max_price")
+	}
+	return f, nil
+}
+
+// filterValues splits comma-separated values, dropping blanks and duplicates
+func filterValues(raw []string) ([]string, error) {
+	var values []string
+	seen := make(map[string]bool)
+	for _, param := range raw {
+		for _, v := range strings.Split(param, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" || seen[v] {
+				continue
+			}
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	if len(values) > maxFilterValues {
+		return nil, errors.New("too many filter values")
+	}
+	return values, nil
+}
+
+// priceParam parses an optional non-negative price from the query string
+func priceParam(c *gin.Context, param string) (*money.Amount, error) {
+	raw := strings.TrimSpace(c.Query(param))
+	if raw == "" {
+		return nil, nil
+	}
+	amount, err := money.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if amount < 0 {
+		return nil, errors.New(param + " must not be negative")
+	}
+	return &amount, nil
+}
@@ -0,0 +1,276 @@
+package product
+
+import (
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	handlererrors "github.com/leoferamos/aroma-sense/internal/handler/errors"
+)
+
+// AddImage handles uploading an image to a product gallery
+//
+// @Summary      Add product image
+// @Description  Uploads an image to a product gallery. The first image of a gallery is always primary (Admin only)
+// @Tags         admin
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id          path      int      true   "Product ID"
+// @Param        image       formData  file     true   "Image (JPEG or PNG, max 5MB)"
+// @Param        alt_text    formData  string   false  "Alt text"
+// @Param        is_primary  formData  boolean  false  "Make this the primary image"
+// @Success      201  {object}  dto.ProductImageResponse  "Created image"
+// @Failure      400  {object}  dto.ErrorResponse    "Error code: invalid_request, invalid_image"
+// @Failure      401  {object}  dto.ErrorResponse    "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse    "Error code: unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse    "Error code: product_not_found"
+// @Failure      500  {object}  dto.ErrorResponse    "Error code: internal_error"
+// @Router       /admin/products/{id}/images [post]
+// @Security     BearerAuth
+func (h *ProductHandler) AddImage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	var form dto.ProductImageFormDTO
+	if err := c.ShouldBindWith(&form, binding.FormMultipart); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+	file, upload, ok := imageUpload(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	image, err := h.productService.AddImage(c.Request.Context(), uint(id), form, upload)
+	if err != nil {
+		respondImageError(c, "AddImage", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, image)
+}
+
+// ReplaceImage handles replacing the file of a gallery image
+//
+// @Summary      Replace product image
+// @Description  Uploads a new file for a gallery image, keeping its position. The old file is removed from storage (Admin only)
+// @Tags         admin
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id          path      int      true   "Product ID"
+// @Param        imageId     path      int      true   "Image ID"
+// @Param        image       formData  file     true   "Image (JPEG or PNG, max 5MB)"
+// @Param        alt_text    formData  string   false  "New alt text (kept when omitted)"
+// @Param        is_primary  formData  boolean  false  "Make this the primary image"
+// @Success      200  {object}  dto.ProductImageResponse  "Replaced image"
+// @Failure      400  {object}  dto.ErrorResponse    "Error code: invalid_request, invalid_image"
+// @Failure      401  {object}  dto.ErrorResponse    "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse    "Error code: unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse    "Error code: product_not_found, image_not_found"
+// @Failure      500  {object}  dto.ErrorResponse    "Error code: internal_error"
+// @Router       /admin/products/{id}/images/{imageId} [put]
+// @Security     BearerAuth
+func (h *ProductHandler) ReplaceImage(c *gin.Context) {
+	id, imageID, ok := imagePathIDs(c)
+	if !ok {
+		return
+	}
+
+	var form dto.ProductImageFormDTO
+	if err := c.ShouldBindWith(&form, binding.FormMultipart); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+	file, upload, ok := imageUpload(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	image, err := h.productService.ReplaceImage(c.Request.Context(), id, imageID, form, upload)
+	if err != nil {
+		respondImageError(c, "ReplaceImage", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
+// UpdateImage handles updating the alt text or primary flag of a gallery image
+//
+// @Summary      Update product image
+// @Description  Updates the alt text of a gallery image or makes it the primary image (Admin only)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path    int                            true  "Product ID"
+// @Param        imageId  path    int                            true  "Image ID"
+// @Param        image    body    dto.UpdateProductImageRequest  true  "Image update data"
+// @Success      200  {object}  dto.ProductImageResponse  "Updated image"
+// @Failure      400  {object}  dto.ErrorResponse    "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse    "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse    "Error code: unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse    "Error code: product_not_found, image_not_found"
+// @Failure      409  {object}  dto.ErrorResponse    "Error code: primary_image_required"
+// @Failure      500  {object}  dto.ErrorResponse    "Error code: internal_error"
+// @Router       /admin/products/{id}/images/{imageId} [patch]
+// @Security     BearerAuth
+func (h *ProductHandler) UpdateImage(c *gin.Context) {
+	id, imageID, ok := imagePathIDs(c)
+	if !ok {
+		return
+	}
+
+	var input dto.UpdateProductImageRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	image, err := h.productService.UpdateImage(c.Request.Context(), id, imageID, input)
+	if err != nil {
+		respondImageError(c, "UpdateImage", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
+// ReorderImages handles changing the display order of a product gallery
+//
+// @Summary      Reorder product images
+// @Description  Sets the display order of a product gallery. Every image of the product must be listed once (Admin only)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id     path    int                              true  "Product ID"
+// @Param        order  body    dto.ReorderProductImagesRequest  true  "Image IDs in display order"
+// @Success      200  {array}   dto.ProductImageResponse  "Gallery in its new order"
+// @Failure      400  {object}  dto.ErrorResponse    "Error code: invalid_request, invalid_image_order"
+// @Failure      401  {object}  dto.ErrorResponse    "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse    "Error code: unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse    "Error code: product_not_found"
+// @Failure      500  {object}  dto.ErrorResponse    "Error code: internal_error"
+// @Router       /admin/products/{id}/images/order [put]
+// @Security     BearerAuth
+func (h *ProductHandler) ReorderImages(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	var input dto.ReorderProductImagesRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	images, err := h.productService.ReorderImages(c.Request.Context(), uint(id), input)
+	if err != nil {
+		respondImageError(c, "ReorderImages", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, images)
+}
+
+// DeleteImage handles removing a gallery image
+//
+// @Summary      Delete product image
+// @Description  Removes a gallery image and its files. When it was primary the first remaining image becomes primary. A product keeps at least one image (Admin only)
+// @Tags         admin
+// @Produce      json
+// @Param        id       path    int  true  "Product ID"
+// @Param        imageId  path    int  true  "Image ID"
+// @Success      200  {object}  dto.MessageResponse  "Image deleted successfully"
+// @Failure      400  {object}  dto.ErrorResponse    "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse    "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse    "Error code: unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse    "Error code: product_not_found, image_not_found"
+// @Failure      409  {object}  dto.ErrorResponse    "Error code: last_image"
+// @Failure      500  {object}  dto.ErrorResponse    "Error code: internal_error"
+// @Router       /admin/products/{id}/images/{imageId} [delete]
+// @Security     BearerAuth
+func (h *ProductHandler) DeleteImage(c *gin.Context) {
+	id, imageID, ok := imagePathIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.productService.DeleteImage(c.Request.Context(), id, imageID); err != nil {
+		respondImageError(c, "DeleteImage", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Image deleted successfully"})
+}
+
+// imagePathIDs parses the product and image IDs from the path
+func imagePathIDs(c *gin.Context) (uint, uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return 0, 0, false
+	}
+	imageID, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return 0, 0, false
+	}
+	return uint(id), uint(imageID), true
+}
+
+// imageUpload reads the "image" form file. The caller closes the returned file.
+func imageUpload(c *gin.Context) (multipart.File, dto.FileUpload, bool) {
+	file, fileHeader, err := c.Request.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "image is required"})
+		return nil, dto.FileUpload{}, false
+	}
+
+	// Convert multipart.File to FileUpload abstraction
+	upload := dto.FileUpload{
+		Content:     file,
+		Name:        fileHeader.Filename,
+		Size:        fileHeader.Size,
+		ContentType: fileHeader.Header.Get("Content-Type"),
+	}
+
+	if upload.ContentType == "" {
+		// Read first 512 bytes to detect content type
+		buf := make([]byte, 512)
+		n, err := file.Read(buf)
+		if err != nil {
+			file.Close()
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+			return nil, dto.FileUpload{}, false
+		}
+
+		// Reset file position
+		if seeker, ok := file.(io.Seeker); ok {
+			seeker.Seek(0, io.SeekStart)
+		}
+
+		upload.ContentType = http.DetectContentType(buf[:n])
+	}
+	return file, upload, true
+}
+
+func respondImageError(c *gin.Context, op string, err error) {
+	if status, code, ok := handlererrors.MapServiceError(err); ok {
+		c.JSON(status, dto.ErrorResponse{Error: code})
+		return
+	}
+	log.Printf("%s: service error: %v", op, err)
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+}
@@ -1,7 +1,6 @@
 package product
 
 import (
-	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -41,11 +40,17 @@ func NewProductHandler(ps productservice.ProductService, rs reviewservice.Review
 // @Param        category       formData  string   true   "Product category"
 // @Param        notes          formData  array    true   "Product notes (fragrance notes)"
 // @Param        stock_quantity formData  integer  true   "Stock quantity"
-// @Param        image          formData  file     true   "Product image"
+// @Param        sku            formData  string   false  "SKU of the default variant (generated when omitted)"
+// @Param        size_ml        formData  integer  false  "Size of the default variant in ml"
+// @Param        concentration  formData  string   false  "Concentration of the default variant: edt, edp or parfum (default edp)"
+// @Param        barcode        formData  string   false  "Barcode of the default variant"
+// @Param        image          formData  file     true   "Product image, which becomes the primary gallery image"
+// @Param        image_alt_text formData  string   false  "Alt text of the product image"
 // @Success      201  {object}  dto.MessageResponse  "Product created successfully"
-// @Failure      400  {object}  dto.ErrorResponse    "Error code: invalid_request (includes missing image)"
+// @Failure      400  {object}  dto.ErrorResponse    "Error code: invalid_request (includes missing image), invalid_image"
 // @Failure      401  {object}  dto.ErrorResponse    "Error code: unauthenticated"
 // @Failure      403  {object}  dto.ErrorResponse    "Error code: unauthorized"
+// @Failure      409  {object}  dto.ErrorResponse    "Error code: variant_sku_taken"
 // @Router       /admin/products [post]
 // @Security     BearerAuth
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
@@ -54,38 +59,12 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
 		return
 	}
-	file, fileHeader, err := c.Request.FormFile("image")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "image is required"})
+	file, fileUpload, ok := imageUpload(c)
+	if !ok {
 		return
 	}
 	defer file.Close()
 
-	// Convert multipart.File to FileUpload abstraction
-	fileUpload := dto.FileUpload{
-		Content:     file,
-		Name:        fileHeader.Filename,
-		Size:        fileHeader.Size,
-		ContentType: fileHeader.Header.Get("Content-Type"),
-	}
-
-	if fileUpload.ContentType == "" {
-		// Read first 512 bytes to detect content type
-		buf := make([]byte, 512)
-		n, err := file.Read(buf)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
-			return
-		}
-
-		// Reset file position
-		if seeker, ok := file.(io.Seeker); ok {
-			seeker.Seek(0, io.SeekStart)
-		}
-
-		fileUpload.ContentType = http.DetectContentType(buf[:n])
-	}
-
 	if err := h.productService.CreateProduct(c.Request.Context(), form, fileUpload); err != nil {
 		if status, code, ok := handlererrors.MapServiceError(err); ok {
 			c.JSON(status, dto.ErrorResponse{Error: code})
@@ -146,16 +125,27 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 // `query` parameter is present.
 //
 // @Summary      List or search products
-// @Description  If `query` is provided, returns a paginated search envelope; otherwise returns the latest products.
+// @Description  If `query` or any filter is provided, returns a paginated search envelope with facet counts; otherwise returns the latest products.
+// @Description  Multi-select filters accept repeated parameters or comma-separated values; values of one filter are alternatives and different filters are combined.
 // @Tags         products
 // @Accept       json
 // @Produce      json
-// @Param        query  query    string  false  "Search term (websearch syntax)"
-// @Param        page   query    int     false  "Page number (1-based)"  default(1)
-// @Param        limit  query    int     false  "Items per page (default 10, max 100)"  default(10)
-// @Param        sort   query    string  false  "Sort order: relevance|latest"  default(relevance)
-// @Success      200  {array}   dto.ProductResponse        "List of latest products (when query is absent and page=1)"
-// @Success      200  {object}  dto.ProductListResponse   "Search results envelope (when query is present) or paginated latest (when query absent and page>1)"
+// @Param        query        query    string    false  "Search term (websearch syntax)"
+// @Param        brand        query    []string  false  "Brands"  collectionFormat(multi)
+// @Param        gender       query    []string  false  "Genders"  collectionFormat(multi)
+// @Param        accords      query    []string  false  "Accords (any of)"  collectionFormat(multi)
+// @Param        occasions    query    []string  false  "Occasions (any of)"  collectionFormat(multi)
+// @Param        seasons      query    []string  false  "Seasons (any of)"  collectionFormat(multi)
+// @Param        intensity    query    []string  false  "Intensities"  collectionFormat(multi)
+// @Param        price_range  query    []string  false  "Price ranges"  collectionFormat(multi)
+// @Param        category     query    []string  false  "Categories"  collectionFormat(multi)
+// @Param        min_price    query    number    false  "Minimum price"
+// @Param        max_price    query    number    false  "Maximum price"
+// @Param        page         query    int       false  "Page number (1-based)"  default(1)
+// @Param        limit        query    int       false  "Items per page (default 10, max 100)"  default(10)
+// @Param        sort         query    string    false  "Sort order: relevance|latest (relevance needs a query)"  default(relevance)
+// @Success      200  {array}   dto.ProductResponse        "List of latest products (when query and filters are absent and page=1)"
+// @Success      200  {object}  dto.ProductListResponse   "Search results envelope with facets (when query or filters are present) or paginated latest (when both are absent and page>1)"
 // @Failure      400  {object}  dto.ErrorResponse         "Error code: invalid_request"
 // @Failure      500  {object}  dto.ErrorResponse         "Error code: internal_error"
 // @Router       /products [get]
@@ -181,7 +171,13 @@ func (h *ProductHandler) GetLatestProducts(c *gin.Context) {
 		limit = maxLimit
 	}
 
-	if query == "" {
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	if query == "" && filter.IsEmpty() {
 		// return latest products
 		products, total, err := h.productService.GetLatestProducts(c.Request.Context(), page, limit)
 		if err != nil {
@@ -211,7 +207,7 @@ func (h *ProductHandler) GetLatestProducts(c *gin.Context) {
 		return
 	}
 
-	items, total, err := h.productService.SearchProducts(c.Request.Context(), query, page, limit, sort)
+	resp, err := h.productService.SearchProducts(c.Request.Context(), query, filter, page, limit, sort)
 	if err != nil {
 		if status, code, ok := handlererrors.MapServiceError(err); ok {
 			c.JSON(status, dto.ErrorResponse{Error: code})
@@ -222,12 +218,6 @@ func (h *ProductHandler) GetLatestProducts(c *gin.Context) {
 		return
 	}
 
-	resp := dto.ProductListResponse{
-		Items: items,
-		Total: total,
-		Page:  page,
-		Limit: limit,
-	}
 	c.JSON(http.StatusOK, resp)
 }
 
@@ -13,8 +13,10 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/apperror"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/handler/product"
+	"github.com/leoferamos/aroma-sense/internal/money"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -54,29 +56,9 @@ func (m *MockProductService) GetLatestProducts(ctx context.Context, page int, li
 	return args.Get(0).([]dto.ProductResponse), args.Int(1), args.Error(2)
 }
 
-func (m *MockProductService) SearchProducts(ctx context.Context, query string, page int, limit int, sort string) ([]dto.ProductResponse, int, error) {
-	args := m.Called(ctx, query, page, limit, sort)
-
-	var items []dto.ProductResponse
-	if v := args.Get(0); v != nil {
-		items = v.([]dto.ProductResponse)
-	} else {
-		items = []dto.ProductResponse{}
-	}
-
-	total := 0
-	if len(args) > 1 {
-		if v, ok := args.Get(1).(int); ok {
-			total = v
-		}
-	}
-
-	var err error
-	if len(args) > 2 {
-		err = args.Error(2)
-	}
-
-	return items, total, err
+func (m *MockProductService) SearchProducts(ctx context.Context, query string, filter dto.ProductFilter, page int, limit int, sort string) (dto.ProductListResponse, error) {
+	args := m.Called(ctx, query, filter, page, limit, sort)
+	return args.Get(0).(dto.ProductListResponse), args.Error(1)
 }
 
 func (m *MockProductService) UpdateProduct(ctx context.Context, id uint, input dto.UpdateProductRequest) error {
@@ -102,6 +84,51 @@ func (m *MockProductService) AdminListProducts(ctx context.Context, page int, li
 	return args.Get(0).([]dto.ProductResponse), args.Int(1), args.Error(2)
 }
 
+func (m *MockProductService) AddVariant(ctx context.Context, productID uint, input dto.ProductVariantRequest) (dto.ProductVariantResponse, error) {
+	args := m.Called(ctx, productID, input)
+	return args.Get(0).(dto.ProductVariantResponse), args.Error(1)
+}
+
+func (m *MockProductService) UpdateVariant(ctx context.Context, productID uint, sku string, input dto.UpdateProductVariantRequest) (dto.ProductVariantResponse, error) {
+	args := m.Called(ctx, productID, sku, input)
+	return args.Get(0).(dto.ProductVariantResponse), args.Error(1)
+}
+
+func (m *MockProductService) DeleteVariant(ctx context.Context, productID uint, sku string) error {
+	args := m.Called(ctx, productID, sku)
+	return args.Error(0)
+}
+
+func (m *MockProductService) AddImage(ctx context.Context, productID uint, input dto.ProductImageFormDTO, file dto.FileUpload) (dto.ProductImageResponse, error) {
+	args := m.Called(ctx, productID, input, file)
+	return args.Get(0).(dto.ProductImageResponse), args.Error(1)
+}
+
+func (m *MockProductService) ReplaceImage(ctx context.Context, productID uint, imageID uint, input dto.ProductImageFormDTO, file dto.FileUpload) (dto.ProductImageResponse, error) {
+	args := m.Called(ctx, productID, imageID, input, file)
+	return args.Get(0).(dto.ProductImageResponse), args.Error(1)
+}
+
+func (m *MockProductService) UpdateImage(ctx context.Context, productID uint, imageID uint, input dto.UpdateProductImageRequest) (dto.ProductImageResponse, error) {
+	args := m.Called(ctx, productID, imageID, input)
+	return args.Get(0).(dto.ProductImageResponse), args.Error(1)
+}
+
+func (m *MockProductService) ReorderImages(ctx context.Context, productID uint, input dto.ReorderProductImagesRequest) ([]dto.ProductImageResponse, error) {
+	args := m.Called(ctx, productID, input)
+	return args.Get(0).([]dto.ProductImageResponse), args.Error(1)
+}
+
+func (m *MockProductService) DeleteImage(ctx context.Context, productID uint, imageID uint) error {
+	args := m.Called(ctx, productID, imageID)
+	return args.Error(0)
+}
+
+func (m *MockProductService) Suggest(ctx context.Context, query string, limit int) (dto.SuggestResponse, error) {
+	args := m.Called(ctx, query, limit)
+	return args.Get(0).(dto.SuggestResponse), args.Error(1)
+}
+
 // ---- SETUP ROUTER ----
 func setupProductRouter() (*gin.Engine, *MockProductService) {
 	mockService := new(MockProductService)
@@ -111,6 +138,7 @@ func setupProductRouter() (*gin.Engine, *MockProductService) {
 	// Public routes
 	router.GET("/products/:slug", productHandler.GetProduct)
 	router.GET("/products", productHandler.GetLatestProducts)
+	router.GET("/products/suggest", productHandler.SuggestProducts)
 
 	// Admin routes
 	adminGroup := router.Group("/admin")
@@ -118,6 +146,14 @@ func setupProductRouter() (*gin.Engine, *MockProductService) {
 		adminGroup.POST("/products", productHandler.CreateProduct)
 		adminGroup.PUT("/products/:id", productHandler.UpdateProduct)
 		adminGroup.DELETE("/products/:id", productHandler.DeleteProduct)
+		adminGroup.POST("/products/:id/variants", productHandler.AddVariant)
+		adminGroup.PATCH("/products/:id/variants/:sku", productHandler.UpdateVariant)
+		adminGroup.DELETE("/products/:id/variants/:sku", productHandler.DeleteVariant)
+		adminGroup.POST("/products/:id/images", productHandler.AddImage)
+		adminGroup.PUT("/products/:id/images/order", productHandler.ReorderImages)
+		adminGroup.PUT("/products/:id/images/:imageId", productHandler.ReplaceImage)
+		adminGroup.PATCH("/products/:id/images/:imageId", productHandler.UpdateImage)
+		adminGroup.DELETE("/products/:id/images/:imageId", productHandler.DeleteImage)
 	}
 
 	return router, mockService
@@ -304,7 +340,7 @@ func TestProductHandler_GetProduct(t *testing.T) {
 		productResponse := dto.ProductResponse{
 			Name:      "Test Fragrance",
 			Brand:     "Test Brand",
-			Price:     99.99,
+			Price:     money.FromCents(9999),
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		}
@@ -469,3 +505,376 @@ func TestProductHandler_DeleteProduct(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestProductHandler_AddVariant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+
+	payload := dto.ProductVariantRequest{SKU: "SAUVAGE-EDP-100", SizeML: 100, Concentration: "edp", Price: money.FromCents(89990), StockQuantity: 4, Weight: 0.35}
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("AddVariant", mock.Anything, uint(1), payload).
+			Return(dto.ProductVariantResponse{SKU: "SAUVAGE-EDP-100", SizeML: 100, Concentration: "edp", Price: money.FromCents(89990), StockQuantity: 4}, nil)
+
+		w := performProductRequest(t, router, http.MethodPost, "/admin/products/1/variants", payload)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"sku":"SAUVAGE-EDP-100"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Concentration", func(t *testing.T) {
+		router, _ := setupProductRouter()
+		invalid := payload
+		invalid.Concentration = "cologne"
+
+		w := performProductRequest(t, router, http.MethodPost, "/admin/products/1/variants", invalid)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("SKU Taken", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("AddVariant", mock.Anything, uint(1), payload).
+			Return(dto.ProductVariantResponse{}, apperror.NewCodeMessage("variant_sku_taken", "sku already exists"))
+
+		w := performProductRequest(t, router, http.MethodPost, "/admin/products/1/variants", payload)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "variant_sku_taken")
+	})
+}
+
+func TestProductHandler_UpdateVariant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+
+	stock := 7
+	payload := dto.UpdateProductVariantRequest{StockQuantity: &stock}
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("UpdateVariant", mock.Anything, uint(1), "SAUVAGE-EDP-100", payload).
+			Return(dto.ProductVariantResponse{SKU: "SAUVAGE-EDP-100", StockQuantity: 7}, nil)
+
+		w := performProductRequest(t, router, http.MethodPatch, "/admin/products/1/variants/SAUVAGE-EDP-100", payload)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"stock_quantity":7`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Variant", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("UpdateVariant", mock.Anything, uint(1), "MISSING", payload).
+			Return(dto.ProductVariantResponse{}, apperror.NewCodeMessage("variant_not_found", "variant not found"))
+
+		w := performProductRequest(t, router, http.MethodPatch, "/admin/products/1/variants/MISSING", payload)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestProductHandler_DeleteVariant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("DeleteVariant", mock.Anything, uint(1), "SAUVAGE-EDP-100").Return(nil)
+
+		w := performProductRequest(t, router, http.MethodDelete, "/admin/products/1/variants/SAUVAGE-EDP-100", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Last Variant", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("DeleteVariant", mock.Anything, uint(1), "SAUVAGE-EDP-100").
+			Return(apperror.NewCodeMessage("last_variant", "a product must keep at least one variant"))
+
+		w := performProductRequest(t, router, http.MethodDelete, "/admin/products/1/variants/SAUVAGE-EDP-100", nil)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "last_variant")
+	})
+}
+
+func TestProductHandler_AddImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("AddImage", mock.Anything, uint(1), dto.ProductImageFormDTO{AltText: "Bottle, side view", IsPrimary: true}, mock.AnythingOfType("dto.FileUpload")).
+			Return(dto.ProductImageResponse{ID: 2, URL: "https://cdn/product-2.jpg", AltText: "Bottle, side view", Position: 1, IsPrimary: true}, nil)
+
+		form := map[string]string{"alt_text": "Bottle, side view", "is_primary": "true"}
+		w := performMultipartRequest(t, router, http.MethodPost, "/admin/products/1/images", form, true)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"is_primary":true`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Missing Image", func(t *testing.T) {
+		router, _ := setupProductRouter()
+
+		w := performMultipartRequest(t, router, http.MethodPost, "/admin/products/1/images", map[string]string{"alt_text": "Bottle"}, false)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "image is required")
+	})
+
+	t.Run("Invalid Image", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("AddImage", mock.Anything, uint(1), dto.ProductImageFormDTO{}, mock.AnythingOfType("dto.FileUpload")).
+			Return(dto.ProductImageResponse{}, apperror.NewCodeMessage("invalid_image", "image too large (max 5MB)"))
+
+		w := performMultipartRequest(t, router, http.MethodPost, "/admin/products/1/images", map[string]string{}, true)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid_image")
+	})
+}
+
+func TestProductHandler_ReplaceImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("ReplaceImage", mock.Anything, uint(1), uint(3), dto.ProductImageFormDTO{}, mock.AnythingOfType("dto.FileUpload")).
+			Return(dto.ProductImageResponse{ID: 3, URL: "https://cdn/product-new.jpg"}, nil)
+
+		w := performMultipartRequest(t, router, http.MethodPut, "/admin/products/1/images/3", map[string]string{}, true)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "product-new.jpg")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Image", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("ReplaceImage", mock.Anything, uint(1), uint(9), dto.ProductImageFormDTO{}, mock.AnythingOfType("dto.FileUpload")).
+			Return(dto.ProductImageResponse{}, apperror.NewCodeMessage("image_not_found", "image not found"))
+
+		w := performMultipartRequest(t, router, http.MethodPut, "/admin/products/1/images/9", map[string]string{}, true)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestProductHandler_UpdateImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		alt := "Bottle and box"
+		payload := dto.UpdateProductImageRequest{AltText: &alt}
+		mockService.On("UpdateImage", mock.Anything, uint(1), uint(3), payload).
+			Return(dto.ProductImageResponse{ID: 3, AltText: alt}, nil)
+
+		w := performProductRequest(t, router, http.MethodPatch, "/admin/products/1/images/3", payload)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Bottle and box")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Unset Primary", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		primary := false
+		payload := dto.UpdateProductImageRequest{IsPrimary: &primary}
+		mockService.On("UpdateImage", mock.Anything, uint(1), uint(3), payload).
+			Return(dto.ProductImageResponse{}, apperror.NewCodeMessage("primary_image_required", "set another image as primary instead"))
+
+		w := performProductRequest(t, router, http.MethodPatch, "/admin/products/1/images/3", payload)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Invalid Image ID", func(t *testing.T) {
+		router, _ := setupProductRouter()
+
+		w := performProductRequest(t, router, http.MethodPatch, "/admin/products/1/images/abc", map[string]string{})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestProductHandler_ReorderImages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		payload := dto.ReorderProductImagesRequest{ImageIDs: []uint{3, 1}}
+		mockService.On("ReorderImages", mock.Anything, uint(1), payload).
+			Return([]dto.ProductImageResponse{{ID: 3, Position: 0}, {ID: 1, Position: 1, IsPrimary: true}}, nil)
+
+		w := performProductRequest(t, router, http.MethodPut, "/admin/products/1/images/order", payload)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var images []dto.ProductImageResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &images))
+		require.Len(t, images, 2)
+		assert.Equal(t, uint(3), images[0].ID)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Incomplete Order", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		payload := dto.ReorderProductImagesRequest{ImageIDs: []uint{3}}
+		mockService.On("ReorderImages", mock.Anything, uint(1), payload).
+			Return([]dto.ProductImageResponse(nil), apperror.NewCodeMessage("invalid_image_order", "every gallery image must be listed once"))
+
+		w := performProductRequest(t, router, http.MethodPut, "/admin/products/1/images/order", payload)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid_image_order")
+	})
+
+	t.Run("Empty Order", func(t *testing.T) {
+		router, _ := setupProductRouter()
+
+		w := performProductRequest(t, router, http.MethodPut, "/admin/products/1/images/order", dto.ReorderProductImagesRequest{})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestProductHandler_DeleteImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("DeleteImage", mock.Anything, uint(1), uint(3)).Return(nil)
+
+		w := performProductRequest(t, router, http.MethodDelete, "/admin/products/1/images/3", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Last Image", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("DeleteImage", mock.Anything, uint(1), uint(3)).
+			Return(apperror.NewCodeMessage("last_image", "a product must keep at least one image"))
+
+		w := performProductRequest(t, router, http.MethodDelete, "/admin/products/1/images/3", nil)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "last_image")
+	})
+}
+
+func TestProductHandler_SearchProductsWithFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+
+	t.Run("Filters And Facets", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+
+		minPrice := money.FromCents(10000)
+		maxPrice := money.FromCents(50000)
+		filter := dto.ProductFilter{
+			Brands:   []string{"Dior", "Chanel"},
+			Accords:  []string{"woody", "citrus"},
+			MinPrice: &minPrice,
+			MaxPrice: &maxPrice,
+		}
+		resp := dto.ProductListResponse{
+			Items: []dto.ProductResponse{{Name: "Sauvage"}},
+			Total: 1,
+			Page:  1,
+			Limit: 10,
+			Facets: &dto.ProductFacets{
+				Brands: []dto.FacetCount{{Value: "Dior", Count: 1}, {Value: "Chanel", Count: 3}},
+			},
+		}
+		mockService.On("SearchProducts", mock.Anything, "", filter, 1, 10, "relevance").Return(resp, nil)
+
+		w := performProductRequest(t, router, http.MethodGet, "/products?brand=Dior&brand=Chanel&accords=woody,citrus,woody&min_price=100&max_price=500", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body dto.ProductListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.NotNil(t, body.Facets)
+		assert.Equal(t, 3, body.Facets.Brands[1].Count)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Query With Filter", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		filter := dto.ProductFilter{Seasons: []string{"summer"}}
+		mockService.On("SearchProducts", mock.Anything, "fresh", filter, 1, 10, "latest").
+			Return(dto.ProductListResponse{Page: 1, Limit: 10, Facets: &dto.ProductFacets{}}, nil)
+
+		w := performProductRequest(t, router, http.MethodGet, "/products?query=fresh&seasons=summer&sort=latest", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Price", func(t *testing.T) {
+		router, _ := setupProductRouter()
+
+		w := performProductRequest(t, router, http.MethodGet, "/products?min_price=abc", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Min Price Above Max Price", func(t *testing.T) {
+		router, _ := setupProductRouter()
+
+		w := performProductRequest(t, router, http.MethodGet, "/products?min_price=500&max_price=100", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestProductHandler_SuggestProducts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("Suggest", mock.Anything, "sau", 5).Return(dto.SuggestResponse{
+			Products: []dto.ProductSuggestion{{Name: "Sauvage", Brand: "Dior", Slug: "dior-sauvage"}},
+			Brands:   []string{},
+			Accords:  []string{},
+			Notes:    []string{"sálvia"},
+		}, nil)
+
+		w := performProductRequest(t, router, http.MethodGet, "/products/suggest?q=sau", nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body dto.SuggestResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Products, 1)
+		assert.Equal(t, "dior-sauvage", body.Products[0].Slug)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Limit", func(t *testing.T) {
+		router, _ := setupProductRouter()
+
+		w := performProductRequest(t, router, http.MethodGet, "/products/suggest?q=sau&limit=0", nil)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Service Error", func(t *testing.T) {
+		router, mockService := setupProductRouter()
+		mockService.On("Suggest", mock.Anything, "oud", 3).Return(dto.SuggestResponse{}, fmt.Errorf("database error"))
+
+		w := performProductRequest(t, router, http.MethodGet, "/products/suggest?q=oud&limit=3", nil)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
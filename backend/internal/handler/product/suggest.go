@@ -0,0 +1,39 @@
+package product
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+)
+
+// SuggestProducts handles search box autocompletion
+//
+// @Summary      Search suggestions
+// @Description  Returns typeahead suggestions for a partial search term, grouped into product names, brands, accords and notes. Matching is accent-insensitive; prefix matches rank first, then trigram similarity. Terms shorter than 2 characters return empty groups.
+// @Tags         products
+// @Produce      json
+// @Param        q      query    string  true   "Partial search term"
+// @Param        limit  query    int     false  "Suggestions per group (default 5, max 10)"  default(5)
+// @Success      200  {object}  dto.SuggestResponse  "Suggestions"
+// @Failure      400  {object}  dto.ErrorResponse    "Error code: invalid_request"
+// @Failure      500  {object}  dto.ErrorResponse    "Error code: internal_error"
+// @Router       /products/suggest [get]
+func (h *ProductHandler) SuggestProducts(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	resp, err := h.productService.Suggest(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		log.Printf("SuggestProducts: suggest error (q=%q): %v", c.Query("q"), err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
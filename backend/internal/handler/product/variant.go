@@ -0,0 +1,132 @@
+package product
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	handlererrors "github.com/leoferamos/aroma-sense/internal/handler/errors"
+)
+
+// AddVariant handles adding a size or concentration to a product
+//
+// @Summary      Add product variant
+// @Description  Adds a variant (size, concentration, price, stock and weight) to a product (Admin only)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path    int                        true  "Product ID"
+// @Param        variant  body    dto.ProductVariantRequest  true  "Variant data"
+// @Success      201  {object}  dto.ProductVariantResponse  "Created variant"
+// @Failure      400  {object}  dto.ErrorResponse    "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse    "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse    "Error code: unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse    "Error code: product_not_found"
+// @Failure      409  {object}  dto.ErrorResponse    "Error code: variant_sku_taken"
+// @Failure      500  {object}  dto.ErrorResponse    "Error code: internal_error"
+// @Router       /admin/products/{id}/variants [post]
+// @Security     BearerAuth
+func (h *ProductHandler) AddVariant(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	var input dto.ProductVariantRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	variant, err := h.productService.AddVariant(c.Request.Context(), uint(id), input)
+	if err != nil {
+		respondVariantError(c, "AddVariant", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, variant)
+}
+
+// UpdateVariant handles updating a product variant
+//
+// @Summary      Update product variant
+// @Description  Updates a product variant identified by its SKU (Admin only)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path    int                              true  "Product ID"
+// @Param        sku      path    string                           true  "Variant SKU"
+// @Param        variant  body    dto.UpdateProductVariantRequest  true  "Variant update data"
+// @Success      200  {object}  dto.ProductVariantResponse  "Updated variant"
+// @Failure      400  {object}  dto.ErrorResponse    "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse    "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse    "Error code: unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse    "Error code: product_not_found, variant_not_found"
+// @Failure      500  {object}  dto.ErrorResponse    "Error code: internal_error"
+// @Router       /admin/products/{id}/variants/{sku} [patch]
+// @Security     BearerAuth
+func (h *ProductHandler) UpdateVariant(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	var input dto.UpdateProductVariantRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	variant, err := h.productService.UpdateVariant(c.Request.Context(), uint(id), c.Param("sku"), input)
+	if err != nil {
+		respondVariantError(c, "UpdateVariant", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, variant)
+}
+
+// DeleteVariant handles removing a product variant
+//
+// @Summary      Delete product variant
+// @Description  Removes a product variant identified by its SKU. A product keeps at least one variant (Admin only)
+// @Tags         admin
+// @Produce      json
+// @Param        id   path    int     true  "Product ID"
+// @Param        sku  path    string  true  "Variant SKU"
+// @Success      200  {object}  dto.MessageResponse  "Variant deleted successfully"
+// @Failure      400  {object}  dto.ErrorResponse    "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse    "Error code: unauthenticated"
+// @Failure      403  {object}  dto.ErrorResponse    "Error code: unauthorized"
+// @Failure      404  {object}  dto.ErrorResponse    "Error code: product_not_found, variant_not_found"
+// @Failure      409  {object}  dto.ErrorResponse    "Error code: last_variant"
+// @Failure      500  {object}  dto.ErrorResponse    "Error code: internal_error"
+// @Router       /admin/products/{id}/variants/{sku} [delete]
+// @Security     BearerAuth
+func (h *ProductHandler) DeleteVariant(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	if err := h.productService.DeleteVariant(c.Request.Context(), uint(id), c.Param("sku")); err != nil {
+		respondVariantError(c, "DeleteVariant", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Variant deleted successfully"})
+}
+
+func respondVariantError(c *gin.Context, op string, err error) {
+	if status, code, ok := handlererrors.MapServiceError(err); ok {
+		c.JSON(status, dto.ErrorResponse{Error: code})
+		return
+	}
+	log.Printf("%s: service error: %v", op, err)
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+}
@@ -74,8 +74,8 @@ func (s stubProductService) GetProductIDBySlug(ctx context.Context, slug string)
 func (s stubProductService) GetLatestProducts(ctx context.Context, page int, limit int) ([]dto.ProductResponse, int, error) {
 	return nil, 0, nil
 }
-func (s stubProductService) SearchProducts(ctx context.Context, query string, page int, limit int, sort string) ([]dto.ProductResponse, int, error) {
-	return nil, 0, nil
+func (s stubProductService) SearchProducts(ctx context.Context, query string, filter dto.ProductFilter, page int, limit int, sort string) (dto.ProductListResponse, error) {
+	return dto.ProductListResponse{}, nil
 }
 func (s stubProductService) AdminListProducts(ctx context.Context, page int, limit int) ([]dto.ProductResponse, int, error) {
 	return nil, 0, nil
@@ -84,6 +84,39 @@ func (s stubProductService) UpdateProduct(ctx context.Context, id uint, input dt
 	return nil
 }
 func (s stubProductService) DeleteProduct(ctx context.Context, id uint) error { return nil }
+func (s stubProductService) AddVariant(ctx context.Context, productID uint, input dto.ProductVariantRequest) (dto.ProductVariantResponse, error) {
+	return dto.ProductVariantResponse{}, nil
+}
+func (s stubProductService) UpdateVariant(ctx context.Context, productID uint, sku string, input dto.UpdateProductVariantRequest) (dto.ProductVariantResponse, error) {
+	return dto.ProductVariantResponse{}, nil
+}
+func (s stubProductService) DeleteVariant(ctx context.Context, productID uint, sku string) error {
+	return nil
+}
+
+func (s stubProductService) AddImage(ctx context.Context, productID uint, input dto.ProductImageFormDTO, file dto.FileUpload) (dto.ProductImageResponse, error) {
+	return dto.ProductImageResponse{}, nil
+}
+
+func (s stubProductService) ReplaceImage(ctx context.Context, productID uint, imageID uint, input dto.ProductImageFormDTO, file dto.FileUpload) (dto.ProductImageResponse, error) {
+	return dto.ProductImageResponse{}, nil
+}
+
+func (s stubProductService) UpdateImage(ctx context.Context, productID uint, imageID uint, input dto.UpdateProductImageRequest) (dto.ProductImageResponse, error) {
+	return dto.ProductImageResponse{}, nil
+}
+
+func (s stubProductService) ReorderImages(ctx context.Context, productID uint, input dto.ReorderProductImagesRequest) ([]dto.ProductImageResponse, error) {
+	return nil, nil
+}
+
+func (s stubProductService) DeleteImage(ctx context.Context, productID uint, imageID uint) error {
+	return nil
+}
+
+func (s stubProductService) Suggest(ctx context.Context, query string, limit int) (dto.SuggestResponse, error) {
+	return dto.SuggestResponse{}, nil
+}
 
 type stubUserProfileService struct {
 	user *model.User
@@ -96,6 +129,9 @@ func (s stubUserProfileService) GetByPublicID(publicID string) (*model.User, err
 func (s stubUserProfileService) UpdateDisplayName(publicID string, displayName string) (*model.User, error) {
 	return s.user, s.err
 }
+func (s stubUserProfileService) UpdateMarketingConsent(publicID string, optIn bool) (*model.User, error) {
+	return s.user, s.err
+}
 func (s stubUserProfileService) SetPasswordHash(publicID string, hashedPassword string) error {
 	return nil
 }
@@ -117,6 +153,12 @@ func (stubAuditLogService) LogAdminAction(adminID uint, userID uint, action mode
 func (stubAuditLogService) LogSystemAction(action model.AuditAction, resource, resourceID string, details map[string]interface{}) error {
 	return nil
 }
+func (stubAuditLogService) LogSystemAlert(action model.AuditAction, resource, resourceID, severity string, details map[string]interface{}) error {
+	return nil
+}
+func (stubAuditLogService) LogOrderAction(actorID *uint, orderPublicID string, action model.AuditAction, details map[string]interface{}, oldValues, newValues map[string]interface{}) error {
+	return nil
+}
 func (stubAuditLogService) LogDataAccess(userID uint, resource string, resourceID string) error {
 	return nil
 }
@@ -4,39 +4,57 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/auth"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	handlererrors "github.com/leoferamos/aroma-sense/internal/handler/errors"
 	shippingservice "github.com/leoferamos/aroma-sense/internal/service/shipping"
+	userservice "github.com/leoferamos/aroma-sense/internal/service/user"
 )
 
 // ShippingHandler handles shipping quotation endpoints.
 type ShippingHandler struct {
 	shippingService shippingservice.ShippingService
+	addressService  userservice.AddressService
 }
 
-func NewShippingHandler(shippingService shippingservice.ShippingService) *ShippingHandler {
-	return &ShippingHandler{shippingService: shippingService}
+func NewShippingHandler(shippingService shippingservice.ShippingService, addressService userservice.AddressService) *ShippingHandler {
+	return &ShippingHandler{shippingService: shippingService, addressService: addressService}
 }
 
-// GetShippingOptions returns shipping options for the authenticated user's cart.
+// GetShippingOptions returns shipping options for the cart of the authenticated user or guest session.
 // @Summary      List shipping options
 // @Description  Returns a list of shipping options (carrier, service code, price, ETA) for the current cart.
+// @Description  The destination is either a postal code or one of the user's saved addresses.
 // @Tags         shipping
 // @Produce      json
-// @Param        postal_code query string true "Destination postal code (CEP)"
+// @Param        postal_code query string false "Destination postal code (CEP)"
+// @Param        address_id  query string false "Public ID of a saved address, used instead of postal_code"
 // @Success      200   {array} dto.ShippingOption
 // @Failure      400   {object} dto.ErrorResponse "Error code: invalid_request"
 // @Failure      401   {object} dto.ErrorResponse "Error code: unauthenticated"
+// @Failure      404   {object} dto.ErrorResponse "Error code: address_not_found"
 // @Failure      500   {object} dto.ErrorResponse "Error code: internal_error"
 // @Router       /shipping/options [get]
 // @Security     BearerAuth
 func (h *ShippingHandler) GetShippingOptions(c *gin.Context) {
-	userID := c.GetString("userID")
+	userID := auth.ShopperID(c)
 	if userID == "" {
 		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
 		return
 	}
 	postalCode := c.Query("postal_code")
+	if addressID := c.Query("address_id"); addressID != "" && h.addressService != nil {
+		address, err := h.addressService.GetAddress(userID, addressID)
+		if err != nil {
+			if status, code, ok := handlererrors.MapServiceError(err); ok {
+				c.JSON(status, dto.ErrorResponse{Error: code})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+			return
+		}
+		postalCode = address.CEP
+	}
 	if postalCode == "" {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
 		return
@@ -11,17 +11,49 @@ import (
 	"github.com/leoferamos/aroma-sense/internal/apperror"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/handler/shipping"
+	"github.com/leoferamos/aroma-sense/internal/money"
 )
 
 type mockShippingService struct {
 	options []dto.ShippingOption
 	err     error
+	wantCEP string
 }
 
 func (m mockShippingService) CalculateOptions(ctx context.Context, userID string, postalCode string) ([]dto.ShippingOption, error) {
+	if m.wantCEP != "" && postalCode != m.wantCEP {
+		return nil, apperror.NewCodeMessage("invalid_postal_code", "unexpected postal code "+postalCode)
+	}
 	return m.options, m.err
 }
 
+type mockAddressService struct {
+	address *dto.AddressResponse
+}
+
+func (m mockAddressService) ListAddresses(userID string) ([]dto.AddressResponse, error) {
+	return nil, nil
+}
+
+func (m mockAddressService) GetAddress(userID string, publicID string) (*dto.AddressResponse, error) {
+	if m.address == nil || m.address.PublicID != publicID {
+		return nil, apperror.NewCodeMessage("address_not_found", "address not found")
+	}
+	return m.address, nil
+}
+
+func (m mockAddressService) CreateAddress(userID string, req *dto.AddressRequest) (*dto.AddressResponse, error) {
+	return nil, nil
+}
+
+func (m mockAddressService) UpdateAddress(userID string, publicID string, req *dto.AddressRequest) (*dto.AddressResponse, error) {
+	return nil, nil
+}
+
+func (m mockAddressService) DeleteAddress(userID string, publicID string) error {
+	return nil
+}
+
 func setupRouterWithUser(h *shipping.ShippingHandler) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -39,15 +71,15 @@ func TestGetShippingOptions(t *testing.T) {
 
 	tests := []struct {
 		name       string
-		postalCode string
+		query      string
 		svc        mockShippingService
 		expectCode int
 		assertion  func(t *testing.T, body []byte)
 	}{
 		{
 			name:       "success",
-			postalCode: "01234-567",
-			svc:        mockShippingService{options: []dto.ShippingOption{{Carrier: "Correios", ServiceCode: "SEDEX", Price: 24.9, EstimatedDays: 2}}},
+			query:      "postal_code=01234-567",
+			svc:        mockShippingService{options: []dto.ShippingOption{{Carrier: "Correios", ServiceCode: "SEDEX", Price: money.FromCents(2490), EstimatedDays: 2}}},
 			expectCode: http.StatusOK,
 			assertion: func(t *testing.T, body []byte) {
 				var got []dto.ShippingOption
@@ -61,7 +93,7 @@ func TestGetShippingOptions(t *testing.T) {
 		},
 		{
 			name:       "invalid postal code",
-			postalCode: "abc",
+			query:      "postal_code=abc",
 			svc:        mockShippingService{err: apperror.NewCodeMessage("invalid_postal_code", "invalid destination postal code")},
 			expectCode: http.StatusBadRequest,
 			assertion: func(t *testing.T, body []byte) {
@@ -74,6 +106,23 @@ func TestGetShippingOptions(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:       "saved address",
+			query:      "address_id=addr-1",
+			svc:        mockShippingService{options: []dto.ShippingOption{{Carrier: "Correios", ServiceCode: "PAC"}}, wantCEP: "01310-100"},
+			expectCode: http.StatusOK,
+		},
+		{
+			name:       "unknown address",
+			query:      "address_id=addr-2",
+			svc:        mockShippingService{},
+			expectCode: http.StatusNotFound,
+		},
+		{
+			name:       "missing destination",
+			svc:        mockShippingService{},
+			expectCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -81,10 +130,14 @@ func TestGetShippingOptions(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			h := shipping.NewShippingHandler(tc.svc)
+			addresses := mockAddressService{address: &dto.AddressResponse{
+				PublicID:              "addr-1",
+				PostalAddressResponse: dto.PostalAddressResponse{CEP: "01310-100"},
+			}}
+			h := shipping.NewShippingHandler(tc.svc, addresses)
 			r := setupRouterWithUser(h)
 
-			req := httptest.NewRequest(http.MethodGet, "/shipping/options?postal_code="+tc.postalCode, nil)
+			req := httptest.NewRequest(http.MethodGet, "/shipping/options?"+tc.query, nil)
 			w := httptest.NewRecorder()
 			r.ServeHTTP(w, req)
 
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	handlererrors "github.com/leoferamos/aroma-sense/internal/handler/errors"
+	userservice "github.com/leoferamos/aroma-sense/internal/service/user"
+)
+
+// AddressHandler serves the authenticated user's address book
+type AddressHandler struct {
+	addressService userservice.AddressService
+}
+
+// NewAddressHandler creates a new instance of AddressHandler
+func NewAddressHandler(addressService userservice.AddressService) *AddressHandler {
+	return &AddressHandler{addressService: addressService}
+}
+
+// ListAddresses returns the authenticated user's addresses
+//
+// @Summary      List addresses
+// @Description  Returns the saved delivery addresses of the authenticated user, default first.
+// @Tags         addresses
+// @Produce      json
+// @Success      200  {array}   dto.AddressResponse
+// @Failure      401  {object}  dto.ErrorResponse  "Error code: unauthenticated"
+// @Failure      500  {object}  dto.ErrorResponse  "Error code: internal_error"
+// @Router       /users/me/addresses [get]
+// @Security     BearerAuth
+func (h *AddressHandler) ListAddresses(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	resp, err := h.addressService.ListAddresses(userID)
+	if err != nil {
+		respondAddressError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetAddress returns one of the authenticated user's addresses
+//
+// @Summary      Get address
+// @Description  Returns a saved delivery address of the authenticated user.
+// @Tags         addresses
+// @Produce      json
+// @Param        addressID  path      string  true  "Address public ID"
+// @Success      200  {object}  dto.AddressResponse
+// @Failure      401  {object}  dto.ErrorResponse  "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse  "Error code: address_not_found"
+// @Failure      500  {object}  dto.ErrorResponse  "Error code: internal_error"
+// @Router       /users/me/addresses/{addressID} [get]
+// @Security     BearerAuth
+func (h *AddressHandler) GetAddress(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	resp, err := h.addressService.GetAddress(userID, c.Param("addressID"))
+	if err != nil {
+		respondAddressError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateAddress adds an address to the authenticated user's address book
+//
+// @Summary      Create address
+// @Description  Saves a structured delivery address. The first address, or one sent with is_default, becomes the default.
+// @Tags         addresses
+// @Accept       json
+// @Produce      json
+// @Param        input  body      dto.AddressRequest  true  "Address"
+// @Success      201  {object}  dto.AddressResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Error code: invalid_request or invalid_postal_code or invalid_uf or invalid_address"
+// @Failure      401  {object}  dto.ErrorResponse  "Error code: unauthenticated"
+// @Failure      409  {object}  dto.ErrorResponse  "Error code: address_limit_reached"
+// @Failure      500  {object}  dto.ErrorResponse  "Error code: internal_error"
+// @Router       /users/me/addresses [post]
+// @Security     BearerAuth
+func (h *AddressHandler) CreateAddress(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	var req dto.AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	resp, err := h.addressService.CreateAddress(userID, &req)
+	if err != nil {
+		respondAddressError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// UpdateAddress replaces one of the authenticated user's addresses
+//
+// @Summary      Update address
+// @Description  Replaces the fields of a saved address. Sending is_default=true makes it the default address.
+// @Tags         addresses
+// @Accept       json
+// @Produce      json
+// @Param        addressID  path      string              true  "Address public ID"
+// @Param        input      body      dto.AddressRequest  true  "Address"
+// @Success      200  {object}  dto.AddressResponse
+// @Failure      400  {object}  dto.ErrorResponse  "Error code: invalid_request or invalid_postal_code or invalid_uf or invalid_address"
+// @Failure      401  {object}  dto.ErrorResponse  "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse  "Error code: address_not_found"
+// @Failure      500  {object}  dto.ErrorResponse  "Error code: internal_error"
+// @Router       /users/me/addresses/{addressID} [put]
+// @Security     BearerAuth
+func (h *AddressHandler) UpdateAddress(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	var req dto.AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	resp, err := h.addressService.UpdateAddress(userID, c.Param("addressID"), &req)
+	if err != nil {
+		respondAddressError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteAddress removes one of the authenticated user's addresses
+//
+// @Summary      Delete address
+// @Description  Removes a saved address. If it was the default, the most recently updated remaining address becomes the default. Existing orders are not affected.
+// @Tags         addresses
+// @Produce      json
+// @Param        addressID  path      string  true  "Address public ID"
+// @Success      200  {object}  dto.MessageResponse
+// @Failure      401  {object}  dto.ErrorResponse  "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse  "Error code: address_not_found"
+// @Failure      500  {object}  dto.ErrorResponse  "Error code: internal_error"
+// @Router       /users/me/addresses/{addressID} [delete]
+// @Security     BearerAuth
+func (h *AddressHandler) DeleteAddress(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	if err := h.addressService.DeleteAddress(userID, c.Param("addressID")); err != nil {
+		respondAddressError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "Address deleted successfully"})
+}
+
+func respondAddressError(c *gin.Context, err error) {
+	if status, code, ok := handlererrors.MapServiceError(err); ok {
+		c.JSON(status, dto.ErrorResponse{Error: code})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+}
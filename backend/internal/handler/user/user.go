@@ -8,6 +8,7 @@ import (
 	"github.com/leoferamos/aroma-sense/internal/auth"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	handlererrors "github.com/leoferamos/aroma-sense/internal/handler/errors"
+	"github.com/leoferamos/aroma-sense/internal/model"
 	authservice "github.com/leoferamos/aroma-sense/internal/service/auth"
 	chatservice "github.com/leoferamos/aroma-sense/internal/service/chat"
 	lgpdservice "github.com/leoferamos/aroma-sense/internal/service/lgpd"
@@ -34,7 +35,7 @@ func (h *UserHandler) UserProfile() userservice.UserProfileService {
 // RegisterUser handles user registration requests.
 //
 // @Summary      Register a new user
-// @Description  Creates a new user account with the provided information.
+// @Description  Creates a new user account with the provided information. Items in the guest session cart are moved into the new account.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -50,6 +51,7 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 	}
 
 	input.Email = strings.ToLower(input.Email)
+	input.GuestID = auth.GuestIDFromCookie(c)
 
 	if err := h.authService.RegisterUser(input); err != nil {
 		if status, code, ok := handlererrors.MapServiceError(err); ok {
@@ -66,7 +68,7 @@ func (h *UserHandler) RegisterUser(c *gin.Context) {
 // LoginUser handles user authentication requests.
 //
 // @Summary      Login
-// @Description  Authenticates a user and returns a JWT token and user info.
+// @Description  Authenticates a user and returns a JWT token and user info. Items in the guest session cart are merged into the user's cart.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -82,6 +84,7 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 	}
 
 	input.Email = strings.ToLower(input.Email)
+	input.GuestID = auth.GuestIDFromCookie(c)
 
 	accessToken, refreshToken, user, err := h.authService.Login(input)
 	if err != nil {
@@ -211,14 +214,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
 		return
 	}
-	resp := dto.ProfileResponse{
-		PublicID:    user.PublicID,
-		Email:       user.Email,
-		Role:        user.Role,
-		DisplayName: user.DisplayName,
-		CreatedAt:   user.CreatedAt,
-	}
-	c.JSON(http.StatusOK, resp)
+	c.JSON(http.StatusOK, profileResponse(user))
 }
 
 // UpdateProfile updates the authenticated user's display name
@@ -257,14 +253,46 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	resp := dto.ProfileResponse{
-		PublicID:    user.PublicID,
-		Email:       user.Email,
-		Role:        user.Role,
-		DisplayName: user.DisplayName,
-		CreatedAt:   user.CreatedAt,
+	c.JSON(http.StatusOK, profileResponse(user))
+}
+
+// UpdatePreferences updates the authenticated user's communication preferences
+//
+// @Summary      Update my preferences
+// @Description  Opts the user in or out of marketing emails, such as abandoned cart reminders.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        input  body  dto.UpdatePreferencesRequest  true  "Preferences update"
+// @Success      200  {object}  dto.ProfileResponse     "Updated profile"
+// @Failure      400  {object}  dto.ErrorResponse       "Error code: invalid_request"
+// @Failure      401  {object}  dto.ErrorResponse       "Error code: unauthenticated"
+// @Router       /users/me/preferences [patch]
+// @Security     BearerAuth
+func (h *UserHandler) UpdatePreferences(c *gin.Context) {
+	publicID := c.GetString("userID")
+	if publicID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
 	}
-	c.JSON(http.StatusOK, resp)
+
+	var req dto.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+		return
+	}
+
+	user, err := h.userProfileService.UpdateMarketingConsent(publicID, *req.MarketingEmails)
+	if err != nil {
+		if status, code, ok := handlererrors.MapServiceError(err); ok {
+			c.JSON(status, dto.ErrorResponse{Error: code})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profileResponse(user))
 }
 
 // ExportUserData exports all user data for GDPR compliance
@@ -465,3 +493,14 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 
 	c.JSON(http.StatusOK, dto.MessageResponse{Message: "password changed successfully"})
 }
+
+func profileResponse(user *model.User) dto.ProfileResponse {
+	return dto.ProfileResponse{
+		PublicID:        user.PublicID,
+		Email:           user.Email,
+		Role:            user.Role,
+		DisplayName:     user.DisplayName,
+		MarketingEmails: user.MarketingOptIn,
+		CreatedAt:       user.CreatedAt,
+	}
+}
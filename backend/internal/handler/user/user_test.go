@@ -184,6 +184,15 @@ func (m *MockUserProfileService) UpdateDisplayName(publicID string, displayName
 	return user, args.Error(1)
 }
 
+func (m *MockUserProfileService) UpdateMarketingConsent(publicID string, optIn bool) (*model.User, error) {
+	args := m.Called(publicID, optIn)
+	var user *model.User
+	if args.Get(0) != nil {
+		user = args.Get(0).(*model.User)
+	}
+	return user, args.Error(1)
+}
+
 func (m *MockUserProfileService) SetPasswordHash(publicID string, hashedPassword string) error {
 	args := m.Called(publicID, hashedPassword)
 	return args.Error(0)
@@ -323,6 +332,43 @@ func TestUpdateProfile(t *testing.T) {
 	mockProfile.AssertExpectations(t)
 }
 
+func TestUpdatePreferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("opts in to marketing emails", func(t *testing.T) {
+		mockProfile := new(MockUserProfileService)
+		user := &model.User{PublicID: "uuid", Email: "test@example.com", Role: "client", MarketingOptIn: true}
+		mockProfile.On("UpdateMarketingConsent", "uuid", true).Return(user, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("PATCH", "/users/me/preferences", bytes.NewBufferString(`{"marketing_emails":true}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("userID", "uuid")
+		handler := handler.NewUserHandler(new(MockAuthService), mockProfile, new(MockLgpdService), new(MockChatService))
+		handler.UpdatePreferences(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"marketing_emails":true`)
+		mockProfile.AssertExpectations(t)
+	})
+
+	t.Run("requires the flag", func(t *testing.T) {
+		mockProfile := new(MockUserProfileService)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("PATCH", "/users/me/preferences", bytes.NewBufferString(`{}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("userID", "uuid")
+		handler := handler.NewUserHandler(new(MockAuthService), mockProfile, new(MockLgpdService), new(MockChatService))
+		handler.UpdatePreferences(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockProfile.AssertNotCalled(t, "UpdateMarketingConsent")
+	})
+}
+
 func ptr(s string) *string { return &s }
 
 func performRequest(t *testing.T, router *gin.Engine, method, url string, payload interface{}) *httptest.ResponseRecorder {
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	handlererrors "github.com/leoferamos/aroma-sense/internal/handler/errors"
+	wishlistservice "github.com/leoferamos/aroma-sense/internal/service/wishlist"
+)
+
+// WishlistHandler serves the authenticated user's wishlist
+type WishlistHandler struct {
+	wishlistService wishlistservice.WishlistService
+}
+
+// NewWishlistHandler creates a new instance of WishlistHandler
+func NewWishlistHandler(wishlistService wishlistservice.WishlistService) *WishlistHandler {
+	return &WishlistHandler{wishlistService: wishlistService}
+}
+
+// GetWishlist returns the authenticated user's wishlist
+//
+// @Summary      Get wishlist
+// @Description  Returns the products the authenticated user saved for later, most recent first, with their current price and stock.
+// @Tags         wishlist
+// @Produce      json
+// @Success      200  {object}  dto.WishlistResponse
+// @Failure      401  {object}  dto.ErrorResponse  "Error code: unauthenticated"
+// @Failure      500  {object}  dto.ErrorResponse  "Error code: internal_error"
+// @Router       /users/me/wishlist [get]
+// @Security     BearerAuth
+func (h *WishlistHandler) GetWishlist(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	resp, err := h.wishlistService.GetWishlist(userID)
+	if err != nil {
+		respondWishlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// AddItem saves a product to the authenticated user's wishlist
+//
+// @Summary      Add product to wishlist
+// @Description  Saves a product at its current price. The user is emailed when it drops below that price or comes back in stock. Saving a product twice keeps the original entry.
+// @Tags         wishlist
+// @Produce      json
+// @Param        productSlug  path      string  true  "Product slug"
+// @Success      200  {object}  dto.WishlistItemResponse  "Already saved"
+// @Success      201  {object}  dto.WishlistItemResponse  "Saved"
+// @Failure      401  {object}  dto.ErrorResponse  "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse  "Error code: product_not_found"
+// @Failure      409  {object}  dto.ErrorResponse  "Error code: wishlist_limit_reached"
+// @Failure      500  {object}  dto.ErrorResponse  "Error code: internal_error"
+// @Router       /users/me/wishlist/{productSlug} [put]
+// @Security     BearerAuth
+func (h *WishlistHandler) AddItem(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	resp, created, err := h.wishlistService.AddItem(userID, c.Param("productSlug"))
+	if err != nil {
+		respondWishlistError(c, err)
+		return
+	}
+	if created {
+		c.JSON(http.StatusCreated, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RemoveItem removes a product from the authenticated user's wishlist
+//
+// @Summary      Remove product from wishlist
+// @Description  Removes a saved product; no further alerts are sent for it.
+// @Tags         wishlist
+// @Param        productSlug  path  string  true  "Product slug"
+// @Success      204  "No Content"
+// @Failure      401  {object}  dto.ErrorResponse  "Error code: unauthenticated"
+// @Failure      404  {object}  dto.ErrorResponse  "Error code: product_not_found or wishlist_item_not_found"
+// @Failure      500  {object}  dto.ErrorResponse  "Error code: internal_error"
+// @Router       /users/me/wishlist/{productSlug} [delete]
+// @Security     BearerAuth
+func (h *WishlistHandler) RemoveItem(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthenticated"})
+		return
+	}
+
+	if err := h.wishlistService.RemoveItem(userID, c.Param("productSlug")); err != nil {
+		respondWishlistError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func respondWishlistError(c *gin.Context, err error) {
+	if status, code, ok := handlererrors.MapServiceError(err); ok {
+		c.JSON(status, dto.ErrorResponse{Error: code})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+}
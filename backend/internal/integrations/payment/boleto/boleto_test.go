@@ -0,0 +1,106 @@
+package boleto
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/febraban"
+	"github.com/leoferamos/aroma-sense/internal/integrations/payment/webhooksig"
+	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func testProvider(webhookURL string, now time.Time) *Provider {
+	return NewProvider(&Config{
+		BankCode:        "001",
+		Agreement:       "1234567",
+		BeneficiaryName: "Aroma Sense",
+		WebhookSecret:   "whsec_local",
+		DueDays:         3,
+		GraceDays:       2,
+		LocalWebhookURL: webhookURL,
+	}).WithOurNumberGenerator(func() string { return "123" }).
+		WithClock(func() time.Time { return now })
+}
+
+func TestProvider_CreatePaymentIntent(t *testing.T) {
+	t.Run("issues boleto with due date and expiry", func(t *testing.T) {
+		// Tuesday 10:00 in Brasília
+		p := testProvider("", time.Date(2025, 12, 16, 13, 0, 0, 0, time.UTC))
+
+		res, err := p.CreatePaymentIntent(context.Background(), paymentservice.PaymentIntentParams{Amount: 12990, Currency: "brl"})
+		assert.NoError(t, err)
+		assert.Equal(t, "123", res.ID)
+		assert.Equal(t, time.Date(2025, 12, 19, 0, 0, 0, 0, time.UTC), *res.DueDate)
+		assert.Equal(t, time.Date(2025, 12, 22, 2, 59, 59, 0, time.UTC), res.ExpiresAt.UTC())
+		assert.Len(t, res.BoletoBarcode, 44)
+		assert.Equal(t, "001", res.BoletoBarcode[:3])
+		assert.Equal(t, febraban.DueFactor(*res.DueDate)+"0000012990", res.BoletoBarcode[5:19])
+		assert.Equal(t, "01234567"+"00000000000000123", res.BoletoBarcode[19:])
+		line, _ := febraban.DigitableLine(res.BoletoBarcode)
+		assert.Equal(t, line, res.BoletoDigitableLine)
+		assert.Equal(t, "Aroma Sense", res.Metadata["boleto_beneficiary"])
+	})
+
+	t.Run("weekend due date moves to monday", func(t *testing.T) {
+		// Wednesday, due Saturday
+		p := testProvider("", time.Date(2025, 12, 17, 13, 0, 0, 0, time.UTC))
+
+		res, err := p.CreatePaymentIntent(context.Background(), paymentservice.PaymentIntentParams{Amount: 100, Currency: "brl"})
+		assert.NoError(t, err)
+		assert.Equal(t, time.Monday, res.DueDate.Weekday())
+		assert.Equal(t, 22, res.DueDate.Day())
+	})
+}
+
+func TestProvider_ParseWebhook(t *testing.T) {
+	p := testProvider("", time.Now())
+	psp := NewLocalPSP("whsec_local", "")
+
+	payload, signature, err := psp.Notification("boleto.paid", "123", 12990, time.Now())
+	assert.NoError(t, err)
+	res, err := p.ParseWebhook(payload, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, "123", res.IntentID)
+	assert.Equal(t, "succeeded", res.Status)
+	assert.Equal(t, int64(12990), res.Amount)
+
+	payload, signature, _ = psp.Notification("boleto.expired", "123", 12990, time.Now())
+	res, err = p.ParseWebhook(payload, signature)
+	assert.NoError(t, err)
+	assert.Equal(t, "canceled", res.Status)
+
+	_, err = p.ParseWebhook(payload, "deadbeef")
+	assert.Error(t, err)
+}
+
+func TestLocalPSP_SettlesScheduledBoleto(t *testing.T) {
+	received := make(chan *paymentservice.PaymentWebhookPayload, 1)
+	var p *Provider
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		res, err := p.ParseWebhook(body, r.Header.Get(webhooksig.Header))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- res
+	}))
+	defer srv.Close()
+
+	p = testProvider(srv.URL, time.Now())
+	_, err := p.CreatePaymentIntent(context.Background(), paymentservice.PaymentIntentParams{Amount: 5000, Currency: "brl"})
+	assert.NoError(t, err)
+
+	select {
+	case res := <-received:
+		assert.Equal(t, "123", res.IntentID)
+		assert.Equal(t, "succeeded", res.Status)
+	case <-time.After(2 * time.Second):
+		t.Fatal("local PSP did not deliver the settlement")
+	}
+}
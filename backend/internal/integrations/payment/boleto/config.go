@@ -0,0 +1,90 @@
+package boleto
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultDueDays is how many days after issue a boleto falls due.
+	DefaultDueDays = 3
+	// DefaultGraceDays is how long after the due date a payment may still be reported, since banks
+	// settle boletos on the following business days.
+	DefaultGraceDays = 3
+)
+
+// Config holds the issuing bank data printed on boletos and the PSP webhook secret.
+type Config struct {
+	BankCode        string
+	Agreement       string
+	BeneficiaryName string
+	WebhookSecret   string
+	DueDays         int
+	GraceDays       int
+	// LocalWebhookURL and LocalPayAfter enable the local PSP stand-in, which reports every
+	// boleto as paid after the delay by posting a signed webhook to the URL.
+	LocalWebhookURL string
+	LocalPayAfter   time.Duration
+}
+
+// LoadConfigFromEnv reads boleto variables from environment.
+func LoadConfigFromEnv() (*Config, error) {
+	cfg := &Config{
+		BankCode:        os.Getenv("BOLETO_BANK_CODE"),
+		Agreement:       os.Getenv("BOLETO_AGREEMENT"),
+		BeneficiaryName: os.Getenv("BOLETO_BENEFICIARY_NAME"),
+		WebhookSecret:   os.Getenv("BOLETO_WEBHOOK_SECRET"),
+		DueDays:         readDays("BOLETO_DUE_DAYS", DefaultDueDays),
+		GraceDays:       readDays("BOLETO_GRACE_DAYS", DefaultGraceDays),
+		LocalWebhookURL: os.Getenv("BOLETO_LOCAL_PSP_WEBHOOK_URL"),
+	}
+
+	if cfg.BankCode == "" || cfg.Agreement == "" || cfg.BeneficiaryName == "" {
+		return nil, fmt.Errorf("BOLETO_BANK_CODE, BOLETO_AGREEMENT and BOLETO_BENEFICIARY_NAME must be set")
+	}
+	if len(cfg.BankCode) != 3 || !isDigits(cfg.BankCode) {
+		return nil, fmt.Errorf("BOLETO_BANK_CODE must have 3 digits")
+	}
+	if len(cfg.Agreement) > agreementLength || !isDigits(cfg.Agreement) {
+		return nil, fmt.Errorf("BOLETO_AGREEMENT must have up to %d digits", agreementLength)
+	}
+	if cfg.WebhookSecret == "" {
+		return nil, fmt.Errorf("BOLETO_WEBHOOK_SECRET not set")
+	}
+
+	if raw := os.Getenv("BOLETO_LOCAL_PSP_PAY_AFTER"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d >= 0 {
+			cfg.LocalPayAfter = d
+		} else {
+			log.Printf("invalid BOLETO_LOCAL_PSP_PAY_AFTER=%q, local PSP disabled", raw)
+			cfg.LocalWebhookURL = ""
+		}
+	}
+
+	return cfg, nil
+}
+
+func readDays(key string, fallback int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		log.Printf("invalid %s=%q, using default %d", key, raw, fallback)
+		return fallback
+	}
+	return n
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,81 @@
+package boleto
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/integrations/payment/webhooksig"
+)
+
+// LocalPSP stands in for the boleto PSP in development and tests. It builds settlement notifications
+// signed with the shared webhook secret and can post them to the app's webhook endpoint.
+type LocalPSP struct {
+	secret     string
+	webhookURL string
+	client     *http.Client
+
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+}
+
+// NewLocalPSP returns a stand-in that signs with secret and delivers to webhookURL.
+func NewLocalPSP(secret, webhookURL string) *LocalPSP {
+	return &LocalPSP{
+		secret:     secret,
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		pending:    map[string]*time.Timer{},
+	}
+}
+
+// Notification returns the body and signature the PSP would send for the event,
+// either "boleto.paid", "boleto.canceled" or "boleto.expired".
+func (l *LocalPSP) Notification(event, ourNumber string, amountCents int64, at time.Time) ([]byte, string, error) {
+	payload, err := json.Marshal(notification{
+		Event:       event,
+		OurNumber:   ourNumber,
+		AmountCents: amountCents,
+		PaidAt:      at.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, webhooksig.Sign(l.secret, payload), nil
+}
+
+// Pay posts a signed settlement notification for the boleto to the webhook URL.
+func (l *LocalPSP) Pay(ctx context.Context, ourNumber string, amountCents int64) error {
+	payload, _, err := l.Notification("boleto.paid", ourNumber, amountCents, time.Now())
+	if err != nil {
+		return err
+	}
+	return webhooksig.Post(ctx, l.client, l.webhookURL, l.secret, payload)
+}
+
+// schedule settles the boleto after the delay unless it is canceled first.
+func (l *LocalPSP) schedule(ourNumber string, amountCents int64, after time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending[ourNumber] = time.AfterFunc(after, func() {
+		l.mu.Lock()
+		delete(l.pending, ourNumber)
+		l.mu.Unlock()
+		if err := l.Pay(context.Background(), ourNumber, amountCents); err != nil {
+			log.Printf("local boleto psp: failed to settle %s: %v", ourNumber, err)
+		}
+	})
+}
+
+// cancel drops a scheduled settlement.
+func (l *LocalPSP) cancel(ourNumber string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t, ok := l.pending[ourNumber]; ok {
+		t.Stop()
+		delete(l.pending, ourNumber)
+	}
+}
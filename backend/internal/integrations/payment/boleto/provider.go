@@ -0,0 +1,198 @@
+package boleto
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/febraban"
+	"github.com/leoferamos/aroma-sense/internal/integrations/payment/webhooksig"
+	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
+)
+
+// ProviderName is stored on payments created through boleto.
+const ProviderName = "boleto"
+
+const (
+	agreementLength = 8
+	ourNumberLength = 17
+)
+
+// brasilia is the time zone due dates are counted in. Brazil has no daylight saving time since 2019.
+var brasilia = time.FixedZone("BRT", -3*60*60)
+
+// ErrRefundUnsupported is returned when no PSP API is available to return a boleto payment.
+var ErrRefundUnsupported = errors.New("boleto payments must be refunded by bank transfer")
+
+// Provider issues boletos with locally computed barcodes and reads the PSP's settlement notifications.
+// The free field of the barcode carries the agreement followed by the "nosso número", which doubles as the intent ID.
+type Provider struct {
+	cfg          Config
+	newOurNumber func() string
+	now          func() time.Time
+	local        *LocalPSP
+}
+
+// NewProvider returns a configured boleto Provider. When the config enables it, boletos are
+// settled by the local PSP stand-in instead of a real PSP.
+func NewProvider(cfg *Config) *Provider {
+	p := &Provider{cfg: *cfg, newOurNumber: randomOurNumber, now: time.Now}
+	if cfg.LocalWebhookURL != "" {
+		p.local = NewLocalPSP(cfg.WebhookSecret, cfg.LocalWebhookURL)
+	}
+	return p
+}
+
+// WithOurNumberGenerator overrides how boleto numbers are generated, e.g. to make them deterministic in tests.
+func (p *Provider) WithOurNumberGenerator(fn func() string) *Provider {
+	p.newOurNumber = fn
+	return p
+}
+
+// WithClock overrides the time source used for due dates.
+func (p *Provider) WithClock(now func() time.Time) *Provider {
+	p.now = now
+	return p
+}
+
+// CreatePaymentIntent issues a boleto due DueDays from now, moved to Monday when it falls on a weekend.
+// The charge expires at the end of the grace period after the due date.
+func (p *Provider) CreatePaymentIntent(ctx context.Context, params paymentservice.PaymentIntentParams) (*paymentservice.PaymentIntentResult, error) {
+	if !strings.EqualFold(params.Currency, "brl") {
+		return nil, fmt.Errorf("boleto only supports BRL, got %q", params.Currency)
+	}
+
+	issued := p.now().In(brasilia)
+	due := time.Date(issued.Year(), issued.Month(), issued.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, p.cfg.DueDays)
+	switch due.Weekday() {
+	case time.Saturday:
+		due = due.AddDate(0, 0, 2)
+	case time.Sunday:
+		due = due.AddDate(0, 0, 1)
+	}
+	last := due.AddDate(0, 0, p.cfg.GraceDays)
+	expiresAt := time.Date(last.Year(), last.Month(), last.Day(), 23, 59, 59, 0, brasilia)
+
+	ourNumber := p.newOurNumber()
+	barcode, err := febraban.Boleto{
+		BankCode:    p.cfg.BankCode,
+		DueDate:     due,
+		AmountCents: params.Amount,
+		FreeField:   leftPad(p.cfg.Agreement, agreementLength) + leftPad(ourNumber, ourNumberLength),
+	}.Barcode()
+	if err != nil {
+		return nil, fmt.Errorf("boleto barcode: %w", err)
+	}
+	line, err := febraban.DigitableLine(barcode)
+	if err != nil {
+		return nil, fmt.Errorf("boleto digitable line: %w", err)
+	}
+
+	if p.local != nil {
+		p.local.schedule(ourNumber, params.Amount, p.cfg.LocalPayAfter)
+	}
+
+	return &paymentservice.PaymentIntentResult{
+		ID:                  ourNumber,
+		BoletoBarcode:       barcode,
+		BoletoDigitableLine: line,
+		DueDate:             &due,
+		ExpiresAt:           &expiresAt,
+		Metadata: map[string]string{
+			"boleto_beneficiary": p.cfg.BeneficiaryName,
+			"boleto_payer":       params.CustomerEmail,
+		},
+	}, nil
+}
+
+// CancelPaymentIntent withdraws an unpaid boleto. Without a bank API there is nothing to write off
+// remotely, so this only stops the local stand-in from settling it.
+func (p *Provider) CancelPaymentIntent(ctx context.Context, intentID string) error {
+	if p.local != nil {
+		p.local.cancel(intentID)
+	}
+	return nil
+}
+
+// RefundPayment returns a boleto payment. Only the local stand-in can do this.
+func (p *Provider) RefundPayment(ctx context.Context, params paymentservice.RefundParams) (*paymentservice.RefundResult, error) {
+	if p.local == nil {
+		return nil, fmt.Errorf("boleto refund %s: %w", params.IntentID, ErrRefundUnsupported)
+	}
+	return &paymentservice.RefundResult{ID: "R" + params.IntentID, Status: "succeeded", Amount: params.Amount}, nil
+}
+
+// GetPaymentIntent is not available: settlements are only learned about through bank notifications.
+func (p *Provider) GetPaymentIntent(ctx context.Context, intentID string) (*paymentservice.PaymentWebhookPayload, error) {
+	return nil, fmt.Errorf("boleto %s: %w", intentID, paymentservice.ErrIntentLookupUnsupported)
+}
+
+// notification is the body the PSP posts when a boleto is settled or written off.
+type notification struct {
+	Event       string `json:"event"`
+	OurNumber   string `json:"our_number"`
+	AmountCents int64  `json:"amount_cents"`
+	PaidAt      string `json:"paid_at,omitempty"`
+}
+
+// ParseWebhook validates the HMAC-SHA256 signature and returns a normalized payload.
+func (p *Provider) ParseWebhook(payload []byte, signature string) (*paymentservice.PaymentWebhookPayload, error) {
+	if p.cfg.WebhookSecret == "" {
+		return nil, fmt.Errorf("boleto webhook secret not configured")
+	}
+	if !webhooksig.Verify(p.cfg.WebhookSecret, payload, signature) {
+		return nil, fmt.Errorf("boleto webhook: %w", paymentservice.ErrWebhookSignature)
+	}
+
+	var n notification
+	if err := json.Unmarshal(payload, &n); err != nil {
+		return nil, fmt.Errorf("boleto webhook unmarshal: %w", err)
+	}
+	if n.OurNumber == "" {
+		return nil, fmt.Errorf("boleto webhook without our_number")
+	}
+
+	var status string
+	switch n.Event {
+	case "boleto.paid":
+		status = "succeeded"
+	case "boleto.canceled", "boleto.expired":
+		status = "canceled"
+	default:
+		return nil, fmt.Errorf("boleto webhook event %s: %w", n.Event, paymentservice.ErrWebhookEventIgnored)
+	}
+
+	// Each boleto goes through every event at most once, so the pair identifies the notification.
+	return &paymentservice.PaymentWebhookPayload{
+		EventID:   n.Event + ":" + n.OurNumber,
+		EventType: n.Event,
+		IntentID:  n.OurNumber,
+		Status:    status,
+		Amount:    n.AmountCents,
+		Currency:  "brl",
+		Metadata:  map[string]string{},
+	}, nil
+}
+
+// leftPad pads s with zeros to n digits.
+func leftPad(s string, n int) string {
+	if len(s) >= n {
+		return s
+	}
+	return strings.Repeat("0", n-len(s)) + s
+}
+
+// randomOurNumber returns a random 17-digit boleto number.
+func randomOurNumber() string {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(ourNumberLength), nil)
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		panic(fmt.Sprintf("boleto: read random number: %v", err))
+	}
+	return fmt.Sprintf("%0*d", ourNumberLength, n)
+}
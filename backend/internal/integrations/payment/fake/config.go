@@ -0,0 +1,32 @@
+package fake
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Config holds the secret fake events are signed with and the seed of the generated IDs.
+type Config struct {
+	WebhookSecret string
+	// Seed is part of every intent ID. A fixed seed makes IDs reproducible across runs, e.g. in
+	// end-to-end tests against a fresh database; by default it is the start time, so IDs do not
+	// collide with payments stored by earlier runs.
+	Seed string
+}
+
+// LoadConfigFromEnv reads fake gateway variables from environment. The webhook secret is required:
+// anyone who knows it can mark payments as paid through the webhook endpoint.
+func LoadConfigFromEnv() (*Config, error) {
+	cfg := &Config{
+		WebhookSecret: os.Getenv("FAKE_PAYMENT_WEBHOOK_SECRET"),
+		Seed:          os.Getenv("FAKE_PAYMENT_SEED"),
+	}
+	if cfg.WebhookSecret == "" {
+		return nil, fmt.Errorf("FAKE_PAYMENT_WEBHOOK_SECRET not set")
+	}
+	if cfg.Seed == "" {
+		cfg.Seed = time.Now().UTC().Format("20060102150405")
+	}
+	return cfg, nil
+}
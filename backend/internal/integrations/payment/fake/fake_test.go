@@ -0,0 +1,90 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func testProvider() *Provider {
+	return NewProvider(&Config{WebhookSecret: "whsec_test", Seed: "e2e"})
+}
+
+func TestProvider_DeterministicIntents(t *testing.T) {
+	params := paymentservice.PaymentIntentParams{Amount: 4990, Currency: "brl", Metadata: map[string]string{"order_public_id": "order-1"}}
+
+	first, err := testProvider().CreatePaymentIntent(context.Background(), params)
+	assert.NoError(t, err)
+	again, err := testProvider().CreatePaymentIntent(context.Background(), params)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "pi_fake_e2e_000001", first.ID)
+	assert.Equal(t, first.ID, again.ID)
+	assert.Equal(t, first.ClientSecret, again.ClientSecret)
+	assert.Contains(t, first.ClientSecret, first.ID+"_secret_")
+
+	p := testProvider()
+	p.CreatePaymentIntent(context.Background(), params)
+	second, _ := p.CreatePaymentIntent(context.Background(), params)
+	assert.Equal(t, "pi_fake_e2e_000002", second.ID)
+}
+
+func TestProvider_SimulateWebhook(t *testing.T) {
+	p := testProvider()
+	res, _ := p.CreatePaymentIntent(context.Background(), paymentservice.PaymentIntentParams{
+		Amount: 4990, Currency: "brl", Metadata: map[string]string{"order_public_id": "order-1"},
+	})
+
+	t.Run("signed event round trip", func(t *testing.T) {
+		payload, signature, err := p.SimulateWebhook(res.ID, OutcomeSucceeded)
+		assert.NoError(t, err)
+
+		event, err := p.ParseWebhook(payload, signature)
+		assert.NoError(t, err)
+		assert.Equal(t, "evt_"+res.ID+"_1", event.EventID)
+		assert.Equal(t, "payment_intent.succeeded", event.EventType)
+		assert.Equal(t, res.ID, event.IntentID)
+		assert.Equal(t, "succeeded", event.Status)
+		assert.Equal(t, int64(4990), event.Amount)
+		assert.Equal(t, "order-1", event.Metadata["order_public_id"])
+
+		current, err := p.GetPaymentIntent(context.Background(), res.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "succeeded", current.Status)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		payload, signature, _ := p.SimulateWebhook(res.ID, OutcomeSucceeded)
+		payload[len(payload)-2] = ' '
+		_, err := p.ParseWebhook(payload, signature)
+		assert.ErrorIs(t, err, paymentservice.ErrWebhookSignature)
+	})
+
+	t.Run("unknown intent or outcome", func(t *testing.T) {
+		_, _, err := p.SimulateWebhook("pi_missing", OutcomeFailed)
+		assert.ErrorIs(t, err, ErrIntentNotFound)
+		_, _, err = p.SimulateWebhook(res.ID, "disputed")
+		assert.ErrorIs(t, err, ErrUnknownOutcome)
+	})
+}
+
+func TestProvider_CancelAndRefund(t *testing.T) {
+	p := testProvider()
+	ctx := context.Background()
+	open, _ := p.CreatePaymentIntent(ctx, paymentservice.PaymentIntentParams{Amount: 1000, Currency: "brl"})
+	paid, _ := p.CreatePaymentIntent(ctx, paymentservice.PaymentIntentParams{Amount: 1000, Currency: "brl"})
+	p.SimulateWebhook(paid.ID, OutcomeSucceeded)
+
+	assert.NoError(t, p.CancelPaymentIntent(ctx, open.ID))
+	assert.ErrorIs(t, p.CancelPaymentIntent(ctx, paid.ID), paymentservice.ErrIntentNotCancelable)
+
+	refund, err := p.RefundPayment(ctx, paymentservice.RefundParams{IntentID: paid.ID, Amount: 600})
+	assert.NoError(t, err)
+	assert.Equal(t, "re_"+paid.ID+"_1", refund.ID)
+	_, err = p.RefundPayment(ctx, paymentservice.RefundParams{IntentID: paid.ID, Amount: 600})
+	assert.Error(t, err)
+	_, err = p.RefundPayment(ctx, paymentservice.RefundParams{IntentID: open.ID, Amount: 100})
+	assert.Error(t, err)
+}
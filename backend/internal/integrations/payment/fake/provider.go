@@ -0,0 +1,250 @@
+package fake
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/leoferamos/aroma-sense/internal/integrations/payment/webhooksig"
+	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
+)
+
+// ProviderName is stored on payments created through the fake gateway.
+const ProviderName = "fake"
+
+// Outcomes that can be simulated for an intent.
+const (
+	OutcomeSucceeded  = "succeeded"
+	OutcomeFailed     = "failed"
+	OutcomeProcessing = "processing"
+	OutcomeCanceled   = "canceled"
+)
+
+// eventTypes maps each outcome to the event type it is delivered as, mirroring Stripe's names.
+var eventTypes = map[string]string{
+	OutcomeSucceeded:  "payment_intent.succeeded",
+	OutcomeFailed:     "payment_intent.payment_failed",
+	OutcomeProcessing: "payment_intent.processing",
+	OutcomeCanceled:   "payment_intent.canceled",
+}
+
+// ErrIntentNotFound is returned for intents this process did not create.
+var ErrIntentNotFound = errors.New("fake payment intent not found")
+
+// ErrUnknownOutcome is returned when simulating an outcome other than the supported ones.
+var ErrUnknownOutcome = errors.New("unknown simulated outcome")
+
+// Provider is an in-process gateway for local development and end-to-end tests. Intents live in
+// memory and only change when an outcome is simulated, which yields a webhook signed with the local
+// secret, exactly as a real gateway would deliver it.
+type Provider struct {
+	cfg Config
+
+	mu      sync.Mutex
+	seq     int
+	intents map[string]*intent
+}
+
+type intent struct {
+	amount   int64
+	currency string
+	email    string
+	metadata map[string]string
+	status   string
+	refunded int64
+	events   int
+	refunds  int
+}
+
+// NewProvider returns a fake gateway with no intents.
+func NewProvider(cfg *Config) *Provider {
+	return &Provider{cfg: *cfg, intents: map[string]*intent{}}
+}
+
+// CreatePaymentIntent registers a pending intent. IDs are numbered in creation order and client
+// secrets are derived from the ID, so both are deterministic for a given seed.
+func (p *Provider) CreatePaymentIntent(ctx context.Context, params paymentservice.PaymentIntentParams) (*paymentservice.PaymentIntentResult, error) {
+	if params.Amount <= 0 {
+		return nil, fmt.Errorf("fake create payment intent: invalid amount %d", params.Amount)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.seq++
+	id := fmt.Sprintf("pi_fake_%s_%06d", p.cfg.Seed, p.seq)
+	metadata := map[string]string{}
+	for k, v := range params.Metadata {
+		metadata[k] = v
+	}
+	p.intents[id] = &intent{
+		amount:   params.Amount,
+		currency: params.Currency,
+		email:    params.CustomerEmail,
+		metadata: metadata,
+		status:   "requires_payment_method",
+	}
+
+	return &paymentservice.PaymentIntentResult{ID: id, ClientSecret: p.clientSecret(id)}, nil
+}
+
+// CancelPaymentIntent cancels an intent unless a payment is already under way.
+func (p *Provider) CancelPaymentIntent(ctx context.Context, intentID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	in, ok := p.intents[intentID]
+	if !ok {
+		return fmt.Errorf("fake cancel payment intent %s: %w", intentID, ErrIntentNotFound)
+	}
+	switch in.status {
+	case OutcomeProcessing, OutcomeSucceeded:
+		return fmt.Errorf("fake cancel payment intent %s: %w", intentID, paymentservice.ErrIntentNotCancelable)
+	}
+	in.status = OutcomeCanceled
+	return nil
+}
+
+// RefundPayment returns part or all of a succeeded intent.
+func (p *Provider) RefundPayment(ctx context.Context, params paymentservice.RefundParams) (*paymentservice.RefundResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	in, ok := p.intents[params.IntentID]
+	if !ok {
+		return nil, fmt.Errorf("fake refund %s: %w", params.IntentID, ErrIntentNotFound)
+	}
+	if in.status != OutcomeSucceeded {
+		return nil, fmt.Errorf("fake refund %s: intent is %s", params.IntentID, in.status)
+	}
+	if params.Amount <= 0 || in.refunded+params.Amount > in.amount {
+		return nil, fmt.Errorf("fake refund %s: invalid amount %d", params.IntentID, params.Amount)
+	}
+	in.refunded += params.Amount
+	in.refunds++
+	return &paymentservice.RefundResult{
+		ID:     fmt.Sprintf("re_%s_%d", params.IntentID, in.refunds),
+		Status: "succeeded",
+		Amount: params.Amount,
+	}, nil
+}
+
+// GetPaymentIntent returns the intent's current state.
+func (p *Provider) GetPaymentIntent(ctx context.Context, intentID string) (*paymentservice.PaymentWebhookPayload, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	in, ok := p.intents[intentID]
+	if !ok {
+		return nil, fmt.Errorf("fake get payment intent %s: %w", intentID, ErrIntentNotFound)
+	}
+	return in.payload(intentID), nil
+}
+
+// SimulateWebhook moves the intent to the outcome and returns the signed event announcing it.
+func (p *Provider) SimulateWebhook(intentID string, outcome string) ([]byte, string, error) {
+	eventType, ok := eventTypes[outcome]
+	if !ok {
+		return nil, "", fmt.Errorf("fake simulate %q: %w", outcome, ErrUnknownOutcome)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	in, ok := p.intents[intentID]
+	if !ok {
+		return nil, "", fmt.Errorf("fake simulate %s: %w", intentID, ErrIntentNotFound)
+	}
+	in.status = outcome
+	in.events++
+
+	payload, err := json.Marshal(event{
+		ID:   fmt.Sprintf("evt_%s_%d", intentID, in.events),
+		Type: eventType,
+		Data: eventData{
+			ID:           intentID,
+			Status:       in.status,
+			Amount:       in.amount,
+			Currency:     in.currency,
+			ReceiptEmail: in.email,
+			Metadata:     in.metadata,
+		},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, webhooksig.Sign(p.cfg.WebhookSecret, payload), nil
+}
+
+// event is the body of a fake gateway webhook.
+type event struct {
+	ID   string    `json:"id"`
+	Type string    `json:"type"`
+	Data eventData `json:"data"`
+}
+
+type eventData struct {
+	ID           string            `json:"id"`
+	Status       string            `json:"status"`
+	Amount       int64             `json:"amount"`
+	Currency     string            `json:"currency"`
+	ReceiptEmail string            `json:"receipt_email,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+// ParseWebhook validates the HMAC-SHA256 signature and returns a normalized payload.
+func (p *Provider) ParseWebhook(payload []byte, signature string) (*paymentservice.PaymentWebhookPayload, error) {
+	if !webhooksig.Verify(p.cfg.WebhookSecret, payload, signature) {
+		return nil, fmt.Errorf("fake webhook: %w", paymentservice.ErrWebhookSignature)
+	}
+
+	var e event
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, fmt.Errorf("fake webhook unmarshal: %w", err)
+	}
+	known := false
+	for _, t := range eventTypes {
+		known = known || t == e.Type
+	}
+	if !known {
+		return nil, fmt.Errorf("fake webhook event %s: %w", e.Type, paymentservice.ErrWebhookEventIgnored)
+	}
+
+	return &paymentservice.PaymentWebhookPayload{
+		EventID:       e.ID,
+		EventType:     e.Type,
+		IntentID:      e.Data.ID,
+		Status:        e.Data.Status,
+		Amount:        e.Data.Amount,
+		Currency:      e.Data.Currency,
+		CustomerEmail: e.Data.ReceiptEmail,
+		Metadata:      e.Data.Metadata,
+	}, nil
+}
+
+func (in *intent) payload(intentID string) *paymentservice.PaymentWebhookPayload {
+	metadata := map[string]string{}
+	for k, v := range in.metadata {
+		metadata[k] = v
+	}
+	return &paymentservice.PaymentWebhookPayload{
+		IntentID:      intentID,
+		Status:        in.status,
+		Amount:        in.amount,
+		Currency:      in.currency,
+		CustomerEmail: in.email,
+		Metadata:      metadata,
+	}
+}
+
+// clientSecret derives a stable secret from the intent ID and the webhook secret.
+func (p *Provider) clientSecret(intentID string) string {
+	m := hmac.New(sha256.New, []byte(p.cfg.WebhookSecret))
+	m.Write([]byte(intentID))
+	return intentID + "_secret_" + hex.EncodeToString(m.Sum(nil))[:24]
+}
@@ -0,0 +1,138 @@
+package pix
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// EMV field IDs used by the Pix BR Code (BCB "Manual de Padrões para Iniciação do Pix").
+const (
+	idPayloadFormat       = "00"
+	idPointOfInitiation   = "01"
+	idMerchantAccount     = "26"
+	idMerchantCategory    = "52"
+	idTransactionCurrency = "53"
+	idTransactionAmount   = "54"
+	idCountryCode         = "58"
+	idMerchantName        = "59"
+	idMerchantCity        = "60"
+	idAdditionalData      = "62"
+	idCRC16               = "63"
+
+	idAccountGUI         = "00"
+	idAccountKey         = "01"
+	idAccountDescription = "02"
+	idAdditionalTxID     = "05"
+
+	pixGUI         = "br.gov.bcb.pix"
+	currencyBRL    = "986"
+	maxNameLength  = 25
+	maxCityLength  = 15
+	maxTxIDLength  = 25
+	maxFieldLength = 99
+)
+
+// BRCode describes a Pix charge encoded as an EMV merchant-presented QR payload.
+type BRCode struct {
+	Key          string
+	Description  string
+	MerchantName string
+	MerchantCity string
+	// AmountCents is optional; zero lets the payer type the amount.
+	AmountCents int64
+	// TxID identifies the charge in the PSP notification; "***" when absent.
+	TxID string
+	// OneTime marks the code as valid for a single payment.
+	OneTime bool
+}
+
+// Encode renders the copy-paste ("Pix Copia e Cola") payload, including its CRC16 checksum.
+func (b BRCode) Encode() (string, error) {
+	if b.Key == "" {
+		return "", fmt.Errorf("pix key is required")
+	}
+	if len(b.TxID) > maxTxIDLength {
+		return "", fmt.Errorf("pix txid longer than %d characters", maxTxIDLength)
+	}
+
+	account := tlv(idAccountGUI, pixGUI) + tlv(idAccountKey, b.Key)
+	if desc := sanitize(b.Description, 0); desc != "" {
+		account += tlv(idAccountDescription, desc)
+	}
+	if len(account) > maxFieldLength {
+		return "", fmt.Errorf("pix merchant account field exceeds %d characters", maxFieldLength)
+	}
+
+	txid := b.TxID
+	if txid == "" {
+		txid = "***"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tlv(idPayloadFormat, "01"))
+	if b.OneTime {
+		sb.WriteString(tlv(idPointOfInitiation, "12"))
+	}
+	sb.WriteString(tlv(idMerchantAccount, account))
+	sb.WriteString(tlv(idMerchantCategory, "0000"))
+	sb.WriteString(tlv(idTransactionCurrency, currencyBRL))
+	if b.AmountCents > 0 {
+		sb.WriteString(tlv(idTransactionAmount, fmt.Sprintf("%d.%02d", b.AmountCents/100, b.AmountCents%100)))
+	}
+	sb.WriteString(tlv(idCountryCode, "BR"))
+	sb.WriteString(tlv(idMerchantName, sanitize(b.MerchantName, maxNameLength)))
+	sb.WriteString(tlv(idMerchantCity, sanitize(b.MerchantCity, maxCityLength)))
+	sb.WriteString(tlv(idAdditionalData, tlv(idAdditionalTxID, txid)))
+
+	// The checksum covers everything up to and including its own ID and length.
+	sb.WriteString(idCRC16 + "04")
+	payload := sb.String()
+	return payload + fmt.Sprintf("%04X", crc16(payload)), nil
+}
+
+// tlv encodes one EMV field as ID, two-digit length and value.
+func tlv(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// crc16 computes CRC16-CCITT-FALSE (polynomial 0x1021, initial value 0xFFFF).
+func crc16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// sanitize strips accents and anything outside printable ASCII, since banking apps reject them,
+// and truncates to max characters when max is positive.
+func sanitize(s string, max int) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	plain, _, err := transform.String(t, s)
+	if err != nil {
+		plain = s
+	}
+	out := make([]byte, 0, len(plain))
+	for _, r := range plain {
+		if r >= 0x20 && r < 0x7F {
+			out = append(out, byte(r))
+		}
+	}
+	result := strings.TrimSpace(string(out))
+	if max > 0 && len(result) > max {
+		result = strings.TrimSpace(result[:max])
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package pix
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// DefaultExpiry is how long a generated BR Code can be paid.
+const DefaultExpiry = 30 * time.Minute
+
+// Config holds the receiving Pix key, the merchant data printed in the BR Code and the PSP webhook secret.
+type Config struct {
+	Key           string
+	MerchantName  string
+	MerchantCity  string
+	WebhookSecret string
+	Expiry        time.Duration
+	// LocalWebhookURL and LocalConfirmAfter enable the local PSP stand-in, which confirms every
+	// charge on its own after the delay by posting a signed webhook to the URL.
+	LocalWebhookURL   string
+	LocalConfirmAfter time.Duration
+}
+
+// LoadConfigFromEnv reads Pix variables from environment.
+func LoadConfigFromEnv() (*Config, error) {
+	cfg := &Config{
+		Key:             os.Getenv("PIX_KEY"),
+		MerchantName:    os.Getenv("PIX_MERCHANT_NAME"),
+		MerchantCity:    os.Getenv("PIX_MERCHANT_CITY"),
+		WebhookSecret:   os.Getenv("PIX_WEBHOOK_SECRET"),
+		Expiry:          DefaultExpiry,
+		LocalWebhookURL: os.Getenv("PIX_LOCAL_PSP_WEBHOOK_URL"),
+	}
+
+	if cfg.Key == "" || cfg.MerchantName == "" || cfg.MerchantCity == "" {
+		return nil, fmt.Errorf("PIX_KEY, PIX_MERCHANT_NAME and PIX_MERCHANT_CITY must be set")
+	}
+	if cfg.WebhookSecret == "" {
+		return nil, fmt.Errorf("PIX_WEBHOOK_SECRET not set")
+	}
+
+	if raw := os.Getenv("PIX_EXPIRY"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			cfg.Expiry = d
+		} else {
+			log.Printf("invalid PIX_EXPIRY=%q, using default %s", raw, DefaultExpiry)
+		}
+	}
+	if raw := os.Getenv("PIX_LOCAL_PSP_CONFIRM_AFTER"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d >= 0 {
+			cfg.LocalConfirmAfter = d
+		} else {
+			log.Printf("invalid PIX_LOCAL_PSP_CONFIRM_AFTER=%q, local PSP disabled", raw)
+			cfg.LocalWebhookURL = ""
+		}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,84 @@
+package pix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/integrations/payment/webhooksig"
+)
+
+// LocalPSP stands in for the Pix PSP in development and tests. It builds payment notifications
+// signed with the shared webhook secret and can post them to the app's webhook endpoint.
+type LocalPSP struct {
+	secret     string
+	webhookURL string
+	client     *http.Client
+
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+}
+
+// NewLocalPSP returns a stand-in that signs with secret and delivers to webhookURL.
+func NewLocalPSP(secret, webhookURL string) *LocalPSP {
+	return &LocalPSP{
+		secret:     secret,
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		pending:    map[string]*time.Timer{},
+	}
+}
+
+// Notification returns the body and signature the PSP would send once the charge is paid.
+func (l *LocalPSP) Notification(txid string, amountCents int64, paidAt time.Time) ([]byte, string, error) {
+	body := map[string]any{
+		"pix": []map[string]string{{
+			"endToEndId": "E00000000" + paidAt.UTC().Format("200601021504") + randomString(11),
+			"txid":       txid,
+			"valor":      fmt.Sprintf("%d.%02d", amountCents/100, amountCents%100),
+			"horario":    paidAt.UTC().Format(time.RFC3339),
+		}},
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, webhooksig.Sign(l.secret, payload), nil
+}
+
+// Pay posts a signed payment notification for the charge to the webhook URL.
+func (l *LocalPSP) Pay(ctx context.Context, txid string, amountCents int64) error {
+	payload, _, err := l.Notification(txid, amountCents, time.Now())
+	if err != nil {
+		return err
+	}
+	return webhooksig.Post(ctx, l.client, l.webhookURL, l.secret, payload)
+}
+
+// schedule confirms the charge after the delay unless it is canceled first.
+func (l *LocalPSP) schedule(txid string, amountCents int64, after time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending[txid] = time.AfterFunc(after, func() {
+		l.mu.Lock()
+		delete(l.pending, txid)
+		l.mu.Unlock()
+		if err := l.Pay(context.Background(), txid, amountCents); err != nil {
+			log.Printf("local pix psp: failed to confirm %s: %v", txid, err)
+		}
+	})
+}
+
+// cancel drops a scheduled confirmation.
+func (l *LocalPSP) cancel(txid string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t, ok := l.pending[txid]; ok {
+		t.Stop()
+		delete(l.pending, txid)
+	}
+}
@@ -0,0 +1,150 @@
+package pix
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/integrations/payment/webhooksig"
+	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBRCodeEncode(t *testing.T) {
+	t.Run("matches the BCB static example", func(t *testing.T) {
+		code, err := BRCode{
+			Key:          "123e4567-e12b-12d1-a456-426655440000",
+			MerchantName: "Fulano de Tal",
+			MerchantCity: "BRASILIA",
+		}.Encode()
+		assert.NoError(t, err)
+		assert.Equal(t, "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D", code)
+	})
+
+	t.Run("one-time charge with amount and txid", func(t *testing.T) {
+		code, err := BRCode{
+			Key:          "pix@aromasense.com",
+			MerchantName: "Aroma Sense Perfumaria Ltda ME",
+			MerchantCity: "São José dos Campos",
+			AmountCents:  12345,
+			TxID:         "ORDER123",
+			OneTime:      true,
+		}.Encode()
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(code, "000201010212"))
+		assert.Contains(t, code, "5406123.45")
+		assert.Contains(t, code, "5925Aroma Sense Perfumaria L")
+		assert.Contains(t, code, "6015Sao Jose dos Ca")
+		assert.Contains(t, code, "62120508ORDER123")
+		body, crc := code[:len(code)-4], code[len(code)-4:]
+		assert.Equal(t, fmt.Sprintf("%04X", crc16(body)), crc)
+	})
+
+	t.Run("rejects long txid", func(t *testing.T) {
+		_, err := BRCode{Key: "k", TxID: strings.Repeat("A", 26)}.Encode()
+		assert.Error(t, err)
+	})
+}
+
+func testProvider(webhookURL string) *Provider {
+	return NewProvider(&Config{
+		Key:             "pix@aromasense.com",
+		MerchantName:    "Aroma Sense",
+		MerchantCity:    "Sao Paulo",
+		WebhookSecret:   "whsec_local",
+		Expiry:          15 * time.Minute,
+		LocalWebhookURL: webhookURL,
+	}).WithTxIDGenerator(func() string { return "TX0001" }).
+		WithClock(func() time.Time { return time.Date(2025, 12, 16, 12, 0, 0, 0, time.UTC) })
+}
+
+func TestProvider_CreatePaymentIntent(t *testing.T) {
+	p := testProvider("")
+
+	res, err := p.CreatePaymentIntent(context.Background(), paymentservice.PaymentIntentParams{Amount: 4990, Currency: "brl"})
+	assert.NoError(t, err)
+	assert.Equal(t, "TX0001", res.ID)
+	assert.Contains(t, res.PixCode, "540549.90")
+	assert.True(t, bytes.HasPrefix(res.PixQRCodePNG, []byte("\x89PNG")))
+	assert.Equal(t, time.Date(2025, 12, 16, 12, 15, 0, 0, time.UTC), *res.ExpiresAt)
+
+	_, err = p.CreatePaymentIntent(context.Background(), paymentservice.PaymentIntentParams{Amount: 4990, Currency: "usd"})
+	assert.Error(t, err)
+}
+
+func TestProvider_ParseWebhook(t *testing.T) {
+	p := testProvider("")
+	psp := NewLocalPSP("whsec_local", "")
+
+	t.Run("signed notification", func(t *testing.T) {
+		payload, signature, err := psp.Notification("TX0001", 4990, time.Now())
+		assert.NoError(t, err)
+
+		res, err := p.ParseWebhook(payload, signature)
+		assert.NoError(t, err)
+		assert.Equal(t, "TX0001", res.IntentID)
+		assert.Equal(t, "succeeded", res.Status)
+		assert.Equal(t, int64(4990), res.Amount)
+		assert.Equal(t, "brl", res.Currency)
+		assert.NotEmpty(t, res.Metadata["end_to_end_id"])
+		assert.Equal(t, res.Metadata["end_to_end_id"], res.EventID)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		payload, signature, _ := NewLocalPSP("other", "").Notification("TX0001", 4990, time.Now())
+		_, err := p.ParseWebhook(payload, signature)
+		assert.ErrorIs(t, err, paymentservice.ErrWebhookSignature)
+	})
+
+	t.Run("batched notifications", func(t *testing.T) {
+		payload := []byte(`{"pix":[{"txid":"A","valor":"1.00"},{"txid":"B","valor":"2.00"}]}`)
+		_, err := p.ParseWebhook(payload, webhooksig.Sign("whsec_local", payload))
+		assert.Error(t, err)
+	})
+
+	t.Run("malformed or non-positive amounts", func(t *testing.T) {
+		for _, valor := range []string{"1.-5", "-1.00", "0.00", "abc"} {
+			payload := []byte(`{"pix":[{"txid":"A","endToEndId":"E1","valor":"` + valor + `"}]}`)
+			_, err := p.ParseWebhook(payload, webhooksig.Sign("whsec_local", payload))
+			assert.Error(t, err, valor)
+		}
+	})
+}
+
+func TestLocalPSP_ConfirmsScheduledCharge(t *testing.T) {
+	received := make(chan *paymentservice.PaymentWebhookPayload, 1)
+	var p *Provider
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		res, err := p.ParseWebhook(body, r.Header.Get(webhooksig.Header))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- res
+	}))
+	defer srv.Close()
+
+	p = testProvider(srv.URL)
+	_, err := p.CreatePaymentIntent(context.Background(), paymentservice.PaymentIntentParams{Amount: 1500, Currency: "brl"})
+	assert.NoError(t, err)
+
+	select {
+	case res := <-received:
+		assert.Equal(t, "TX0001", res.IntentID)
+		assert.Equal(t, int64(1500), res.Amount)
+	case <-time.After(2 * time.Second):
+		t.Fatal("local PSP did not deliver the confirmation")
+	}
+
+	_, err = p.RefundPayment(context.Background(), paymentservice.RefundParams{IntentID: "TX0001", Amount: 1500})
+	assert.NoError(t, err)
+	_, err = testProvider("").RefundPayment(context.Background(), paymentservice.RefundParams{IntentID: "TX0001", Amount: 1500})
+	assert.ErrorIs(t, err, ErrRefundUnsupported)
+}
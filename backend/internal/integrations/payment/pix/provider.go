@@ -0,0 +1,187 @@
+package pix
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+
+	"github.com/leoferamos/aroma-sense/internal/integrations/payment/webhooksig"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
+)
+
+// ProviderName is stored on payments created through Pix.
+const ProviderName = "pix"
+
+// qrCodeSize is the side of the generated QR code PNG, in pixels.
+const qrCodeSize = 256
+
+// ErrRefundUnsupported is returned when no PSP API is available to return a Pix payment.
+var ErrRefundUnsupported = errors.New("pix refunds must be issued from the PSP")
+
+// Provider issues Pix charges as locally generated BR Codes and reads the PSP's payment notifications.
+type Provider struct {
+	cfg     Config
+	newTxID func() string
+	now     func() time.Time
+	local   *LocalPSP
+}
+
+// NewProvider returns a configured Pix Provider. When the config enables it, charges are
+// confirmed by the local PSP stand-in instead of a real PSP.
+func NewProvider(cfg *Config) *Provider {
+	p := &Provider{cfg: *cfg, newTxID: randomTxID, now: time.Now}
+	if p.cfg.Expiry <= 0 {
+		p.cfg.Expiry = DefaultExpiry
+	}
+	if cfg.LocalWebhookURL != "" {
+		p.local = NewLocalPSP(cfg.WebhookSecret, cfg.LocalWebhookURL)
+	}
+	return p
+}
+
+// WithTxIDGenerator overrides how charge identifiers are generated, e.g. to make them deterministic in tests.
+func (p *Provider) WithTxIDGenerator(fn func() string) *Provider {
+	p.newTxID = fn
+	return p
+}
+
+// WithClock overrides the time source used for expiry.
+func (p *Provider) WithClock(now func() time.Time) *Provider {
+	p.now = now
+	return p
+}
+
+// CreatePaymentIntent generates a one-time BR Code for the amount. The txid doubles as the intent ID
+// because it is the only reference the PSP sends back when the charge is paid.
+func (p *Provider) CreatePaymentIntent(ctx context.Context, params paymentservice.PaymentIntentParams) (*paymentservice.PaymentIntentResult, error) {
+	if !strings.EqualFold(params.Currency, "brl") {
+		return nil, fmt.Errorf("pix only supports BRL, got %q", params.Currency)
+	}
+
+	txid := p.newTxID()
+	code, err := BRCode{
+		Key:          p.cfg.Key,
+		MerchantName: p.cfg.MerchantName,
+		MerchantCity: p.cfg.MerchantCity,
+		AmountCents:  params.Amount,
+		TxID:         txid,
+		OneTime:      true,
+	}.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("pix encode brcode: %w", err)
+	}
+
+	png, err := qrcode.Encode(code, qrcode.Medium, qrCodeSize)
+	if err != nil {
+		return nil, fmt.Errorf("pix render qr code: %w", err)
+	}
+
+	expiresAt := p.now().Add(p.cfg.Expiry)
+	if p.local != nil {
+		p.local.schedule(txid, params.Amount, p.cfg.LocalConfirmAfter)
+	}
+
+	return &paymentservice.PaymentIntentResult{
+		ID:           txid,
+		PixCode:      code,
+		PixQRCodePNG: png,
+		ExpiresAt:    &expiresAt,
+	}, nil
+}
+
+// CancelPaymentIntent withdraws a charge. A static BR Code cannot be revoked at the PSP, so this only
+// stops the local stand-in from confirming it; payments arriving later are reported by the webhook.
+func (p *Provider) CancelPaymentIntent(ctx context.Context, intentID string) error {
+	if p.local != nil {
+		p.local.cancel(intentID)
+	}
+	return nil
+}
+
+// RefundPayment returns a Pix payment. Only the local stand-in can do this; real devolutions go through the PSP.
+func (p *Provider) RefundPayment(ctx context.Context, params paymentservice.RefundParams) (*paymentservice.RefundResult, error) {
+	if p.local == nil {
+		return nil, fmt.Errorf("pix refund %s: %w", params.IntentID, ErrRefundUnsupported)
+	}
+	return &paymentservice.RefundResult{ID: "D" + params.IntentID, Status: "succeeded", Amount: params.Amount}, nil
+}
+
+// GetPaymentIntent is not available: payments are only learned about through PSP notifications.
+func (p *Provider) GetPaymentIntent(ctx context.Context, intentID string) (*paymentservice.PaymentWebhookPayload, error) {
+	return nil, fmt.Errorf("pix charge %s: %w", intentID, paymentservice.ErrIntentLookupUnsupported)
+}
+
+// notification is the body the PSP posts when charges are paid (BCB Pix API webhook format).
+type notification struct {
+	Pix []struct {
+		EndToEndID string `json:"endToEndId"`
+		TxID       string `json:"txid"`
+		Valor      string `json:"valor"`
+		Horario    string `json:"horario"`
+	} `json:"pix"`
+}
+
+// ParseWebhook validates the HMAC-SHA256 signature and returns a normalized payload.
+func (p *Provider) ParseWebhook(payload []byte, signature string) (*paymentservice.PaymentWebhookPayload, error) {
+	if p.cfg.WebhookSecret == "" {
+		return nil, fmt.Errorf("pix webhook secret not configured")
+	}
+	if !webhooksig.Verify(p.cfg.WebhookSecret, payload, signature) {
+		return nil, fmt.Errorf("pix webhook: %w", paymentservice.ErrWebhookSignature)
+	}
+
+	var n notification
+	if err := json.Unmarshal(payload, &n); err != nil {
+		return nil, fmt.Errorf("pix webhook unmarshal: %w", err)
+	}
+	// Charges are one-time codes, so PSPs are configured to notify each payment separately.
+	if len(n.Pix) != 1 {
+		return nil, fmt.Errorf("pix webhook must carry exactly one payment, got %d", len(n.Pix))
+	}
+	paid := n.Pix[0]
+	if paid.TxID == "" {
+		return nil, fmt.Errorf("pix webhook payment without txid")
+	}
+	amount, err := money.Parse(paid.Valor)
+	if err != nil {
+		return nil, fmt.Errorf("pix webhook amount: %w", err)
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("pix webhook amount: invalid amount %q", paid.Valor)
+	}
+
+	return &paymentservice.PaymentWebhookPayload{
+		EventID:   paid.EndToEndID,
+		EventType: "pix.received",
+		IntentID:  paid.TxID,
+		Status:    "succeeded",
+		Amount:    amount.Cents(),
+		Currency:  "brl",
+		Metadata:  map[string]string{"end_to_end_id": paid.EndToEndID},
+	}, nil
+}
+
+const txIDAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// randomTxID returns a 25-character alphanumeric txid, the longest a static BR Code accepts.
+func randomTxID() string {
+	return randomString(maxTxIDLength)
+}
+
+func randomString(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("pix: read random bytes: %v", err))
+	}
+	for i := range buf {
+		buf[i] = txIDAlphabet[int(buf[i])%len(txIDAlphabet)]
+	}
+	return string(buf)
+}
@@ -3,10 +3,12 @@ package stripe
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	stripe "github.com/stripe/stripe-go/v78"
 	"github.com/stripe/stripe-go/v78/paymentintent"
+	"github.com/stripe/stripe-go/v78/refund"
 	"github.com/stripe/stripe-go/v78/webhook"
 
 	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
@@ -43,6 +45,59 @@ func (p *Provider) CreatePaymentIntent(ctx context.Context, params paymentservic
 	return &paymentservice.PaymentIntentResult{ID: intent.ID, ClientSecret: intent.ClientSecret}, nil
 }
 
+// CancelPaymentIntent cancels an intent that has not been paid yet.
+func (p *Provider) CancelPaymentIntent(ctx context.Context, intentID string) error {
+	_, err := paymentintent.Cancel(intentID, &stripe.PaymentIntentCancelParams{
+		CancellationReason: stripe.String(string(stripe.PaymentIntentCancellationReasonAbandoned)),
+	})
+	if err != nil {
+		var stripeErr *stripe.Error
+		if errors.As(err, &stripeErr) && stripeErr.Code == stripe.ErrorCodePaymentIntentUnexpectedState {
+			return fmt.Errorf("stripe cancel payment intent %s: %w", intentID, paymentservice.ErrIntentNotCancelable)
+		}
+		return fmt.Errorf("stripe cancel payment intent: %w", err)
+	}
+	return nil
+}
+
+// GetPaymentIntent fetches an intent and returns its current state in webhook form.
+func (p *Provider) GetPaymentIntent(ctx context.Context, intentID string) (*paymentservice.PaymentWebhookPayload, error) {
+	pi, err := paymentintent.Get(intentID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("stripe get payment intent: %w", err)
+	}
+	return &paymentservice.PaymentWebhookPayload{
+		IntentID:      pi.ID,
+		Status:        string(pi.Status),
+		Amount:        pi.Amount,
+		Currency:      string(pi.Currency),
+		CustomerEmail: pi.ReceiptEmail,
+		Metadata:      pi.Metadata,
+	}, nil
+}
+
+// RefundPayment refunds the given amount of a captured intent.
+func (p *Provider) RefundPayment(ctx context.Context, params paymentservice.RefundParams) (*paymentservice.RefundResult, error) {
+	metadata := map[string]string{}
+	for k, v := range params.Metadata {
+		metadata[k] = v
+	}
+	if params.Reason != "" {
+		metadata["reason"] = params.Reason
+	}
+
+	r, err := refund.New(&stripe.RefundParams{
+		PaymentIntent: stripe.String(params.IntentID),
+		Amount:        stripe.Int64(params.Amount),
+		Reason:        stripe.String(string(stripe.RefundReasonRequestedByCustomer)),
+		Metadata:      metadata,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("stripe create refund: %w", err)
+	}
+	return &paymentservice.RefundResult{ID: r.ID, Status: string(r.Status), Amount: r.Amount}, nil
+}
+
 // ParseWebhook validates signature and returns a normalized payload.
 func (p *Provider) ParseWebhook(payload []byte, signature string) (*paymentservice.PaymentWebhookPayload, error) {
 	if p.webhookSecret == "" {
@@ -50,7 +105,7 @@ func (p *Provider) ParseWebhook(payload []byte, signature string) (*paymentservi
 	}
 	event, err := webhook.ConstructEventWithOptions(payload, signature, p.webhookSecret, webhook.ConstructEventOptions{IgnoreAPIVersionMismatch: true})
 	if err != nil {
-		return nil, fmt.Errorf("stripe webhook validation failed: %w", err)
+		return nil, fmt.Errorf("stripe webhook validation failed: %w: %w", paymentservice.ErrWebhookSignature, err)
 	}
 
 	switch event.Type {
@@ -61,6 +116,8 @@ func (p *Provider) ParseWebhook(payload []byte, signature string) (*paymentservi
 		}
 		status := string(pi.Status)
 		return &paymentservice.PaymentWebhookPayload{
+			EventID:       event.ID,
+			EventType:     string(event.Type),
 			IntentID:      pi.ID,
 			Status:        status,
 			Amount:        pi.Amount,
@@ -74,6 +131,12 @@ func (p *Provider) ParseWebhook(payload []byte, signature string) (*paymentservi
 			return nil, fmt.Errorf("stripe webhook unmarshal charge: %w", err)
 		}
 		status := string(ch.Status)
+		if event.Type == "charge.refunded" {
+			status = "partially_refunded"
+			if ch.Refunded {
+				status = "refunded"
+			}
+		}
 		intentID := ""
 		if ch.PaymentIntent != nil {
 			intentID = ch.PaymentIntent.ID
@@ -83,14 +146,17 @@ func (p *Provider) ParseWebhook(payload []byte, signature string) (*paymentservi
 			meta[k] = v
 		}
 		return &paymentservice.PaymentWebhookPayload{
-			IntentID:      intentID,
-			Status:        status,
-			Amount:        ch.Amount,
-			Currency:      string(ch.Currency),
-			CustomerEmail: ch.ReceiptEmail,
-			Metadata:      meta,
+			EventID:        event.ID,
+			EventType:      string(event.Type),
+			IntentID:       intentID,
+			Status:         status,
+			Amount:         ch.Amount,
+			Currency:       string(ch.Currency),
+			CustomerEmail:  ch.ReceiptEmail,
+			Metadata:       meta,
+			RefundedAmount: ch.AmountRefunded,
 		}, nil
 	default:
-		return nil, fmt.Errorf("unsupported webhook event: %s", event.Type)
+		return nil, fmt.Errorf("stripe webhook event %s: %w", event.Type, paymentservice.ErrWebhookEventIgnored)
 	}
 }
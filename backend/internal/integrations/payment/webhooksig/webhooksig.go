@@ -0,0 +1,56 @@
+// Package webhooksig signs and verifies webhook bodies for gateways that use a shared HMAC secret.
+package webhooksig
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Header carries the hex HMAC-SHA256 of a webhook body.
+const Header = "X-Webhook-Signature"
+
+// Sign returns the hex HMAC-SHA256 of the payload under secret.
+func Sign(secret string, payload []byte) string {
+	return hex.EncodeToString(mac(secret, payload))
+}
+
+// Verify reports whether signature is the valid hex HMAC-SHA256 of the payload under secret.
+func Verify(secret string, payload []byte, signature string) bool {
+	expected, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(expected, mac(secret, payload))
+}
+
+// Post signs the payload and delivers it to url, as local gateway stand-ins do.
+func Post(ctx context.Context, client *http.Client, url, secret string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(Header, Sign(secret, payload))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("deliver webhook: endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func mac(secret string, payload []byte) []byte {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write(payload)
+	return m.Sum(nil)
+}
@@ -1,6 +1,9 @@
 package shipping
 
-import "github.com/leoferamos/aroma-sense/internal/dto"
+import (
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
 
 // mapProviderQuotes converts provider quotes into public DTO options, filtering invalid entries.
 func mapProviderQuotes(items []providerQuote) []dto.ShippingOption {
@@ -17,7 +20,7 @@ func mapProviderQuotes(items []providerQuote) []dto.ShippingOption {
 		opts = append(opts, dto.ShippingOption{
 			Carrier:       carrier,
 			ServiceCode:   serviceCode,
-			Price:         it.Price,
+			Price:         money.FromFloat(it.Price),
 			EstimatedDays: it.DeliveryTime,
 		})
 	}
@@ -0,0 +1,55 @@
+package job
+
+import (
+	"log"
+	"time"
+
+	cartservice "github.com/leoferamos/aroma-sense/internal/service/cart"
+)
+
+// AbandonedCartReminderJob emails opted-in users about carts they left behind
+type AbandonedCartReminderJob struct {
+	recoveryService cartservice.CartRecoveryService
+}
+
+// NewAbandonedCartReminderJob creates a new abandoned cart reminder job instance
+func NewAbandonedCartReminderJob(recoveryService cartservice.CartRecoveryService) *AbandonedCartReminderJob {
+	return &AbandonedCartReminderJob{recoveryService: recoveryService}
+}
+
+// Start schedules hourly reminder runs
+func (j *AbandonedCartReminderJob) Start() {
+	log.Println("Starting abandoned cart reminder job...")
+
+	// Run initial pass
+	j.runReminders()
+
+	ticker := time.NewTicker(time.Hour)
+	go func() {
+		for {
+			<-ticker.C
+			j.runReminders()
+		}
+	}()
+
+	log.Println("Abandoned cart reminder job scheduled to run every hour")
+}
+
+// runReminders performs the actual reminder work
+func (j *AbandonedCartReminderJob) runReminders() {
+	sent, err := j.recoveryService.SendReminders()
+	if err != nil {
+		log.Printf("Error sending abandoned cart reminders: %v", err)
+		return
+	}
+	if sent > 0 {
+		log.Printf("Abandoned cart reminder run completed: %d reminders sent", sent)
+	}
+}
+
+// ManualRun allows manual triggering of the abandoned cart reminder job (for testing/admin purposes)
+func (j *AbandonedCartReminderJob) ManualRun() error {
+	log.Println("Manual abandoned cart reminder run triggered...")
+	j.runReminders()
+	return nil
+}
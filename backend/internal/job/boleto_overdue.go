@@ -0,0 +1,133 @@
+package job
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+	logservice "github.com/leoferamos/aroma-sense/internal/service/log"
+	orderservice "github.com/leoferamos/aroma-sense/internal/service/order"
+	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
+)
+
+// boletoBatchSize caps how many overdue boletos are handled per run
+const boletoBatchSize = 100
+
+// boletoProvider is the provider name stored on boleto payments
+const boletoProvider = "boleto"
+
+// BoletoOverdueJob cancels boletos that were not paid by the end of their grace period,
+// along with the orders waiting on them, and returns those orders' stock
+type BoletoOverdueJob struct {
+	paymentRepo     repository.PaymentRepository
+	orderService    orderservice.OrderService
+	paymentService  paymentservice.PaymentService
+	auditLogService logservice.AuditLogService
+}
+
+// NewBoletoOverdueJob creates a new boleto overdue job instance
+func NewBoletoOverdueJob(paymentRepo repository.PaymentRepository, orderService orderservice.OrderService, paymentService paymentservice.PaymentService, auditLogService logservice.AuditLogService) *BoletoOverdueJob {
+	return &BoletoOverdueJob{
+		paymentRepo:     paymentRepo,
+		orderService:    orderService,
+		paymentService:  paymentService,
+		auditLogService: auditLogService,
+	}
+}
+
+// Start schedules hourly overdue boleto runs
+func (j *BoletoOverdueJob) Start() {
+	log.Println("Starting overdue boleto job...")
+
+	// Run initial pass
+	j.runOverdue()
+
+	ticker := time.NewTicker(time.Hour)
+	go func() {
+		for {
+			<-ticker.C
+			j.runOverdue()
+		}
+	}()
+
+	log.Println("Overdue boleto job scheduled to run hourly")
+}
+
+// runOverdue performs the actual cancellation work
+func (j *BoletoOverdueJob) runOverdue() {
+	now := time.Now()
+	payments, err := j.paymentRepo.FindExpiredPending(boletoProvider, now, boletoBatchSize)
+	if err != nil {
+		log.Printf("Error querying overdue boletos: %v", err)
+		return
+	}
+
+	if len(payments) == 0 {
+		return
+	}
+
+	canceled := 0
+	skipped := 0
+
+	for _, p := range payments {
+		if err := j.paymentService.ExpireIntent(context.Background(), p.IntentID, "boleto_overdue"); err != nil {
+			log.Printf("Skipping overdue boleto %s: %v", p.IntentID, err)
+			skipped++
+			continue
+		}
+
+		orderCancelled := false
+		if p.OrderPublicID != nil && !j.hasOpenPayment(*p.OrderPublicID, now) {
+			if err := j.orderService.CancelUnpaidOrder(*p.OrderPublicID, "boleto overdue"); err != nil {
+				log.Printf("Error cancelling order %s for overdue boleto %s: %v", *p.OrderPublicID, p.IntentID, err)
+			} else {
+				orderCancelled = true
+			}
+		}
+
+		if j.auditLogService != nil {
+			j.auditLogService.LogSystemAction(model.AuditActionBoletoOverdue, "payment", p.IntentID,
+				map[string]interface{}{
+					"order_public_id": p.OrderPublicID,
+					"due_date":        p.DueDate,
+					"amount_cents":    p.AmountCents,
+					"order_cancelled": orderCancelled,
+				})
+		}
+
+		canceled++
+		log.Printf("Canceled overdue boleto %s", p.IntentID)
+	}
+
+	log.Printf("Overdue boleto run completed: %d canceled, %d skipped", canceled, skipped)
+}
+
+// hasOpenPayment reports whether the order still has another payment that may complete,
+// e.g. when the customer switched to a new boleto or a card after the first one lapsed
+func (j *BoletoOverdueJob) hasOpenPayment(orderPublicID string, now time.Time) bool {
+	payments, err := j.paymentRepo.FindByOrderPublicID(orderPublicID)
+	if err != nil {
+		log.Printf("Error loading payments of order %s: %v", orderPublicID, err)
+		return true
+	}
+	for _, p := range payments {
+		switch p.Status {
+		case model.PaymentStatusProcessing, model.PaymentStatusSucceeded:
+			return true
+		case model.PaymentStatusPending:
+			if p.ExpiresAt == nil || p.ExpiresAt.After(now) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ManualRun allows manual triggering of the overdue boleto job (for testing/admin purposes)
+func (j *BoletoOverdueJob) ManualRun() error {
+	log.Println("Manual overdue boleto run triggered...")
+	j.runOverdue()
+	return nil
+}
@@ -0,0 +1,68 @@
+package job
+
+import (
+	"log"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/envconfig"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+)
+
+// DefaultGuestCartTTL matches the lifetime of the guest session cookie
+const DefaultGuestCartTTL = 30 * 24 * time.Hour
+
+// GuestCartCleanupJob deletes guest carts nobody has touched within the TTL
+type GuestCartCleanupJob struct {
+	cartRepo repository.CartRepository
+	ttl      time.Duration
+}
+
+// NewGuestCartCleanupJob creates a new guest cart cleanup job instance
+func NewGuestCartCleanupJob(cartRepo repository.CartRepository, ttl time.Duration) *GuestCartCleanupJob {
+	if ttl <= 0 {
+		ttl = DefaultGuestCartTTL
+	}
+	return &GuestCartCleanupJob{cartRepo: cartRepo, ttl: ttl}
+}
+
+// GuestCartTTLFromEnv reads GUEST_CART_TTL (e.g. "720h"), falling back to the default
+func GuestCartTTLFromEnv() time.Duration {
+	return envconfig.Duration("GUEST_CART_TTL", DefaultGuestCartTTL)
+}
+
+// Start schedules the cleanup to run every hour
+func (j *GuestCartCleanupJob) Start() {
+	log.Println("Starting guest cart cleanup job...")
+
+	// Run initial pass
+	j.runCleanup()
+
+	ticker := time.NewTicker(time.Hour)
+	go func() {
+		for {
+			<-ticker.C
+			j.runCleanup()
+		}
+	}()
+
+	log.Println("Guest cart cleanup job scheduled to run every hour")
+}
+
+// runCleanup performs the actual cleanup work
+func (j *GuestCartCleanupJob) runCleanup() {
+	deleted, err := j.cartRepo.DeleteStaleGuestCarts(time.Now().Add(-j.ttl))
+	if err != nil {
+		log.Printf("Error deleting expired guest carts: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Guest cart cleanup completed: %d expired carts deleted", deleted)
+	}
+}
+
+// ManualRun allows manual triggering of the guest cart cleanup job (for testing/admin purposes)
+func (j *GuestCartCleanupJob) ManualRun() error {
+	log.Println("Manual guest cart cleanup triggered...")
+	j.runCleanup()
+	return nil
+}
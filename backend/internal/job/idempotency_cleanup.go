@@ -0,0 +1,55 @@
+package job
+
+import (
+	"log"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/repository"
+)
+
+// IdempotencyCleanupJob deletes stored Idempotency-Key responses whose replay window has passed
+type IdempotencyCleanupJob struct {
+	idempotencyRepo repository.IdempotencyKeyRepository
+}
+
+// NewIdempotencyCleanupJob creates a new idempotency cleanup job instance
+func NewIdempotencyCleanupJob(idempotencyRepo repository.IdempotencyKeyRepository) *IdempotencyCleanupJob {
+	return &IdempotencyCleanupJob{idempotencyRepo: idempotencyRepo}
+}
+
+// Start schedules the cleanup to run every hour
+func (j *IdempotencyCleanupJob) Start() {
+	log.Println("Starting idempotency key cleanup job...")
+
+	// Run initial pass
+	j.runCleanup()
+
+	ticker := time.NewTicker(time.Hour)
+	go func() {
+		for {
+			<-ticker.C
+			j.runCleanup()
+		}
+	}()
+
+	log.Println("Idempotency key cleanup job scheduled to run every hour")
+}
+
+// runCleanup performs the actual cleanup work
+func (j *IdempotencyCleanupJob) runCleanup() {
+	deleted, err := j.idempotencyRepo.DeleteExpired(time.Now())
+	if err != nil {
+		log.Printf("Error deleting expired idempotency keys: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Idempotency key cleanup completed: %d expired keys deleted", deleted)
+	}
+}
+
+// ManualRun allows manual triggering of the idempotency cleanup job (for testing/admin purposes)
+func (j *IdempotencyCleanupJob) ManualRun() error {
+	log.Println("Manual idempotency key cleanup triggered...")
+	j.runCleanup()
+	return nil
+}
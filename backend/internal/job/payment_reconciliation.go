@@ -0,0 +1,157 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+	logservice "github.com/leoferamos/aroma-sense/internal/service/log"
+	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
+)
+
+// reconcileBatchSize caps how many stale payments are checked with the gateway per run
+const reconcileBatchSize = 100
+
+// PaymentReconciliationJob is the safety net for lost webhooks: it asks the gateway for the state of
+// payments that have waited too long and applies it. Once a day it also reports orders whose
+// payments do not add up
+type PaymentReconciliationJob struct {
+	paymentRepo     repository.PaymentRepository
+	paymentService  paymentservice.PaymentService
+	auditLogService logservice.AuditLogService
+	config          paymentservice.Config
+}
+
+// NewPaymentReconciliationJob creates a new payment reconciliation job instance
+func NewPaymentReconciliationJob(paymentRepo repository.PaymentRepository, paymentService paymentservice.PaymentService, auditLogService logservice.AuditLogService, config paymentservice.Config) *PaymentReconciliationJob {
+	return &PaymentReconciliationJob{
+		paymentRepo:     paymentRepo,
+		paymentService:  paymentService,
+		auditLogService: auditLogService,
+		config:          config,
+	}
+}
+
+// Start schedules periodic reconciliation runs and the daily discrepancy report at midnight
+func (j *PaymentReconciliationJob) Start() {
+	log.Println("Starting payment reconciliation job...")
+
+	// Run initial pass
+	j.runReconcile()
+
+	ticker := time.NewTicker(j.config.ReconcileInterval)
+	go func() {
+		for {
+			<-ticker.C
+			j.runReconcile()
+		}
+	}()
+
+	go func() {
+		for {
+			time.Sleep(time.Until(nextMidnight(time.Now())))
+			j.runReport(time.Now())
+		}
+	}()
+
+	log.Printf("Payment reconciliation job scheduled to run every %s, discrepancy report daily", j.config.ReconcileInterval)
+}
+
+// runReconcile checks stale payments with their gateway
+func (j *PaymentReconciliationJob) runReconcile() {
+	payments, err := j.paymentRepo.FindStaleOpen(j.config.ReconcileProviders, time.Now().Add(-j.config.ReconcileAfter), reconcileBatchSize)
+	if err != nil {
+		log.Printf("Error querying stale payments: %v", err)
+		return
+	}
+
+	if len(payments) == 0 {
+		return
+	}
+
+	updated := 0
+	unchanged := 0
+	failed := 0
+
+	for _, p := range payments {
+		status, err := j.paymentService.ReconcileIntent(context.Background(), p.IntentID)
+		if err != nil {
+			if !errors.Is(err, paymentservice.ErrIntentLookupUnsupported) {
+				log.Printf("Error reconciling payment %s: %v", p.IntentID, err)
+			}
+			failed++
+			continue
+		}
+		if status == p.Status {
+			unchanged++
+			continue
+		}
+
+		if j.auditLogService != nil {
+			j.auditLogService.LogSystemAction(model.AuditActionPaymentReconciled, "payment", p.IntentID,
+				map[string]interface{}{
+					"provider":        p.Provider,
+					"order_public_id": p.OrderPublicID,
+					"from_status":     p.Status,
+					"to_status":       status,
+					"waited":          time.Since(p.CreatedAt).Round(time.Second).String(),
+				})
+		}
+
+		updated++
+		log.Printf("Reconciled payment %s: %s -> %s", p.IntentID, p.Status, status)
+	}
+
+	log.Printf("Payment reconciliation run completed: %d updated, %d unchanged, %d failed", updated, unchanged, failed)
+}
+
+// runReport compares the payments of the orders created on the day before now against their totals
+func (j *PaymentReconciliationJob) runReport(now time.Time) {
+	to := startOfDay(now)
+	from := to.AddDate(0, 0, -1)
+
+	report, err := j.paymentService.DiscrepancyReport(from, to)
+	if err != nil {
+		log.Printf("Error building payment discrepancy report: %v", err)
+		return
+	}
+
+	for _, d := range report.Discrepancies {
+		log.Printf("Payment discrepancy on order %s (%s): %s, expected %d cents, captured %d, refunded %d",
+			d.OrderPublicID, d.OrderStatus, d.Kind, d.ExpectedCents, d.CapturedCents, d.RefundedCents)
+	}
+
+	if j.auditLogService != nil {
+		j.auditLogService.LogSystemAction(model.AuditActionPaymentDiscrepancies, "payment_report", from.Format("2006-01-02"),
+			map[string]interface{}{
+				"from":           report.From,
+				"to":             report.To,
+				"orders_checked": report.OrdersChecked,
+				"discrepancies":  report.Discrepancies,
+			})
+	}
+
+	log.Printf("Payment discrepancy report for %s: %d orders checked, %d discrepancies",
+		from.Format("2006-01-02"), report.OrdersChecked, len(report.Discrepancies))
+}
+
+// ManualRun allows manual triggering of the payment reconciliation job (for testing/admin purposes).
+// It reconciles stale payments and reports on the previous day
+func (j *PaymentReconciliationJob) ManualRun() error {
+	log.Println("Manual payment reconciliation run triggered...")
+	j.runReconcile()
+	j.runReport(time.Now())
+	return nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func nextMidnight(t time.Time) time.Time {
+	return startOfDay(t).AddDate(0, 0, 1)
+}
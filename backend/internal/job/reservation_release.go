@@ -0,0 +1,129 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+	logservice "github.com/leoferamos/aroma-sense/internal/service/log"
+	orderservice "github.com/leoferamos/aroma-sense/internal/service/order"
+	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
+)
+
+// reservationBatchSize caps how many expired orders are handled per run
+const reservationBatchSize = 100
+
+// ReservationReleaseJob cancels unpaid orders whose stock reservation has expired and returns their stock
+type ReservationReleaseJob struct {
+	orderRepo       repository.OrderRepository
+	orderService    orderservice.OrderService
+	paymentService  paymentservice.PaymentService
+	auditLogService logservice.AuditLogService
+	interval        time.Duration
+}
+
+// NewReservationReleaseJob creates a new reservation release job instance
+func NewReservationReleaseJob(orderRepo repository.OrderRepository, orderService orderservice.OrderService, paymentService paymentservice.PaymentService, auditLogService logservice.AuditLogService, interval time.Duration) *ReservationReleaseJob {
+	if interval <= 0 {
+		interval = orderservice.DefaultReservationSweepInterval
+	}
+	return &ReservationReleaseJob{
+		orderRepo:       orderRepo,
+		orderService:    orderService,
+		paymentService:  paymentService,
+		auditLogService: auditLogService,
+		interval:        interval,
+	}
+}
+
+// Start schedules periodic reservation release runs
+func (j *ReservationReleaseJob) Start() {
+	log.Println("Starting order reservation release job...")
+
+	// Run initial pass
+	j.runRelease()
+
+	ticker := time.NewTicker(j.interval)
+	go func() {
+		for {
+			<-ticker.C
+			j.runRelease()
+		}
+	}()
+
+	log.Printf("Order reservation release job scheduled to run every %s", j.interval)
+}
+
+// runRelease performs the actual release work
+func (j *ReservationReleaseJob) runRelease() {
+	orders, err := j.orderRepo.FindExpiredReservations(time.Now(), reservationBatchSize)
+	if err != nil {
+		log.Printf("Error querying expired order reservations: %v", err)
+		return
+	}
+
+	if len(orders) == 0 {
+		return
+	}
+
+	released := 0
+	skipped := 0
+
+	for _, o := range orders {
+		// Cancel open intents first so the customer cannot pay for stock we are about to release
+		var canceledIntents []string
+		if j.paymentService != nil {
+			canceledIntents, err = j.paymentService.CancelOrderIntents(context.Background(), o.PublicID, "reservation_expired", "order reservation expired")
+			for _, intentID := range canceledIntents {
+				if j.auditLogService != nil {
+					j.auditLogService.LogSystemAction(model.AuditActionPaymentIntentCanceled, "payment", intentID,
+						map[string]interface{}{"order_public_id": o.PublicID, "reason": "reservation_expired"})
+				}
+			}
+			if err != nil {
+				if errors.Is(err, paymentservice.ErrIntentNotCancelable) {
+					log.Printf("Skipping reservation release for order %s: payment already in progress", o.PublicID)
+				} else {
+					log.Printf("Error canceling payment intents for order %s: %v", o.PublicID, err)
+				}
+				skipped++
+				continue
+			}
+		}
+
+		if err := j.orderService.ReleaseExpiredReservation(o.PublicID); err != nil {
+			log.Printf("Error releasing reservation for order %s: %v", o.PublicID, err)
+			skipped++
+			continue
+		}
+
+		items := make([]map[string]interface{}, 0, len(o.Items))
+		for _, it := range o.Items {
+			items = append(items, map[string]interface{}{"product_id": it.ProductID, "quantity": it.Quantity})
+		}
+
+		if j.auditLogService != nil {
+			j.auditLogService.LogSystemAction(model.AuditActionOrderReservationExpired, "order", o.PublicID,
+				map[string]interface{}{
+					"expired_at":               o.ReservationExpiresAt,
+					"released_items":           items,
+					"canceled_payment_intents": canceledIntents,
+				})
+		}
+
+		released++
+		log.Printf("Released expired reservation for order %s", o.PublicID)
+	}
+
+	log.Printf("Order reservation release completed: %d released, %d skipped", released, skipped)
+}
+
+// ManualRun allows manual triggering of the reservation release job (for testing/admin purposes)
+func (j *ReservationReleaseJob) ManualRun() error {
+	log.Println("Manual reservation release run triggered...")
+	j.runRelease()
+	return nil
+}
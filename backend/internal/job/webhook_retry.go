@@ -0,0 +1,81 @@
+package job
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/repository"
+	paymentservice "github.com/leoferamos/aroma-sense/internal/service/payment"
+)
+
+// webhookBatchSize caps how many webhook events are retried per run
+const webhookBatchSize = 100
+
+// WebhookRetryJob reprocesses payment webhook events whose processing failed,
+// or was interrupted by a crash, once their backoff has elapsed
+type WebhookRetryJob struct {
+	webhookRepo    repository.WebhookEventRepository
+	paymentService paymentservice.PaymentService
+}
+
+// NewWebhookRetryJob creates a new webhook retry job instance
+func NewWebhookRetryJob(webhookRepo repository.WebhookEventRepository, paymentService paymentservice.PaymentService) *WebhookRetryJob {
+	return &WebhookRetryJob{
+		webhookRepo:    webhookRepo,
+		paymentService: paymentService,
+	}
+}
+
+// Start schedules webhook retries every minute
+func (j *WebhookRetryJob) Start() {
+	log.Println("Starting webhook retry job...")
+
+	// Run initial pass
+	j.runRetry()
+
+	ticker := time.NewTicker(time.Minute)
+	go func() {
+		for {
+			<-ticker.C
+			j.runRetry()
+		}
+	}()
+
+	log.Println("Webhook retry job scheduled to run every minute")
+}
+
+// runRetry performs the actual retry work
+func (j *WebhookRetryJob) runRetry() {
+	now := time.Now()
+	events, err := j.webhookRepo.FindDue(now, now.Add(-paymentservice.WebhookStaleAfter), webhookBatchSize)
+	if err != nil {
+		log.Printf("Error querying due webhook events: %v", err)
+		return
+	}
+
+	if len(events) == 0 {
+		return
+	}
+
+	processed := 0
+	failed := 0
+
+	for _, e := range events {
+		if err := j.paymentService.ProcessWebhookEvent(context.Background(), e.ID); err != nil {
+			log.Printf("Retry of %s webhook event %s failed (attempt %d): %v", e.Provider, e.EventID, e.Attempts+1, err)
+			failed++
+			continue
+		}
+		processed++
+	}
+
+	log.Printf("Webhook retry run completed: %d processed, %d failed", processed, failed)
+}
+
+// ManualRun allows manual triggering of the webhook retry job (for testing/admin purposes)
+func (j *WebhookRetryJob) ManualRun() error {
+	log.Println("Manual webhook retry run triggered...")
+	j.runRetry()
+	return nil
+}
@@ -0,0 +1,55 @@
+package job
+
+import (
+	"log"
+	"time"
+
+	wishlistservice "github.com/leoferamos/aroma-sense/internal/service/wishlist"
+)
+
+// WishlistAlertJob emails users when products they saved drop in price or come back in stock
+type WishlistAlertJob struct {
+	wishlistService wishlistservice.WishlistService
+}
+
+// NewWishlistAlertJob creates a new wishlist alert job instance
+func NewWishlistAlertJob(wishlistService wishlistservice.WishlistService) *WishlistAlertJob {
+	return &WishlistAlertJob{wishlistService: wishlistService}
+}
+
+// Start schedules hourly alert runs
+func (j *WishlistAlertJob) Start() {
+	log.Println("Starting wishlist alert job...")
+
+	// Run initial pass
+	j.runAlerts()
+
+	ticker := time.NewTicker(time.Hour)
+	go func() {
+		for {
+			<-ticker.C
+			j.runAlerts()
+		}
+	}()
+
+	log.Println("Wishlist alert job scheduled to run every hour")
+}
+
+// runAlerts performs the actual alert work
+func (j *WishlistAlertJob) runAlerts() {
+	sent, err := j.wishlistService.ProcessAlerts()
+	if err != nil {
+		log.Printf("Error processing wishlist alerts: %v", err)
+		return
+	}
+	if sent > 0 {
+		log.Printf("Wishlist alert run completed: %d emails sent", sent)
+	}
+}
+
+// ManualRun allows manual triggering of the wishlist alert job (for testing/admin purposes)
+func (j *WishlistAlertJob) ManualRun() error {
+	log.Println("Manual wishlist alert run triggered...")
+	j.runAlerts()
+	return nil
+}
@@ -0,0 +1,219 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/envconfig"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header clients use to make a POST safe to retry.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses served from a stored result.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// DefaultIdempotencyTTL is how long a stored response can be replayed.
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL bounds how long a request holds its key before a retry may take over,
+	// in case the instance handling it died before storing a response.
+	idempotencyLockTTL = time.Minute
+)
+
+var (
+	idempotencyStore repository.IdempotencyKeyRepository
+	idempotencyTTL   = DefaultIdempotencyTTL
+)
+
+// SetIdempotencyStore sets the storage used by IdempotencyMiddleware and how long responses are kept
+func SetIdempotencyStore(store repository.IdempotencyKeyRepository, ttl time.Duration) {
+	idempotencyStore = store
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	idempotencyTTL = ttl
+}
+
+// IdempotencyTTLFromEnv reads IDEMPOTENCY_KEY_TTL (e.g. "24h"), falling back to the default
+func IdempotencyTTLFromEnv() time.Duration {
+	return envconfig.Duration("IDEMPOTENCY_KEY_TTL", DefaultIdempotencyTTL)
+}
+
+// IdempotencyMiddleware makes authenticated POST endpoints safe to retry. A request carrying an
+// Idempotency-Key runs once per user (or guest session) and key; retries with the same body get the stored response,
+// reusing the key for a different request is rejected, and a retry arriving while the first
+// attempt is still running is told to try again. Requests without the header pass through.
+func IdempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+		userID := c.GetString("userID")
+		if userID == "" {
+			userID = c.GetString("guestID")
+		}
+		if key == "" || userID == "" || idempotencyStore == nil {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_idempotency_key"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid_request"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		record := &model.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			Fingerprint: requestFingerprint(c.Request.Method, c.Request.URL.Path, body),
+			Status:      model.IdempotencyKeyInFlight,
+			LockedUntil: now.Add(idempotencyLockTTL),
+			ExpiresAt:   now.Add(idempotencyTTL),
+		}
+
+		acquired, err := acquireIdempotencyKey(c, record, now)
+		if err != nil {
+			log.Printf("idempotency: key lookup failed for user %s: %v", userID, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "internal_error"})
+			return
+		}
+		if !acquired {
+			return
+		}
+
+		writer := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer func() {
+			if r := recover(); r != nil {
+				releaseIdempotencyKey(record)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if !replayable(status) {
+			releaseIdempotencyKey(record)
+			return
+		}
+		if err := idempotencyStore.Complete(record.ID, status, writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			log.Printf("idempotency: failed to store response for key %s: %v", record.Key, err)
+		}
+	}
+}
+
+// acquireIdempotencyKey claims the key for this request. When the key is already taken it writes
+// the response itself (replay or error) and reports false.
+func acquireIdempotencyKey(c *gin.Context, record *model.IdempotencyKey, now time.Time) (bool, error) {
+	created, err := idempotencyStore.CreateIfAbsent(record)
+	if err != nil || created {
+		return created, err
+	}
+
+	existing, err := idempotencyStore.Find(record.UserID, record.Key)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil && existing.ExpiresAt.Before(now) {
+		// The old response is past its window; the key starts over.
+		if err := idempotencyStore.Delete(existing.ID); err != nil {
+			return false, err
+		}
+		if created, err = idempotencyStore.CreateIfAbsent(record); err != nil || created {
+			return created, err
+		}
+		if existing, err = idempotencyStore.Find(record.UserID, record.Key); err != nil {
+			return false, err
+		}
+	}
+	if existing == nil {
+		// Released by a failed attempt between our insert and lookup; let the client retry.
+		abortInProgress(c)
+		return false, nil
+	}
+
+	if existing.Fingerprint != record.Fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, dto.ErrorResponse{Error: "idempotency_key_reused"})
+		return false, nil
+	}
+
+	if existing.Status == model.IdempotencyKeyCompleted {
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(existing.ResponseStatus, existing.ResponseContentType, existing.ResponseBody)
+		c.Abort()
+		return false, nil
+	}
+
+	if existing.LockedUntil.Before(now) {
+		won, err := idempotencyStore.Relock(existing.ID, now, record.LockedUntil)
+		if err != nil {
+			return false, err
+		}
+		if won {
+			*record = *existing
+			return true, nil
+		}
+	}
+	abortInProgress(c)
+	return false, nil
+}
+
+func abortInProgress(c *gin.Context) {
+	c.Header("Retry-After", "1")
+	c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{Error: "idempotency_request_in_progress"})
+}
+
+// releaseIdempotencyKey forgets a request that did not produce a final answer, so a retry runs it again.
+func releaseIdempotencyKey(record *model.IdempotencyKey) {
+	if err := idempotencyStore.Delete(record.ID); err != nil {
+		log.Printf("idempotency: failed to release key %s: %v", record.Key, err)
+	}
+}
+
+// replayable reports whether a response is final. Server errors, conflicts and rate limits are
+// transient, so those requests may run again.
+func replayable(status int) bool {
+	return status < http.StatusInternalServerError && status != http.StatusConflict && status != http.StatusTooManyRequests
+}
+
+func requestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// capturingWriter keeps a copy of the response body so it can be stored for replay.
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryIdempotencyStore is an in-memory IdempotencyKeyRepository for tests
+type memoryIdempotencyStore struct {
+	mu     sync.Mutex
+	nextID uint
+	rows   map[string]*model.IdempotencyKey
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{rows: map[string]*model.IdempotencyKey{}}
+}
+
+func (s *memoryIdempotencyStore) CreateIfAbsent(record *model.IdempotencyKey) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := record.UserID + "|" + record.Key
+	if _, ok := s.rows[k]; ok {
+		return false, nil
+	}
+	s.nextID++
+	record.ID = s.nextID
+	cp := *record
+	s.rows[k] = &cp
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) Find(userID string, key string) (*model.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if row, ok := s.rows[userID+"|"+key]; ok {
+		cp := *row
+		return &cp, nil
+	}
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) byID(id uint) *model.IdempotencyKey {
+	for _, row := range s.rows {
+		if row.ID == id {
+			return row
+		}
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Relock(id uint, now time.Time, lockedUntil time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	row := s.byID(id)
+	if row == nil || row.Status != model.IdempotencyKeyInFlight || !row.LockedUntil.Before(now) {
+		return false, nil
+	}
+	row.LockedUntil = lockedUntil
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(id uint, status int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if row := s.byID(id); row != nil {
+		row.Status = model.IdempotencyKeyCompleted
+		row.ResponseStatus = status
+		row.ResponseContentType = contentType
+		row.ResponseBody = append([]byte(nil), body...)
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Delete(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, row := range s.rows {
+		if row.ID == id {
+			delete(s.rows, k)
+		}
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) DeleteExpired(now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func setupIdempotencyRouter(store *memoryIdempotencyStore, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	SetIdempotencyStore(store, time.Hour)
+	r := gin.New()
+	r.POST("/orders", func(c *gin.Context) {
+		c.Set("userID", "user-1")
+		c.Next()
+	}, IdempotencyMiddleware(), handler)
+	return r
+}
+
+func postWithKey(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddleware_NoHeaderPassesThrough(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	r := setupIdempotencyRouter(store, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"n": calls})
+	})
+
+	postWithKey(r, "", `{}`)
+	postWithKey(r, "", `{}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, store.rows)
+}
+
+func TestIdempotencyMiddleware_ReplaysStoredResponse(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	r := setupIdempotencyRouter(store, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"n": calls})
+	})
+
+	first := postWithKey(r, "abc", `{"a":1}`)
+	second := postWithKey(r, "abc", `{"a":1}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	assert.JSONEq(t, first.Body.String(), second.Body.String())
+	assert.Contains(t, second.Header().Get("Content-Type"), "application/json")
+}
+
+func TestIdempotencyMiddleware_RejectsKeyReuseWithDifferentBody(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	r := setupIdempotencyRouter(store, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	postWithKey(r, "abc", `{"a":1}`)
+	w := postWithKey(r, "abc", `{"a":2}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "idempotency_key_reused")
+}
+
+func TestIdempotencyMiddleware_InFlightDuplicateConflicts(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	var r *gin.Engine
+	var inner *httptest.ResponseRecorder
+	r = setupIdempotencyRouter(store, func(c *gin.Context) {
+		if inner == nil {
+			inner = postWithKey(r, "abc", `{"a":1}`)
+		}
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	outer := postWithKey(r, "abc", `{"a":1}`)
+
+	assert.Equal(t, http.StatusCreated, outer.Code)
+	assert.Equal(t, http.StatusConflict, inner.Code)
+	assert.Equal(t, "1", inner.Header().Get("Retry-After"))
+	assert.Contains(t, inner.Body.String(), "idempotency_request_in_progress")
+}
+
+func TestIdempotencyMiddleware_ServerErrorReleasesKey(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	r := setupIdempotencyRouter(store, func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal_error"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	first := postWithKey(r, "abc", `{"a":1}`)
+	second := postWithKey(r, "abc", `{"a":1}`)
+
+	assert.Equal(t, http.StatusInternalServerError, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Empty(t, second.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyMiddleware_TakesOverStaleLock(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	calls := 0
+	r := setupIdempotencyRouter(store, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	stale := &model.IdempotencyKey{
+		UserID:      "user-1",
+		Key:         "abc",
+		Fingerprint: requestFingerprint(http.MethodPost, "/orders", []byte(`{"a":1}`)),
+		Status:      model.IdempotencyKeyInFlight,
+		LockedUntil: time.Now().Add(-time.Second),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	_, _ = store.CreateIfAbsent(stale)
+
+	w := postWithKey(r, "abc", `{"a":1}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 1, calls)
+	row, _ := store.Find("user-1", "abc")
+	assert.Equal(t, model.IdempotencyKeyCompleted, row.Status)
+}
+
+func TestIdempotencyMiddleware_RejectsOverlongKey(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	r := setupIdempotencyRouter(store, func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	w := postWithKey(r, strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
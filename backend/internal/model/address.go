@@ -0,0 +1,63 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// PostalAddress is a structured Brazilian postal address.
+type PostalAddress struct {
+	Recipient    string `gorm:"column:recipient;size:120" json:"recipient"`
+	CEP          string `gorm:"column:cep;size:8" json:"cep"`
+	Street       string `gorm:"column:street;size:255" json:"street"`
+	Number       string `gorm:"column:number;size:20" json:"number"`
+	Complement   string `gorm:"column:complement;size:120" json:"complement,omitempty"`
+	Neighborhood string `gorm:"column:neighborhood;size:120" json:"neighborhood"`
+	City         string `gorm:"column:city;size:120" json:"city"`
+	UF           string `gorm:"column:uf;size:2" json:"uf"`
+}
+
+// IsZero reports whether no structured address was recorded.
+func (a PostalAddress) IsZero() bool {
+	return a == PostalAddress{}
+}
+
+// FormattedCEP returns the CEP as 01234-567.
+func (a PostalAddress) FormattedCEP() string {
+	if len(a.CEP) != 8 {
+		return a.CEP
+	}
+	return a.CEP[:5] + "-" + a.CEP[5:]
+}
+
+// String formats the address on one line, e.g. "Rua Example, 123, Apto 4 - Centro, São Paulo - SP, 01234-567".
+func (a PostalAddress) String() string {
+	var b strings.Builder
+	b.WriteString(a.Street)
+	b.WriteString(", ")
+	b.WriteString(a.Number)
+	if a.Complement != "" {
+		b.WriteString(", ")
+		b.WriteString(a.Complement)
+	}
+	b.WriteString(" - ")
+	b.WriteString(a.Neighborhood)
+	b.WriteString(", ")
+	b.WriteString(a.City)
+	b.WriteString(" - ")
+	b.WriteString(a.UF)
+	b.WriteString(", ")
+	b.WriteString(a.FormattedCEP())
+	return b.String()
+}
+
+// Address is an entry in a user's address book.
+type Address struct {
+	ID            uint   `gorm:"primaryKey" json:"-"`
+	PublicID      string `gorm:"type:uuid;not null;uniqueIndex;default:gen_random_uuid()" json:"public_id"`
+	UserID        string `gorm:"type:uuid;not null;index" json:"-"`
+	PostalAddress `gorm:"embedded"`
+	IsDefault     bool      `gorm:"not null;default:false" json:"is_default"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -28,6 +28,34 @@ const (
 	AuditActionReviewDeleted     AuditAction = "review_deleted"
 )
 
+// Order-related audit actions
+const (
+	AuditActionOrderStatusChanged      AuditAction = "order_status_changed"
+	AuditActionOrderReservationExpired AuditAction = "order_reservation_expired"
+	AuditActionOrderCancelledByUser    AuditAction = "order_cancelled_by_user"
+	AuditActionOrderRefunded           AuditAction = "order_refunded"
+	AuditActionPaymentIntentCanceled   AuditAction = "payment_intent_canceled"
+	AuditActionBoletoOverdue           AuditAction = "boleto_overdue"
+	AuditActionPaymentReconciled       AuditAction = "payment_reconciled"
+	AuditActionPaymentDiscrepancies    AuditAction = "payment_discrepancies"
+	AuditActionPaymentMismatch         AuditAction = "payment_mismatch"
+)
+
+// Promotion-related audit actions
+const (
+	AuditActionPromotionCreated AuditAction = "promotion_created"
+	AuditActionPromotionUpdated AuditAction = "promotion_updated"
+	AuditActionPromotionDeleted AuditAction = "promotion_deleted"
+)
+
+// Audit log severities
+const (
+	AuditSeverityInfo     = "info"
+	AuditSeverityWarning  = "warning"
+	AuditSeverityError    = "error"
+	AuditSeverityCritical = "critical"
+)
+
 // AuditLog represents an audit log entry for LGPD compliance
 type AuditLog struct {
 	ID         uint        `json:"id" gorm:"primaryKey"`
@@ -1,26 +1,36 @@
 package model
 
-import "time"
+import (
+	"time"
 
-// Cart represents a user's shopping cart.
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
+
+// Cart represents a shopping cart, owned either by a user or by a guest session.
 type Cart struct {
-	ID        uint       `gorm:"primaryKey" json:"id"`
-	UserID    string     `gorm:"size:255;not null;uniqueIndex" json:"user_id"`
-	Items     []CartItem `gorm:"foreignKey:CartID" json:"items"`
-	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt *time.Time `gorm:"index" json:"-"`
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	UserID  string `gorm:"type:uuid;default:null;uniqueIndex" json:"user_id,omitempty"`
+	GuestID string `gorm:"type:uuid;default:null;uniqueIndex" json:"-"`
+	// PromotionCode is the coupon the shopper applied; it is re-validated whenever the cart is priced
+	PromotionCode string     `gorm:"size:64" json:"promotion_code,omitempty"`
+	Items         []CartItem `gorm:"foreignKey:CartID" json:"items"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt     *time.Time `gorm:"index" json:"-"`
 }
 
-// CartItem represents an item in a shopping cart.
+// CartItem represents an item in a shopping cart. ProductID and VariantID become nil when the
+// product or variant is deleted, so the line stays in the cart and is reported as removed.
 type CartItem struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	CartID    uint      `gorm:"not null" json:"cart_id"`
-	ProductID uint      `gorm:"not null" json:"product_id"`
-	Product   *Product  `gorm:"foreignKey:ProductID" json:"product,omitempty"`
-	Quantity  int       `gorm:"not null;default:1" json:"quantity"`
-	Price     float64   `gorm:"type:decimal(10,2);not null" json:"price"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt *time.Time `gorm:"index" json:"-"`
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	CartID    uint            `gorm:"not null" json:"cart_id"`
+	ProductID *uint           `json:"product_id"`
+	Product   *Product        `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	VariantID *uint           `json:"variant_id"`
+	Variant   *ProductVariant `gorm:"foreignKey:VariantID" json:"variant,omitempty"`
+	Quantity  int             `gorm:"not null;default:1" json:"quantity"`
+	Price     money.Amount    `gorm:"column:price_cents;not null" json:"price"`
+	CreatedAt time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt *time.Time      `gorm:"index" json:"-"`
 }
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
+
+// CartReminder records an abandoned cart reminder email sent to a user.
+// CartActivityAt is the cart's last activity when the reminder was sent; reminders with the same
+// value belong to the same abandonment, and Sequence counts them from 1.
+type CartReminder struct {
+	ID             uint         `gorm:"primaryKey" json:"id"`
+	CartID         uint         `gorm:"not null" json:"cart_id"`
+	UserID         string       `gorm:"type:uuid;not null;index" json:"user_id"`
+	CartActivityAt time.Time    `gorm:"not null" json:"cart_activity_at"`
+	Sequence       int          `gorm:"not null" json:"sequence"`
+	ItemCount      int          `gorm:"not null;default:0" json:"item_count"`
+	CartValue      money.Amount `gorm:"column:cart_value_cents;not null;default:0" json:"cart_value"`
+	SentAt         time.Time    `gorm:"not null;index" json:"sent_at"`
+}
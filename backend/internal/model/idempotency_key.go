@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// IdempotencyKeyStatus tracks a keyed request from first sight to stored response.
+type IdempotencyKeyStatus string
+
+const (
+	// IdempotencyKeyInFlight marks a request that is still being handled.
+	IdempotencyKeyInFlight IdempotencyKeyStatus = "in_flight"
+	// IdempotencyKeyCompleted marks a request whose response is stored for replay.
+	IdempotencyKeyCompleted IdempotencyKeyStatus = "completed"
+)
+
+// IdempotencyKey remembers a client-supplied Idempotency-Key together with the request it
+// was first used for, so retries get the original response instead of repeating side effects.
+type IdempotencyKey struct {
+	ID                  uint                 `gorm:"primaryKey" json:"-"`
+	UserID              string               `gorm:"size:255;not null;uniqueIndex:uq_idempotency_keys_user_key" json:"-"`
+	Key                 string               `gorm:"size:255;not null;uniqueIndex:uq_idempotency_keys_user_key" json:"-"`
+	Method              string               `gorm:"size:10;not null" json:"-"`
+	Path                string               `gorm:"size:255;not null" json:"-"`
+	Fingerprint         string               `gorm:"size:80;not null" json:"-"`
+	Status              IdempotencyKeyStatus `gorm:"type:varchar(20);not null;default:'in_flight'" json:"-"`
+	ResponseStatus      int                  `json:"-"`
+	ResponseContentType string               `gorm:"size:100" json:"-"`
+	ResponseBody        []byte               `json:"-"`
+	LockedUntil         time.Time            `gorm:"not null" json:"-"`
+	ExpiresAt           time.Time            `gorm:"not null;index" json:"-"`
+	CreatedAt           time.Time            `gorm:"autoCreateTime" json:"-"`
+	UpdatedAt           time.Time            `gorm:"autoUpdateTime" json:"-"`
+}
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
 
 // OrderStatus represents the status of an order
 type OrderStatus string
@@ -25,39 +29,75 @@ const (
 
 // Order represents a customer order.
 type Order struct {
-	ID                        uint          `gorm:"primaryKey" json:"-"`
-	PublicID                  string        `gorm:"type:uuid;not null;uniqueIndex;default:gen_random_uuid()" json:"public_id"`
-	UserID                    string        `gorm:"size:255;not null;index" json:"user_id"`
-	User                      *User         `gorm:"foreignKey:UserID;references:PublicID" json:"user,omitempty"`
-	TotalAmount               float64       `gorm:"type:decimal(10,2);not null" json:"total_amount"`
-	Status                    OrderStatus   `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
-	ShippingAddress           string        `gorm:"type:text;not null" json:"shipping_address"`
-	PaymentMethod             PaymentMethod `gorm:"type:varchar(20);not null" json:"payment_method"`
-	ShippingPrice             float64       `gorm:"type:decimal(10,2);not null;default:0" json:"shipping_price"`
-	ShippingCarrier           string        `gorm:"type:varchar(100)" json:"shipping_carrier,omitempty"`
-	ShippingServiceCode       string        `gorm:"type:varchar(100)" json:"shipping_service_code,omitempty"`
-	ShippingEstimatedDelivery *time.Time    `json:"shipping_estimated_delivery,omitempty"`
-	ShippingTracking          string        `gorm:"type:varchar(255)" json:"shipping_tracking,omitempty"`
-	ShippingStatus            string        `gorm:"type:varchar(50)" json:"shipping_status,omitempty"`
-	Items                     []OrderItem   `gorm:"foreignKey:OrderID" json:"items"`
-	CreatedAt                 time.Time     `gorm:"autoCreateTime;index" json:"created_at"`
-	UpdatedAt                 time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt                 *time.Time    `gorm:"index" json:"-"`
+	ID                        uint           `gorm:"primaryKey" json:"-"`
+	PublicID                  string         `gorm:"type:uuid;not null;uniqueIndex;default:gen_random_uuid()" json:"public_id"`
+	UserID                    string         `gorm:"type:uuid;default:null;index" json:"user_id,omitempty"`
+	GuestID                   string         `gorm:"type:uuid;default:null;index" json:"-"`
+	GuestEmail                string         `gorm:"size:255;default:null" json:"guest_email,omitempty"`
+	GuestLookupTokenHash      string         `gorm:"size:64;default:null;uniqueIndex" json:"-"`
+	User                      *User          `gorm:"foreignKey:UserID;references:PublicID" json:"user,omitempty"`
+	TotalAmount               money.Amount   `gorm:"column:total_amount_cents;not null" json:"total_amount"`
+	Currency                  money.Currency `gorm:"type:varchar(3);not null;default:'brl'" json:"currency"`
+	Status                    OrderStatus    `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	ShippingAddress           string         `gorm:"type:text;not null" json:"shipping_address"`
+	ShippingDetails           PostalAddress  `gorm:"embedded;embeddedPrefix:shipping_" json:"shipping_details"`
+	PaymentMethod             PaymentMethod  `gorm:"type:varchar(20);not null" json:"payment_method"`
+	ShippingPrice             money.Amount   `gorm:"column:shipping_price_cents;not null;default:0" json:"shipping_price"`
+	DiscountAmount            money.Amount   `gorm:"column:discount_cents;not null;default:0" json:"discount_amount"`
+	PromotionID               *uint          `gorm:"index" json:"-"`
+	PromotionCode             string         `gorm:"size:64" json:"promotion_code,omitempty"`
+	ShippingCarrier           string         `gorm:"type:varchar(100)" json:"shipping_carrier,omitempty"`
+	ShippingServiceCode       string         `gorm:"type:varchar(100)" json:"shipping_service_code,omitempty"`
+	ShippingEstimatedDelivery *time.Time     `json:"shipping_estimated_delivery,omitempty"`
+	ShippingTracking          string         `gorm:"type:varchar(255)" json:"shipping_tracking,omitempty"`
+	ShippingStatus            string         `gorm:"type:varchar(50)" json:"shipping_status,omitempty"`
+	StockReserved             bool           `gorm:"not null;default:false" json:"-"`
+	ReservationExpiresAt      *time.Time     `json:"reservation_expires_at,omitempty"`
+	PaymentReviewReason       string         `gorm:"type:varchar(50)" json:"payment_review_reason,omitempty"`
+	PaymentReviewAt           *time.Time     `json:"payment_review_at,omitempty"`
+	Items                     []OrderItem    `gorm:"foreignKey:OrderID" json:"items"`
+	CreatedAt                 time.Time      `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt                 time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt                 *time.Time     `gorm:"index" json:"-"`
 }
 
 // OrderItem represents an item in an order.
 type OrderItem struct {
-	ID              uint      `gorm:"primaryKey" json:"id"`
-	OrderID         uint      `gorm:"not null;index" json:"order_id"`
-	ProductID       uint      `gorm:"not null;index" json:"product_id"`
-	Product         *Product  `gorm:"foreignKey:ProductID" json:"product,omitempty"`
-	ProductSlug     string    `gorm:"size:255" json:"product_slug"`
-	ProductName     string    `gorm:"size:255" json:"product_name"`
-	ProductImageURL string    `gorm:"size:500" json:"product_image_url"`
-	Quantity        int       `gorm:"not null" json:"quantity"`
-	PriceAtPurchase float64   `gorm:"type:decimal(10,2);not null" json:"price_at_purchase"`
-	Subtotal        float64   `gorm:"type:decimal(10,2);not null" json:"subtotal"`
-	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt       *time.Time `gorm:"index" json:"-"`
+	ID              uint     `gorm:"primaryKey" json:"id"`
+	OrderID         uint     `gorm:"not null;index" json:"order_id"`
+	ProductID       uint     `gorm:"not null;index" json:"product_id"`
+	Product         *Product `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	ProductSlug     string   `gorm:"size:255" json:"product_slug"`
+	ProductName     string   `gorm:"size:255" json:"product_name"`
+	ProductImageURL string   `gorm:"size:500" json:"product_image_url"`
+	// VariantID is cleared when the variant is removed; the variant fields below keep what was bought
+	VariantID            *uint         `gorm:"index" json:"variant_id,omitempty"`
+	VariantSKU           string        `gorm:"column:variant_sku;size:64" json:"variant_sku,omitempty"`
+	VariantSizeML        int           `gorm:"column:variant_size_ml;not null;default:0" json:"variant_size_ml,omitempty"`
+	VariantConcentration Concentration `gorm:"size:16" json:"variant_concentration,omitempty"`
+	Quantity             int           `gorm:"not null" json:"quantity"`
+	PriceAtPurchase      money.Amount  `gorm:"column:price_at_purchase_cents;not null" json:"price_at_purchase"`
+	Subtotal             money.Amount  `gorm:"column:subtotal_cents;not null" json:"subtotal"`
+	CreatedAt            time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt            time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt            *time.Time    `gorm:"index" json:"-"`
+}
+
+// IsGuest reports whether the order was placed without an account.
+func (o *Order) IsGuest() bool {
+	return o.UserID == ""
+}
+
+// OwnedBy reports whether the order belongs to the given user or guest session.
+func (o *Order) OwnedBy(ownerID string) bool {
+	return ownerID != "" && (o.UserID == ownerID || o.GuestID == ownerID)
+}
+
+// Total returns the order total with its currency.
+func (o *Order) Total() money.Money {
+	currency := o.Currency
+	if currency == "" {
+		currency = money.BRL
+	}
+	return money.New(o.TotalAmount, currency)
 }
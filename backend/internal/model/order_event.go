@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// OrderEventType represents the kind of change recorded on an order.
+type OrderEventType string
+
+const (
+	OrderEventCreated         OrderEventType = "created"
+	OrderEventStatusChanged   OrderEventType = "status_changed"
+	OrderEventPaymentUpdated  OrderEventType = "payment_updated"
+	OrderEventShippingUpdated OrderEventType = "shipping_updated"
+	OrderEventPaymentReview   OrderEventType = "payment_review"
+)
+
+// OrderEventActor identifies who triggered an order event.
+type OrderEventActor string
+
+const (
+	OrderEventActorUser    OrderEventActor = "user"
+	OrderEventActorAdmin   OrderEventActor = "admin"
+	OrderEventActorSystem  OrderEventActor = "system"
+	OrderEventActorWebhook OrderEventActor = "webhook"
+)
+
+// OrderEvent is an append-only entry in an order's history.
+type OrderEvent struct {
+	ID         uint              `gorm:"primaryKey" json:"id"`
+	OrderID    uint              `gorm:"not null;index" json:"order_id"`
+	Type       OrderEventType    `gorm:"type:varchar(30);not null" json:"type"`
+	FromStatus string            `gorm:"type:varchar(30)" json:"from_status,omitempty"`
+	ToStatus   string            `gorm:"type:varchar(30)" json:"to_status,omitempty"`
+	ActorType  OrderEventActor   `gorm:"type:varchar(20);not null" json:"actor_type"`
+	ActorID    *string           `gorm:"size:255" json:"actor_id,omitempty"`
+	Note       string            `gorm:"type:text" json:"note,omitempty"`
+	Metadata   datatypes.JSONMap `gorm:"type:jsonb" json:"metadata,omitempty"`
+	CreatedAt  time.Time         `gorm:"autoCreateTime;index" json:"created_at"`
+}
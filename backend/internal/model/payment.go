@@ -10,26 +10,62 @@ import (
 type PaymentStatus string
 
 const (
-	PaymentStatusPending    PaymentStatus = "pending"
-	PaymentStatusProcessing PaymentStatus = "processing"
-	PaymentStatusSucceeded  PaymentStatus = "succeeded"
-	PaymentStatusFailed     PaymentStatus = "failed"
-	PaymentStatusCanceled   PaymentStatus = "canceled"
+	PaymentStatusPending           PaymentStatus = "pending"
+	PaymentStatusProcessing        PaymentStatus = "processing"
+	PaymentStatusSucceeded         PaymentStatus = "succeeded"
+	PaymentStatusFailed            PaymentStatus = "failed"
+	PaymentStatusCanceled          PaymentStatus = "canceled"
+	PaymentStatusRefunded          PaymentStatus = "refunded"
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+)
+
+// Reasons an order is held for manual review because its payment does not match it.
+const (
+	PaymentReviewUnderpaid        = "payment_underpaid"
+	PaymentReviewOverpaid         = "payment_overpaid"
+	PaymentReviewCurrencyMismatch = "payment_currency_mismatch"
+	// The payment was captured after the order was cancelled or had already been paid
+	PaymentReviewOrderNotPayable = "payment_order_not_payable"
 )
 
 // Payment stores gateway intent information for reconciliation.
+// Payments made during guest checkout carry a GuestID instead of a UserID.
 type Payment struct {
-	ID            uint              `gorm:"primaryKey" json:"-"`
-	IntentID      string            `gorm:"size:255;not null;uniqueIndex" json:"intent_id"`
-	Provider      string            `gorm:"size:50;not null" json:"provider"`
-	UserID        string            `gorm:"size:255;not null;index" json:"user_id"`
-	OrderPublicID *string           `gorm:"type:uuid;index" json:"order_public_id,omitempty"`
-	AmountCents   int64             `gorm:"not null" json:"amount_cents"`
-	Currency      string            `gorm:"size:10;not null" json:"currency"`
-	Status        PaymentStatus     `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
-	Metadata      datatypes.JSONMap `gorm:"type:jsonb" json:"metadata,omitempty"`
-	ErrorCode     string            `gorm:"size:100" json:"error_code,omitempty"`
-	ErrorMessage  string            `gorm:"type:text" json:"error_message,omitempty"`
-	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+	ID                  uint              `gorm:"primaryKey" json:"-"`
+	IntentID            string            `gorm:"size:255;not null;uniqueIndex" json:"intent_id"`
+	Provider            string            `gorm:"size:50;not null" json:"provider"`
+	UserID              string            `gorm:"type:uuid;default:null;index" json:"user_id,omitempty"`
+	GuestID             string            `gorm:"type:uuid;default:null;index" json:"-"`
+	OrderPublicID       *string           `gorm:"type:uuid;index" json:"order_public_id,omitempty"`
+	AmountCents         int64             `gorm:"not null" json:"amount_cents"`
+	RefundedCents       int64             `gorm:"not null;default:0" json:"refunded_cents"`
+	Currency            string            `gorm:"size:10;not null" json:"currency"`
+	Status              PaymentStatus     `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Metadata            datatypes.JSONMap `gorm:"type:jsonb" json:"metadata,omitempty"`
+	ErrorCode           string            `gorm:"size:100" json:"error_code,omitempty"`
+	ErrorMessage        string            `gorm:"type:text" json:"error_message,omitempty"`
+	ExpiresAt           *time.Time        `json:"expires_at,omitempty"`
+	DueDate             *time.Time        `gorm:"type:date" json:"due_date,omitempty"`
+	BoletoBarcode       string            `gorm:"size:44" json:"boleto_barcode,omitempty"`
+	BoletoDigitableLine string            `gorm:"size:60" json:"boleto_digitable_line,omitempty"`
+	CreatedAt           time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt           time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// IsRefunded reports whether any part of the payment has been returned.
+func (s PaymentStatus) IsRefunded() bool {
+	return s == PaymentStatusRefunded || s == PaymentStatusPartiallyRefunded
+}
+
+// RefundableCents is the captured amount that has not been returned yet.
+func (p *Payment) RefundableCents() int64 {
+	if p.Status != PaymentStatusSucceeded && p.Status != PaymentStatusPartiallyRefunded {
+		return 0
+	}
+	return p.AmountCents - p.RefundedCents
+}
+
+// OwnedBy reports whether the payment belongs to the given user or guest session.
+func (p *Payment) OwnedBy(ownerID string) bool {
+	return ownerID != "" && (p.UserID == ownerID || p.GuestID == ownerID)
 }
@@ -3,17 +3,20 @@ package model
 import (
 	"time"
 
+	"github.com/leoferamos/aroma-sense/internal/money"
 	"github.com/lib/pq"
 )
 
-// Product represents a product in the catalog.
+// Product represents a product in the catalog. Price, Weight and StockQuantity summarize the
+// product's variants: the lowest variant price, the default variant's weight and the total stock.
+// ImageURL and ThumbnailURL mirror the primary image of the gallery.
 type Product struct {
 	ID           uint           `gorm:"primaryKey" json:"id"`
 	Name         string         `gorm:"size:128;not null" json:"name"`
 	Brand        string         `gorm:"size:64;not null" json:"brand"`
 	Weight       float64        `gorm:"not null" json:"weight"`
 	Description  string         `gorm:"type:text" json:"description"`
-	Price        float64        `gorm:"not null" json:"price"`
+	Price        money.Amount   `gorm:"column:price_cents;not null" json:"price"`
 	ImageURL     string         `gorm:"size:256" json:"image_url"`
 	ThumbnailURL string         `gorm:"size:256" json:"thumbnail_url"`
 	Slug         string         `gorm:"size:128" json:"slug,omitempty"`
@@ -27,8 +30,10 @@ type Product struct {
 	NotesHeart   pq.StringArray `gorm:"type:text[]" json:"notes_heart,omitempty"`
 	NotesBase    pq.StringArray `gorm:"type:text[]" json:"notes_base,omitempty"`
 
-	Category      string    `gorm:"size:64;not null" json:"category"`
-	StockQuantity int       `gorm:"not null" json:"stock_quantity"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	Category      string           `gorm:"size:64;not null" json:"category"`
+	StockQuantity int              `gorm:"not null" json:"stock_quantity"`
+	Variants      []ProductVariant `gorm:"foreignKey:ProductID" json:"variants,omitempty"`
+	Images        []ProductImage   `gorm:"foreignKey:ProductID" json:"images,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
 }
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// ProductImage is an image in a product's gallery. The primary image is mirrored into
+// Product.ImageURL and Product.ThumbnailURL.
+type ProductImage struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ProductID    uint      `gorm:"not null;index" json:"product_id"`
+	ImageURL     string    `gorm:"size:256;not null" json:"image_url"`
+	ThumbnailURL string    `gorm:"size:256;not null;default:''" json:"thumbnail_url"`
+	AltText      string    `gorm:"size:255;not null;default:''" json:"alt_text"`
+	Position     int       `gorm:"not null;default:0" json:"position"`
+	IsPrimary    bool      `gorm:"not null;default:false" json:"is_primary"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Image returns the gallery image with the given ID, or nil when it is not one of the product's images
+func (p *Product) Image(id uint) *ProductImage {
+	for i := range p.Images {
+		if p.Images[i].ID == id {
+			return &p.Images[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
+
+// Concentration is the perfume concentration of a product variant
+type Concentration string
+
+const (
+	ConcentrationEDT    Concentration = "edt"
+	ConcentrationEDP    Concentration = "edp"
+	ConcentrationParfum Concentration = "parfum"
+)
+
+// ProductVariant is a purchasable size and concentration of a product, with its own SKU, price,
+// stock and weight. The first variant by position is the product's default.
+type ProductVariant struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	ProductID     uint          `gorm:"not null;index" json:"product_id"`
+	SKU           string        `gorm:"column:sku;size:64;not null;uniqueIndex" json:"sku"`
+	SizeML        int           `gorm:"column:size_ml;not null;default:0" json:"size_ml"`
+	Concentration Concentration `gorm:"size:16;not null" json:"concentration"`
+	Price         money.Amount  `gorm:"column:price_cents;not null" json:"price"`
+	StockQuantity int           `gorm:"not null;default:0" json:"stock_quantity"`
+	Weight        float64       `gorm:"not null" json:"weight"`
+	Barcode       *string       `gorm:"size:32" json:"barcode,omitempty"`
+	Position      int           `gorm:"not null;default:0" json:"position"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// Variant returns the product variant with the given ID, or nil when it is not one of the product's variants
+func (p *Product) Variant(id uint) *ProductVariant {
+	for i := range p.Variants {
+		if p.Variants[i].ID == id {
+			return &p.Variants[i]
+		}
+	}
+	return nil
+}
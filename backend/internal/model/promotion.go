@@ -0,0 +1,88 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
+
+// PromotionType selects how a promotion computes its discount.
+type PromotionType string
+
+const (
+	// PromotionTypePercentage takes PercentOff percent off the eligible items.
+	PromotionTypePercentage PromotionType = "percentage"
+	// PromotionTypeFixedAmount takes AmountOff off the eligible items.
+	PromotionTypeFixedAmount PromotionType = "fixed_amount"
+	// PromotionTypeFreeShipping waives the shipping price.
+	PromotionTypeFreeShipping PromotionType = "free_shipping"
+	// PromotionTypeBuyXGetY makes the cheapest GetQuantity units free for every BuyQuantity+GetQuantity eligible units.
+	PromotionTypeBuyXGetY PromotionType = "buy_x_get_y"
+)
+
+// Promotion is a discount redeemed with a coupon code. Brand and Category restrict which products
+// it applies to; MinSubtotal, the usage limits and the validity window decide whether it applies at all.
+type Promotion struct {
+	ID             uint          `gorm:"primaryKey" json:"id"`
+	Code           string        `gorm:"size:64;not null;uniqueIndex" json:"code"`
+	Name           string        `gorm:"size:128;not null" json:"name"`
+	Description    string        `gorm:"type:text" json:"description,omitempty"`
+	Type           PromotionType `gorm:"type:varchar(20);not null" json:"type"`
+	PercentOff     int           `gorm:"not null;default:0" json:"percent_off,omitempty"`
+	AmountOff      money.Amount  `gorm:"column:amount_off_cents;not null;default:0" json:"amount_off,omitempty"`
+	BuyQuantity    int           `gorm:"not null;default:0" json:"buy_quantity,omitempty"`
+	GetQuantity    int           `gorm:"not null;default:0" json:"get_quantity,omitempty"`
+	Brand          string        `gorm:"size:64" json:"brand,omitempty"`
+	Category       string        `gorm:"size:64" json:"category,omitempty"`
+	MinSubtotal    money.Amount  `gorm:"column:min_subtotal_cents;not null;default:0" json:"min_subtotal,omitempty"`
+	MaxUses        *int          `json:"max_uses,omitempty"`
+	MaxUsesPerUser *int          `json:"max_uses_per_user,omitempty"`
+	UsedCount      int           `gorm:"not null;default:0" json:"used_count"`
+	StartsAt       *time.Time    `json:"starts_at,omitempty"`
+	EndsAt         *time.Time    `json:"ends_at,omitempty"`
+	Active         bool          `gorm:"not null;default:true" json:"active"`
+	CreatedAt      time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// NormalizePromotionCode puts a coupon code in the form it is stored in.
+func NormalizePromotionCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Matches reports whether a product falls within the promotion's brand and category scope.
+func (p *Promotion) Matches(brand string, category string) bool {
+	if p.Brand != "" && !strings.EqualFold(p.Brand, brand) {
+		return false
+	}
+	if p.Category != "" && !strings.EqualFold(p.Category, category) {
+		return false
+	}
+	return true
+}
+
+// ActiveAt reports whether the promotion can be redeemed at the given time.
+func (p *Promotion) ActiveAt(now time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// PromotionRedemption records a promotion used by an order. It is removed again if the order is cancelled.
+type PromotionRedemption struct {
+	ID             uint         `gorm:"primaryKey" json:"id"`
+	PromotionID    uint         `gorm:"not null;index" json:"promotion_id"`
+	OrderID        uint         `gorm:"not null;uniqueIndex" json:"order_id"`
+	UserID         string       `gorm:"type:uuid;default:null;index" json:"user_id,omitempty"`
+	GuestEmail     string       `gorm:"size:255;default:null" json:"guest_email,omitempty"`
+	DiscountAmount money.Amount `gorm:"column:discount_cents;not null" json:"discount_amount"`
+	CreatedAt      time.Time    `gorm:"autoCreateTime" json:"created_at"`
+}
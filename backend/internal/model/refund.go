@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// RefundStatus represents the outcome of a refund request at the gateway.
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+// Refund records money returned against a captured payment.
+type Refund struct {
+	ID               uint            `gorm:"primaryKey" json:"-"`
+	PaymentID        uint            `gorm:"not null;index" json:"-"`
+	IntentID         string          `gorm:"size:255;not null" json:"intent_id"`
+	OrderPublicID    string          `gorm:"type:uuid;not null;index" json:"order_public_id"`
+	ProviderRefundID string          `gorm:"size:255" json:"provider_refund_id,omitempty"`
+	AmountCents      int64           `gorm:"not null" json:"amount_cents"`
+	Currency         string          `gorm:"size:10;not null" json:"currency"`
+	Status           RefundStatus    `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Reason           string          `gorm:"type:text" json:"reason,omitempty"`
+	ActorType        OrderEventActor `gorm:"type:varchar(20);not null" json:"actor_type"`
+	ActorID          *string         `gorm:"size:255" json:"actor_id,omitempty"`
+	ErrorMessage     string          `gorm:"type:text" json:"error_message,omitempty"`
+	CreatedAt        time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "github.com/leoferamos/aroma-sense/internal/money"
+
 // Parcel represents a package to be shipped.
 type Parcel struct {
 	WeightKg float64 `json:"weight_kg"`
@@ -10,10 +12,10 @@ type Parcel struct {
 
 // Shipment captures shipping metadata in the domain layer.
 type Shipment struct {
-	Carrier       string  `json:"carrier"`
-	ServiceCode   string  `json:"service_code"`
-	Price         float64 `json:"price"`
-	EstimatedDays int     `json:"estimated_days"`
-	Tracking      string  `json:"tracking,omitempty"`
-	Status        string  `json:"status,omitempty"`
+	Carrier       string       `json:"carrier"`
+	ServiceCode   string       `json:"service_code"`
+	Price         money.Amount `json:"price"`
+	EstimatedDays int          `json:"estimated_days"`
+	Tracking      string       `json:"tracking,omitempty"`
+	Status        string       `json:"status,omitempty"`
 }
@@ -15,6 +15,8 @@ type User struct {
 	Role                  string         `gorm:"size:16;not null;default:client" json:"role"`
 	IsProtected           bool           `gorm:"not null;default:false" json:"-"`
 	DisplayName           *string        `gorm:"size:64" json:"display_name,omitempty"`
+	MarketingOptIn        bool           `gorm:"not null;default:false" json:"marketing_opt_in"`
+	MarketingOptInAt      *time.Time     `json:"marketing_opt_in_at,omitempty"`
 	RefreshTokenHash      *string        `gorm:"size:255" json:"-"`
 	RefreshTokenExpiresAt *time.Time     `json:"-"`
 	CreatedAt             time.Time      `gorm:"autoCreateTime" json:"created_at"`
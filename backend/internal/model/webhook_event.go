@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// WebhookEventStatus tracks an inbox entry through processing.
+type WebhookEventStatus string
+
+const (
+	WebhookEventPending    WebhookEventStatus = "pending"
+	WebhookEventProcessing WebhookEventStatus = "processing"
+	WebhookEventProcessed  WebhookEventStatus = "processed"
+	WebhookEventFailed     WebhookEventStatus = "failed"
+	// WebhookEventRejected marks notifications that failed verification or parsing; they are never processed.
+	WebhookEventRejected WebhookEventStatus = "rejected"
+	// WebhookEventIgnored marks verified notifications of event types we do not act on.
+	WebhookEventIgnored WebhookEventStatus = "ignored"
+)
+
+// WebhookEvent is a gateway notification kept in the inbox exactly as received.
+// Processing works from the normalized payload captured at verification time, since
+// gateway signatures are time-limited and cannot be checked again on retry.
+type WebhookEvent struct {
+	ID             uint               `gorm:"primaryKey" json:"id"`
+	Provider       string             `gorm:"size:50;not null;uniqueIndex:uq_webhook_events_provider_event" json:"provider"`
+	EventID        string             `gorm:"size:255;not null;uniqueIndex:uq_webhook_events_provider_event" json:"event_id"`
+	EventType      string             `gorm:"size:100" json:"event_type,omitempty"`
+	Payload        string             `gorm:"type:text;not null" json:"payload"`
+	Normalized     datatypes.JSON     `gorm:"type:jsonb" json:"-"`
+	SignatureValid bool               `gorm:"not null" json:"signature_valid"`
+	Status         WebhookEventStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	Attempts       int                `gorm:"not null;default:0" json:"attempts"`
+	LastError      string             `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time         `json:"next_attempt_at,omitempty"`
+	ProcessedAt    *time.Time         `json:"processed_at,omitempty"`
+	CreatedAt      time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/money"
+)
+
+// WishlistItem is a product a user saved for later.
+// AlertPrice is the price the last price-drop alert was sent for, starting at the saved price, and
+// InStock is the stock state last seen by the alert job; both keep alerts from repeating.
+// AlertDeferredUntil is set while an alert is held back by the user's alert rate limit.
+type WishlistItem struct {
+	ID         uint         `gorm:"primaryKey" json:"id"`
+	UserID     string       `gorm:"type:uuid;not null" json:"user_id"`
+	User       *User        `gorm:"foreignKey:UserID;references:PublicID" json:"-"`
+	ProductID  uint         `gorm:"not null" json:"product_id"`
+	Product    *Product     `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	SavedPrice money.Amount `gorm:"column:saved_price_cents;not null" json:"saved_price"`
+	AlertPrice money.Amount `gorm:"column:alert_price_cents;not null" json:"-"`
+	InStock    bool         `gorm:"not null;default:true" json:"-"`
+	// AlertDeferredUntil keeps the entry out of the alert job until the user may be emailed again
+	AlertDeferredUntil *time.Time `json:"-"`
+	CreatedAt          time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// WishlistAlert tells a user why a wishlisted product is worth another look.
+type WishlistAlert struct {
+	Product      Product
+	SavedPrice   money.Amount
+	PriceDropped bool
+	BackInStock  bool
+}
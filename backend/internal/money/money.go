@@ -0,0 +1,204 @@
+// Package money represents monetary values as integer cents so totals never pick up
+// floating point drift. Amounts still read and write JSON as decimal numbers (19.99),
+// keeping the API unchanged for clients.
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Currency is a lower-case ISO 4217 code, the form the payment gateways use.
+type Currency string
+
+// BRL is the currency the catalogue is priced in.
+const BRL Currency = "brl"
+
+// Normalize lower-cases and trims a currency code coming from an external system.
+func Normalize(code string) Currency {
+	return Currency(strings.ToLower(strings.TrimSpace(code)))
+}
+
+// Amount is a value in cents of the store currency.
+type Amount int64
+
+// FromCents wraps a cent value.
+func FromCents(cents int64) Amount {
+	return Amount(cents)
+}
+
+// FromFloat converts a decimal value such as 19.99 to cents, rounding half-up.
+// The float is formatted to its shortest decimal form first, so 19.99 is 1999
+// and not 1998 as int64(19.99*100) would give.
+func FromFloat(v float64) Amount {
+	a, _ := Parse(strconv.FormatFloat(v, 'f', -1, 64))
+	return a
+}
+
+// Parse reads a decimal string such as "19.99" or "-5". Digits past the second
+// decimal place are rounded half-up (away from zero), the single rounding rule
+// used for money in this codebase.
+func Parse(s string) (Amount, error) {
+	raw := strings.TrimSpace(s)
+	neg := strings.HasPrefix(raw, "-")
+	digits := strings.TrimPrefix(strings.TrimPrefix(raw, "-"), "+")
+
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("money: invalid amount %q: %w", s, err)
+	}
+	padded := frac + "000"
+	cents := units*100 + int64(padded[0]-'0')*10 + int64(padded[1]-'0')
+	if padded[2] >= '5' {
+		cents++
+	}
+	if neg {
+		cents = -cents
+	}
+	return Amount(cents), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Cents returns the amount in cents, the unit gateways expect.
+func (a Amount) Cents() int64 {
+	return int64(a)
+}
+
+// Float64 returns the amount in currency units, for integrations that only take decimals.
+func (a Amount) Float64() float64 {
+	f, _ := strconv.ParseFloat(a.String(), 64)
+	return f
+}
+
+// Mul returns the amount multiplied by a quantity.
+func (a Amount) Mul(quantity int) Amount {
+	return a * Amount(quantity)
+}
+
+// Div splits the amount into n parts, rounding the result half-up like Parse.
+func (a Amount) Div(n int64) Amount {
+	q, r := int64(a)/n, int64(a)%n
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= n {
+		if int64(a) < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Amount(q)
+}
+
+// String formats the amount with two decimal places, e.g. "19.90".
+func (a Amount) String() string {
+	sign := ""
+	cents := int64(a)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON writes the amount as a decimal number, matching the old float64 output.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(a.Float64(), 'f', -1, 64)), nil
+}
+
+// UnmarshalJSON accepts a decimal number or a quoted decimal string.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(string(data), `"`)
+	if raw == "null" {
+		return nil
+	}
+	parsed, err := Parse(raw)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// UnmarshalParam lets gin bind form and query values such as price=19.99.
+func (a *Amount) UnmarshalParam(param string) error {
+	parsed, err := Parse(param)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value stores the amount as an integer number of cents.
+func (a Amount) Value() (driver.Value, error) {
+	return int64(a), nil
+}
+
+// Scan reads an integer cent column.
+func (a *Amount) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = 0
+	case int64:
+		*a = Amount(v)
+	case []byte:
+		cents, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return fmt.Errorf("money: cannot scan %q: %w", v, err)
+		}
+		*a = Amount(cents)
+	case string:
+		cents, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("money: cannot scan %q: %w", v, err)
+		}
+		*a = Amount(cents)
+	default:
+		return fmt.Errorf("money: cannot scan %T", value)
+	}
+	return nil
+}
+
+// Money is an amount together with its currency.
+type Money struct {
+	Amount   Amount
+	Currency Currency
+}
+
+// New builds a Money value.
+func New(amount Amount, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// SameCurrency reports whether both values are in the same currency.
+func (m Money) SameCurrency(other Money) bool {
+	return m.Currency == other.Currency
+}
+
+// String formats the value as "19.90 BRL".
+func (m Money) String() string {
+	return m.Amount.String() + " " + strings.ToUpper(string(m.Currency))
+}
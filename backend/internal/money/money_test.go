@@ -0,0 +1,93 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Amount
+	}{
+		{"19.99", 1999},
+		{"19.9", 1990},
+		{"19", 1900},
+		{".5", 50},
+		{"1.005", 101},
+		{"1.004", 100},
+		{"-2.345", -235},
+		{" 7.10 ", 710},
+	}
+	for _, tc := range cases {
+		got, err := Parse(tc.in)
+		assert.NoError(t, err, tc.in)
+		assert.Equal(t, tc.want, got, tc.in)
+	}
+
+	for _, bad := range []string{"", "-", "abc", "1.2.3", "1,50"} {
+		_, err := Parse(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestFromFloat_DoesNotTruncate(t *testing.T) {
+	assert.Equal(t, Amount(1999), FromFloat(19.99))
+	assert.Equal(t, Amount(1), FromFloat(0.005))
+	assert.Equal(t, Amount(12990), FromFloat(129.9))
+}
+
+func TestAmount_Arithmetic(t *testing.T) {
+	assert.Equal(t, Amount(5997), Amount(1999).Mul(3))
+	assert.Equal(t, Amount(3334), Amount(10001).Div(3))
+	assert.Equal(t, Amount(2), Amount(5).Div(3))
+	assert.Equal(t, Amount(-2), Amount(-5).Div(3))
+}
+
+func TestAmount_String(t *testing.T) {
+	assert.Equal(t, "19.90", Amount(1990).String())
+	assert.Equal(t, "0.05", Amount(5).String())
+	assert.Equal(t, "-1.50", Amount(-150).String())
+	assert.Equal(t, "19.90 BRL", New(1990, BRL).String())
+}
+
+func TestAmount_JSON(t *testing.T) {
+	type payload struct {
+		Price Amount `json:"price"`
+	}
+
+	out, err := json.Marshal(payload{Price: 1999})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"price":19.99}`, string(out))
+
+	out, err = json.Marshal(payload{Price: 1990})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"price":19.9}`, string(out))
+
+	var in payload
+	assert.NoError(t, json.Unmarshal([]byte(`{"price":24.9}`), &in))
+	assert.Equal(t, Amount(2490), in.Price)
+	assert.NoError(t, json.Unmarshal([]byte(`{"price":"0.10"}`), &in))
+	assert.Equal(t, Amount(10), in.Price)
+	assert.Error(t, json.Unmarshal([]byte(`{"price":true}`), &in))
+}
+
+func TestAmount_Scan(t *testing.T) {
+	var a Amount
+	assert.NoError(t, a.Scan(int64(1999)))
+	assert.Equal(t, Amount(1999), a)
+	assert.NoError(t, a.Scan([]byte("4500")))
+	assert.Equal(t, Amount(4500), a)
+	assert.Error(t, a.Scan(1.5))
+
+	v, err := Amount(1999).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1999), v)
+}
+
+func TestMoney_SameCurrency(t *testing.T) {
+	assert.True(t, New(100, BRL).SameCurrency(New(5, Normalize(" BRL "))))
+	assert.False(t, New(100, BRL).SameCurrency(New(100, "usd")))
+}
@@ -1,8 +1,12 @@
 package notification
 
 import (
+	"errors"
+	"net/url"
+
 	"github.com/leoferamos/aroma-sense/internal/email"
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
 )
 
 // NotificationService defines high-level notifications used by services.
@@ -10,6 +14,8 @@ type NotificationService interface {
 	SendPasswordResetCode(to, code string) error
 	SendWelcomeEmail(to, name string) error
 	SendOrderConfirmation(to string, order *model.Order) error
+	SendGuestOrderConfirmation(to string, order *model.Order, lookupToken string) error
+	SendOrderCancelled(to string, order *model.Order, refundedAmount money.Amount) error
 	SendAccountDeactivated(to, reason string, contestationDeadline string) error
 	SendContestationReceived(to string) error
 	SendContestationResult(to string, approved bool, reason string) error
@@ -18,16 +24,21 @@ type NotificationService interface {
 	SendDeletionCancelled(to string) error
 	SendDataAnonymized(to string) error
 	SendPromotional(to, subject, htmlBody string) error
+	SendAbandonedCartReminder(to string, items []model.CartItem) error
+	SendWishlistAlert(to string, alerts []model.WishlistAlert) error
+	SendAdminAlert(subject, message string) error
 }
 
 type notifier struct {
-	es           email.EmailService
-	frontendBase string
+	es              email.EmailService
+	frontendBase    string
+	adminRecipients []string
 }
 
-// NewNotifier creates a notification service that delegates to the provided EmailService
-func NewNotifier(es email.EmailService, frontendBase string) NotificationService {
-	return &notifier{es: es, frontendBase: frontendBase}
+// NewNotifier creates a notification service that delegates to the provided EmailService.
+// Admin alerts go to adminRecipients; with none configured they are dropped.
+func NewNotifier(es email.EmailService, frontendBase string, adminRecipients []string) NotificationService {
+	return &notifier{es: es, frontendBase: frontendBase, adminRecipients: adminRecipients}
 }
 
 func (n *notifier) SendPasswordResetCode(to, code string) error {
@@ -42,6 +53,16 @@ func (n *notifier) SendOrderConfirmation(to string, order *model.Order) error {
 	return n.es.SendOrderConfirmation(to, order)
 }
 
+// SendGuestOrderConfirmation emails a guest order confirmation with a link to track it
+func (n *notifier) SendGuestOrderConfirmation(to string, order *model.Order, lookupToken string) error {
+	trackLink := n.frontendBase + "/orders/track?" + url.Values{"email": {to}, "token": {lookupToken}}.Encode()
+	return n.es.SendGuestOrderConfirmation(to, order, lookupToken, trackLink)
+}
+
+func (n *notifier) SendOrderCancelled(to string, order *model.Order, refundedAmount money.Amount) error {
+	return n.es.SendOrderCancelled(to, order, refundedAmount)
+}
+
 func (n *notifier) SendAccountDeactivated(to, reason string, contestationDeadline string) error {
 	return n.es.SendAccountDeactivated(to, reason, contestationDeadline)
 }
@@ -81,3 +102,25 @@ func (n *notifier) SendDataAnonymized(to string) error {
 func (n *notifier) SendPromotional(to, subject, htmlBody string) error {
 	return n.es.SendPromotional(to, subject, htmlBody)
 }
+
+// SendAbandonedCartReminder emails the items left in a cart with a link back to it
+func (n *notifier) SendAbandonedCartReminder(to string, items []model.CartItem) error {
+	cartLink := n.frontendBase + "/cart"
+	return n.es.SendPromotional(to, "Your cart is waiting", email.AbandonedCartTemplate(items, cartLink))
+}
+
+// SendWishlistAlert emails price drops and restocks of wishlisted products with links to them
+func (n *notifier) SendWishlistAlert(to string, alerts []model.WishlistAlert) error {
+	productBase := n.frontendBase + "/products"
+	return n.es.SendPromotional(to, "News about your wishlist", email.WishlistAlertTemplate(alerts, productBase))
+}
+
+func (n *notifier) SendAdminAlert(subject, message string) error {
+	var errs []error
+	for _, to := range n.adminRecipients {
+		if err := n.es.SendAdminAlert(to, subject, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"gorm.io/gorm"
+)
+
+// AddressRepository stores the users' address books.
+type AddressRepository interface {
+	Create(address *model.Address) error
+	Update(address *model.Address) error
+	Delete(address *model.Address) error
+	FindByPublicID(userID string, publicID string) (*model.Address, error)
+	ListByUser(userID string) ([]model.Address, error)
+	CountByUser(userID string) (int64, error)
+}
+
+type addressRepository struct {
+	db *gorm.DB
+}
+
+func NewAddressRepository(db *gorm.DB) AddressRepository {
+	return &addressRepository{db: db}
+}
+
+// Create inserts an address. A default address replaces the user's previous default.
+func (r *addressRepository) Create(address *model.Address) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := clearDefaultAddress(tx, address.UserID, 0); err != nil {
+				return err
+			}
+		}
+		return tx.Create(address).Error
+	})
+}
+
+// Update saves an address. A default address replaces the user's previous default.
+func (r *addressRepository) Update(address *model.Address) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := clearDefaultAddress(tx, address.UserID, address.ID); err != nil {
+				return err
+			}
+		}
+		return tx.Save(address).Error
+	})
+}
+
+// Delete removes an address. When it was the default, the most recently updated remaining address takes over.
+func (r *addressRepository) Delete(address *model.Address) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.Address{}, address.ID).Error; err != nil {
+			return err
+		}
+		if !address.IsDefault {
+			return nil
+		}
+		var next model.Address
+		err := tx.Where("user_id = ?", address.UserID).Order("updated_at DESC, id DESC").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_default", true).Error
+	})
+}
+
+// FindByPublicID returns the user's address with the given public id, or nil when there is none.
+func (r *addressRepository) FindByPublicID(userID string, publicID string) (*model.Address, error) {
+	var address model.Address
+	err := r.db.Where("user_id = ? AND public_id = ?", userID, publicID).First(&address).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &address, nil
+}
+
+// ListByUser returns the user's addresses, default first.
+func (r *addressRepository) ListByUser(userID string) ([]model.Address, error) {
+	var addresses []model.Address
+	err := r.db.Where("user_id = ?", userID).Order("is_default DESC, created_at ASC").Find(&addresses).Error
+	return addresses, err
+}
+
+// CountByUser returns how many addresses the user has saved.
+func (r *addressRepository) CountByUser(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Address{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func clearDefaultAddress(tx *gorm.DB, userID string, exceptID uint) error {
+	return tx.Model(&model.Address{}).
+		Where("user_id = ? AND is_default AND id <> ?", userID, exceptID).
+		Update("is_default", false).Error
+}
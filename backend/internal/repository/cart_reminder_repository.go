@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AbandonedCart is a user cart with items that has been idle since LastActivityAt.
+// RemindersSent counts the reminders already sent for this abandonment.
+type AbandonedCart struct {
+	CartID         uint
+	UserID         string
+	Email          string
+	LastActivityAt time.Time
+	RemindersSent  int
+}
+
+// CartRecoveryTotals aggregates reminders sent in a period and the orders placed after them.
+type CartRecoveryTotals struct {
+	RemindersSent    int64
+	CartsReminded    int64
+	CartsRecovered   int64
+	RecoveredRevenue money.Amount
+}
+
+// CartReminderRepository stores abandoned cart reminders and finds carts that need one.
+type CartReminderRepository interface {
+	FindDueAbandonedCarts(now time.Time, idleAfter time.Duration, maxReminders int, limit int) ([]AbandonedCart, error)
+	CreateIfAbsent(reminder *model.CartReminder) (bool, error)
+	Delete(id uint) error
+	RecoveryTotals(since time.Time, window time.Duration) (*CartRecoveryTotals, error)
+}
+
+type cartReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewCartReminderRepository(db *gorm.DB) CartReminderRepository {
+	return &cartReminderRepository{db: db}
+}
+
+// FindDueAbandonedCarts lists carts of active, opted-in users that are due their next reminder: the cart
+// has items, got fewer than maxReminders reminders for its current abandonment, its owner has not ordered
+// since, and its last activity (the cart or any of its items) is at least idleAfter times the next
+// reminder's number ago. Oldest abandonments come first.
+func (r *cartReminderRepository) FindDueAbandonedCarts(now time.Time, idleAfter time.Duration, maxReminders int, limit int) ([]AbandonedCart, error) {
+	const query = `
+		WITH activity AS (
+			SELECT c.id AS cart_id, c.user_id,
+				GREATEST(c.updated_at, MAX(ci.updated_at)) AS last_activity_at
+			FROM carts c
+			JOIN cart_items ci ON ci.cart_id = c.id AND ci.deleted_at IS NULL
+			WHERE c.user_id IS NOT NULL AND c.deleted_at IS NULL
+			GROUP BY c.id, c.user_id, c.updated_at
+		), pending AS (
+			SELECT a.cart_id, a.user_id, a.last_activity_at,
+				(SELECT COUNT(*) FROM cart_reminders cr
+					WHERE cr.cart_id = a.cart_id AND cr.cart_activity_at = a.last_activity_at) AS reminders_sent
+			FROM activity a
+		)
+		SELECT p.cart_id, p.user_id, u.email, p.last_activity_at, p.reminders_sent
+		FROM pending p
+		JOIN users u ON u.public_id = p.user_id
+		WHERE u.marketing_opt_in AND u.deleted_at IS NULL AND u.deactivated_at IS NULL
+			AND p.reminders_sent < ?
+			AND p.last_activity_at <= CAST(? AS TIMESTAMPTZ) - make_interval(secs => ?) * (p.reminders_sent + 1)
+			AND NOT EXISTS (
+				SELECT 1 FROM orders o
+				WHERE o.user_id = p.user_id AND o.created_at > p.last_activity_at AND o.deleted_at IS NULL
+			)
+		ORDER BY p.last_activity_at ASC
+		LIMIT ?`
+
+	var carts []AbandonedCart
+	if err := r.db.Raw(query, maxReminders, now, idleAfter.Seconds(), limit).Scan(&carts).Error; err != nil {
+		return nil, err
+	}
+	return carts, nil
+}
+
+// CreateIfAbsent records a reminder. It reports false when that reminder of the abandonment was
+// already recorded, which keeps concurrent runs from emailing twice.
+func (r *cartReminderRepository) CreateIfAbsent(reminder *model.CartReminder) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cart_id"}, {Name: "cart_activity_at"}, {Name: "sequence"}},
+		DoNothing: true,
+	}).Create(reminder)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Delete removes a reminder record, e.g. when its email could not be sent.
+func (r *cartReminderRepository) Delete(id uint) error {
+	return r.db.Delete(&model.CartReminder{}, id).Error
+}
+
+// RecoveryTotals counts reminders sent since the given time and the abandonments they recovered. An
+// abandonment is recovered when its owner places an order, not cancelled, after the first reminder and
+// within window of the last one; the first such order counts as recovered revenue.
+func (r *cartReminderRepository) RecoveryTotals(since time.Time, window time.Duration) (*CartRecoveryTotals, error) {
+	const query = `
+		WITH episodes AS (
+			SELECT cart_id, user_id, cart_activity_at, COUNT(*) AS sent,
+				MIN(sent_at) AS first_sent_at, MAX(sent_at) AS last_sent_at
+			FROM cart_reminders
+			WHERE sent_at >= ?
+			GROUP BY cart_id, user_id, cart_activity_at
+		), recovered AS (
+			SELECT (
+				SELECT o.total_amount_cents FROM orders o
+				WHERE o.user_id = e.user_id AND o.deleted_at IS NULL AND o.status <> ?
+					AND o.created_at > e.first_sent_at
+					AND o.created_at <= e.last_sent_at + make_interval(secs => ?)
+				ORDER BY o.created_at ASC
+				LIMIT 1
+			) AS total
+			FROM episodes e
+		)
+		SELECT
+			(SELECT COALESCE(SUM(sent), 0) FROM episodes) AS reminders_sent,
+			(SELECT COUNT(*) FROM episodes) AS carts_reminded,
+			COUNT(total) AS carts_recovered,
+			COALESCE(SUM(total), 0) AS recovered_revenue
+		FROM recovered`
+
+	var totals CartRecoveryTotals
+	if err := r.db.Raw(query, since, model.OrderStatusCancelled, window.Seconds()).Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
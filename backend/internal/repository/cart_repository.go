@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/leoferamos/aroma-sense/internal/model"
 	"gorm.io/gorm"
 )
@@ -16,6 +18,10 @@ type CartRepository interface {
 	FindCartItemByID(itemID uint) (*model.CartItem, error)
 	DeleteCartItem(itemID uint) error
 	ClearCartItems(cartID uint) error
+	SetPromotionCode(cartID uint, code string) error
+	MergeGuestCart(guestCartID uint, items []model.CartItem) error
+	ReconcileItems(updated []model.CartItem, removedIDs []uint) error
+	DeleteStaleGuestCarts(cutoff time.Time) (int64, error)
 }
 
 type cartRepository struct {
@@ -32,12 +38,14 @@ func (r *cartRepository) Create(cart *model.Cart) error {
 	return r.db.Create(cart).Error
 }
 
-// FindByUserID retrieves a cart by user ID with items and products preloaded
+// FindByUserID retrieves a cart by owner with items, products and variants preloaded. The owner is a user's
+// public ID or a guest session ID; both are random UUIDs, so one lookup serves both kinds of cart.
 func (r *cartRepository) FindByUserID(userID string) (*model.Cart, error) {
 	var cart model.Cart
-	err := r.db.Where("user_id = ?", userID).
+	err := r.db.Where("user_id = ? OR guest_id = ?", userID, userID).
 		Preload("Items").
 		Preload("Items.Product").
+		Preload("Items.Variant").
 		First(&cart).Error
 	return &cart, err
 }
@@ -62,11 +70,12 @@ func (r *cartRepository) UpdateCartItem(item *model.CartItem) error {
 	return r.db.Save(item).Error
 }
 
-// FindCartItemByID retrieves a cart item by its ID with product preloaded
+// FindCartItemByID retrieves a cart item by its ID with product and variant preloaded
 func (r *cartRepository) FindCartItemByID(itemID uint) (*model.CartItem, error) {
 	var item model.CartItem
 	err := r.db.Where("id = ?", itemID).
 		Preload("Product").
+		Preload("Variant").
 		First(&item).Error
 	return &item, err
 }
@@ -80,3 +89,47 @@ func (r *cartRepository) DeleteCartItem(itemID uint) error {
 func (r *cartRepository) ClearCartItems(cartID uint) error {
 	return r.db.Where("cart_id = ?", cartID).Delete(&model.CartItem{}).Error
 }
+
+// SetPromotionCode applies a coupon code to a cart; an empty code removes it
+func (r *cartRepository) SetPromotionCode(cartID uint, code string) error {
+	return r.db.Model(&model.Cart{}).Where("id = ?", cartID).Update("promotion_code", code).Error
+}
+
+// MergeGuestCart saves the merged items of a user's cart and deletes the guest cart they came from
+// in one transaction. Items with an ID are updated; new ones must carry the user's cart ID.
+func (r *cartRepository) MergeGuestCart(guestCartID uint, items []model.CartItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range items {
+			if err := tx.Omit("Product", "Variant").Save(&items[i]).Error; err != nil {
+				return err
+			}
+		}
+		// Items of the guest cart are removed by ON DELETE CASCADE
+		return tx.Where("id = ? AND guest_id IS NOT NULL", guestCartID).Delete(&model.Cart{}).Error
+	})
+}
+
+// ReconcileItems saves repriced or resized cart items and deletes unavailable ones in one transaction
+func (r *cartRepository) ReconcileItems(updated []model.CartItem, removedIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range updated {
+			if err := tx.Model(&model.CartItem{}).Where("id = ?", updated[i].ID).
+				Updates(map[string]interface{}{"quantity": updated[i].Quantity, "price_cents": updated[i].Price}).Error; err != nil {
+				return err
+			}
+		}
+		if len(removedIDs) > 0 {
+			return tx.Where("id IN ?", removedIDs).Delete(&model.CartItem{}).Error
+		}
+		return nil
+	})
+}
+
+// DeleteStaleGuestCarts removes guest carts untouched since the cutoff, including their items
+func (r *cartRepository) DeleteStaleGuestCarts(cutoff time.Time) (int64, error) {
+	result := r.db.
+		Where("guest_id IS NOT NULL AND updated_at < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM cart_items ci WHERE ci.cart_id = carts.id AND ci.updated_at >= ?)", cutoff).
+		Delete(&model.Cart{})
+	return result.RowsAffected, result.Error
+}
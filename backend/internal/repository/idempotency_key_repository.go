@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IdempotencyKeyRepository stores keyed requests and their responses.
+type IdempotencyKeyRepository interface {
+	CreateIfAbsent(record *model.IdempotencyKey) (bool, error)
+	Find(userID string, key string) (*model.IdempotencyKey, error)
+	Relock(id uint, now time.Time, lockedUntil time.Time) (bool, error)
+	Complete(id uint, status int, contentType string, body []byte) error
+	Delete(id uint) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// CreateIfAbsent claims a key for a new request. It reports false when the user already used the key,
+// which is also how concurrent duplicates lose the race.
+func (r *idempotencyKeyRepository) CreateIfAbsent(record *model.IdempotencyKey) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "key"}},
+		DoNothing: true,
+	}).Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Find retrieves a user's key, or nil when it does not exist.
+func (r *idempotencyKeyRepository) Find(userID string, key string) (*model.IdempotencyKey, error) {
+	var record model.IdempotencyKey
+	if err := r.db.Where("user_id = ? AND key = ?", userID, key).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Relock takes over an in-flight request whose lock lapsed, e.g. because the instance handling it crashed.
+// Only one caller can win.
+func (r *idempotencyKeyRepository) Relock(id uint, now time.Time, lockedUntil time.Time) (bool, error) {
+	result := r.db.Model(&model.IdempotencyKey{}).
+		Where("id = ? AND status = ? AND locked_until < ?", id, model.IdempotencyKeyInFlight, now).
+		Update("locked_until", lockedUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Complete stores the response to replay on retries.
+func (r *idempotencyKeyRepository) Complete(id uint, status int, contentType string, body []byte) error {
+	return r.db.Model(&model.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":                model.IdempotencyKeyCompleted,
+		"response_status":       status,
+		"response_content_type": contentType,
+		"response_body":         body,
+	}).Error
+}
+
+// Delete forgets a key so the request can be tried again.
+func (r *idempotencyKeyRepository) Delete(id uint) error {
+	return r.db.Delete(&model.IdempotencyKey{}, id).Error
+}
+
+// DeleteExpired removes keys past their replay window.
+func (r *idempotencyKeyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&model.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
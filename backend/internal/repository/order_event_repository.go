@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"gorm.io/gorm"
+)
+
+// OrderEventRepository persists the history of changes made to orders.
+type OrderEventRepository interface {
+	Create(event *model.OrderEvent) error
+	ListByOrderID(orderID uint) ([]model.OrderEvent, error)
+}
+
+type orderEventRepository struct {
+	db *gorm.DB
+}
+
+func NewOrderEventRepository(db *gorm.DB) OrderEventRepository {
+	return &orderEventRepository{db: db}
+}
+
+// Create appends a new event to an order's history.
+func (r *orderEventRepository) Create(event *model.OrderEvent) error {
+	return r.db.Create(event).Error
+}
+
+// ListByOrderID returns all events of an order, oldest first.
+func (r *orderEventRepository) ListByOrderID(orderID uint) ([]model.OrderEvent, error) {
+	var events []model.OrderEvent
+	err := r.db.Where("order_id = ?", orderID).Order("created_at asc, id asc").Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -1,20 +1,38 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrOrderStatusConflict is returned when an order is no longer in the expected status.
+var ErrOrderStatusConflict = errors.New("order status changed concurrently")
+
 type OrderRepository interface {
 	Create(order *model.Order) error
+	CreateWithStockReservation(order *model.Order, cartID uint) error
+	ReserveStock(publicID string) error
+	CancelAndReleaseStock(publicID string, from model.OrderStatus) error
+	FindExpiredReservations(now time.Time, limit int) ([]model.Order, error)
+	ExtendReservation(publicID string, until time.Time) error
+	FlagPaymentReview(publicID string, reason string, at time.Time) (bool, error)
 	FindByID(id uint) (*model.Order, error)
 	FindByUserID(userID string) ([]model.Order, error)
 	FindByPublicIDWithItems(publicID string) (*model.Order, error)
-	ListOrders(status *string, startDate *time.Time, endDate *time.Time, page int, perPage int) ([]model.Order, int64, float64, error)
+	FindByGuestLookupTokenHash(tokenHash string) (*model.Order, error)
+	ClaimGuestOrders(userID string, email string, guestID string, tokenHashes []string) ([]string, error)
+	ListOrders(status *string, startDate *time.Time, endDate *time.Time, page int, perPage int) ([]model.Order, int64, money.Amount, error)
 	HasUserDeliveredOrderWithProduct(userID string, productID uint) (bool, error)
 	UpdateStatusByPublicID(publicID string, status model.OrderStatus) error
+	UpdateFulfillmentStatus(publicID string, from model.OrderStatus, to model.OrderStatus, shippingStatus string, tracking string) error
 }
 
 type orderRepository struct {
@@ -29,6 +47,193 @@ func (r *orderRepository) Create(order *model.Order) error {
 	return r.db.Create(order).Error
 }
 
+// CreateWithStockReservation creates the order, takes its items out of stock, redeems its promotion
+// and clears the cart in a single transaction. If any product lacks stock or the promotion is used
+// up nothing is persisted.
+func (r *orderRepository) CreateWithStockReservation(order *model.Order, cartID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := reserveItemStock(tx, order.Items); err != nil {
+			return err
+		}
+
+		if order.PromotionID != nil {
+			if err := redeemPromotion(tx, order); err != nil {
+				return err
+			}
+		}
+
+		order.StockReserved = true
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+
+		if order.PromotionID != nil {
+			redemption := &model.PromotionRedemption{
+				PromotionID:    *order.PromotionID,
+				OrderID:        order.ID,
+				UserID:         order.UserID,
+				GuestEmail:     order.GuestEmail,
+				DiscountAmount: order.DiscountAmount,
+			}
+			if err := tx.Create(redemption).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("cart_id = ?", cartID).Delete(&model.CartItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Cart{}).Where("id = ?", cartID).Update("promotion_code", "").Error
+	})
+}
+
+// ReserveStock takes the items of an existing order out of stock unless they are already held.
+// It is idempotent, so repeated calls for the same order never decrement twice.
+func (r *orderRepository) ReserveStock(publicID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var order model.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("public_id = ?", publicID).
+			First(&order).Error; err != nil {
+			return err
+		}
+		if order.StockReserved {
+			return nil
+		}
+
+		var items []model.OrderItem
+		if err := tx.Where("order_id = ?", order.ID).Find(&items).Error; err != nil {
+			return err
+		}
+		if err := reserveItemStock(tx, items); err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Order{}).Where("id = ?", order.ID).Update("stock_reserved", true).Error
+	})
+}
+
+// CancelAndReleaseStock cancels an order that is still in the expected status and, in the same
+// transaction, gives back its promotion use and, when its stock was reserved, the reserved units.
+func (r *orderRepository) CancelAndReleaseStock(publicID string, from model.OrderStatus) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var order model.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Items").
+			Where("public_id = ?", publicID).
+			First(&order).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.Order{}).
+			Where("id = ? AND status = ?", order.ID, from).
+			Updates(map[string]interface{}{
+				"status":         model.OrderStatusCancelled,
+				"stock_reserved": false,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrOrderStatusConflict
+		}
+
+		if order.PromotionID != nil {
+			if err := releasePromotion(tx, &order); err != nil {
+				return err
+			}
+		}
+		if !order.StockReserved {
+			return nil
+		}
+		return releaseItemStock(tx, order.Items)
+	})
+}
+
+// reserveItemStock takes the variants of order items out of stock. Variants are locked in a stable
+// order, and product totals only after all of them, so concurrent checkouts cannot deadlock.
+func reserveItemStock(tx *gorm.DB, items []model.OrderItem) error {
+	sorted := sortedByVariant(items)
+	productIDs := make([]uint, 0, len(sorted))
+	for _, item := range sorted {
+		if item.VariantID == nil {
+			return fmt.Errorf("%w: variant of product %d was removed", ErrInsufficientStock, item.ProductID)
+		}
+		if err := decrementStock(tx, *item.VariantID, item.Quantity); err != nil {
+			return err
+		}
+		productIDs = append(productIDs, item.ProductID)
+	}
+	return syncProductStock(tx, productIDs)
+}
+
+// releaseItemStock returns the units of order items to their variants. Units of removed variants
+// have nowhere to go and are dropped.
+func releaseItemStock(tx *gorm.DB, items []model.OrderItem) error {
+	sorted := sortedByVariant(items)
+	productIDs := make([]uint, 0, len(sorted))
+	for _, item := range sorted {
+		if item.VariantID == nil {
+			continue
+		}
+		if err := incrementStock(tx, *item.VariantID, item.Quantity); err != nil {
+			return err
+		}
+		productIDs = append(productIDs, item.ProductID)
+	}
+	return syncProductStock(tx, productIDs)
+}
+
+func sortedByVariant(items []model.OrderItem) []model.OrderItem {
+	sorted := make([]model.OrderItem, len(items))
+	copy(sorted, items)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].VariantID == nil || sorted[j].VariantID == nil {
+			return sorted[j].VariantID == nil && sorted[i].VariantID != nil
+		}
+		return *sorted[i].VariantID < *sorted[j].VariantID
+	})
+	return sorted
+}
+
+// FindExpiredReservations returns pending orders whose stock reservation has lapsed, oldest first.
+// Orders held for payment review keep their stock until an operator resolves them.
+func (r *orderRepository) FindExpiredReservations(now time.Time, limit int) ([]model.Order, error) {
+	var orders []model.Order
+	err := r.db.Preload("Items").
+		Where("status = ? AND stock_reserved = ? AND reservation_expires_at IS NOT NULL AND reservation_expires_at <= ?", model.OrderStatusPending, true, now).
+		Where("payment_review_reason IS NULL OR payment_review_reason = ''").
+		Order("reservation_expires_at ASC").
+		Limit(limit).
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// ExtendReservation pushes back the reservation deadline of a pending order. Deadlines are never shortened.
+func (r *orderRepository) ExtendReservation(publicID string, until time.Time) error {
+	return r.db.Model(&model.Order{}).
+		Where("public_id = ? AND status = ? AND stock_reserved = ? AND reservation_expires_at < ?", publicID, model.OrderStatusPending, true, until).
+		Update("reservation_expires_at", until).Error
+}
+
+// FlagPaymentReview holds an order for manual payment review. It reports false when the order
+// was already flagged, so repeated webhook deliveries do not raise the alarm twice.
+func (r *orderRepository) FlagPaymentReview(publicID string, reason string, at time.Time) (bool, error) {
+	result := r.db.Model(&model.Order{}).
+		Where("public_id = ? AND (payment_review_reason IS NULL OR payment_review_reason = '')", publicID).
+		Updates(map[string]interface{}{
+			"payment_review_reason": reason,
+			"payment_review_at":     at,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
 func (r *orderRepository) FindByID(id uint) (*model.Order, error) {
 	var order model.Order
 	err := r.db.Preload("Items").Preload("Items.Product").First(&order, id).Error
@@ -60,8 +265,72 @@ func (r *orderRepository) FindByPublicIDWithItems(publicID string) (*model.Order
 	return &order, nil
 }
 
+// FindByGuestLookupTokenHash retrieves a guest order, including items, by the hash of its lookup token.
+func (r *orderRepository) FindByGuestLookupTokenHash(tokenHash string) (*model.Order, error) {
+	var order model.Order
+	err := r.db.Preload("Items").Where("guest_lookup_token_hash = ?", tokenHash).First(&order).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// ClaimGuestOrders moves guest orders placed with the given email to the user, together with their
+// payments. Only orders from the guest session or whose lookup token hash is given are claimed, so
+// knowing an email address is not enough. Returns the public IDs of the claimed orders.
+func (r *orderRepository) ClaimGuestOrders(userID string, email string, guestID string, tokenHashes []string) ([]string, error) {
+	if guestID == "" && len(tokenHashes) == 0 {
+		return nil, nil
+	}
+	var claimed []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var proof []string
+		var args []interface{}
+		if guestID != "" {
+			proof = append(proof, "guest_id = ?")
+			args = append(args, guestID)
+		}
+		if len(tokenHashes) > 0 {
+			proof = append(proof, "guest_lookup_token_hash IN ?")
+			args = append(args, tokenHashes)
+		}
+
+		var orders []model.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "public_id").
+			Where("user_id IS NULL AND LOWER(guest_email) = LOWER(?)", email).
+			Where("("+strings.Join(proof, " OR ")+")", args...).
+			Find(&orders).Error; err != nil {
+			return err
+		}
+		if len(orders) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(orders))
+		for i := range orders {
+			ids[i] = orders[i].ID
+			claimed = append(claimed, orders[i].PublicID)
+		}
+		if err := tx.Model(&model.Order{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"user_id": userID, "updated_at": time.Now()}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Payment{}).
+			Where("user_id IS NULL AND order_public_id IN ?", claimed).
+			Update("user_id", userID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
 // ListOrders implements OrderRepository.ListOrders
-func (r *orderRepository) ListOrders(status *string, startDate *time.Time, endDate *time.Time, page int, perPage int) ([]model.Order, int64, float64, error) {
+func (r *orderRepository) ListOrders(status *string, startDate *time.Time, endDate *time.Time, page int, perPage int) ([]model.Order, int64, money.Amount, error) {
 	var orders []model.Order
 
 	// Base query for count and list
@@ -93,8 +362,8 @@ func (r *orderRepository) ListOrders(status *string, startDate *time.Time, endDa
 	if endDate != nil {
 		revQuery = revQuery.Where("created_at <= ?", *endDate)
 	}
-	var totalRevenue float64
-	if err := revQuery.Select("COALESCE(SUM(total_amount),0)").Scan(&totalRevenue).Error; err != nil {
+	var totalRevenue money.Amount
+	if err := revQuery.Select("COALESCE(SUM(total_amount_cents),0)").Scan(&totalRevenue).Error; err != nil {
 		return nil, 0, 0, err
 	}
 
@@ -149,3 +418,27 @@ func (r *orderRepository) UpdateStatusByPublicID(publicID string, status model.O
 		Where("public_id = ?", publicID).
 		Update("status", status).Error
 }
+
+// UpdateFulfillmentStatus moves an order from one status to another, optionally updating shipping fields.
+// The update only applies while the order is still in the expected status.
+func (r *orderRepository) UpdateFulfillmentStatus(publicID string, from model.OrderStatus, to model.OrderStatus, shippingStatus string, tracking string) error {
+	updates := map[string]interface{}{
+		"status": to,
+	}
+	if shippingStatus != "" {
+		updates["shipping_status"] = shippingStatus
+	}
+	if tracking != "" {
+		updates["shipping_tracking"] = tracking
+	}
+	result := r.db.Model(&model.Order{}).
+		Where("public_id = ? AND status = ?", publicID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderStatusConflict
+	}
+	return nil
+}
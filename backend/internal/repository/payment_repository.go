@@ -1,16 +1,40 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrRefundExceedsPayment is returned when a refund would return more than was captured.
+var ErrRefundExceedsPayment = errors.New("refund exceeds captured amount")
+
+// OrderPaymentTotals sums an order's payments next to the order total, for reconciliation.
+type OrderPaymentTotals struct {
+	OrderPublicID string
+	OrderStatus   model.OrderStatus
+	TotalAmount   money.Amount
+	CapturedCents int64
+	RefundedCents int64
+	PaymentCount  int
+}
+
 // PaymentRepository manages payment records for reconciliation.
 type PaymentRepository interface {
 	Create(payment *model.Payment) error
 	FindByIntentID(intentID string) (*model.Payment, error)
 	UpdateStatusByIntentID(intentID string, status model.PaymentStatus, errorCode, errorMessage string) error
 	AttachOrderPublicID(intentID string, orderPublicID string) error
+	FindByOrderPublicID(orderPublicID string) ([]model.Payment, error)
+	ApplyRefund(intentID string, amountCents int64, issue func() error) error
+	SyncProviderRefund(intentID string, refundedCents int64) error
+	FindExpiredPending(provider string, now time.Time, limit int) ([]model.Payment, error)
+	FindStaleOpen(providers []string, createdBefore time.Time, limit int) ([]model.Payment, error)
+	OrderTotals(from, to time.Time) ([]OrderPaymentTotals, error)
 }
 
 type paymentRepository struct {
@@ -52,3 +76,102 @@ func (r *paymentRepository) UpdateStatusByIntentID(intentID string, status model
 func (r *paymentRepository) AttachOrderPublicID(intentID string, orderPublicID string) error {
 	return r.db.Model(&model.Payment{}).Where("intent_id = ?", intentID).Update("order_public_id", orderPublicID).Error
 }
+
+// FindByOrderPublicID returns every payment attempt linked to an order.
+func (r *paymentRepository) FindByOrderPublicID(orderPublicID string) ([]model.Payment, error) {
+	var payments []model.Payment
+	if err := r.db.Where("order_public_id = ?", orderPublicID).Order("created_at ASC").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// ApplyRefund books a refund against a payment and moves it to refunded or partially_refunded.
+// The payment row stays locked while issue sends the refund to the provider, so concurrent refunds
+// are checked against the captured amount one at a time and never reach the provider together.
+// Nothing is booked when the amount exceeds what is refundable or issue fails.
+func (r *paymentRepository) ApplyRefund(intentID string, amountCents int64, issue func() error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var payment model.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("intent_id = ?", intentID).
+			First(&payment).Error; err != nil {
+			return err
+		}
+		if amountCents > payment.RefundableCents() {
+			return ErrRefundExceedsPayment
+		}
+		if err := issue(); err != nil {
+			return err
+		}
+		return tx.Model(&model.Payment{}).
+			Where("id = ?", payment.ID).
+			Updates(map[string]interface{}{
+				"refunded_cents": gorm.Expr("refunded_cents + ?", amountCents),
+				"status": gorm.Expr("CASE WHEN refunded_cents + ? >= amount_cents THEN ? ELSE ? END",
+					amountCents, model.PaymentStatusRefunded, model.PaymentStatusPartiallyRefunded),
+			}).Error
+	})
+}
+
+// SyncProviderRefund records the total refunded amount reported by the provider, which includes
+// refunds issued outside the store such as from the provider dashboard. The total never decreases,
+// so replayed or late events cannot undo refunds booked by ApplyRefund.
+func (r *paymentRepository) SyncProviderRefund(intentID string, refundedCents int64) error {
+	return r.db.Model(&model.Payment{}).
+		Where("intent_id = ?", intentID).
+		Updates(map[string]interface{}{
+			"refunded_cents": gorm.Expr("GREATEST(refunded_cents, LEAST(?, amount_cents))", refundedCents),
+			"status": gorm.Expr("CASE WHEN GREATEST(refunded_cents, LEAST(?, amount_cents)) >= amount_cents THEN ? ELSE ? END",
+				refundedCents, model.PaymentStatusRefunded, model.PaymentStatusPartiallyRefunded),
+		}).Error
+}
+
+// FindExpiredPending returns unpaid payments of a provider whose payment deadline has passed, oldest first.
+func (r *paymentRepository) FindExpiredPending(provider string, now time.Time, limit int) ([]model.Payment, error) {
+	var payments []model.Payment
+	err := r.db.Where("provider = ? AND status = ? AND expires_at IS NOT NULL AND expires_at <= ?", provider, model.PaymentStatusPending, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// FindStaleOpen returns pending or processing payments of the given providers created before the cutoff,
+// i.e. still waiting for a webhook, oldest first.
+func (r *paymentRepository) FindStaleOpen(providers []string, createdBefore time.Time, limit int) ([]model.Payment, error) {
+	var payments []model.Payment
+	err := r.db.Where("provider IN ? AND status IN ? AND created_at < ?", providers,
+		[]model.PaymentStatus{model.PaymentStatusPending, model.PaymentStatusProcessing}, createdBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// OrderTotals returns, for each order created in [from, to), the order total and the sum of its captured
+// and refunded payments. Orders without payments are included with zero sums.
+func (r *paymentRepository) OrderTotals(from, to time.Time) ([]OrderPaymentTotals, error) {
+	captured := []model.PaymentStatus{model.PaymentStatusSucceeded, model.PaymentStatusRefunded, model.PaymentStatusPartiallyRefunded}
+	var totals []OrderPaymentTotals
+	err := r.db.Table("orders AS o").
+		Select(`o.public_id AS order_public_id, o.status AS order_status, o.total_amount_cents AS total_amount,
+			COALESCE(SUM(p.amount_cents) FILTER (WHERE p.status IN ?), 0) AS captured_cents,
+			COALESCE(SUM(p.refunded_cents), 0) AS refunded_cents,
+			COUNT(p.id) AS payment_count`, captured).
+		Joins("LEFT JOIN payments AS p ON p.order_public_id = o.public_id").
+		Where("o.created_at >= ? AND o.created_at < ? AND o.deleted_at IS NULL", from, to).
+		Group("o.public_id, o.status, o.total_amount_cents").
+		Order("o.public_id").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
@@ -3,17 +3,24 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
 	"github.com/leoferamos/aroma-sense/internal/utils"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
+// ErrInsufficientStock is returned when a product does not have enough units to fulfill a request.
+var ErrInsufficientStock = errors.New("insufficient stock")
+
 type ProductRepository interface {
 	Create(input dto.ProductFormDTO, imageURL string, thumbnailURL string) (uint, error)
 	FindAll(limit int) ([]model.Product, error)
@@ -22,9 +29,20 @@ type ProductRepository interface {
 	FindBySlug(slug string) (model.Product, error)
 	SearchProducts(ctx context.Context, query string, limit int, offset int, sort string) ([]model.Product, int, error)
 	SearchProductsByGender(ctx context.Context, query string, limit int, offset int, sort string, gender string) ([]model.Product, int, error)
+	FilterProducts(ctx context.Context, query string, filter dto.ProductFilter, limit int, offset int, sort string) ([]model.Product, int, error)
+	FacetCounts(ctx context.Context, query string, filter dto.ProductFilter) (dto.ProductFacets, error)
+	Suggest(ctx context.Context, term string, limit int) (dto.SuggestResponse, error)
 	Update(product *model.Product) error
 	Delete(id uint) error
-	DecrementStock(productID uint, quantity int) error
+	DecrementStock(variantID uint, quantity int) error
+	FindVariantBySKU(sku string) (*model.ProductVariant, error)
+	CreateVariant(variant *model.ProductVariant) error
+	UpdateVariant(variant *model.ProductVariant, fields []string) error
+	DeleteVariant(variant *model.ProductVariant) error
+	CreateImage(image *model.ProductImage) error
+	UpdateImage(image *model.ProductImage) error
+	DeleteImage(image *model.ProductImage) error
+	ReorderImages(productID uint, imageIDs []uint) error
 	EnsureUniqueSlug(base string) (string, error)
 	UpsertProductEmbedding(productID uint, embedding []float32) error
 	HasProductEmbedding(productID uint) (bool, error)
@@ -40,7 +58,7 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 	return &productRepository{db: db}
 }
 
-// Create inserts a new product into the database
+// Create inserts a new product into the database together with its default variant and primary image
 func (r *productRepository) Create(input dto.ProductFormDTO, imageURL string, thumbnailURL string) (uint, error) {
 	// Generate unique slug from brand + name
 	base := utils.Slugify(input.Brand, input.Name)
@@ -70,12 +88,51 @@ func (r *productRepository) Create(input dto.ProductFormDTO, imageURL string, th
 		NotesHeart:    input.NotesHeart,
 		NotesBase:     input.NotesBase,
 	}
-	if err := r.db.Create(&product).Error; err != nil {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&product).Error; err != nil {
+			return err
+		}
+		variant := model.ProductVariant{
+			ProductID:     product.ID,
+			SKU:           input.SKU,
+			SizeML:        input.SizeML,
+			Concentration: model.Concentration(input.Concentration),
+			Price:         input.Price,
+			StockQuantity: input.StockQuantity,
+			Weight:        input.Weight,
+		}
+		if variant.SKU == "" {
+			variant.SKU = defaultSKU(product.ID)
+		}
+		if variant.Concentration == "" {
+			variant.Concentration = model.ConcentrationEDP
+		}
+		if input.Barcode != "" {
+			variant.Barcode = &input.Barcode
+		}
+		if err := tx.Create(&variant).Error; err != nil {
+			return err
+		}
+		image := model.ProductImage{
+			ProductID:    product.ID,
+			ImageURL:     imageURL,
+			ThumbnailURL: thumbnailURL,
+			AltText:      input.ImageAltText,
+			IsPrimary:    true,
+		}
+		return tx.Create(&image).Error
+	})
+	if err != nil {
 		return 0, err
 	}
 	return product.ID, nil
 }
 
+// defaultSKU is the SKU given to a product's first variant when the admin does not choose one
+func defaultSKU(productID uint) string {
+	return fmt.Sprintf("AS-%06d", productID)
+}
+
 // FindAll retrieves all products, limited by the specified number
 func (r *productRepository) FindAll(limit int) ([]model.Product, error) {
 	var products []model.Product
@@ -105,27 +162,71 @@ func (r *productRepository) FindAllPaginated(limit int, offset int) ([]model.Pro
 	if offset > 0 {
 		query = query.Offset(offset)
 	}
-	err := query.Find(&products).Error
-	return products, int(total), err
+	if err := query.Find(&products).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadVariants(products); err != nil {
+		return nil, 0, err
+	}
+	return products, int(total), nil
 }
 
 // FindByID retrieves a product by its ID
 func (r *productRepository) FindByID(id uint) (model.Product, error) {
 	var product model.Product
-	err := r.db.First(&product, id).Error
+	err := r.db.Preload("Variants", orderVariants).Preload("Images", orderImages).First(&product, id).Error
 	return product, err
 }
 
 // FindBySlug retrieves a product by its slug
 func (r *productRepository) FindBySlug(slug string) (model.Product, error) {
 	var product model.Product
-	err := r.db.Where("slug = ?", slug).First(&product).Error
+	err := r.db.Where("slug = ?", slug).Preload("Variants", orderVariants).Preload("Images", orderImages).First(&product).Error
 	return product, err
 }
 
-// Update updates an existing product in the database
+// orderVariants lists variants default first
+func orderVariants(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+// orderImages lists gallery images in display order
+func orderImages(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+// loadVariants attaches the variants of the given products with a single query
+func (r *productRepository) loadVariants(products []model.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(products))
+	for i := range products {
+		ids[i] = products[i].ID
+	}
+	var variants []model.ProductVariant
+	if err := orderVariants(r.db.Where("product_id IN ?", ids)).Find(&variants).Error; err != nil {
+		return err
+	}
+	byProduct := make(map[uint][]model.ProductVariant, len(products))
+	for _, v := range variants {
+		byProduct[v.ProductID] = append(byProduct[v.ProductID], v)
+	}
+	for i := range products {
+		products[i].Variants = byProduct[products[i].ID]
+	}
+	return nil
+}
+
+// Update updates an existing product in the database. Price, weight and stock are derived from
+// the variants and the image URLs from the gallery, which are saved through their own methods.
 func (r *productRepository) Update(product *model.Product) error {
-	return r.db.Save(product).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Variants", "Images", "Price", "Weight", "StockQuantity", "ImageURL", "ThumbnailURL").Save(product).Error; err != nil {
+			return err
+		}
+		return syncVariantTotals(tx, product.ID)
+	})
 }
 
 // Delete removes a product from the database by its ID
@@ -133,11 +234,200 @@ func (r *productRepository) Delete(id uint) error {
 	return r.db.Delete(&model.Product{}, id).Error
 }
 
-// DecrementStock decreases the stock quantity of a product
-func (r *productRepository) DecrementStock(productID uint, quantity int) error {
-	return r.db.Model(&model.Product{}).
-		Where("id = ? AND stock_quantity >= ?", productID, quantity).
-		UpdateColumn("stock_quantity", gorm.Expr("stock_quantity - ?", quantity)).Error
+// DecrementStock decreases the stock quantity of a product variant.
+// Returns ErrInsufficientStock when the variant does not have enough units.
+func (r *productRepository) DecrementStock(variantID uint, quantity int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := decrementStock(tx, variantID, quantity); err != nil {
+			return err
+		}
+		var productID uint
+		if err := tx.Model(&model.ProductVariant{}).Where("id = ?", variantID).Select("product_id").Scan(&productID).Error; err != nil {
+			return err
+		}
+		return syncProductStock(tx, []uint{productID})
+	})
+}
+
+// decrementStock atomically takes quantity units from a variant, never letting stock go negative.
+// Callers refresh the product's total stock with syncProductStock once all variants are updated.
+func decrementStock(db *gorm.DB, variantID uint, quantity int) error {
+	result := db.Model(&model.ProductVariant{}).
+		Where("id = ? AND stock_quantity >= ?", variantID, quantity).
+		UpdateColumn("stock_quantity", gorm.Expr("stock_quantity - ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: variant %d", ErrInsufficientStock, variantID)
+	}
+	return nil
+}
+
+// incrementStock returns quantity units to a variant.
+func incrementStock(db *gorm.DB, variantID uint, quantity int) error {
+	return db.Model(&model.ProductVariant{}).
+		Where("id = ?", variantID).
+		UpdateColumn("stock_quantity", gorm.Expr("stock_quantity + ?", quantity)).Error
+}
+
+// syncProductStock recomputes the total stock of products from their variants, in ID order so
+// concurrent transactions lock products consistently.
+func syncProductStock(db *gorm.DB, productIDs []uint) error {
+	ids := append([]uint(nil), productIDs...)
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for i, id := range ids {
+		if id == 0 || (i > 0 && id == ids[i-1]) {
+			continue
+		}
+		err := db.Exec(`UPDATE products SET stock_quantity =
+			COALESCE((SELECT SUM(v.stock_quantity) FROM product_variants v WHERE v.product_id = products.id), 0)
+			WHERE id = ?`, id).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindVariantBySKU returns the variant with the given SKU, or nil when there is none
+func (r *productRepository) FindVariantBySKU(sku string) (*model.ProductVariant, error) {
+	var variant model.ProductVariant
+	if err := r.db.Where("sku = ?", sku).First(&variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// CreateVariant adds a variant to a product and refreshes the product's price, stock and weight
+func (r *productRepository) CreateVariant(variant *model.ProductVariant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(variant).Error; err != nil {
+			return err
+		}
+		return syncVariantTotals(tx, variant.ProductID)
+	})
+}
+
+// UpdateVariant writes the named fields of a variant and refreshes the product's price, stock and
+// weight. Other columns are left alone, so stock taken by concurrent checkouts is not overwritten.
+func (r *productRepository) UpdateVariant(variant *model.ProductVariant, fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(variant).Select(fields).Updates(variant).Error; err != nil {
+			return err
+		}
+		return syncVariantTotals(tx, variant.ProductID)
+	})
+}
+
+// DeleteVariant removes a variant and refreshes the product's price, stock and weight. Cart lines
+// holding the variant are removed with it; order items keep their snapshot.
+func (r *productRepository) DeleteVariant(variant *model.ProductVariant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.ProductVariant{}, variant.ID).Error; err != nil {
+			return err
+		}
+		return syncVariantTotals(tx, variant.ProductID)
+	})
+}
+
+// syncVariantTotals recomputes the product columns that summarize its variants: the lowest price,
+// the total stock and the default variant's weight.
+func syncVariantTotals(db *gorm.DB, productID uint) error {
+	return db.Exec(`
+		UPDATE products SET
+			price_cents = COALESCE((SELECT MIN(v.price_cents) FROM product_variants v WHERE v.product_id = products.id), price_cents),
+			stock_quantity = COALESCE((SELECT SUM(v.stock_quantity) FROM product_variants v WHERE v.product_id = products.id), 0),
+			weight = COALESCE((SELECT v.weight FROM product_variants v WHERE v.product_id = products.id ORDER BY v.position, v.id LIMIT 1), weight),
+			updated_at = NOW()
+		WHERE id = ?`, productID).Error
+}
+
+// CreateImage adds an image to a product's gallery. A primary image replaces the current one.
+func (r *productRepository) CreateImage(image *model.ProductImage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if image.IsPrimary {
+			if err := clearPrimaryImage(tx, image.ProductID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(image).Error; err != nil {
+			return err
+		}
+		return syncPrimaryImage(tx, image.ProductID)
+	})
+}
+
+// UpdateImage saves a gallery image. A primary image replaces the current one.
+func (r *productRepository) UpdateImage(image *model.ProductImage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if image.IsPrimary {
+			if err := clearPrimaryImage(tx, image.ProductID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(image).Error; err != nil {
+			return err
+		}
+		return syncPrimaryImage(tx, image.ProductID)
+	})
+}
+
+// DeleteImage removes a gallery image. When it was the primary image the first remaining image
+// becomes primary.
+func (r *productRepository) DeleteImage(image *model.ProductImage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.ProductImage{}, image.ID).Error; err != nil {
+			return err
+		}
+		return syncPrimaryImage(tx, image.ProductID)
+	})
+}
+
+// ReorderImages sets the gallery positions to the order of imageIDs
+func (r *productRepository) ReorderImages(productID uint, imageIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for position, id := range imageIDs {
+			err := tx.Model(&model.ProductImage{}).
+				Where("id = ? AND product_id = ?", id, productID).
+				Updates(map[string]interface{}{"position": position, "updated_at": time.Now()}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// clearPrimaryImage unsets the primary flag of a product's images
+func clearPrimaryImage(db *gorm.DB, productID uint) error {
+	return db.Model(&model.ProductImage{}).
+		Where("product_id = ? AND is_primary", productID).
+		Update("is_primary", false).Error
+}
+
+// syncPrimaryImage promotes the first gallery image when the product has no primary image and
+// mirrors the primary image into the product's image and thumbnail URLs.
+func syncPrimaryImage(db *gorm.DB, productID uint) error {
+	err := db.Exec(`
+		UPDATE product_images SET is_primary = TRUE, updated_at = NOW()
+		WHERE id = (SELECT i.id FROM product_images i WHERE i.product_id = ? ORDER BY i.position, i.id LIMIT 1)
+		  AND NOT EXISTS (SELECT 1 FROM product_images p WHERE p.product_id = ? AND p.is_primary)`, productID, productID).Error
+	if err != nil {
+		return err
+	}
+	return db.Exec(`
+		UPDATE products SET
+			image_url = COALESCE((SELECT i.image_url FROM product_images i WHERE i.product_id = products.id AND i.is_primary), ''),
+			thumbnail_url = COALESCE((SELECT i.thumbnail_url FROM product_images i WHERE i.product_id = products.id AND i.is_primary), ''),
+			updated_at = NOW()
+		WHERE id = ?`, productID).Error
 }
 
 // SearchProducts performs a search with pagination and sort.
@@ -172,6 +462,9 @@ func (r *productRepository) SearchProducts(ctx context.Context, query string, li
 	if err := r.db.WithContext(ctx).Raw(selectSQL, args...).Scan(&products).Error; err != nil {
 		return nil, 0, err
 	}
+	if err := r.loadVariants(products); err != nil {
+		return nil, 0, err
+	}
 
 	// Count total matches
 	var total int64
@@ -230,6 +523,9 @@ func (r *productRepository) SearchProductsByGender(ctx context.Context, query st
 	if err := r.db.WithContext(ctx).Raw(selectSQL, args...).Scan(&products).Error; err != nil {
 		return nil, 0, err
 	}
+	if err := r.loadVariants(products); err != nil {
+		return nil, 0, err
+	}
 
 	var total int64
 	countSQL := `SELECT COUNT(*) FROM products p WHERE p.search_vector @@ websearch_to_tsquery('portuguese', unaccent(?))` + genderClause
@@ -242,6 +538,246 @@ func (r *productRepository) SearchProductsByGender(ctx context.Context, query st
 	return products, int(total), nil
 }
 
+// productFacet is a filterable product attribute. Array attributes match when they share any value
+// with the filter.
+type productFacet struct {
+	name   string
+	column string
+	array  bool
+	values func(f dto.ProductFilter) []string
+}
+
+var productFacets = []productFacet{
+	{name: "brand", column: "brand", values: func(f dto.ProductFilter) []string { return f.Brands }},
+	{name: "gender", column: "gender", values: func(f dto.ProductFilter) []string { return f.Genders }},
+	{name: "accords", column: "accords", array: true, values: func(f dto.ProductFilter) []string { return f.Accords }},
+	{name: "occasions", column: "occasions", array: true, values: func(f dto.ProductFilter) []string { return f.Occasions }},
+	{name: "seasons", column: "seasons", array: true, values: func(f dto.ProductFilter) []string { return f.Seasons }},
+	{name: "intensity", column: "intensity", values: func(f dto.ProductFilter) []string { return f.Intensities }},
+	{name: "price_range", column: "price_range", values: func(f dto.ProductFilter) []string { return f.PriceRanges }},
+	{name: "category", column: "category", values: func(f dto.ProductFilter) []string { return f.Categories }},
+}
+
+// priceFacet names the price filter when it is left out of the conditions
+const priceFacet = "price"
+
+// productFilterConditions builds the WHERE conditions for a search query and filter. The filter
+// named by skip is left out, which is how each facet is counted against the other filters.
+func productFilterConditions(query string, f dto.ProductFilter, skip string) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if query != "" {
+		conds = append(conds, "p.search_vector @@ websearch_to_tsquery('portuguese', unaccent(?))")
+		args = append(args, query)
+	}
+	for _, facet := range productFacets {
+		values := facet.values(f)
+		if facet.name == skip || len(values) == 0 {
+			continue
+		}
+		if facet.array {
+			conds = append(conds, "p."+facet.column+" && ?::text[]")
+			args = append(args, pq.StringArray(values))
+		} else {
+			conds = append(conds, "p."+facet.column+" IN ?")
+			args = append(args, values)
+		}
+	}
+	if skip != priceFacet {
+		if f.MinPrice != nil {
+			conds = append(conds, "p.price_cents >= ?")
+			args = append(args, *f.MinPrice)
+		}
+		if f.MaxPrice != nil {
+			conds = append(conds, "p.price_cents <= ?")
+			args = append(args, *f.MaxPrice)
+		}
+	}
+	if len(conds) == 0 {
+		return "TRUE", nil
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// FilterProducts lists the products matching an optional search query and the structured filter.
+// Relevance ordering needs a query; without one products are listed latest first.
+func (r *productRepository) FilterProducts(ctx context.Context, query string, filter dto.ProductFilter, limit int, offset int, sort string) ([]model.Product, int, error) {
+	where, whereArgs := productFilterConditions(query, filter, "")
+
+	orderBy := "p.created_at DESC"
+	var orderArgs []interface{}
+	if sort != "latest" && query != "" {
+		orderBy = "ts_rank_cd(p.search_vector, websearch_to_tsquery('portuguese', unaccent(?))) DESC, p.created_at DESC"
+		orderArgs = append(orderArgs, query)
+	}
+
+	selectSQL := `SELECT p.* FROM products p WHERE ` + where + ` ORDER BY ` + orderBy + ` LIMIT ? OFFSET ?`
+	args := append(append(append([]interface{}{}, whereArgs...), orderArgs...), limit, offset)
+
+	var products []model.Product
+	if err := r.db.WithContext(ctx).Raw(selectSQL, args...).Scan(&products).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadVariants(products); err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Raw(`SELECT COUNT(*) FROM products p WHERE `+where, whereArgs...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return products, int(total), nil
+}
+
+// FacetCounts counts the matching products per attribute value with a single query, plus the price
+// bounds. Each attribute is counted against every filter except its own.
+func (r *productRepository) FacetCounts(ctx context.Context, query string, filter dto.ProductFilter) (dto.ProductFacets, error) {
+	parts := make([]string, 0, len(productFacets))
+	var args []interface{}
+	for _, facet := range productFacets {
+		where, whereArgs := productFilterConditions(query, filter, facet.name)
+		if facet.array {
+			parts = append(parts, `(SELECT '`+facet.name+`' AS facet, v.value AS value, COUNT(DISTINCT p.id) AS count
+				FROM products p CROSS JOIN LATERAL unnest(p.`+facet.column+`) AS v(value)
+				WHERE `+where+` AND v.value <> ''
+				GROUP BY v.value)`)
+		} else {
+			parts = append(parts, `(SELECT '`+facet.name+`' AS facet, p.`+facet.column+` AS value, COUNT(*) AS count
+				FROM products p
+				WHERE `+where+` AND p.`+facet.column+` <> ''
+				GROUP BY p.`+facet.column+`)`)
+		}
+		args = append(args, whereArgs...)
+	}
+
+	var rows []struct {
+		Facet string
+		Value string
+		Count int
+	}
+	facetSQL := strings.Join(parts, " UNION ALL ") + ` ORDER BY facet, count DESC, value`
+	if err := r.db.WithContext(ctx).Raw(facetSQL, args...).Scan(&rows).Error; err != nil {
+		return dto.ProductFacets{}, err
+	}
+
+	facets := dto.ProductFacets{}
+	byName := map[string]*[]dto.FacetCount{
+		"brand":       &facets.Brands,
+		"gender":      &facets.Genders,
+		"accords":     &facets.Accords,
+		"occasions":   &facets.Occasions,
+		"seasons":     &facets.Seasons,
+		"intensity":   &facets.Intensities,
+		"price_range": &facets.PriceRanges,
+		"category":    &facets.Categories,
+	}
+	for _, counts := range byName {
+		*counts = []dto.FacetCount{}
+	}
+	for _, row := range rows {
+		if counts, ok := byName[row.Facet]; ok {
+			*counts = append(*counts, dto.FacetCount{Value: row.Value, Count: row.Count})
+		}
+	}
+
+	where, whereArgs := productFilterConditions(query, filter, priceFacet)
+	var bounds struct {
+		Min   money.Amount
+		Max   money.Amount
+		Count int
+	}
+	boundsSQL := `SELECT MIN(p.price_cents) AS min, MAX(p.price_cents) AS max, COUNT(*) AS count FROM products p WHERE ` + where
+	if err := r.db.WithContext(ctx).Raw(boundsSQL, whereArgs...).Scan(&bounds).Error; err != nil {
+		return dto.ProductFacets{}, err
+	}
+	if bounds.Count > 0 {
+		facets.Price = &dto.PriceBounds{Min: bounds.Min, Max: bounds.Max}
+	}
+
+	return facets, nil
+}
+
+// suggestSources are the typeahead groups. Each source yields the suggested value, the product
+// fields shown next to product names and the accent-insensitive text that is matched.
+var suggestSources = []struct {
+	kind   string
+	source string
+}{
+	{"product", `SELECT name AS value, COALESCE(slug, '') AS slug, brand, COALESCE(thumbnail_url, '') AS thumbnail_url,
+		lower(immutable_unaccent(name)) AS norm FROM products`},
+	{"brand", `SELECT DISTINCT brand AS value, '' AS slug, '' AS brand, '' AS thumbnail_url,
+		lower(immutable_unaccent(brand)) AS norm FROM products`},
+	{"accord", `SELECT DISTINCT a AS value, '' AS slug, '' AS brand, '' AS thumbnail_url,
+		lower(immutable_unaccent(a)) AS norm FROM products, unnest(accords) AS a WHERE a <> ''`},
+	{"note", `SELECT DISTINCT n AS value, '' AS slug, '' AS brand, '' AS thumbnail_url,
+		lower(immutable_unaccent(n)) AS norm
+		FROM products, unnest(COALESCE(notes_top, '{}') || COALESCE(notes_heart, '{}') || COALESCE(notes_base, '{}')) AS n
+		WHERE n <> ''`},
+}
+
+// Suggest returns up to limit typeahead suggestions per group for a partial search term. Values that
+// start with the term come first, then values with a word starting with it, then the rest by
+// trigram word similarity.
+func (r *productRepository) Suggest(ctx context.Context, term string, limit int) (dto.SuggestResponse, error) {
+	parts := make([]string, 0, len(suggestSources))
+	args := []interface{}{term, escapeLike(term)}
+	for _, src := range suggestSources {
+		parts = append(parts, `(SELECT '`+src.kind+`' AS kind, s.value, s.slug, s.brand, s.thumbnail_url,
+				CASE WHEN s.norm LIKE q.pattern || '%' THEN 0 WHEN s.norm LIKE '% ' || q.pattern || '%' THEN 1 ELSE 2 END AS rank,
+				word_similarity(q.term, s.norm) AS score
+			FROM (`+src.source+`) s, q
+			WHERE s.norm LIKE '%' || q.pattern || '%' OR q.term <% s.norm
+			ORDER BY rank, score DESC, s.value
+			LIMIT ?)`)
+		args = append(args, limit)
+	}
+	suggestSQL := `WITH q AS (SELECT lower(immutable_unaccent(?)) AS term, lower(immutable_unaccent(?)) AS pattern)
+		` + strings.Join(parts, " UNION ALL ") + `
+		ORDER BY kind, rank, score DESC, value`
+
+	var rows []struct {
+		Kind         string
+		Value        string
+		Slug         string
+		Brand        string
+		ThumbnailURL string
+	}
+	if err := r.db.WithContext(ctx).Raw(suggestSQL, args...).Scan(&rows).Error; err != nil {
+		return dto.SuggestResponse{}, err
+	}
+
+	resp := dto.SuggestResponse{
+		Products: []dto.ProductSuggestion{},
+		Brands:   []string{},
+		Accords:  []string{},
+		Notes:    []string{},
+	}
+	for _, row := range rows {
+		switch row.Kind {
+		case "product":
+			resp.Products = append(resp.Products, dto.ProductSuggestion{
+				Name:         row.Value,
+				Brand:        row.Brand,
+				Slug:         row.Slug,
+				ThumbnailURL: row.ThumbnailURL,
+			})
+		case "brand":
+			resp.Brands = append(resp.Brands, row.Value)
+		case "accord":
+			resp.Accords = append(resp.Accords, row.Value)
+		case "note":
+			resp.Notes = append(resp.Notes, row.Value)
+		}
+	}
+	return resp, nil
+}
+
+// escapeLike escapes the LIKE wildcards of a user-supplied term
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
 // uniqueSlug ensures the provided base slug is unique.
 func (r *productRepository) uniqueSlug(base string) (string, error) {
 	candidate := base
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPromotionUsageLimit is returned when an order would redeem a promotion past its usage limits.
+var ErrPromotionUsageLimit = errors.New("promotion usage limit reached")
+
+// PromotionRepository defines data access for promotions and their redemptions
+type PromotionRepository interface {
+	Create(promotion *model.Promotion) error
+	Update(promotion *model.Promotion) error
+	Delete(id uint) error
+	FindByID(id uint) (*model.Promotion, error)
+	FindByCode(code string) (*model.Promotion, error)
+	List(page int, perPage int) ([]model.Promotion, int64, error)
+	CountRedemptions(promotionID uint, userID string, guestEmail string) (int64, error)
+}
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+// NewPromotionRepository creates a new instance of PromotionRepository
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+// Create inserts a new promotion
+func (r *promotionRepository) Create(promotion *model.Promotion) error {
+	return r.db.Create(promotion).Error
+}
+
+// Update saves every editable field of a promotion. The usage counter is left alone, since
+// orders change it concurrently.
+func (r *promotionRepository) Update(promotion *model.Promotion) error {
+	return r.db.Model(promotion).Select("*").Omit("id", "used_count", "created_at").Updates(promotion).Error
+}
+
+// Delete removes a promotion
+func (r *promotionRepository) Delete(id uint) error {
+	return r.db.Delete(&model.Promotion{}, id).Error
+}
+
+// FindByID returns a promotion by ID, or nil if it does not exist
+func (r *promotionRepository) FindByID(id uint) (*model.Promotion, error) {
+	var promotion model.Promotion
+	if err := r.db.First(&promotion, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// FindByCode returns a promotion by its normalized code, or nil if it does not exist
+func (r *promotionRepository) FindByCode(code string) (*model.Promotion, error) {
+	var promotion model.Promotion
+	if err := r.db.Where("code = ?", code).First(&promotion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// List returns a page of promotions, newest first, with the total count
+func (r *promotionRepository) List(page int, perPage int) ([]model.Promotion, int64, error) {
+	var total int64
+	if err := r.db.Model(&model.Promotion{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var promotions []model.Promotion
+	if err := r.db.Order("created_at DESC, id DESC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&promotions).Error; err != nil {
+		return nil, 0, err
+	}
+	return promotions, total, nil
+}
+
+// CountRedemptions counts how many orders of a customer redeemed a promotion. Customers are
+// identified by user ID, or by email for guests.
+func (r *promotionRepository) CountRedemptions(promotionID uint, userID string, guestEmail string) (int64, error) {
+	return countRedemptions(r.db, promotionID, userID, guestEmail)
+}
+
+func countRedemptions(db *gorm.DB, promotionID uint, userID string, guestEmail string) (int64, error) {
+	query := db.Model(&model.PromotionRedemption{}).Where("promotion_id = ?", promotionID)
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	} else {
+		query = query.Where("user_id IS NULL AND LOWER(guest_email) = LOWER(?)", guestEmail)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// redeemPromotion claims one use of the order's promotion inside the checkout transaction. The
+// promotion row is locked so concurrent orders cannot overshoot the limits.
+func redeemPromotion(tx *gorm.DB, order *model.Order) error {
+	var promotion model.Promotion
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&promotion, *order.PromotionID).Error; err != nil {
+		return err
+	}
+	if promotion.MaxUses != nil && promotion.UsedCount >= *promotion.MaxUses {
+		return ErrPromotionUsageLimit
+	}
+	if promotion.MaxUsesPerUser != nil {
+		used, err := countRedemptions(tx, promotion.ID, order.UserID, order.GuestEmail)
+		if err != nil {
+			return err
+		}
+		if used >= int64(*promotion.MaxUsesPerUser) {
+			return ErrPromotionUsageLimit
+		}
+	}
+	return tx.Model(&model.Promotion{}).Where("id = ?", promotion.ID).
+		UpdateColumn("used_count", gorm.Expr("used_count + 1")).Error
+}
+
+// releasePromotion gives back the use taken by a cancelled order.
+func releasePromotion(tx *gorm.DB, order *model.Order) error {
+	result := tx.Where("order_id = ?", order.ID).Delete(&model.PromotionRedemption{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+	return tx.Model(&model.Promotion{}).Where("id = ? AND used_count > 0", *order.PromotionID).
+		UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"gorm.io/gorm"
+)
+
+// RefundRepository persists refunds issued against payments.
+type RefundRepository interface {
+	Create(refund *model.Refund) error
+	MarkSucceeded(id uint, providerRefundID string) error
+	MarkFailed(id uint, errorMessage string) error
+	ListByOrderPublicID(orderPublicID string) ([]model.Refund, error)
+}
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) RefundRepository {
+	return &refundRepository{db: db}
+}
+
+// Create inserts a new refund, normally in pending status before the gateway is called.
+func (r *refundRepository) Create(refund *model.Refund) error {
+	return r.db.Create(refund).Error
+}
+
+// MarkSucceeded stores the gateway reference of a completed refund.
+func (r *refundRepository) MarkSucceeded(id uint, providerRefundID string) error {
+	return r.db.Model(&model.Refund{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":             model.RefundStatusSucceeded,
+		"provider_refund_id": providerRefundID,
+	}).Error
+}
+
+// MarkFailed records why the gateway rejected a refund.
+func (r *refundRepository) MarkFailed(id uint, errorMessage string) error {
+	return r.db.Model(&model.Refund{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":        model.RefundStatusFailed,
+		"error_message": errorMessage,
+	}).Error
+}
+
+// ListByOrderPublicID returns every refund of an order, oldest first.
+func (r *refundRepository) ListByOrderPublicID(orderPublicID string) ([]model.Refund, error) {
+	var refunds []model.Refund
+	if err := r.db.Where("order_public_id = ?", orderPublicID).Order("created_at ASC, id ASC").Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
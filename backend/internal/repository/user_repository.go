@@ -224,5 +224,7 @@ func (r *userRepository) AnonymizeUser(publicID string, anonymizedEmail string,
 		"suspension_until":         nil,
 		"reactivation_requested":   false,
 		"contestation_deadline":    nil,
+		"marketing_opt_in":         false,
+		"marketing_opt_in_at":      nil,
 	}).Error
 }
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookEventRepository persists the webhook inbox.
+type WebhookEventRepository interface {
+	CreateIfAbsent(event *model.WebhookEvent) (bool, error)
+	FindByID(id uint) (*model.WebhookEvent, error)
+	Claim(id uint, staleBefore time.Time) (bool, error)
+	MarkProcessed(id uint) error
+	MarkFailed(id uint, errorMessage string, nextAttemptAt *time.Time) error
+	FindDue(now time.Time, staleBefore time.Time, limit int) ([]model.WebhookEvent, error)
+	List(status string, page int, perPage int) ([]model.WebhookEvent, int64, error)
+}
+
+type webhookEventRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookEventRepository(db *gorm.DB) WebhookEventRepository {
+	return &webhookEventRepository{db: db}
+}
+
+// CreateIfAbsent stores an event unless the provider already delivered one with the same ID.
+// It reports whether a new row was written.
+func (r *webhookEventRepository) CreateIfAbsent(event *model.WebhookEvent) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "provider"}, {Name: "event_id"}},
+		DoNothing: true,
+	}).Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// FindByID retrieves an event, or nil when it does not exist.
+func (r *webhookEventRepository) FindByID(id uint) (*model.WebhookEvent, error) {
+	var event model.WebhookEvent
+	if err := r.db.First(&event, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Claim marks an event as processing and counts the attempt. Only one caller can claim an event;
+// events stuck in processing since before staleBefore are assumed abandoned by a crashed worker.
+func (r *webhookEventRepository) Claim(id uint, staleBefore time.Time) (bool, error) {
+	result := r.db.Model(&model.WebhookEvent{}).
+		Where("id = ? AND (status IN ? OR (status = ? AND updated_at < ?))",
+			id, []model.WebhookEventStatus{model.WebhookEventPending, model.WebhookEventFailed}, model.WebhookEventProcessing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":   model.WebhookEventProcessing,
+			"attempts": gorm.Expr("attempts + 1"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// MarkProcessed records a successful run.
+func (r *webhookEventRepository) MarkProcessed(id uint) error {
+	return r.db.Model(&model.WebhookEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          model.WebhookEventProcessed,
+		"last_error":      "",
+		"next_attempt_at": nil,
+		"processed_at":    time.Now(),
+	}).Error
+}
+
+// MarkFailed records a failed run. A nil nextAttemptAt stops automatic retries.
+func (r *webhookEventRepository) MarkFailed(id uint, errorMessage string, nextAttemptAt *time.Time) error {
+	return r.db.Model(&model.WebhookEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          model.WebhookEventFailed,
+		"last_error":      errorMessage,
+		"next_attempt_at": nextAttemptAt,
+	}).Error
+}
+
+// FindDue returns events waiting for a retry, including ones abandoned mid-processing, oldest first.
+func (r *webhookEventRepository) FindDue(now time.Time, staleBefore time.Time, limit int) ([]model.WebhookEvent, error) {
+	var events []model.WebhookEvent
+	err := r.db.
+		Where("(status = ? AND updated_at < ?) OR (status = ? AND next_attempt_at <= ?) OR (status = ? AND updated_at < ?)",
+			model.WebhookEventPending, staleBefore,
+			model.WebhookEventFailed, now,
+			model.WebhookEventProcessing, staleBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// List returns events in the given status (all statuses when empty), newest first.
+func (r *webhookEventRepository) List(status string, page int, perPage int) ([]model.WebhookEvent, int64, error) {
+	query := r.db.Model(&model.WebhookEvent{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []model.WebhookEvent
+	err := query.Order("created_at DESC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
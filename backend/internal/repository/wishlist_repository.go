@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WishlistRepository defines the interface for wishlist data access operations
+type WishlistRepository interface {
+	ListByUser(userID string) ([]model.WishlistItem, error)
+	Find(userID string, productID uint) (*model.WishlistItem, error)
+	CountByUser(userID string) (int64, error)
+	CreateIfAbsent(item *model.WishlistItem) (bool, error)
+	Delete(userID string, productID uint) (int64, error)
+	FindChanged(now time.Time, limit int) ([]model.WishlistItem, error)
+	UpdateAlertState(id uint, alertPrice money.Amount, inStock bool) error
+	DeferAlerts(ids []uint, until time.Time) error
+}
+
+type wishlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWishlistRepository(db *gorm.DB) WishlistRepository {
+	return &wishlistRepository{db: db}
+}
+
+// ListByUser returns a user's wishlist with products preloaded, most recently saved first
+func (r *wishlistRepository) ListByUser(userID string) ([]model.WishlistItem, error) {
+	var items []model.WishlistItem
+	err := r.db.Where("user_id = ?", userID).
+		Preload("Product").
+		Order("created_at DESC, id DESC").
+		Find(&items).Error
+	return items, err
+}
+
+// Find returns a user's wishlist entry for a product, or nil when it was not saved
+func (r *wishlistRepository) Find(userID string, productID uint) (*model.WishlistItem, error) {
+	var item model.WishlistItem
+	if err := r.db.Where("user_id = ? AND product_id = ?", userID, productID).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CountByUser returns how many products the user has saved
+func (r *wishlistRepository) CountByUser(userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.WishlistItem{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// CreateIfAbsent saves a product to a wishlist. It reports false when the product was already saved.
+func (r *wishlistRepository) CreateIfAbsent(item *model.WishlistItem) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+		DoNothing: true,
+	}).Create(item)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Delete removes a product from a wishlist and returns how many entries were removed
+func (r *wishlistRepository) Delete(userID string, productID uint) (int64, error) {
+	result := r.db.Where("user_id = ? AND product_id = ?", userID, productID).Delete(&model.WishlistItem{})
+	return result.RowsAffected, result.Error
+}
+
+// FindChanged lists wishlist entries of active users whose product changed since the alert job last
+// looked: the price of an in-stock product fell below the alert price, rose back after an alert, or the
+// stock state flipped. Entries whose alert is deferred until after now are skipped. Products and users
+// are preloaded and entries are grouped by user.
+func (r *wishlistRepository) FindChanged(now time.Time, limit int) ([]model.WishlistItem, error) {
+	var items []model.WishlistItem
+	err := r.db.
+		Joins("JOIN products p ON p.id = wishlist_items.product_id").
+		Joins("JOIN users u ON u.public_id = wishlist_items.user_id AND u.deleted_at IS NULL AND u.deactivated_at IS NULL AND u.deletion_confirmed_at IS NULL").
+		Where(`((p.price_cents < wishlist_items.alert_price_cents AND p.stock_quantity > 0)
+			OR (p.price_cents > wishlist_items.alert_price_cents AND wishlist_items.alert_price_cents < wishlist_items.saved_price_cents)
+			OR wishlist_items.in_stock <> (p.stock_quantity > 0))`).
+		Where("(wishlist_items.alert_deferred_until IS NULL OR wishlist_items.alert_deferred_until <= ?)", now).
+		Preload("Product").
+		Preload("User").
+		Order("wishlist_items.user_id, wishlist_items.id").
+		Limit(limit).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpdateAlertState records the price and stock state a wishlist entry was last checked at and lifts
+// any deferral
+func (r *wishlistRepository) UpdateAlertState(id uint, alertPrice money.Amount, inStock bool) error {
+	return r.db.Model(&model.WishlistItem{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"alert_price_cents":    alertPrice,
+		"in_stock":             inStock,
+		"alert_deferred_until": nil,
+	}).Error
+}
+
+// DeferAlerts keeps wishlist entries out of FindChanged until the given time
+func (r *wishlistRepository) DeferAlerts(ids []uint, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&model.WishlistItem{}).Where("id IN ?", ids).Update("alert_deferred_until", until).Error
+}
@@ -6,6 +6,7 @@ import (
 	admin "github.com/leoferamos/aroma-sense/internal/handler/admin"
 	loghandler "github.com/leoferamos/aroma-sense/internal/handler/log"
 	orderhandler "github.com/leoferamos/aroma-sense/internal/handler/order"
+	paymenthandler "github.com/leoferamos/aroma-sense/internal/handler/payment"
 	product "github.com/leoferamos/aroma-sense/internal/handler/product"
 )
 
@@ -14,7 +15,10 @@ func AdminRoutes(r *gin.Engine, adminUserHandler *admin.AdminUserHandler,
 	productHandler *product.ProductHandler, orderHandler *orderhandler.OrderHandler,
 	auditLogHandler *loghandler.AuditLogHandler,
 	adminContestationHandler *admin.AdminContestationHandler,
-	adminReviewReportHandler *admin.AdminReviewReportHandler) {
+	adminReviewReportHandler *admin.AdminReviewReportHandler,
+	adminPromotionHandler *admin.AdminPromotionHandler,
+	adminCartRecoveryHandler *admin.AdminCartRecoveryHandler,
+	paymentHandler *paymenthandler.PaymentHandler) {
 	adminGroup := r.Group("/admin")
 	adminGroup.Use(auth.JWTAuthMiddleware(), auth.AdminOnly())
 
@@ -37,9 +41,25 @@ func AdminRoutes(r *gin.Engine, adminUserHandler *admin.AdminUserHandler,
 		adminGroup.GET("/products/:id", productHandler.GetProductByID)
 		adminGroup.PATCH("/products/:id", productHandler.UpdateProduct)
 		adminGroup.DELETE("/products/:id", productHandler.DeleteProduct)
+		adminGroup.POST("/products/:id/variants", productHandler.AddVariant)
+		adminGroup.PATCH("/products/:id/variants/:sku", productHandler.UpdateVariant)
+		adminGroup.DELETE("/products/:id/variants/:sku", productHandler.DeleteVariant)
+		adminGroup.POST("/products/:id/images", productHandler.AddImage)
+		adminGroup.PUT("/products/:id/images/order", productHandler.ReorderImages)
+		adminGroup.PUT("/products/:id/images/:imageId", productHandler.ReplaceImage)
+		adminGroup.PATCH("/products/:id/images/:imageId", productHandler.UpdateImage)
+		adminGroup.DELETE("/products/:id/images/:imageId", productHandler.DeleteImage)
 
 		// Order management
 		adminGroup.GET("/orders", orderHandler.ListOrders)
+		adminGroup.GET("/orders/:publicID", orderHandler.AdminGetOrder)
+		adminGroup.PATCH("/orders/:publicID/status", orderHandler.UpdateOrderStatus)
+		adminGroup.POST("/orders/:publicID/refunds", orderHandler.AdminRefundOrder)
+
+		// Payment webhook inbox
+		adminGroup.GET("/webhook-events", paymentHandler.AdminListWebhookEvents)
+		adminGroup.POST("/webhook-events/:id/replay", paymentHandler.AdminReplayWebhookEvent)
+		adminGroup.POST("/payments/:intentID/simulate", paymentHandler.AdminSimulatePayment)
 
 		// Audit logs
 		adminGroup.GET("/audit-logs/:id/detailed", auditLogHandler.GetAuditLogDetailed)
@@ -56,5 +76,15 @@ func AdminRoutes(r *gin.Engine, adminUserHandler *admin.AdminUserHandler,
 		// Review reports
 		adminGroup.GET("/review-reports", adminReviewReportHandler.ListReports)
 		adminGroup.POST("/review-reports/:id/resolve", adminReviewReportHandler.ResolveReport)
+
+		// Promotions
+		adminGroup.GET("/promotions", adminPromotionHandler.ListPromotions)
+		adminGroup.POST("/promotions", adminPromotionHandler.CreatePromotion)
+		adminGroup.GET("/promotions/:id", adminPromotionHandler.GetPromotion)
+		adminGroup.PUT("/promotions/:id", adminPromotionHandler.UpdatePromotion)
+		adminGroup.DELETE("/promotions/:id", adminPromotionHandler.DeletePromotion)
+
+		// Metrics
+		adminGroup.GET("/metrics/abandoned-carts", adminCartRecoveryHandler.GetAbandonedCartMetrics)
 	}
 }
@@ -10,11 +10,14 @@ import (
 // CartRoutes sets up the cart-related routes
 func CartRoutes(r *gin.Engine, handler *carthandler.CartHandler) {
 	cartGroup := r.Group("/cart")
-	cartGroup.Use(auth.JWTAuthMiddleware(), middleware.AccountStatusMiddleware())
+	cartGroup.Use(auth.ShopperAuthMiddleware(), middleware.AccountStatusMiddleware())
 	{
 		cartGroup.GET("", handler.GetCart)
 		cartGroup.POST("", handler.AddItem)
 		cartGroup.DELETE("", handler.ClearCart)
+		cartGroup.POST("/reconcile", handler.ReconcileCart)
+		cartGroup.PUT("/promotion", handler.ApplyPromotion)
+		cartGroup.DELETE("/promotion", handler.RemovePromotion)
 		cartGroup.PATCH("/items/:productSlug", handler.UpdateItemQuantity)
 		cartGroup.DELETE("/items/:productSlug", handler.RemoveItem)
 	}
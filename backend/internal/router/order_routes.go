@@ -9,10 +9,17 @@ import (
 
 // OrderRoutes sets up the order-related routes
 func OrderRoutes(r *gin.Engine, orderHandler *orderhandler.OrderHandler) {
+	// Guest checkout and order tracking do not need an account
+	r.POST("/orders/guest", auth.GuestSessionMiddleware(), middleware.IdempotencyMiddleware(), orderHandler.CreateGuestOrder)
+	r.POST("/orders/track", orderHandler.TrackOrder)
+
 	orderGroup := r.Group("/orders")
 	orderGroup.Use(auth.JWTAuthMiddleware(), middleware.AccountStatusMiddleware())
 	{
 		orderGroup.GET("", orderHandler.ListUserOrders)
-		orderGroup.POST("", orderHandler.CreateOrderFromCart)
+		orderGroup.POST("", middleware.IdempotencyMiddleware(), orderHandler.CreateOrderFromCart)
+		orderGroup.POST("/guest/claim", orderHandler.ClaimGuestOrders)
+		orderGroup.GET("/:publicID", orderHandler.GetUserOrder)
+		orderGroup.POST("/:publicID/cancel", middleware.IdempotencyMiddleware(), orderHandler.CancelOrder)
 	}
 }
@@ -4,17 +4,20 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/leoferamos/aroma-sense/internal/auth"
 	paymenthandler "github.com/leoferamos/aroma-sense/internal/handler/payment"
+	"github.com/leoferamos/aroma-sense/internal/middleware"
 )
 
 // PaymentRoutes defines payment-related routes.
 func PaymentRoutes(r *gin.Engine, handler *paymenthandler.PaymentHandler) {
 
 	payments := r.Group("/payments")
-	payments.Use(auth.JWTAuthMiddleware())
+	payments.Use(auth.ShopperAuthMiddleware())
 	{
-		payments.POST("/intent", handler.CreateIntent)
+		payments.POST("/intent", middleware.IdempotencyMiddleware(), handler.CreateIntent)
+		payments.GET("/:intentID/boleto", handler.GetBoleto)
 	}
 
-	// Payment webhook
+	// Payment webhooks (default gateway and per-gateway)
 	r.POST("/payments/webhook", handler.HandleWebhook)
+	r.POST("/payments/webhook/:provider", handler.HandleWebhook)
 }
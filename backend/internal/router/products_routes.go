@@ -16,6 +16,7 @@ func ProductRoutes(r *gin.Engine, productHandler *product.ProductHandler, review
 	{
 		// Product listing and details
 		publicProductGroup.GET("", productHandler.GetLatestProducts)
+		publicProductGroup.GET("/suggest", productHandler.SuggestProducts)
 		publicProductGroup.GET("/:slug", productHandler.GetProduct)
 
 		// Public review operations
@@ -19,7 +19,8 @@ func SetupRouter(handlers *bootstrap.AppHandlers) *gin.Engine {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", middleware.IdempotencyKeyHeader},
+		ExposeHeaders:    []string{middleware.IdempotentReplayedHeader},
 		AllowCredentials: true,
 	}))
 
@@ -32,8 +33,8 @@ func SetupRouter(handlers *bootstrap.AppHandlers) *gin.Engine {
 	middleware.SetUserProfileService(handlers.UserHandler.UserProfile())
 
 	// Register domain routes
-	UserRoutes(r, handlers.UserHandler, handlers.PasswordResetHandler)
-	AdminRoutes(r, handlers.AdminUserHandler, handlers.ProductHandler, handlers.OrderHandler, handlers.AuditLogHandler, handlers.AdminContestationHandler, handlers.AdminReviewReportHandler)
+	UserRoutes(r, handlers.UserHandler, handlers.AddressHandler, handlers.WishlistHandler, handlers.PasswordResetHandler)
+	AdminRoutes(r, handlers.AdminUserHandler, handlers.ProductHandler, handlers.OrderHandler, handlers.AuditLogHandler, handlers.AdminContestationHandler, handlers.AdminReviewReportHandler, handlers.AdminPromotionHandler, handlers.AdminCartRecoveryHandler, handlers.PaymentHandler)
 	ProductRoutes(r, handlers.ProductHandler, handlers.ReviewHandler)
 	CartRoutes(r, handlers.CartHandler)
 	OrderRoutes(r, handlers.OrderHandler)
@@ -10,7 +10,7 @@ import (
 // ShippingRoutes sets up the shipping-related routes
 func ShippingRoutes(r *gin.Engine, shippingHandler *shipping.ShippingHandler) {
 	grp := r.Group("/shipping")
-	grp.Use(auth.JWTAuthMiddleware(), middleware.AccountStatusMiddleware())
+	grp.Use(auth.ShopperAuthMiddleware(), middleware.AccountStatusMiddleware())
 	{
 		grp.GET("/options", shippingHandler.GetShippingOptions)
 	}
@@ -9,7 +9,7 @@ import (
 )
 
 // UserRoutes sets up the user-related routes
-func UserRoutes(r *gin.Engine, userHandler *userhandler.UserHandler, resetHandler *authhandler.PasswordResetHandler) {
+func UserRoutes(r *gin.Engine, userHandler *userhandler.UserHandler, addressHandler *userhandler.AddressHandler, wishlistHandler *userhandler.WishlistHandler, resetHandler *authhandler.PasswordResetHandler) {
 	userGroup := r.Group("/users")
 	{
 		userGroup.POST("/register", userHandler.RegisterUser)
@@ -25,8 +25,21 @@ func UserRoutes(r *gin.Engine, userHandler *userhandler.UserHandler, resetHandle
 		{
 			authGroup.GET("/me", userHandler.GetProfile)
 			authGroup.PATCH("/me/profile", userHandler.UpdateProfile)
+			authGroup.PATCH("/me/preferences", userHandler.UpdatePreferences)
 			authGroup.POST("/change-password", userHandler.ChangePassword)
 			authGroup.POST("/me/deletion", userHandler.RequestAccountDeletion)
+
+			// Address book
+			authGroup.GET("/me/addresses", addressHandler.ListAddresses)
+			authGroup.POST("/me/addresses", addressHandler.CreateAddress)
+			authGroup.GET("/me/addresses/:addressID", addressHandler.GetAddress)
+			authGroup.PUT("/me/addresses/:addressID", addressHandler.UpdateAddress)
+			authGroup.DELETE("/me/addresses/:addressID", addressHandler.DeleteAddress)
+
+			// Wishlist
+			authGroup.GET("/me/wishlist", wishlistHandler.GetWishlist)
+			authGroup.PUT("/me/wishlist/:productSlug", wishlistHandler.AddItem)
+			authGroup.DELETE("/me/wishlist/:productSlug", wishlistHandler.RemoveItem)
 		}
 		// Routes that require authentication but must remain callable while the account is suspended or in cooling-off period.
 		authNoStatus := userGroup.Group("")
@@ -11,6 +11,7 @@ import (
 
 	"github.com/leoferamos/aroma-sense/internal/bootstrap"
 	"github.com/leoferamos/aroma-sense/internal/job"
+	"github.com/leoferamos/aroma-sense/internal/middleware"
 	"github.com/leoferamos/aroma-sense/internal/router"
 
 	swaggerFiles "github.com/swaggo/files"
@@ -34,6 +35,62 @@ func StartServer(app *bootstrap.AppComponents, addr string) error {
 	)
 	cleanupJob.Start()
 
+	// Release stock held by orders that were never paid
+	reservationJob := job.NewReservationReleaseJob(
+		app.Repos.OrderRepo,
+		app.Services.OrderService,
+		app.Services.PaymentService,
+		app.Services.AuditLogService,
+		app.Services.OrderConfig.ReservationSweepInterval,
+	)
+	reservationJob.Start()
+
+	// Cancel boletos left unpaid past their due date
+	if app.Services.PaymentService != nil {
+		boletoJob := job.NewBoletoOverdueJob(
+			app.Repos.PaymentRepo,
+			app.Services.OrderService,
+			app.Services.PaymentService,
+			app.Services.AuditLogService,
+		)
+		boletoJob.Start()
+
+		// Retry payment webhooks whose processing failed or was interrupted
+		webhookJob := job.NewWebhookRetryJob(
+			app.Repos.WebhookEventRepo,
+			app.Services.PaymentService,
+		)
+		webhookJob.Start()
+
+		// Catch up on payments whose webhook never arrived and report mismatched totals
+		reconciliationJob := job.NewPaymentReconciliationJob(
+			app.Repos.PaymentRepo,
+			app.Services.PaymentService,
+			app.Services.AuditLogService,
+			app.Services.PaymentConfig,
+		)
+		reconciliationJob.Start()
+	}
+
+	// Drop stored idempotent responses once their replay window has passed
+	idempotencyCleanupJob := job.NewIdempotencyCleanupJob(app.Repos.IdempotencyKeyRepo)
+	idempotencyCleanupJob.Start()
+
+	// Delete guest carts abandoned past the guest session lifetime
+	guestCartCleanupJob := job.NewGuestCartCleanupJob(app.Repos.CartRepo, job.GuestCartTTLFromEnv())
+	guestCartCleanupJob.Start()
+
+	// Remind opted-in users of carts they abandoned
+	abandonedCartJob := job.NewAbandonedCartReminderJob(app.Services.CartRecoveryService)
+	abandonedCartJob.Start()
+
+	// Tell users about price drops and restocks of products they wishlisted
+	wishlistAlertJob := job.NewWishlistAlertJob(app.Services.WishlistService)
+	wishlistAlertJob.Start()
+
+	// Provide storage to the Idempotency-Key middleware
+	middleware.SetIdempotencyStore(app.Repos.IdempotencyKeyRepo, middleware.IdempotencyTTLFromEnv())
+
 	// Setup router with all handlers
 	r := router.SetupRouter(app.Handlers)
 
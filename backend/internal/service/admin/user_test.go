@@ -7,6 +7,7 @@ import (
 
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
 	"github.com/leoferamos/aroma-sense/internal/notification"
 	"github.com/leoferamos/aroma-sense/internal/repository"
 	logservice "github.com/leoferamos/aroma-sense/internal/service/log"
@@ -121,6 +122,14 @@ func (m *mockAuditLogService) LogSystemAction(action model.AuditAction, resource
 	return nil
 }
 
+func (m *mockAuditLogService) LogSystemAlert(action model.AuditAction, resource, resourceID, severity string, details map[string]interface{}) error {
+	return nil
+}
+
+func (m *mockAuditLogService) LogOrderAction(actorID *uint, orderPublicID string, action model.AuditAction, details map[string]interface{}, oldValues, newValues map[string]interface{}) error {
+	return nil
+}
+
 func (m *mockAuditLogService) LogDataAccess(userID uint, resource string, resourceID string) error {
 	return nil
 }
@@ -186,6 +195,14 @@ func (m *mockNotificationService) SendOrderConfirmation(to string, order *model.
 	return nil
 }
 
+func (m *mockNotificationService) SendGuestOrderConfirmation(to string, order *model.Order, lookupToken string) error {
+	return nil
+}
+
+func (m *mockNotificationService) SendOrderCancelled(to string, order *model.Order, refundedAmount money.Amount) error {
+	return nil
+}
+
 func (m *mockNotificationService) SendAccountDeactivated(to, reason string, contestationDeadline string) error {
 	return m.sendAccountDeactivatedErr
 }
@@ -218,6 +235,18 @@ func (m *mockNotificationService) SendPromotional(to, subject, htmlBody string)
 	return nil
 }
 
+func (m *mockNotificationService) SendAbandonedCartReminder(to string, items []model.CartItem) error {
+	return nil
+}
+
+func (m *mockNotificationService) SendWishlistAlert(to string, alerts []model.WishlistAlert) error {
+	return nil
+}
+
+func (m *mockNotificationService) SendAdminAlert(subject, message string) error {
+	return nil
+}
+
 // Test helpers
 func createTestUser() *model.User {
 	return &model.User{
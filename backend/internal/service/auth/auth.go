@@ -3,6 +3,7 @@ package service
 import (
 	"crypto/subtle"
 	"fmt"
+	"log"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -77,6 +78,7 @@ func (s *authService) RegisterUser(input dto.CreateUserRequest) error {
 	if err := s.cartService.CreateCartForUser(user.PublicID); err != nil {
 		return apperror.NewCodeMessage("cart_create_failed", "failed to create cart for user")
 	}
+	s.mergeGuestCart(input.GuestID, user.PublicID)
 
 	return nil
 }
@@ -114,6 +116,7 @@ func (s *authService) Login(input dto.LoginRequest) (string, string, *model.User
 	if err := s.cartService.CreateCartForUser(user.PublicID); err != nil {
 		return "", "", nil, apperror.NewCodeMessage("cart_create_failed", "failed to ensure cart exists")
 	}
+	s.mergeGuestCart(input.GuestID, user.PublicID)
 
 	// Generate access token
 	accessToken, err := auth.GenerateJWT(user.PublicID, user.Role)
@@ -146,6 +149,17 @@ func (s *authService) Login(input dto.LoginRequest) (string, string, *model.User
 	return accessToken, refreshToken, user, nil
 }
 
+// mergeGuestCart moves what the visitor added before signing in into their cart. A failed merge
+// leaves the guest cart in place and does not block authentication.
+func (s *authService) mergeGuestCart(guestID string, userID string) {
+	if guestID == "" {
+		return
+	}
+	if err := s.cartService.MergeGuestCart(guestID, userID); err != nil {
+		log.Printf("failed to merge guest cart into user %s: %v", userID, err)
+	}
+}
+
 // RefreshAccessToken validates refresh token and generates new access token
 func (s *authService) RefreshAccessToken(refreshToken string) (string, string, *model.User, error) {
 	refreshTokenHash := auth.HashRefreshToken(refreshToken)
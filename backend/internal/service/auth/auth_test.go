@@ -20,12 +20,35 @@ import (
 // Additional mocks for auth service
 type mockCartService struct {
 	createCartForUserErr error
+	mergeErr             error
+	mergedGuestID        string
 }
 
 func (m *mockCartService) CreateCartForUser(userID string) error {
 	return m.createCartForUserErr
 }
 
+func (m *mockCartService) CreateCartForGuest(guestID string) error {
+	return nil
+}
+
+func (m *mockCartService) MergeGuestCart(guestID string, userID string) error {
+	m.mergedGuestID = guestID
+	return m.mergeErr
+}
+
+func (m *mockCartService) ReconcileCart(userID string, acceptPrices bool, adjustQuantities bool) (*dto.CartResponse, error) {
+	return nil, nil
+}
+
+func (m *mockCartService) ApplyPromotion(userID string, code string) (*dto.CartResponse, error) {
+	return nil, nil
+}
+
+func (m *mockCartService) RemovePromotion(userID string) (*dto.CartResponse, error) {
+	return nil, nil
+}
+
 func (m *mockCartService) GetCartByUserID(userID string) (*model.Cart, error) {
 	return nil, nil
 }
@@ -34,7 +57,11 @@ func (m *mockCartService) GetCartResponse(userID string) (*dto.CartResponse, err
 	return nil, nil
 }
 
-func (m *mockCartService) AddItemToCart(userID string, productSlug string, quantity int) (*dto.CartResponse, error) {
+func (m *mockCartService) GetGuestCartResponse(guestID string) (*dto.CartResponse, error) {
+	return nil, nil
+}
+
+func (m *mockCartService) AddItemToCart(userID string, productSlug string, variantSKU string, quantity int) (*dto.CartResponse, error) {
 	return nil, nil
 }
 
@@ -42,7 +69,7 @@ func (m *mockCartService) UpdateItemQuantity(userID string, itemID uint, quantit
 	return nil, nil
 }
 
-func (m *mockCartService) UpdateItemQuantityBySlug(userID string, productSlug string, quantity int) (*dto.CartResponse, error) {
+func (m *mockCartService) UpdateItemQuantityBySlug(userID string, productSlug string, variantSKU string, quantity int) (*dto.CartResponse, error) {
 	return nil, nil
 }
 
@@ -50,7 +77,7 @@ func (m *mockCartService) RemoveItem(userID string, itemID uint) (*dto.CartRespo
 	return nil, nil
 }
 
-func (m *mockCartService) RemoveItemBySlug(userID string, productSlug string) (*dto.CartResponse, error) {
+func (m *mockCartService) RemoveItemBySlug(userID string, productSlug string, variantSKU string) (*dto.CartResponse, error) {
 	return nil, nil
 }
 
@@ -155,6 +182,14 @@ func (m *mockAuditLogService) LogSystemAction(action model.AuditAction, resource
 	return nil
 }
 
+func (m *mockAuditLogService) LogSystemAlert(action model.AuditAction, resource, resourceID, severity string, details map[string]interface{}) error {
+	return nil
+}
+
+func (m *mockAuditLogService) LogOrderAction(actorID *uint, orderPublicID string, action model.AuditAction, details map[string]interface{}, oldValues, newValues map[string]interface{}) error {
+	return nil
+}
+
 func (m *mockAuditLogService) LogDataAccess(userID uint, resource string, resourceID string) error {
 	return nil
 }
@@ -385,6 +420,30 @@ func TestAuthService_Login(t *testing.T) {
 	}
 }
 
+func TestAuthService_Login_MergesGuestCart(t *testing.T) {
+	os.Setenv("JWT_SECRET", "test-secret-key-for-jwt-generation")
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("ValidPass123!"), bcrypt.DefaultCost)
+	user := &model.User{ID: 1, PublicID: "user123", Email: "user@example.com", PasswordHash: string(hashedPassword), Role: "client"}
+
+	t.Run("guest cart is merged", func(t *testing.T) {
+		cartSvc := &mockCartService{}
+		authSvc := service.NewAuthService(&mockUserRepo{findByEmailUser: user}, cartSvc, &mockAuditLogService{})
+
+		_, _, _, err := authSvc.Login(dto.LoginRequest{Email: "user@example.com", Password: "ValidPass123!", GuestID: "guest-1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "guest-1", cartSvc.mergedGuestID)
+	})
+
+	t.Run("failed merge does not block login", func(t *testing.T) {
+		cartSvc := &mockCartService{mergeErr: errors.New("db down")}
+		authSvc := service.NewAuthService(&mockUserRepo{findByEmailUser: user}, cartSvc, &mockAuditLogService{})
+
+		accessToken, _, _, err := authSvc.Login(dto.LoginRequest{Email: "user@example.com", Password: "ValidPass123!", GuestID: "guest-1"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, accessToken)
+	})
+}
+
 func TestAuthService_RefreshAccessToken(t *testing.T) {
 	os.Setenv("JWT_SECRET", "test-secret-key-for-jwt-generation")
 	refreshToken, expiresAt, _ := auth.GenerateRefreshToken()
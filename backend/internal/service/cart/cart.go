@@ -2,36 +2,47 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 
 	"github.com/leoferamos/aroma-sense/internal/apperror"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/model"
 	"github.com/leoferamos/aroma-sense/internal/repository"
 	productservice "github.com/leoferamos/aroma-sense/internal/service/product"
+	promotionservice "github.com/leoferamos/aroma-sense/internal/service/promotion"
+	"gorm.io/gorm"
 )
 
 // CartService defines the interface for cart-related business logic
 type CartService interface {
 	CreateCartForUser(userID string) error
+	CreateCartForGuest(guestID string) error
+	MergeGuestCart(guestID string, userID string) error
 	GetCartByUserID(userID string) (*model.Cart, error)
 	GetCartResponse(userID string) (*dto.CartResponse, error)
-	AddItemToCart(userID string, productSlug string, quantity int) (*dto.CartResponse, error)
+	GetGuestCartResponse(guestID string) (*dto.CartResponse, error)
+	AddItemToCart(userID string, productSlug string, variantSKU string, quantity int) (*dto.CartResponse, error)
 	UpdateItemQuantity(userID string, itemID uint, quantity int) (*dto.CartResponse, error)
-	UpdateItemQuantityBySlug(userID string, productSlug string, quantity int) (*dto.CartResponse, error)
+	UpdateItemQuantityBySlug(userID string, productSlug string, variantSKU string, quantity int) (*dto.CartResponse, error)
 	RemoveItem(userID string, itemID uint) (*dto.CartResponse, error)
-	RemoveItemBySlug(userID string, productSlug string) (*dto.CartResponse, error)
+	RemoveItemBySlug(userID string, productSlug string, variantSKU string) (*dto.CartResponse, error)
 	ClearCart(userID string) (*dto.CartResponse, error)
+	ReconcileCart(userID string, acceptPrices bool, adjustQuantities bool) (*dto.CartResponse, error)
+	ApplyPromotion(userID string, code string) (*dto.CartResponse, error)
+	RemovePromotion(userID string) (*dto.CartResponse, error)
 }
 
 type cartService struct {
-	repo           repository.CartRepository
-	productService productservice.ProductService
+	repo             repository.CartRepository
+	productService   productservice.ProductService
+	promotionService promotionservice.PromotionService
 }
 
 // NewCartService creates a new instance of CartService
-func NewCartService(repo repository.CartRepository, productService productservice.ProductService) CartService {
-	return &cartService{repo: repo, productService: productService}
+func NewCartService(repo repository.CartRepository, productService productservice.ProductService, promotionService promotionservice.PromotionService) CartService {
+	return &cartService{repo: repo, productService: productService, promotionService: promotionService}
 }
 
 // CreateCartForUser creates a new empty cart for a user
@@ -49,6 +60,104 @@ func (s *cartService) CreateCartForUser(userID string) error {
 	return s.repo.Create(&cart)
 }
 
+// CreateCartForGuest creates a new empty cart for a guest session, if it does not have one yet
+func (s *cartService) CreateCartForGuest(guestID string) error {
+	if _, err := s.repo.FindByUserID(guestID); err == nil {
+		return nil
+	}
+
+	cart := model.Cart{
+		GuestID: guestID,
+		Items:   []model.CartItem{},
+	}
+	return s.repo.Create(&cart)
+}
+
+// MergeGuestCart moves the items of a guest session's cart into the user's cart, then deletes the
+// guest cart. Quantities of variants in both carts are summed, every quantity is capped at current
+// stock, prices are refreshed to the current variant price, and variants that no longer exist or
+// are out of stock are dropped. The guest's coupon is kept unless the user cart already has one.
+// A guest without a cart is not an error. Any other failure leaves the guest cart untouched, so
+// the merge can be retried on the next sign-in.
+func (s *cartService) MergeGuestCart(guestID string, userID string) error {
+	guestCart, err := s.repo.FindByUserID(guestID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if guestCart == nil || guestCart.GuestID != guestID {
+		return nil
+	}
+	userCart, err := s.repo.FindByUserID(userID)
+	if err != nil || userCart.UserID != userID {
+		return apperror.NewCodeMessage("cart_not_found", "cart not found")
+	}
+
+	existing := make(map[uint]*model.CartItem, len(userCart.Items))
+	for i := range userCart.Items {
+		if variantID := userCart.Items[i].VariantID; variantID != nil {
+			existing[*variantID] = &userCart.Items[i]
+		}
+	}
+
+	var merged []model.CartItem
+	dropped := 0
+	for _, guestItem := range guestCart.Items {
+		if guestItem.ProductID == nil || guestItem.VariantID == nil {
+			dropped++
+			continue
+		}
+		product, err := s.productService.GetProductByID(context.Background(), *guestItem.ProductID)
+		if err != nil {
+			if !productGone(err) {
+				return fmt.Errorf("failed to load product %d of guest cart: %w", *guestItem.ProductID, err)
+			}
+			dropped++
+			continue
+		}
+		variant := variantByID(product, *guestItem.VariantID)
+		if variant == nil || variant.StockQuantity <= 0 {
+			dropped++
+			continue
+		}
+
+		item := model.CartItem{CartID: userCart.ID, ProductID: guestItem.ProductID, VariantID: guestItem.VariantID, Quantity: guestItem.Quantity}
+		if current, ok := existing[*guestItem.VariantID]; ok {
+			item = *current
+			item.Quantity += guestItem.Quantity
+		}
+		if item.Quantity > variant.StockQuantity {
+			item.Quantity = variant.StockQuantity
+		}
+		item.Price = variant.Price
+		item.Product = nil
+		item.Variant = nil
+		merged = append(merged, item)
+	}
+
+	if err := s.repo.MergeGuestCart(guestCart.ID, merged); err != nil {
+		return apperror.NewDomain(err, "cart_update_failed", "failed to merge guest cart")
+	}
+	if guestCart.PromotionCode != "" && userCart.PromotionCode == "" {
+		if err := s.repo.SetPromotionCode(userCart.ID, guestCart.PromotionCode); err != nil {
+			log.Printf("failed to carry promotion %s over to user %s cart: %v", guestCart.PromotionCode, userID, err)
+		}
+	}
+	if dropped > 0 {
+		log.Printf("merged guest cart into user %s: %d unavailable items dropped", userID, dropped)
+	}
+	return nil
+}
+
+// productGone reports whether a product lookup failed because the product no longer exists,
+// rather than for a reason that may go away on retry.
+func productGone(err error) bool {
+	var de *apperror.DomainError
+	return errors.Is(err, gorm.ErrRecordNotFound) || (errors.As(err, &de) && de.Code == "product_not_found")
+}
+
 // GetCartByUserID retrieves a cart by user ID
 func (s *cartService) GetCartByUserID(userID string) (*model.Cart, error) {
 	return s.repo.FindByUserID(userID)
@@ -61,20 +170,127 @@ func (s *cartService) GetCartResponse(userID string) (*dto.CartResponse, error)
 		return nil, err
 	}
 
+	resp := toCartResponse(cart)
+	s.applyPromotion(cart, resp)
+	return resp, nil
+}
+
+// GetGuestCartResponse retrieves a guest's cart, or an empty one when the guest has not added
+// anything yet. Guest carts are only created on writes, so reading never stores one.
+func (s *cartService) GetGuestCartResponse(guestID string) (*dto.CartResponse, error) {
+	cart, err := s.GetCartByUserID(guestID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return toCartResponse(&model.Cart{GuestID: guestID}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toCartResponse(cart)
+	s.applyPromotion(cart, resp)
+	return resp, nil
+}
+
+// applyPromotion prices the cart's coupon against its lines. A coupon that no longer applies stays
+// on the cart with the reason, so the shopper can fix the cart or remove it.
+func (s *cartService) applyPromotion(cart *model.Cart, resp *dto.CartResponse) {
+	if cart.PromotionCode == "" || s.promotionService == nil {
+		return
+	}
+
+	resp.Promotion = &dto.CartPromotionResponse{Code: cart.PromotionCode}
+	discount, err := s.promotionService.Evaluate(cart.PromotionCode, promotionservice.Customer{UserID: cart.UserID}, cartLines(cart), 0)
+	if err != nil {
+		var de *apperror.DomainError
+		if !errors.As(err, &de) {
+			log.Printf("failed to evaluate promotion %s for cart %d: %v", cart.PromotionCode, cart.ID, err)
+			resp.Promotion.Error = "internal_error"
+			return
+		}
+		resp.Promotion.Error = de.Code
+		return
+	}
+
+	resp.Promotion.Name = discount.Promotion.Name
+	resp.Promotion.Type = string(discount.Promotion.Type)
+	resp.Promotion.Discount = discount.Items
+	resp.Promotion.FreeShipping = discount.Promotion.Type == model.PromotionTypeFreeShipping
+	resp.Discount = discount.Items
+	resp.Total = resp.Subtotal - resp.Discount
+}
+
+// cartLines lists the purchasable cart lines at the price captured in the cart
+func cartLines(cart *model.Cart) []promotionservice.Line {
+	lines := make([]promotionservice.Line, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		if item.Product == nil || item.Variant == nil {
+			continue
+		}
+		lines = append(lines, promotionservice.Line{
+			ProductID: item.Product.ID,
+			Brand:     item.Product.Brand,
+			Category:  item.Product.Category,
+			UnitPrice: item.Price,
+			Quantity:  item.Quantity,
+		})
+	}
+	return lines
+}
+
+// ApplyPromotion validates a coupon code against the cart and keeps it on the cart
+func (s *cartService) ApplyPromotion(userID string, code string) (*dto.CartResponse, error) {
+	cart, err := s.GetCartByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, apperror.NewCodeMessage("cart_empty", "cart is empty")
+	}
+
+	discount, err := s.promotionService.Evaluate(code, promotionservice.Customer{UserID: cart.UserID}, cartLines(cart), 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetPromotionCode(cart.ID, discount.Promotion.Code); err != nil {
+		return nil, apperror.NewDomain(err, "cart_update_failed", "failed to apply promotion")
+	}
+
+	return s.GetCartResponse(userID)
+}
+
+// RemovePromotion takes the coupon off the cart
+func (s *cartService) RemovePromotion(userID string) (*dto.CartResponse, error) {
+	cart, err := s.GetCartByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if cart.PromotionCode != "" {
+		if err := s.repo.SetPromotionCode(cart.ID, ""); err != nil {
+			return nil, apperror.NewDomain(err, "cart_update_failed", "failed to remove promotion")
+		}
+	}
+
+	return s.GetCartResponse(userID)
+}
+
+// toCartResponse maps a cart with preloaded products and variants, checking every line against the current variant.
+// Lines whose product was removed are listed with a warning but left out of the totals, since checkout rejects them.
+func toCartResponse(cart *model.Cart) *dto.CartResponse {
 	cartResponse := &dto.CartResponse{
 		Items:     []dto.CartItemResponse{},
-		Total:     0.0,
+		Subtotal:  0,
 		ItemCount: 0,
 	}
 
 	// Convert cart items and calculate totals
 	for _, item := range cart.Items {
-		itemTotal := item.Price * float64(item.Quantity)
+		itemTotal := item.Price.Mul(item.Quantity)
 
 		cartItemResponse := dto.CartItemResponse{
 			Quantity: item.Quantity,
 			Price:    item.Price,
 			Total:    itemTotal,
+			Warnings: lineWarnings(item),
 		}
 
 		if item.Product != nil {
@@ -93,17 +309,92 @@ func (s *cartService) GetCartResponse(userID string) (*dto.CartResponse, error)
 				UpdatedAt:     item.Product.UpdatedAt,
 			}
 		}
+		if item.Variant != nil {
+			variant := dto.ProductVariantResponseFromModel(*item.Variant)
+			cartItemResponse.Variant = &variant
+		}
 
 		cartResponse.Items = append(cartResponse.Items, cartItemResponse)
-		cartResponse.Total += itemTotal
-		cartResponse.ItemCount += item.Quantity
+		if item.Product != nil && item.Variant != nil {
+			cartResponse.Subtotal += itemTotal
+			cartResponse.ItemCount += item.Quantity
+		}
+		if len(cartItemResponse.Warnings) > 0 {
+			cartResponse.HasWarnings = true
+		}
+	}
+	cartResponse.Total = cartResponse.Subtotal
+
+	return cartResponse
+}
+
+// lineWarnings compares a cart line with its variant as it is now
+func lineWarnings(item model.CartItem) []dto.CartItemWarning {
+	if item.Product == nil || item.Variant == nil {
+		return []dto.CartItemWarning{{Code: dto.CartWarningProductRemoved}}
+	}
+
+	var warnings []dto.CartItemWarning
+	if item.Price != item.Variant.Price {
+		previous, current := item.Price, item.Variant.Price
+		warnings = append(warnings, dto.CartItemWarning{Code: dto.CartWarningPriceChanged, PreviousPrice: &previous, CurrentPrice: &current})
+	}
+	available := item.Variant.StockQuantity
+	switch {
+	case available <= 0:
+		warnings = append(warnings, dto.CartItemWarning{Code: dto.CartWarningOutOfStock})
+	case available < item.Quantity:
+		warnings = append(warnings, dto.CartItemWarning{Code: dto.CartWarningInsufficientStock, Available: &available})
+	}
+	return warnings
+}
+
+// ReconcileCart resolves cart warnings: acceptPrices moves every line to the current variant price,
+// adjustQuantities lowers quantities to the available stock and removes lines that can no longer be bought.
+func (s *cartService) ReconcileCart(userID string, acceptPrices bool, adjustQuantities bool) (*dto.CartResponse, error) {
+	cart, err := s.GetCartByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []model.CartItem
+	var removedIDs []uint
+	for _, item := range cart.Items {
+		if item.Product == nil || item.Variant == nil || item.Variant.StockQuantity <= 0 {
+			if adjustQuantities {
+				removedIDs = append(removedIDs, item.ID)
+			}
+			continue
+		}
+
+		changed := false
+		if adjustQuantities && item.Quantity > item.Variant.StockQuantity {
+			item.Quantity = item.Variant.StockQuantity
+			changed = true
+		}
+		if acceptPrices && item.Price != item.Variant.Price {
+			item.Price = item.Variant.Price
+			changed = true
+		}
+		if changed {
+			item.Product = nil
+			item.Variant = nil
+			updated = append(updated, item)
+		}
+	}
+
+	if len(updated) > 0 || len(removedIDs) > 0 {
+		if err := s.repo.ReconcileItems(updated, removedIDs); err != nil {
+			return nil, apperror.NewDomain(err, "cart_update_failed", "failed to update cart")
+		}
 	}
 
-	return cartResponse, nil
+	return s.GetCartResponse(userID)
 }
 
-// AddItemToCart adds an item to the user's cart or increases quantity if exists
-func (s *cartService) AddItemToCart(userID string, productSlug string, quantity int) (*dto.CartResponse, error) {
+// AddItemToCart adds a product variant to the user's cart or increases its quantity if it is already
+// there. An empty variantSKU picks the product's default variant.
+func (s *cartService) AddItemToCart(userID string, productSlug string, variantSKU string, quantity int) (*dto.CartResponse, error) {
 	// Get product ID by slug
 	productID, err := s.productService.GetProductIDBySlug(context.Background(), productSlug)
 	if err != nil {
@@ -115,13 +406,17 @@ func (s *cartService) AddItemToCart(userID string, productSlug string, quantity
 	if err != nil {
 		return nil, apperror.NewCodeMessage("product_not_found", "product not found")
 	}
+	variant, err := variantBySKU(product, variantSKU)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check stock availability
-	if product.StockQuantity <= 0 {
+	if variant.StockQuantity <= 0 {
 		return nil, apperror.NewCodeMessage("insufficient_stock", "product out of stock")
 	}
-	if product.StockQuantity < quantity {
-		return nil, apperror.NewCodeMessage("insufficient_stock", fmt.Sprintf("insufficient stock - only %d items available", product.StockQuantity))
+	if variant.StockQuantity < quantity {
+		return nil, apperror.NewCodeMessage("insufficient_stock", fmt.Sprintf("insufficient stock - only %d items available", variant.StockQuantity))
 	}
 
 	// Get user's cart
@@ -134,16 +429,13 @@ func (s *cartService) AddItemToCart(userID string, productSlug string, quantity
 	var existingItemID uint
 	itemExists := false
 	for _, item := range cart.Items {
-		if item.ProductID == productID {
+		if item.VariantID != nil && *item.VariantID == variant.ID {
 			existingItemID = item.ID
 			itemExists = true
 			// Calculate new total quantity and validate stock
 			newQuantity := item.Quantity + quantity
-			if product.StockQuantity <= 0 {
-				return nil, apperror.NewCodeMessage("insufficient_stock", "product out of stock")
-			}
-			if product.StockQuantity < newQuantity {
-				return nil, apperror.NewCodeMessage("insufficient_stock", fmt.Sprintf("insufficient stock - only %d items available, you already have %d in cart", product.StockQuantity, item.Quantity))
+			if variant.StockQuantity < newQuantity {
+				return nil, apperror.NewCodeMessage("insufficient_stock", fmt.Sprintf("insufficient stock - only %d items available, you already have %d in cart", variant.StockQuantity, item.Quantity))
 			}
 			break
 		}
@@ -159,11 +451,13 @@ func (s *cartService) AddItemToCart(userID string, productSlug string, quantity
 		return s.UpdateItemQuantity(userID, existingItemID, newQuantity)
 	} else {
 		// Create new cart item
+		variantID := variant.ID
 		newItem := model.CartItem{
 			CartID:    cart.ID,
-			ProductID: productID,
+			ProductID: &productID,
+			VariantID: &variantID,
 			Quantity:  quantity,
-			Price:     product.Price,
+			Price:     variant.Price,
 		}
 
 		// Save to database
@@ -176,6 +470,32 @@ func (s *cartService) AddItemToCart(userID string, productSlug string, quantity
 	return s.GetCartResponse(userID)
 }
 
+// variantBySKU picks a product variant by SKU, or the default variant when sku is empty
+func variantBySKU(product dto.ProductResponse, sku string) (*dto.ProductVariantResponse, error) {
+	if len(product.Variants) == 0 {
+		return nil, apperror.NewCodeMessage("product_not_found", "product not found")
+	}
+	if sku == "" {
+		return &product.Variants[0], nil
+	}
+	for i := range product.Variants {
+		if product.Variants[i].SKU == sku {
+			return &product.Variants[i], nil
+		}
+	}
+	return nil, apperror.NewCodeMessage("variant_not_found", "variant not found")
+}
+
+// variantByID returns one of the product's variants, or nil when it no longer exists
+func variantByID(product dto.ProductResponse, id uint) *dto.ProductVariantResponse {
+	for i := range product.Variants {
+		if product.Variants[i].ID == id {
+			return &product.Variants[i]
+		}
+	}
+	return nil
+}
+
 // UpdateItemQuantity updates the quantity of a specific cart item
 func (s *cartService) UpdateItemQuantity(userID string, itemID uint, quantity int) (*dto.CartResponse, error) {
 	// Get the cart item
@@ -210,16 +530,23 @@ func (s *cartService) UpdateItemQuantity(userID string, itemID uint, quantity in
 		}
 	} else {
 		// Validate stock availability for the new quantity
-		product, err := s.productService.GetProductByID(context.Background(), cartItem.ProductID)
+		if cartItem.ProductID == nil || cartItem.VariantID == nil {
+			return nil, apperror.NewCodeMessage("product_not_found", "product not found")
+		}
+		product, err := s.productService.GetProductByID(context.Background(), *cartItem.ProductID)
 		if err != nil {
 			return nil, apperror.NewCodeMessage("product_not_found", "product not found")
 		}
+		variant := variantByID(product, *cartItem.VariantID)
+		if variant == nil {
+			return nil, apperror.NewCodeMessage("variant_not_found", "variant not found")
+		}
 
-		if product.StockQuantity <= 0 {
+		if variant.StockQuantity <= 0 {
 			return nil, apperror.NewCodeMessage("insufficient_stock", "product out of stock")
 		}
-		if product.StockQuantity < quantity {
-			return nil, apperror.NewCodeMessage("insufficient_stock", fmt.Sprintf("insufficient stock - only %d items available", product.StockQuantity))
+		if variant.StockQuantity < quantity {
+			return nil, apperror.NewCodeMessage("insufficient_stock", fmt.Sprintf("insufficient stock - only %d items available", variant.StockQuantity))
 		}
 
 		// Update the quantity
@@ -254,68 +581,59 @@ func (s *cartService) ClearCart(userID string) (*dto.CartResponse, error) {
 	return s.GetCartResponse(userID)
 }
 
-// UpdateItemQuantityBySlug updates the quantity of a cart item by product slug
-func (s *cartService) UpdateItemQuantityBySlug(userID string, productSlug string, quantity int) (*dto.CartResponse, error) {
-	// Get product ID by slug
-	productID, err := s.productService.GetProductIDBySlug(context.Background(), productSlug)
-	if err != nil {
-		return nil, apperror.NewCodeMessage("product_not_found", "product not found")
-	}
-
-	// Get user's cart
-	cart, err := s.GetCartByUserID(userID)
+// UpdateItemQuantityBySlug updates the quantity of a cart item by product slug and variant SKU
+func (s *cartService) UpdateItemQuantityBySlug(userID string, productSlug string, variantSKU string, quantity int) (*dto.CartResponse, error) {
+	itemID, err := s.findLine(userID, productSlug, variantSKU)
 	if err != nil {
 		return nil, err
 	}
 
-	// Find the cart item by product ID
-	var itemID uint
-	found := false
-	for _, item := range cart.Items {
-		if item.ProductID == productID {
-			itemID = item.ID
-			found = true
-			break
-		}
-	}
+	// Use existing UpdateItemQuantity method
+	return s.UpdateItemQuantity(userID, itemID, quantity)
+}
 
-	if !found {
-		return nil, apperror.NewCodeMessage("cart_item_not_found", "cart item not found in user's cart")
+// RemoveItemBySlug removes a cart item by product slug and variant SKU
+func (s *cartService) RemoveItemBySlug(userID string, productSlug string, variantSKU string) (*dto.CartResponse, error) {
+	itemID, err := s.findLine(userID, productSlug, variantSKU)
+	if err != nil {
+		return nil, err
 	}
 
-	// Use existing UpdateItemQuantity method
-	return s.UpdateItemQuantity(userID, itemID, quantity)
+	// Use existing RemoveItem method
+	return s.RemoveItem(userID, itemID)
 }
 
-// RemoveItemBySlug removes a cart item by product slug
-func (s *cartService) RemoveItemBySlug(userID string, productSlug string) (*dto.CartResponse, error) {
+// findLine returns the ID of the cart line holding a product variant. Without a SKU the product must
+// be in the cart exactly once.
+func (s *cartService) findLine(userID string, productSlug string, variantSKU string) (uint, error) {
 	// Get product ID by slug
 	productID, err := s.productService.GetProductIDBySlug(context.Background(), productSlug)
 	if err != nil {
-		return nil, apperror.NewCodeMessage("product_not_found", "product not found")
+		return 0, apperror.NewCodeMessage("product_not_found", "product not found")
 	}
 
 	// Get user's cart
 	cart, err := s.GetCartByUserID(userID)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	// Find the cart item by product ID
-	var itemID uint
-	found := false
+	var matches []uint
 	for _, item := range cart.Items {
-		if item.ProductID == productID {
-			itemID = item.ID
-			found = true
-			break
+		if item.ProductID == nil || *item.ProductID != productID {
+			continue
+		}
+		if variantSKU == "" || (item.Variant != nil && item.Variant.SKU == variantSKU) {
+			matches = append(matches, item.ID)
 		}
 	}
 
-	if !found {
-		return nil, apperror.NewCodeMessage("cart_item_not_found", "cart item not found in user's cart")
+	switch len(matches) {
+	case 0:
+		return 0, apperror.NewCodeMessage("cart_item_not_found", "cart item not found in user's cart")
+	case 1:
+		return matches[0], nil
+	default:
+		return 0, apperror.NewCodeMessage("variant_required", "product is in the cart in several variants")
 	}
-
-	// Use existing RemoveItem method
-	return s.RemoveItem(userID, itemID)
 }
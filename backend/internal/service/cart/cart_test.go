@@ -0,0 +1,485 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	promotionservice "github.com/leoferamos/aroma-sense/internal/service/promotion"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockCartRepo struct {
+	carts        map[string]*model.Cart
+	mergedCartID uint
+	mergedItems  []model.CartItem
+	mergeCalls   int
+	updated      []model.CartItem
+	removedIDs   []uint
+	promotion    map[uint]string
+	created      []model.CartItem
+	deletedIDs   []uint
+}
+
+func (m *mockCartRepo) Create(cart *model.Cart) error { return nil }
+func (m *mockCartRepo) FindByUserID(userID string) (*model.Cart, error) {
+	if cart, ok := m.carts[userID]; ok {
+		return cart, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+func (m *mockCartRepo) Update(cart *model.Cart) error { return nil }
+func (m *mockCartRepo) Delete(id uint) error          { return nil }
+func (m *mockCartRepo) CreateCartItem(item *model.CartItem) error {
+	m.created = append(m.created, *item)
+	return nil
+}
+func (m *mockCartRepo) UpdateCartItem(item *model.CartItem) error             { return nil }
+func (m *mockCartRepo) FindCartItemByID(itemID uint) (*model.CartItem, error) { return nil, nil }
+func (m *mockCartRepo) DeleteCartItem(itemID uint) error {
+	m.deletedIDs = append(m.deletedIDs, itemID)
+	return nil
+}
+func (m *mockCartRepo) ClearCartItems(cartID uint) error { return nil }
+func (m *mockCartRepo) SetPromotionCode(cartID uint, code string) error {
+	if m.promotion == nil {
+		m.promotion = map[uint]string{}
+	}
+	m.promotion[cartID] = code
+	return nil
+}
+func (m *mockCartRepo) MergeGuestCart(guestCartID uint, items []model.CartItem) error {
+	m.mergeCalls++
+	m.mergedCartID = guestCartID
+	m.mergedItems = items
+	return nil
+}
+func (m *mockCartRepo) ReconcileItems(updated []model.CartItem, removedIDs []uint) error {
+	m.updated = updated
+	m.removedIDs = removedIDs
+	return nil
+}
+func (m *mockCartRepo) DeleteStaleGuestCarts(cutoff time.Time) (int64, error) { return 0, nil }
+
+// stubProductService serves products by ID and slug; unknown products behave like deleted ones
+type stubProductService struct {
+	products map[uint]dto.ProductResponse
+	slugs    map[string]uint
+	// err is returned for unknown products instead of not found
+	err error
+}
+
+func (s stubProductService) CreateProduct(ctx context.Context, input dto.ProductFormDTO, file dto.FileUpload) error {
+	return nil
+}
+func (s stubProductService) GetProductByID(ctx context.Context, id uint) (dto.ProductResponse, error) {
+	if p, ok := s.products[id]; ok {
+		return p, nil
+	}
+	if s.err != nil {
+		return dto.ProductResponse{}, s.err
+	}
+	return dto.ProductResponse{}, gorm.ErrRecordNotFound
+}
+func (s stubProductService) GetProductBySlug(ctx context.Context, slug string) (dto.ProductResponse, error) {
+	return dto.ProductResponse{}, nil
+}
+func (s stubProductService) GetProductIDBySlug(ctx context.Context, slug string) (uint, error) {
+	if id, ok := s.slugs[slug]; ok {
+		return id, nil
+	}
+	return 0, errors.New("record not found")
+}
+func (s stubProductService) GetLatestProducts(ctx context.Context, page int, limit int) ([]dto.ProductResponse, int, error) {
+	return nil, 0, nil
+}
+func (s stubProductService) SearchProducts(ctx context.Context, query string, filter dto.ProductFilter, page int, limit int, sort string) (dto.ProductListResponse, error) {
+	return dto.ProductListResponse{}, nil
+}
+func (s stubProductService) AdminListProducts(ctx context.Context, page int, limit int) ([]dto.ProductResponse, int, error) {
+	return nil, 0, nil
+}
+func (s stubProductService) UpdateProduct(ctx context.Context, id uint, input dto.UpdateProductRequest) error {
+	return nil
+}
+func (s stubProductService) DeleteProduct(ctx context.Context, id uint) error { return nil }
+func (s stubProductService) AddVariant(ctx context.Context, productID uint, input dto.ProductVariantRequest) (dto.ProductVariantResponse, error) {
+	return dto.ProductVariantResponse{}, nil
+}
+func (s stubProductService) UpdateVariant(ctx context.Context, productID uint, sku string, input dto.UpdateProductVariantRequest) (dto.ProductVariantResponse, error) {
+	return dto.ProductVariantResponse{}, nil
+}
+func (s stubProductService) DeleteVariant(ctx context.Context, productID uint, sku string) error {
+	return nil
+}
+
+func (s stubProductService) AddImage(ctx context.Context, productID uint, input dto.ProductImageFormDTO, file dto.FileUpload) (dto.ProductImageResponse, error) {
+	return dto.ProductImageResponse{}, nil
+}
+
+func (s stubProductService) ReplaceImage(ctx context.Context, productID uint, imageID uint, input dto.ProductImageFormDTO, file dto.FileUpload) (dto.ProductImageResponse, error) {
+	return dto.ProductImageResponse{}, nil
+}
+
+func (s stubProductService) UpdateImage(ctx context.Context, productID uint, imageID uint, input dto.UpdateProductImageRequest) (dto.ProductImageResponse, error) {
+	return dto.ProductImageResponse{}, nil
+}
+
+func (s stubProductService) ReorderImages(ctx context.Context, productID uint, input dto.ReorderProductImagesRequest) ([]dto.ProductImageResponse, error) {
+	return nil, nil
+}
+
+func (s stubProductService) DeleteImage(ctx context.Context, productID uint, imageID uint) error {
+	return nil
+}
+
+func (s stubProductService) Suggest(ctx context.Context, query string, limit int) (dto.SuggestResponse, error) {
+	return dto.SuggestResponse{}, nil
+}
+
+// stubPromotionService takes 10% off the lines it is given, or fails with err
+type stubPromotionService struct {
+	err error
+}
+
+func (s stubPromotionService) Evaluate(code string, customer promotionservice.Customer, lines []promotionservice.Line, shipping money.Amount) (*promotionservice.Discount, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var subtotal money.Amount
+	for _, line := range lines {
+		subtotal += line.UnitPrice.Mul(line.Quantity)
+	}
+	promotion := &model.Promotion{ID: 1, Code: model.NormalizePromotionCode(code), Name: "Ten off", Type: model.PromotionTypePercentage, PercentOff: 10}
+	return &promotionservice.Discount{Promotion: promotion, Items: subtotal.Div(10)}, nil
+}
+func (s stubPromotionService) CreatePromotion(req *dto.PromotionRequest, adminPublicID string) (*dto.PromotionResponse, error) {
+	return nil, nil
+}
+func (s stubPromotionService) UpdatePromotion(id uint, req *dto.PromotionRequest, adminPublicID string) (*dto.PromotionResponse, error) {
+	return nil, nil
+}
+func (s stubPromotionService) DeletePromotion(id uint, adminPublicID string) error { return nil }
+func (s stubPromotionService) GetPromotion(id uint) (*dto.PromotionResponse, error) {
+	return nil, nil
+}
+func (s stubPromotionService) ListPromotions(page int, perPage int) (*dto.PromotionListResponse, error) {
+	return nil, nil
+}
+
+func uintPtr(v uint) *uint { return &v }
+
+func TestMergeGuestCart(t *testing.T) {
+	products := stubProductService{products: map[uint]dto.ProductResponse{
+		1: {Variants: []dto.ProductVariantResponse{
+			{ID: 11, SKU: "P1-50", Price: money.FromCents(1500), StockQuantity: 5},
+			{ID: 12, SKU: "P1-100", Price: money.FromCents(2500), StockQuantity: 2},
+		}},
+		2: {Variants: []dto.ProductVariantResponse{{ID: 21, SKU: "P2", Price: money.FromCents(900), StockQuantity: 10}}},
+		3: {Variants: []dto.ProductVariantResponse{{ID: 31, SKU: "P3", Price: money.FromCents(700), StockQuantity: 0}}},
+	}}
+
+	t.Run("sums quantities, caps at stock, refreshes prices and drops unavailable variants", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{
+			"guest-1": {ID: 10, GuestID: "guest-1", Items: []model.CartItem{
+				{ID: 101, CartID: 10, ProductID: uintPtr(1), VariantID: uintPtr(11), Quantity: 4, Price: money.FromCents(1200)},
+				{ID: 102, CartID: 10, ProductID: uintPtr(1), VariantID: uintPtr(12), Quantity: 1, Price: money.FromCents(2400)},
+				{ID: 103, CartID: 10, ProductID: uintPtr(2), VariantID: uintPtr(21), Quantity: 1, Price: money.FromCents(800)},
+				{ID: 104, CartID: 10, ProductID: uintPtr(3), VariantID: uintPtr(31), Quantity: 1, Price: money.FromCents(700)},
+				{ID: 105, CartID: 10, ProductID: uintPtr(99), VariantID: uintPtr(991), Quantity: 1, Price: money.FromCents(100)},
+				{ID: 106, CartID: 10, ProductID: uintPtr(2), VariantID: uintPtr(22), Quantity: 1, Price: money.FromCents(100)},
+				{ID: 107, CartID: 10, Quantity: 1, Price: money.FromCents(100)},
+			}},
+			"user-1": {ID: 20, UserID: "user-1", Items: []model.CartItem{
+				{ID: 201, CartID: 20, ProductID: uintPtr(1), VariantID: uintPtr(11), Quantity: 3, Price: money.FromCents(1400)},
+			}},
+		}}
+		svc := NewCartService(repo, products, nil)
+
+		err := svc.MergeGuestCart("guest-1", "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(10), repo.mergedCartID)
+		assert.Equal(t, []model.CartItem{
+			{ID: 201, CartID: 20, ProductID: uintPtr(1), VariantID: uintPtr(11), Quantity: 5, Price: money.FromCents(1500)},
+			{CartID: 20, ProductID: uintPtr(1), VariantID: uintPtr(12), Quantity: 1, Price: money.FromCents(2500)},
+			{CartID: 20, ProductID: uintPtr(2), VariantID: uintPtr(21), Quantity: 1, Price: money.FromCents(900)},
+		}, repo.mergedItems)
+	})
+
+	t.Run("lookup failure keeps the guest cart", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{
+			"guest-1": {ID: 10, GuestID: "guest-1", Items: []model.CartItem{
+				{ID: 101, CartID: 10, ProductID: uintPtr(1), VariantID: uintPtr(11), Quantity: 1},
+				{ID: 105, CartID: 10, ProductID: uintPtr(99), VariantID: uintPtr(991), Quantity: 1},
+			}},
+			"user-1": {ID: 20, UserID: "user-1"},
+		}}
+		failing := products
+		failing.err = errors.New("connection reset")
+		svc := NewCartService(repo, failing, nil)
+
+		assert.Error(t, svc.MergeGuestCart("guest-1", "user-1"))
+		assert.Equal(t, 0, repo.mergeCalls)
+	})
+
+	t.Run("guest without a cart", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": {ID: 20, UserID: "user-1"}}}
+		svc := NewCartService(repo, products, nil)
+
+		assert.NoError(t, svc.MergeGuestCart("guest-1", "user-1"))
+		assert.Equal(t, 0, repo.mergeCalls)
+	})
+
+	t.Run("user cart is never treated as a guest cart", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": {ID: 20, UserID: "user-1"}}}
+		svc := NewCartService(repo, products, nil)
+
+		assert.NoError(t, svc.MergeGuestCart("user-1", "user-1"))
+		assert.Equal(t, 0, repo.mergeCalls)
+	})
+}
+
+func TestAddItemToCart_Variants(t *testing.T) {
+	products := stubProductService{
+		slugs: map[string]uint{"sauvage": 1},
+		products: map[uint]dto.ProductResponse{1: {Variants: []dto.ProductVariantResponse{
+			{ID: 11, SKU: "SAUVAGE-EDT-60", Price: money.FromCents(45000), StockQuantity: 5},
+			{ID: 12, SKU: "SAUVAGE-EDP-100", Price: money.FromCents(79000), StockQuantity: 2},
+		}}},
+	}
+	newService := func() (CartService, *mockCartRepo) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": {ID: 20, UserID: "user-1"}}}
+		return NewCartService(repo, products, nil), repo
+	}
+
+	t.Run("defaults to the first variant", func(t *testing.T) {
+		svc, repo := newService()
+
+		_, err := svc.AddItemToCart("user-1", "sauvage", "", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.CartItem{{CartID: 20, ProductID: uintPtr(1), VariantID: uintPtr(11), Quantity: 1, Price: money.FromCents(45000)}}, repo.created)
+	})
+
+	t.Run("adds the chosen variant at its price", func(t *testing.T) {
+		svc, repo := newService()
+
+		_, err := svc.AddItemToCart("user-1", "sauvage", "SAUVAGE-EDP-100", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.CartItem{{CartID: 20, ProductID: uintPtr(1), VariantID: uintPtr(12), Quantity: 2, Price: money.FromCents(79000)}}, repo.created)
+	})
+
+	t.Run("checks the stock of the variant", func(t *testing.T) {
+		svc, _ := newService()
+
+		_, err := svc.AddItemToCart("user-1", "sauvage", "SAUVAGE-EDP-100", 3)
+		assert.Equal(t, "insufficient_stock", errorCode(err))
+	})
+
+	t.Run("unknown variant", func(t *testing.T) {
+		svc, _ := newService()
+
+		_, err := svc.AddItemToCart("user-1", "sauvage", "SAUVAGE-PARFUM-30", 1)
+		assert.Equal(t, "variant_not_found", errorCode(err))
+	})
+}
+
+func TestRemoveItemBySlug_Variants(t *testing.T) {
+	products := stubProductService{slugs: map[string]uint{"sauvage": 1}}
+	cart := func() *model.Cart {
+		return &model.Cart{ID: 20, UserID: "user-1", Items: []model.CartItem{
+			{ID: 1, CartID: 20, ProductID: uintPtr(1), VariantID: uintPtr(11), Quantity: 1, Variant: &model.ProductVariant{ID: 11, SKU: "SAUVAGE-EDT-60"}},
+			{ID: 2, CartID: 20, ProductID: uintPtr(1), VariantID: uintPtr(12), Quantity: 1, Variant: &model.ProductVariant{ID: 12, SKU: "SAUVAGE-EDP-100"}},
+		}}
+	}
+
+	t.Run("requires a SKU when the product is in the cart twice", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": cart()}}
+		svc := NewCartService(repo, products, nil)
+
+		_, err := svc.RemoveItemBySlug("user-1", "sauvage", "")
+		assert.Equal(t, "variant_required", errorCode(err))
+		assert.Empty(t, repo.deletedIDs)
+	})
+
+	t.Run("removes the chosen variant", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": cart()}}
+		svc := NewCartService(repo, products, nil)
+
+		_, err := svc.RemoveItemBySlug("user-1", "sauvage", "SAUVAGE-EDP-100")
+		assert.NoError(t, err)
+		assert.Equal(t, []uint{2}, repo.deletedIDs)
+	})
+}
+
+func errorCode(err error) string {
+	var de *apperror.DomainError
+	if errors.As(err, &de) {
+		return de.Code
+	}
+	return ""
+}
+
+func staleCart() *model.Cart {
+	// A nil variant is a line whose product was deleted, which clears its product and variant
+	line := func(id uint, quantity int, price int64, variant *model.ProductVariant) model.CartItem {
+		item := model.CartItem{ID: id, CartID: 20, Quantity: quantity, Price: money.FromCents(price)}
+		if variant != nil {
+			variant.ID = id * 10
+			item.ProductID, item.VariantID = uintPtr(id), uintPtr(id*10)
+			item.Variant = variant
+			item.Product = &model.Product{ID: id, Price: variant.Price, StockQuantity: variant.StockQuantity}
+		}
+		return item
+	}
+	return &model.Cart{ID: 20, UserID: "user-1", Items: []model.CartItem{
+		line(1, 2, 1000, &model.ProductVariant{SKU: "repriced", Price: money.FromCents(1200), StockQuantity: 10}),
+		line(2, 5, 500, &model.ProductVariant{SKU: "low-stock", Price: money.FromCents(500), StockQuantity: 3}),
+		line(3, 1, 700, &model.ProductVariant{SKU: "sold-out", Price: money.FromCents(700), StockQuantity: 0}),
+		line(4, 1, 300, nil),
+		line(5, 1, 900, &model.ProductVariant{SKU: "unchanged", Price: money.FromCents(900), StockQuantity: 4}),
+	}}
+}
+
+func TestGetCartResponse_Warnings(t *testing.T) {
+	repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": staleCart()}}
+	svc := NewCartService(repo, stubProductService{}, nil)
+
+	resp, err := svc.GetCartResponse("user-1")
+	assert.NoError(t, err)
+	assert.True(t, resp.HasWarnings)
+
+	previous, current := money.FromCents(1000), money.FromCents(1200)
+	available := 3
+	assert.Equal(t, []dto.CartItemWarning{{Code: dto.CartWarningPriceChanged, PreviousPrice: &previous, CurrentPrice: &current}}, resp.Items[0].Warnings)
+	assert.Equal(t, []dto.CartItemWarning{{Code: dto.CartWarningInsufficientStock, Available: &available}}, resp.Items[1].Warnings)
+	assert.Equal(t, []dto.CartItemWarning{{Code: dto.CartWarningOutOfStock}}, resp.Items[2].Warnings)
+	assert.Equal(t, []dto.CartItemWarning{{Code: dto.CartWarningProductRemoved}}, resp.Items[3].Warnings)
+	assert.Empty(t, resp.Items[4].Warnings)
+	// Lines keep their captured price until the shopper accepts the new one
+	assert.Equal(t, money.FromCents(1000), resp.Items[0].Price)
+}
+
+func TestGetCartResponse_RemovedProductTotals(t *testing.T) {
+	repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": staleCart()}}
+	svc := NewCartService(repo, stubProductService{}, nil)
+
+	resp, err := svc.GetCartResponse("user-1")
+	assert.NoError(t, err)
+
+	// The removed line is still listed, but checkout cannot charge for it
+	assert.Len(t, resp.Items, 5)
+	assert.Equal(t, money.FromCents(300), resp.Items[3].Total)
+	assert.Equal(t, money.FromCents(2*1000+5*500+700+900), resp.Subtotal)
+	assert.Equal(t, resp.Subtotal, resp.Total)
+	assert.Equal(t, 9, resp.ItemCount)
+}
+
+func TestGetGuestCartResponse(t *testing.T) {
+	repo := &mockCartRepo{carts: map[string]*model.Cart{"guest-1": staleCart()}}
+	svc := NewCartService(repo, stubProductService{}, nil)
+
+	resp, err := svc.GetGuestCartResponse("guest-1")
+	assert.NoError(t, err)
+	assert.Len(t, resp.Items, 5)
+
+	// A guest that never wrote to the cart has none stored and sees an empty one
+	resp, err = svc.GetGuestCartResponse("guest-2")
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Items)
+	assert.NotContains(t, repo.carts, "guest-2")
+}
+
+func TestReconcileCart(t *testing.T) {
+	t.Run("accept prices and adjust quantities", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": staleCart()}}
+		svc := NewCartService(repo, stubProductService{}, nil)
+
+		_, err := svc.ReconcileCart("user-1", true, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.CartItem{
+			{ID: 1, CartID: 20, ProductID: uintPtr(1), VariantID: uintPtr(10), Quantity: 2, Price: money.FromCents(1200)},
+			{ID: 2, CartID: 20, ProductID: uintPtr(2), VariantID: uintPtr(20), Quantity: 3, Price: money.FromCents(500)},
+		}, repo.updated)
+		assert.Equal(t, []uint{3, 4}, repo.removedIDs)
+	})
+
+	t.Run("accept prices only", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": staleCart()}}
+		svc := NewCartService(repo, stubProductService{}, nil)
+
+		_, err := svc.ReconcileCart("user-1", true, false)
+		assert.NoError(t, err)
+		assert.Len(t, repo.updated, 1)
+		assert.Equal(t, uint(1), repo.updated[0].ID)
+		assert.Empty(t, repo.removedIDs)
+	})
+}
+
+func promotionCart(code string) *model.Cart {
+	return &model.Cart{ID: 30, UserID: "user-1", PromotionCode: code, Items: []model.CartItem{
+		{ID: 1, CartID: 30, ProductID: uintPtr(1), VariantID: uintPtr(10), Quantity: 2, Price: money.FromCents(1000),
+			Product: &model.Product{ID: 1, Price: money.FromCents(1000), StockQuantity: 5},
+			Variant: &model.ProductVariant{ID: 10, ProductID: 1, Price: money.FromCents(1000), StockQuantity: 5}},
+		{ID: 2, CartID: 30, ProductID: uintPtr(2), Quantity: 1, Price: money.FromCents(500)},
+	}}
+}
+
+func TestGetCartResponse_Promotion(t *testing.T) {
+	t.Run("discounts purchasable lines", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": promotionCart("TEN")}}
+		svc := NewCartService(repo, stubProductService{}, stubPromotionService{})
+
+		resp, err := svc.GetCartResponse("user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, money.FromCents(2000), resp.Subtotal)
+		assert.Equal(t, money.FromCents(200), resp.Discount)
+		assert.Equal(t, money.FromCents(1800), resp.Total)
+		assert.Equal(t, &dto.CartPromotionResponse{Code: "TEN", Name: "Ten off", Type: "percentage", Discount: money.FromCents(200)}, resp.Promotion)
+	})
+
+	t.Run("coupon that no longer applies reports why", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": promotionCart("TEN")}}
+		svc := NewCartService(repo, stubProductService{}, stubPromotionService{err: apperror.NewCodeMessage("promotion_min_subtotal_not_met", "too small")})
+
+		resp, err := svc.GetCartResponse("user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "promotion_min_subtotal_not_met", resp.Promotion.Error)
+		assert.Equal(t, money.Amount(0), resp.Discount)
+		assert.Equal(t, resp.Subtotal, resp.Total)
+	})
+}
+
+func TestApplyPromotion(t *testing.T) {
+	t.Run("stores the normalized code", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": promotionCart("")}}
+		svc := NewCartService(repo, stubProductService{}, stubPromotionService{})
+
+		_, err := svc.ApplyPromotion("user-1", " ten ")
+		assert.NoError(t, err)
+		assert.Equal(t, "TEN", repo.promotion[30])
+	})
+
+	t.Run("rejected coupon is not stored", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": promotionCart("")}}
+		svc := NewCartService(repo, stubProductService{}, stubPromotionService{err: apperror.NewCodeMessage("promotion_not_found", "promotion not found")})
+
+		_, err := svc.ApplyPromotion("user-1", "NOPE")
+		assert.Error(t, err)
+		assert.Empty(t, repo.promotion)
+	})
+
+	t.Run("remove clears the code", func(t *testing.T) {
+		repo := &mockCartRepo{carts: map[string]*model.Cart{"user-1": promotionCart("TEN")}}
+		svc := NewCartService(repo, stubProductService{}, stubPromotionService{})
+
+		_, err := svc.RemovePromotion("user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "", repo.promotion[30])
+	})
+}
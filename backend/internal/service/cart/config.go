@@ -0,0 +1,49 @@
+package service
+
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/envconfig"
+)
+
+const (
+	// DefaultAbandonAfter is how long a cart must sit idle before its first reminder; each further
+	// reminder waits the same amount again.
+	DefaultAbandonAfter = 24 * time.Hour
+	// DefaultMaxReminders caps the reminders sent for one abandonment.
+	DefaultMaxReminders = 2
+	// DefaultRecoveryWindow is how long after the last reminder an order still counts as recovered.
+	DefaultRecoveryWindow = 7 * 24 * time.Hour
+)
+
+// RecoveryConfig holds tunable abandoned cart reminder behaviour.
+type RecoveryConfig struct {
+	AbandonAfter   time.Duration
+	MaxReminders   int
+	RecoveryWindow time.Duration
+}
+
+// LoadRecoveryConfigFromEnv reads abandoned cart settings from the environment, falling back to defaults.
+// Durations use Go syntax, e.g. ABANDONED_CART_AFTER=12h; ABANDONED_CART_MAX_REMINDERS is a count.
+func LoadRecoveryConfigFromEnv() RecoveryConfig {
+	cfg := RecoveryConfig{
+		AbandonAfter:   envconfig.Duration("ABANDONED_CART_AFTER", DefaultAbandonAfter),
+		MaxReminders:   envconfig.Count("ABANDONED_CART_MAX_REMINDERS", DefaultMaxReminders),
+		RecoveryWindow: envconfig.Duration("ABANDONED_CART_RECOVERY_WINDOW", DefaultRecoveryWindow),
+	}
+	return cfg.withDefaults()
+}
+
+// withDefaults fills unset or invalid values.
+func (c RecoveryConfig) withDefaults() RecoveryConfig {
+	if c.AbandonAfter <= 0 {
+		c.AbandonAfter = DefaultAbandonAfter
+	}
+	if c.MaxReminders <= 0 {
+		c.MaxReminders = DefaultMaxReminders
+	}
+	if c.RecoveryWindow <= 0 {
+		c.RecoveryWindow = DefaultRecoveryWindow
+	}
+	return c
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"log"
+	"math"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/notification"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+)
+
+// reminderBatchSize caps how many abandoned carts are reminded per run
+const reminderBatchSize = 200
+
+// maxRecoveryMetricsDays caps the period of the recovery metric
+const maxRecoveryMetricsDays = 365
+
+// CartRecoveryService reminds opted-in users of carts they abandoned and reports how many came back
+type CartRecoveryService interface {
+	SendReminders() (int, error)
+	GetRecoveryMetrics(days int) (*dto.CartRecoveryMetricsResponse, error)
+}
+
+type cartRecoveryService struct {
+	reminders repository.CartReminderRepository
+	carts     repository.CartRepository
+	notifier  notification.NotificationService
+	cfg       RecoveryConfig
+	now       func() time.Time
+}
+
+// NewCartRecoveryService creates a new abandoned cart recovery service
+func NewCartRecoveryService(reminders repository.CartReminderRepository, carts repository.CartRepository, notifier notification.NotificationService, cfg RecoveryConfig) CartRecoveryService {
+	return &cartRecoveryService{
+		reminders: reminders,
+		carts:     carts,
+		notifier:  notifier,
+		cfg:       cfg.withDefaults(),
+		now:       time.Now,
+	}
+}
+
+// SendReminders emails every cart due a reminder and returns how many were sent. Each send is
+// recorded before the email goes out, so a cart never gets the same reminder twice.
+func (s *cartRecoveryService) SendReminders() (int, error) {
+	now := s.now()
+	due, err := s.reminders.FindDueAbandonedCarts(now, s.cfg.AbandonAfter, s.cfg.MaxReminders, reminderBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, abandoned := range due {
+		cart, err := s.carts.FindByUserID(abandoned.UserID)
+		if err != nil {
+			log.Printf("abandoned cart %d: failed to load cart: %v", abandoned.CartID, err)
+			continue
+		}
+		if len(cart.Items) == 0 {
+			continue
+		}
+
+		reminder := &model.CartReminder{
+			CartID:         abandoned.CartID,
+			UserID:         abandoned.UserID,
+			CartActivityAt: abandoned.LastActivityAt,
+			Sequence:       abandoned.RemindersSent + 1,
+			SentAt:         now,
+		}
+		for _, item := range cart.Items {
+			reminder.ItemCount += item.Quantity
+			reminder.CartValue += item.Price.Mul(item.Quantity)
+		}
+
+		created, err := s.reminders.CreateIfAbsent(reminder)
+		if err != nil {
+			log.Printf("abandoned cart %d: failed to record reminder: %v", abandoned.CartID, err)
+			continue
+		}
+		if !created {
+			continue
+		}
+
+		if err := s.notifier.SendAbandonedCartReminder(abandoned.Email, cart.Items); err != nil {
+			log.Printf("abandoned cart %d: failed to send reminder: %v", abandoned.CartID, err)
+			// Forget the reminder so the next run tries again
+			if err := s.reminders.Delete(reminder.ID); err != nil {
+				log.Printf("abandoned cart %d: failed to release reminder: %v", abandoned.CartID, err)
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// GetRecoveryMetrics reports reminders sent in the last days and how many of the reminded carts
+// turned into orders within the recovery window
+func (s *cartRecoveryService) GetRecoveryMetrics(days int) (*dto.CartRecoveryMetricsResponse, error) {
+	if days <= 0 || days > maxRecoveryMetricsDays {
+		return nil, apperror.NewCodeMessage("invalid_request", "days must be between 1 and 365")
+	}
+
+	since := s.now().AddDate(0, 0, -days)
+	totals, err := s.reminders.RecoveryTotals(since, s.cfg.RecoveryWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.CartRecoveryMetricsResponse{
+		Days:             days,
+		Since:            since,
+		RemindersSent:    totals.RemindersSent,
+		CartsReminded:    totals.CartsReminded,
+		CartsRecovered:   totals.CartsRecovered,
+		RecoveredRevenue: totals.RecoveredRevenue,
+	}
+	if totals.CartsReminded > 0 {
+		rate := float64(totals.CartsRecovered) / float64(totals.CartsReminded)
+		resp.ConversionRate = math.Round(rate*10000) / 10000
+	}
+	return resp, nil
+}
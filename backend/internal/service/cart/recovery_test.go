@@ -0,0 +1,162 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCartReminderRepo struct {
+	due       []repository.AbandonedCart
+	recorded  map[string]bool
+	created   []model.CartReminder
+	deleted   []uint
+	totals    *repository.CartRecoveryTotals
+	since     time.Time
+	window    time.Duration
+	findAfter time.Duration
+}
+
+func (m *mockCartReminderRepo) FindDueAbandonedCarts(now time.Time, idleAfter time.Duration, maxReminders int, limit int) ([]repository.AbandonedCart, error) {
+	m.findAfter = idleAfter
+	return m.due, nil
+}
+func (m *mockCartReminderRepo) CreateIfAbsent(reminder *model.CartReminder) (bool, error) {
+	key := fmt.Sprintf("%d/%s/%d", reminder.CartID, reminder.CartActivityAt, reminder.Sequence)
+	if m.recorded[key] {
+		return false, nil
+	}
+	m.recorded[key] = true
+	reminder.ID = uint(len(m.created) + 1)
+	m.created = append(m.created, *reminder)
+	return true, nil
+}
+func (m *mockCartReminderRepo) Delete(id uint) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+func (m *mockCartReminderRepo) RecoveryTotals(since time.Time, window time.Duration) (*repository.CartRecoveryTotals, error) {
+	m.since, m.window = since, window
+	return m.totals, nil
+}
+
+type mockRecoveryNotifier struct {
+	sentTo []string
+	err    error
+}
+
+func (m *mockRecoveryNotifier) SendPasswordResetCode(to, code string) error           { return nil }
+func (m *mockRecoveryNotifier) SendWelcomeEmail(to, name string) error                { return nil }
+func (m *mockRecoveryNotifier) SendOrderConfirmation(to string, o *model.Order) error { return nil }
+func (m *mockRecoveryNotifier) SendGuestOrderConfirmation(to string, o *model.Order, lookupToken string) error {
+	return nil
+}
+func (m *mockRecoveryNotifier) SendOrderCancelled(to string, o *model.Order, refundedAmount money.Amount) error {
+	return nil
+}
+func (m *mockRecoveryNotifier) SendAccountDeactivated(to, reason string, deadline string) error {
+	return nil
+}
+func (m *mockRecoveryNotifier) SendContestationReceived(to string) error { return nil }
+func (m *mockRecoveryNotifier) SendContestationResult(to string, approved bool, reason string) error {
+	return nil
+}
+func (m *mockRecoveryNotifier) SendDeletionRequested(to string, cancelLink string) error { return nil }
+func (m *mockRecoveryNotifier) SendDeletionAutoConfirmed(to string) error                { return nil }
+func (m *mockRecoveryNotifier) SendDeletionCancelled(to string) error                    { return nil }
+func (m *mockRecoveryNotifier) SendDataAnonymized(to string) error                       { return nil }
+func (m *mockRecoveryNotifier) SendPromotional(to, subject, htmlBody string) error       { return nil }
+func (m *mockRecoveryNotifier) SendAbandonedCartReminder(to string, items []model.CartItem) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sentTo = append(m.sentTo, to)
+	return nil
+}
+func (m *mockRecoveryNotifier) SendWishlistAlert(to string, alerts []model.WishlistAlert) error {
+	return nil
+}
+func (m *mockRecoveryNotifier) SendAdminAlert(subject, message string) error { return nil }
+
+func newTestRecoveryService(due []repository.AbandonedCart, carts map[string]*model.Cart) (*cartRecoveryService, *mockCartReminderRepo, *mockRecoveryNotifier) {
+	reminders := &mockCartReminderRepo{due: due, recorded: map[string]bool{}}
+	notifier := &mockRecoveryNotifier{}
+	svc := NewCartRecoveryService(reminders, &mockCartRepo{carts: carts}, notifier, RecoveryConfig{}).(*cartRecoveryService)
+	svc.now = func() time.Time { return time.Date(2025, 12, 27, 12, 0, 0, 0, time.UTC) }
+	return svc, reminders, notifier
+}
+
+func TestSendReminders(t *testing.T) {
+	lastActivity := time.Date(2025, 12, 26, 8, 0, 0, 0, time.UTC)
+	carts := map[string]*model.Cart{
+		"user-1": {ID: 1, UserID: "user-1", Items: []model.CartItem{
+			{ProductID: uintPtr(1), Quantity: 2, Price: money.FromCents(10000), Product: &model.Product{Name: "Sauvage"}},
+			{ProductID: uintPtr(2), Quantity: 1, Price: money.FromCents(5000), Product: &model.Product{Name: "Kaiak"}},
+		}},
+		"user-2": {ID: 2, UserID: "user-2"},
+	}
+
+	t.Run("sends and records the next reminder", func(t *testing.T) {
+		svc, reminders, notifier := newTestRecoveryService([]repository.AbandonedCart{
+			{CartID: 1, UserID: "user-1", Email: "ana@example.com", LastActivityAt: lastActivity, RemindersSent: 1},
+			{CartID: 2, UserID: "user-2", Email: "empty@example.com", LastActivityAt: lastActivity},
+		}, carts)
+
+		sent, err := svc.SendReminders()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Equal(t, []string{"ana@example.com"}, notifier.sentTo)
+		assert.Equal(t, DefaultAbandonAfter, reminders.findAfter)
+		if assert.Len(t, reminders.created, 1) {
+			assert.Equal(t, 2, reminders.created[0].Sequence)
+			assert.Equal(t, lastActivity, reminders.created[0].CartActivityAt)
+			assert.Equal(t, 3, reminders.created[0].ItemCount)
+			assert.Equal(t, money.FromCents(25000), reminders.created[0].CartValue)
+		}
+	})
+
+	t.Run("does not send a reminder twice", func(t *testing.T) {
+		due := []repository.AbandonedCart{{CartID: 1, UserID: "user-1", Email: "ana@example.com", LastActivityAt: lastActivity}}
+		svc, _, notifier := newTestRecoveryService(append(due, due...), carts)
+
+		sent, err := svc.SendReminders()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Len(t, notifier.sentTo, 1)
+	})
+
+	t.Run("releases the record when the email fails", func(t *testing.T) {
+		svc, reminders, notifier := newTestRecoveryService([]repository.AbandonedCart{
+			{CartID: 1, UserID: "user-1", Email: "ana@example.com", LastActivityAt: lastActivity},
+		}, carts)
+		notifier.err = errors.New("smtp down")
+
+		sent, err := svc.SendReminders()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+		assert.Equal(t, []uint{1}, reminders.deleted)
+	})
+}
+
+func TestGetRecoveryMetrics(t *testing.T) {
+	svc, reminders, _ := newTestRecoveryService(nil, nil)
+	reminders.totals = &repository.CartRecoveryTotals{RemindersSent: 14, CartsReminded: 9, CartsRecovered: 3, RecoveredRevenue: money.FromCents(45000)}
+
+	resp, err := svc.GetRecoveryMetrics(30)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.3333, resp.ConversionRate)
+	assert.Equal(t, money.FromCents(45000), resp.RecoveredRevenue)
+	assert.Equal(t, time.Date(2025, 11, 27, 12, 0, 0, 0, time.UTC), reminders.since)
+	assert.Equal(t, DefaultRecoveryWindow, reminders.window)
+
+	_, err = svc.GetRecoveryMetrics(0)
+	assert.Error(t, err)
+	_, err = svc.GetRecoveryMetrics(400)
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -89,6 +90,12 @@ func (m *mockAuditLogService) LogAdminAction(adminID uint, userID uint, action m
 func (m *mockAuditLogService) LogSystemAction(action model.AuditAction, resource, resourceID string, details map[string]interface{}) error {
 	return nil
 }
+func (m *mockAuditLogService) LogSystemAlert(action model.AuditAction, resource, resourceID, severity string, details map[string]interface{}) error {
+	return nil
+}
+func (m *mockAuditLogService) LogOrderAction(actorID *uint, orderPublicID string, action model.AuditAction, details map[string]interface{}, oldValues, newValues map[string]interface{}) error {
+	return nil
+}
 func (m *mockAuditLogService) LogDataAccess(userID uint, resource string, resourceID string) error {
 	return nil
 }
@@ -129,6 +136,12 @@ type mockNotifier struct {
 func (m *mockNotifier) SendPasswordResetCode(to, code string) error               { return m.err }
 func (m *mockNotifier) SendWelcomeEmail(to, name string) error                    { return m.err }
 func (m *mockNotifier) SendOrderConfirmation(to string, order *model.Order) error { return m.err }
+func (m *mockNotifier) SendGuestOrderConfirmation(to string, order *model.Order, lookupToken string) error {
+	return m.err
+}
+func (m *mockNotifier) SendOrderCancelled(to string, order *model.Order, refundedAmount money.Amount) error {
+	return m.err
+}
 func (m *mockNotifier) SendAccountDeactivated(to, reason string, contestationDeadline string) error {
 	return m.err
 }
@@ -141,6 +154,13 @@ func (m *mockNotifier) SendDeletionAutoConfirmed(to string) error
 func (m *mockNotifier) SendDeletionCancelled(to string) error                    { return m.err }
 func (m *mockNotifier) SendDataAnonymized(to string) error                       { return m.err }
 func (m *mockNotifier) SendPromotional(to, subject, htmlBody string) error       { return m.err }
+func (m *mockNotifier) SendAbandonedCartReminder(to string, items []model.CartItem) error {
+	return m.err
+}
+func (m *mockNotifier) SendWishlistAlert(to string, alerts []model.WishlistAlert) error {
+	return m.err
+}
+func (m *mockNotifier) SendAdminAlert(subject, message string) error { return m.err }
 
 // --- Test helpers: create a base user for tests ---
 func baseUser() *model.User {
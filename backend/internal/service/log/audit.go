@@ -16,6 +16,8 @@ type AuditLogService interface {
 	LogUserUpdate(actorID uint, userID uint, oldUser, newUser *model.User) error
 	LogAdminAction(adminID uint, userID uint, action model.AuditAction, details map[string]interface{}) error
 	LogSystemAction(action model.AuditAction, resource, resourceID string, details map[string]interface{}) error
+	LogSystemAlert(action model.AuditAction, resource, resourceID, severity string, details map[string]interface{}) error
+	LogOrderAction(actorID *uint, orderPublicID string, action model.AuditAction, details map[string]interface{}, oldValues, newValues map[string]interface{}) error
 	LogDataAccess(userID uint, resource string, resourceID string) error
 	LogDeletionAction(actorID *uint, userID uint, action model.AuditAction, details map[string]interface{}) error
 	ListAuditLogs(filter *model.AuditLogFilter) ([]*model.AuditLog, int64, error)
@@ -81,6 +83,16 @@ func (s *auditLogService) LogSystemAction(action model.AuditAction, resource, re
 	return s.logAuditEntry(nil, nil, action, resource, &resourceID, details, nil, nil)
 }
 
+// LogSystemAlert logs a system-level action with an explicit severity
+func (s *auditLogService) LogSystemAlert(action model.AuditAction, resource, resourceID, severity string, details map[string]interface{}) error {
+	return s.logAuditEntryWithSeverity(nil, nil, action, resource, &resourceID, severity, details, nil, nil)
+}
+
+// LogOrderAction logs actions performed on an order, with before/after values
+func (s *auditLogService) LogOrderAction(actorID *uint, orderPublicID string, action model.AuditAction, details map[string]interface{}, oldValues, newValues map[string]interface{}) error {
+	return s.logAuditEntry(actorID, nil, action, "order", &orderPublicID, details, oldValues, newValues)
+}
+
 // LogDataAccess logs when user data is accessed (LGPD compliance)
 func (s *auditLogService) LogDataAccess(userID uint, resource string, resourceID string) error {
 	details := map[string]interface{}{
@@ -101,6 +113,11 @@ func (s *auditLogService) LogDeletionAction(actorID *uint, userID uint, action m
 
 // logAuditEntry is a helper method to create audit log entries
 func (s *auditLogService) logAuditEntry(actorID, userID *uint, action model.AuditAction, resource string, resourceID *string, details, oldValues, newValues map[string]interface{}) error {
+	return s.logAuditEntryWithSeverity(actorID, userID, action, resource, resourceID, model.AuditSeverityInfo, details, oldValues, newValues)
+}
+
+// logAuditEntryWithSeverity creates an audit log entry with the given severity
+func (s *auditLogService) logAuditEntryWithSeverity(actorID, userID *uint, action model.AuditAction, resource string, resourceID *string, severity string, details, oldValues, newValues map[string]interface{}) error {
 	// Convert maps to JSON strings
 	detailsJSON := "{}"
 	if details != nil {
@@ -135,7 +152,7 @@ func (s *auditLogService) logAuditEntry(actorID, userID *uint, action model.Audi
 		NewValues:  newValuesJSON,
 		Timestamp:  time.Now(),
 		Compliance: "LGPD",
-		Severity:   "info",
+		Severity:   severity,
 	}
 
 	if actorID == nil {
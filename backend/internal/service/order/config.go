@@ -0,0 +1,84 @@
+package service
+
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/envconfig"
+	"github.com/leoferamos/aroma-sense/internal/model"
+)
+
+const (
+	// DefaultReservationTTL is how long stock stays reserved for an unpaid order.
+	DefaultReservationTTL = 30 * time.Minute
+	// DefaultReservationSweepInterval is how often expired reservations are released.
+	DefaultReservationSweepInterval = time.Minute
+	// DefaultCustomerCancelRules lets customers cancel unpaid orders at any time and paid orders within a day.
+	DefaultCustomerCancelRules = "pending=0,processing=24h"
+)
+
+// Config holds tunable order behaviour.
+type Config struct {
+	ReservationTTL           time.Duration
+	ReservationSweepInterval time.Duration
+	// CustomerCancelWindows lists the statuses a customer may cancel from and how long after
+	// order creation that stays possible. A zero window means no limit.
+	CustomerCancelWindows map[model.OrderStatus]time.Duration
+}
+
+// LoadConfigFromEnv reads order settings from the environment, falling back to defaults.
+// Durations use Go syntax, e.g. ORDER_RESERVATION_TTL=45m. Customer cancellation rules are
+// status=window pairs, e.g. ORDER_CUSTOMER_CANCEL_RULES=pending=0,processing=2h; "none" disables it.
+func LoadConfigFromEnv() Config {
+	cfg := Config{
+		ReservationTTL:           envconfig.Duration("ORDER_RESERVATION_TTL", DefaultReservationTTL),
+		ReservationSweepInterval: envconfig.Duration("ORDER_RESERVATION_SWEEP_INTERVAL", DefaultReservationSweepInterval),
+	}
+	if raw := os.Getenv("ORDER_CUSTOMER_CANCEL_RULES"); raw != "" {
+		cfg.CustomerCancelWindows = ParseCancelRules(raw)
+	}
+	return cfg.withDefaults()
+}
+
+// ParseCancelRules parses status=window pairs. Statuses that can never be cancelled are dropped.
+func ParseCancelRules(raw string) map[model.OrderStatus]time.Duration {
+	rules := map[model.OrderStatus]time.Duration{}
+	if strings.EqualFold(strings.TrimSpace(raw), "none") {
+		return rules
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		status, window, _ := strings.Cut(strings.TrimSpace(pair), "=")
+		st := model.OrderStatus(strings.TrimSpace(status))
+		if !CanTransition(st, model.OrderStatusCancelled) {
+			log.Printf("ignoring cancel rule %q: orders in that status cannot be cancelled", pair)
+			continue
+		}
+		d := time.Duration(0)
+		if w := strings.TrimSpace(window); w != "" && w != "0" {
+			parsed, err := time.ParseDuration(w)
+			if err != nil || parsed < 0 {
+				log.Printf("ignoring cancel rule %q: invalid window", pair)
+				continue
+			}
+			d = parsed
+		}
+		rules[st] = d
+	}
+	return rules
+}
+
+// withDefaults fills unset or invalid values.
+func (c Config) withDefaults() Config {
+	if c.ReservationTTL <= 0 {
+		c.ReservationTTL = DefaultReservationTTL
+	}
+	if c.ReservationSweepInterval <= 0 {
+		c.ReservationSweepInterval = DefaultReservationSweepInterval
+	}
+	if c.CustomerCancelWindows == nil {
+		c.CustomerCancelWindows = ParseCancelRules(DefaultCustomerCancelRules)
+	}
+	return c
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/model"
+)
+
+// orderTransitions lists, for each order status, the statuses it may move to.
+// Delivered and cancelled are terminal.
+var orderTransitions = map[model.OrderStatus][]model.OrderStatus{
+	model.OrderStatusPending:    {model.OrderStatusProcessing, model.OrderStatusCancelled},
+	model.OrderStatusProcessing: {model.OrderStatusShipped, model.OrderStatusCancelled},
+	model.OrderStatusShipped:    {model.OrderStatusDelivered},
+	model.OrderStatusDelivered:  {},
+	model.OrderStatusCancelled:  {},
+}
+
+// IsValidOrderStatus reports whether status is one of the known OrderStatus values.
+func IsValidOrderStatus(status model.OrderStatus) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// CanTransition reports whether an order may move from one status to another.
+func CanTransition(from, to model.OrderStatus) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns a domain error when the transition from -> to is not allowed.
+func ValidateTransition(from, to model.OrderStatus) error {
+	if !IsValidOrderStatus(to) {
+		return apperror.NewCodeMessage("invalid_order_status", "invalid order status")
+	}
+	if !CanTransition(from, to) {
+		return apperror.NewDomain(
+			fmt.Errorf("order status transition not allowed: %s -> %s", from, to),
+			"invalid_status_transition",
+			"order status transition not allowed",
+		)
+	}
+	return nil
+}
@@ -2,101 +2,274 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/auth"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/leoferamos/aroma-sense/internal/notification"
 	"github.com/leoferamos/aroma-sense/internal/repository"
+	logservice "github.com/leoferamos/aroma-sense/internal/service/log"
+	promotionservice "github.com/leoferamos/aroma-sense/internal/service/promotion"
 	shippingservice "github.com/leoferamos/aroma-sense/internal/service/shipping"
+	userservice "github.com/leoferamos/aroma-sense/internal/service/user"
 	"github.com/leoferamos/aroma-sense/internal/validation"
+	"gorm.io/datatypes"
 )
 
 type OrderService interface {
 	CreateOrderFromCart(userID string, req *dto.CreateOrderFromCartRequest) (*dto.OrderResponse, error)
+	CreateGuestOrderFromCart(guestID string, req *dto.CreateGuestOrderRequest) (*dto.GuestOrderResponse, error)
+	TrackGuestOrder(req *dto.TrackOrderRequest) (*dto.OrderDetailResponse, error)
+	ClaimGuestOrders(userID string, guestID string, req *dto.ClaimGuestOrdersRequest) (*dto.ClaimGuestOrdersResponse, error)
 	AdminListOrders(status *string, startDate *time.Time, endDate *time.Time, page int, perPage int) (*dto.AdminOrdersResponse, error)
 	GetOrdersByUser(userID string) ([]dto.OrderResponse, error)
+	UpdateOrderStatus(ctx context.Context, publicID string, req *dto.UpdateOrderStatusRequest, adminPublicID string) (*dto.OrderResponse, error)
+	GetOrderByPublicID(userID string, publicID string) (*dto.OrderDetailResponse, error)
+	AdminGetOrder(publicID string) (*dto.AdminOrderDetailResponse, error)
+	ReleaseExpiredReservation(publicID string) error
+	CancelUnpaidOrder(publicID string, note string) error
+	CancelOrder(ctx context.Context, userID string, publicID string, reason string) (*dto.OrderCancellationResponse, error)
+	AdminRefundOrder(ctx context.Context, publicID string, req *dto.AdminRefundRequest, adminPublicID string) (*dto.RefundResponse, error)
 }
 
+// OrderPayments is the part of the payment service needed to settle an order's payments.
+type OrderPayments interface {
+	CancelOrderIntents(ctx context.Context, orderPublicID string, reason string, message string) ([]string, error)
+	RefundOrder(ctx context.Context, orderPublicID string, req RefundRequest) (*RefundOutcome, error)
+}
+
+// RefundRequest describes money to return for an order. A zero AmountCents refunds everything still captured.
+type RefundRequest struct {
+	AmountCents int64
+	Reason      string
+	ActorType   model.OrderEventActor
+	ActorID     string
+}
+
+// RefundOutcome reports how much was returned and how much captured money is left.
+type RefundOutcome struct {
+	RefundedCents  int64
+	RemainingCents int64
+}
+
+// Refund outcomes reported to customers on cancellation.
+const (
+	RefundStatusNone     = "none"
+	RefundStatusRefunded = "refunded"
+	RefundStatusFailed   = "failed"
+)
+
 type orderService struct {
-	orderRepo   repository.OrderRepository
-	cartRepo    repository.CartRepository
-	productRepo repository.ProductRepository
-	shippingSvc shippingservice.ShippingService
+	orderRepo       repository.OrderRepository
+	eventRepo       repository.OrderEventRepository
+	cartRepo        repository.CartRepository
+	productRepo     repository.ProductRepository
+	userRepo        repository.UserRepository
+	addressRepo     repository.AddressRepository
+	shippingSvc     shippingservice.ShippingService
+	auditLogService logservice.AuditLogService
+	payments        OrderPayments
+	notifier        notification.NotificationService
+	promotions      promotionservice.PromotionService
+	cfg             Config
 }
 
-func NewOrderService(orderRepo repository.OrderRepository, cartRepo repository.CartRepository, productRepo repository.ProductRepository, shippingSvc shippingservice.ShippingService) OrderService {
-	return &orderService{orderRepo: orderRepo, cartRepo: cartRepo, productRepo: productRepo, shippingSvc: shippingSvc}
+func NewOrderService(orderRepo repository.OrderRepository, eventRepo repository.OrderEventRepository, cartRepo repository.CartRepository, productRepo repository.ProductRepository, userRepo repository.UserRepository, addressRepo repository.AddressRepository, shippingSvc shippingservice.ShippingService, auditLogService logservice.AuditLogService, payments OrderPayments, notifier notification.NotificationService, promotions promotionservice.PromotionService, cfg Config) OrderService {
+	return &orderService{orderRepo: orderRepo, eventRepo: eventRepo, cartRepo: cartRepo, productRepo: productRepo, userRepo: userRepo, addressRepo: addressRepo, shippingSvc: shippingSvc, auditLogService: auditLogService, payments: payments, notifier: notifier, promotions: promotions, cfg: cfg.withDefaults()}
 }
 
 func (s *orderService) CreateOrderFromCart(userID string, req *dto.CreateOrderFromCartRequest) (*dto.OrderResponse, error) {
-	cart, err := s.cartRepo.FindByUserID(userID)
-	if err != nil || cart == nil || len(cart.Items) == 0 {
-		return nil, apperror.NewCodeMessage("cart_empty", "cart is empty")
+	cart, orderItems, total, err := s.priceCart(userID)
+	if err != nil {
+		return nil, err
 	}
 
-	var orderItems []model.OrderItem
-	total := 0.0
-	for _, cartItem := range cart.Items {
-		product, err := s.productRepo.FindByID(cartItem.ProductID)
-		if err != nil {
-			return nil, apperror.NewDomain(fmt.Errorf("product not found: %d", cartItem.ProductID), "product_not_found", "product not found")
-		}
-		if product.StockQuantity < cartItem.Quantity {
-			return nil, apperror.NewDomain(fmt.Errorf("insufficient stock for product: %s", product.Name), "insufficient_stock", "insufficient stock")
-		}
-		itemSubtotal := float64(cartItem.Quantity) * product.Price
-		orderItems = append(orderItems, model.OrderItem{
-			ProductID:       product.ID,
-			ProductSlug:     product.Slug,
-			ProductName:     product.Name,
-			ProductImageURL: product.ImageURL,
-			Quantity:        cartItem.Quantity,
-			PriceAtPurchase: product.Price,
-			Subtotal:        itemSubtotal,
-		})
-		total += itemSubtotal
+	shippingAddress, shippingDetails, err := s.resolveShippingAddress(userID, req)
+	if err != nil {
+		return nil, err
 	}
 
 	// Initialize order
 	order := &model.Order{
 		UserID:          userID,
 		TotalAmount:     total,
+		Currency:        money.BRL,
 		Status:          model.OrderStatusPending,
-		ShippingAddress: req.ShippingAddress,
+		ShippingAddress: shippingAddress,
+		ShippingDetails: shippingDetails,
 		PaymentMethod:   model.PaymentMethod(req.PaymentMethod),
 		Items:           orderItems,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
 
+	if err := s.placeOrder(userID, cart, order, req.ShippingSelection); err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(&model.OrderEvent{
+		OrderID:   order.ID,
+		Type:      model.OrderEventCreated,
+		ToStatus:  string(order.Status),
+		ActorType: model.OrderEventActorUser,
+		ActorID:   &userID,
+	})
+
+	resp := toOrderResponse(order)
+	return &resp, nil
+}
+
+// CreateGuestOrderFromCart checks out a guest session's cart. The order is tied to the guest's email
+// and a random lookup token, which is returned once and never stored in clear.
+func (s *orderService) CreateGuestOrderFromCart(guestID string, req *dto.CreateGuestOrderRequest) (*dto.GuestOrderResponse, error) {
+	cart, orderItems, total, err := s.priceCart(guestID)
+	if err != nil {
+		return nil, err
+	}
+
+	shippingAddress := strings.TrimSpace(req.ShippingAddress)
+	var shippingDetails model.PostalAddress
+	if req.Address != nil {
+		shippingDetails, err = userservice.NormalizePostalAddress(req.Address)
+		if err != nil {
+			return nil, err
+		}
+		shippingAddress = shippingDetails.String()
+	}
+	if shippingAddress == "" {
+		return nil, apperror.NewCodeMessage("invalid_request", "shipping address is required")
+	}
+
+	token, tokenHash, err := auth.GenerateGuestLookupToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate lookup token: %w", err)
+	}
+
+	order := &model.Order{
+		GuestID:              guestID,
+		GuestEmail:           strings.ToLower(strings.TrimSpace(req.Email)),
+		GuestLookupTokenHash: tokenHash,
+		TotalAmount:          total,
+		Currency:             money.BRL,
+		Status:               model.OrderStatusPending,
+		ShippingAddress:      shippingAddress,
+		ShippingDetails:      shippingDetails,
+		PaymentMethod:        model.PaymentMethod(req.PaymentMethod),
+		Items:                orderItems,
+		CreatedAt:            time.Now(),
+		UpdatedAt:            time.Now(),
+	}
+
+	if err := s.placeOrder(guestID, cart, order, req.ShippingSelection); err != nil {
+		return nil, err
+	}
+
+	s.recordEvent(&model.OrderEvent{
+		OrderID:   order.ID,
+		Type:      model.OrderEventCreated,
+		ToStatus:  string(order.Status),
+		ActorType: model.OrderEventActorUser,
+		ActorID:   &guestID,
+		Note:      "guest checkout",
+	})
+
+	// The token is shown only once in the response, so the email is the guest's only other copy.
+	if s.notifier != nil {
+		if err := s.notifier.SendGuestOrderConfirmation(order.GuestEmail, order, token); err != nil {
+			log.Printf("order %s: failed to send guest confirmation email: %v", order.PublicID, err)
+		}
+	}
+
+	return &dto.GuestOrderResponse{
+		OrderResponse: toOrderResponse(order),
+		GuestEmail:    order.GuestEmail,
+		LookupToken:   token,
+	}, nil
+}
+
+// priceCart turns the owner's cart into order items at current variant prices, checking stock.
+func (s *orderService) priceCart(ownerID string) (*model.Cart, []model.OrderItem, money.Amount, error) {
+	cart, err := s.cartRepo.FindByUserID(ownerID)
+	if err != nil || cart == nil || len(cart.Items) == 0 {
+		return nil, nil, 0, apperror.NewCodeMessage("cart_empty", "cart is empty")
+	}
+
+	var orderItems []model.OrderItem
+	var total money.Amount
+	for _, cartItem := range cart.Items {
+		if cartItem.ProductID == nil || cartItem.VariantID == nil {
+			return nil, nil, 0, apperror.NewDomain(fmt.Errorf("cart item %d: product was removed", cartItem.ID), "product_not_found", "product not found")
+		}
+		product, err := s.productRepo.FindByID(*cartItem.ProductID)
+		if err != nil {
+			return nil, nil, 0, apperror.NewDomain(fmt.Errorf("product not found: %d", *cartItem.ProductID), "product_not_found", "product not found")
+		}
+		variant := product.Variant(*cartItem.VariantID)
+		if variant == nil {
+			return nil, nil, 0, apperror.NewDomain(fmt.Errorf("variant not found: %d", *cartItem.VariantID), "product_not_found", "product not found")
+		}
+		if variant.StockQuantity < cartItem.Quantity {
+			return nil, nil, 0, apperror.NewDomain(fmt.Errorf("insufficient stock for product: %s (%s)", product.Name, variant.SKU), "insufficient_stock", "insufficient stock")
+		}
+		itemSubtotal := variant.Price.Mul(cartItem.Quantity)
+		variantID := variant.ID
+		orderItems = append(orderItems, model.OrderItem{
+			ProductID:            product.ID,
+			ProductSlug:          product.Slug,
+			ProductName:          product.Name,
+			ProductImageURL:      product.ImageURL,
+			VariantID:            &variantID,
+			VariantSKU:           variant.SKU,
+			VariantSizeML:        variant.SizeML,
+			VariantConcentration: variant.Concentration,
+			Quantity:             cartItem.Quantity,
+			PriceAtPurchase:      variant.Price,
+			Subtotal:             itemSubtotal,
+		})
+		total += itemSubtotal
+	}
+	return cart, orderItems, total, nil
+}
+
+// placeOrder validates the shipping selection and the cart's coupon, then reserves stock, persists
+// the order and clears the cart.
+func (s *orderService) placeOrder(ownerID string, cart *model.Cart, order *model.Order, selection *dto.ShippingSelection) error {
 	// Validate shipping selection against fresh quotes and persist shipping fields
-	if req.ShippingSelection != nil {
-		cep := validation.ExtractCEPFromString(req.ShippingAddress)
+	if selection != nil {
+		cep := order.ShippingDetails.CEP
+		if cep == "" {
+			cep = validation.ExtractCEPFromString(order.ShippingAddress)
+		}
 		if cep == "" {
-			return nil, apperror.NewCodeMessage("invalid_postal_code", "invalid destination postal code")
+			return apperror.NewCodeMessage("invalid_postal_code", "invalid destination postal code")
 		}
 
 		if s.shippingSvc == nil {
-			return nil, apperror.NewCodeMessage("provider_unavailable", "shipping provider not configured")
+			return apperror.NewCodeMessage("provider_unavailable", "shipping provider not configured")
 		}
 
 		// Re-quote to validate selection.
-		options, err := s.shippingSvc.CalculateOptions(context.Background(), userID, cep)
+		options, err := s.shippingSvc.CalculateOptions(context.Background(), ownerID, cep)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		var matched *dto.ShippingOption
 		for i := range options {
-			if options[i].Carrier == req.ShippingSelection.Carrier && options[i].ServiceCode == req.ShippingSelection.ServiceCode {
+			if options[i].Carrier == selection.Carrier && options[i].ServiceCode == selection.ServiceCode {
 				matched = &options[i]
 				break
 			}
 		}
 		if matched == nil {
-			return nil, apperror.NewCodeMessage("invalid_shipping_selection", "invalid shipping selection")
+			return apperror.NewCodeMessage("invalid_shipping_selection", "invalid shipping selection")
 		}
 
 		order.ShippingCarrier = matched.Carrier
@@ -110,42 +283,76 @@ func (s *orderService) CreateOrderFromCart(userID string, req *dto.CreateOrderFr
 		order.TotalAmount += matched.Price
 	}
 
-	if err := s.orderRepo.Create(order); err != nil {
-		return nil, err
+	if cart.PromotionCode != "" && s.promotions != nil {
+		customer := promotionservice.Customer{UserID: order.UserID, Email: order.GuestEmail}
+		discount, err := s.promotions.Evaluate(cart.PromotionCode, customer, orderLines(cart, order.Items), order.ShippingPrice)
+		if err != nil {
+			return err
+		}
+		order.DiscountAmount = discount.Total()
+		order.PromotionID = &discount.Promotion.ID
+		order.PromotionCode = discount.Promotion.Code
+		order.TotalAmount -= order.DiscountAmount
+	}
+
+	// Reserve stock, persist the order and clear the cart atomically.
+	// The reservation is released by the expiry job if the order is still unpaid after the TTL.
+	expiresAt := time.Now().Add(s.cfg.ReservationTTL)
+	order.ReservationExpiresAt = &expiresAt
+	if err := s.orderRepo.CreateWithStockReservation(order, cart.ID); err != nil {
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			return apperror.NewDomain(err, "insufficient_stock", "insufficient stock")
+		}
+		if errors.Is(err, repository.ErrPromotionUsageLimit) {
+			return apperror.NewDomain(err, "promotion_usage_limit_reached", "promotion usage limit reached")
+		}
+		return err
 	}
+	return nil
+}
 
-	// Clear cart
-	if err := s.cartRepo.ClearCartItems(cart.ID); err != nil {
-		return nil, apperror.NewCodeMessage("cart_clear_failed", "order created, but failed to clear cart")
+// orderLines describes order items to the promotion engine, with the brand and category of the
+// cart's products.
+func orderLines(cart *model.Cart, items []model.OrderItem) []promotionservice.Line {
+	products := make(map[uint]*model.Product, len(cart.Items))
+	for i := range cart.Items {
+		if product := cart.Items[i].Product; product != nil {
+			products[product.ID] = product
+		}
 	}
 
-	// Map order items to response
-	items := make([]dto.OrderItemResponse, len(order.Items))
-	for i, item := range order.Items {
-		items[i] = dto.OrderItemResponse{
-			ProductSlug:     item.ProductSlug,
-			ProductName:     item.ProductName,
-			ProductImageURL: item.ProductImageURL,
-			Quantity:        item.Quantity,
-			PriceAtPurchase: item.PriceAtPurchase,
-			Subtotal:        item.Subtotal,
-		}
-	}
-	return &dto.OrderResponse{
-		PublicID:                  order.PublicID,
-		TotalAmount:               order.TotalAmount,
-		Status:                    string(order.Status),
-		ShippingAddress:           order.ShippingAddress,
-		PaymentMethod:             string(order.PaymentMethod),
-		ShippingPrice:             order.ShippingPrice,
-		ShippingCarrier:           order.ShippingCarrier,
-		ShippingServiceCode:       order.ShippingServiceCode,
-		ShippingEstimatedDelivery: order.ShippingEstimatedDelivery,
-		Items:                     items,
-		ItemCount:                 len(order.Items),
-		CreatedAt:                 order.CreatedAt,
-		UpdatedAt:                 order.UpdatedAt,
-	}, nil
+	lines := make([]promotionservice.Line, len(items))
+	for i, item := range items {
+		lines[i] = promotionservice.Line{ProductID: item.ProductID, UnitPrice: item.PriceAtPurchase, Quantity: item.Quantity}
+		if product := products[item.ProductID]; product != nil {
+			lines[i].Brand = product.Brand
+			lines[i].Category = product.Category
+		}
+	}
+	return lines
+}
+
+// resolveShippingAddress returns the delivery address for a new order. An address book entry is
+// copied onto the order so later edits to the book do not change where the order ships.
+func (s *orderService) resolveShippingAddress(userID string, req *dto.CreateOrderFromCartRequest) (string, model.PostalAddress, error) {
+	if req.AddressID == "" {
+		address := strings.TrimSpace(req.ShippingAddress)
+		if address == "" {
+			return "", model.PostalAddress{}, apperror.NewCodeMessage("invalid_request", "shipping address is required")
+		}
+		return address, model.PostalAddress{}, nil
+	}
+	if s.addressRepo == nil {
+		return "", model.PostalAddress{}, apperror.NewCodeMessage("address_not_found", "address not found")
+	}
+	address, err := s.addressRepo.FindByPublicID(userID, req.AddressID)
+	if err != nil {
+		return "", model.PostalAddress{}, err
+	}
+	if address == nil {
+		return "", model.PostalAddress{}, apperror.NewCodeMessage("address_not_found", "address not found")
+	}
+	return address.PostalAddress.String(), address.PostalAddress, nil
 }
 
 // AdminListOrders returns orders for admin listing with pagination and stats
@@ -159,12 +366,13 @@ func (s *orderService) AdminListOrders(status *string, startDate *time.Time, end
 	var items []dto.AdminOrderItem
 	for _, o := range orders {
 		items = append(items, dto.AdminOrderItem{
-			ID:          o.ID,
-			PublicID:    o.PublicID,
-			UserID:      o.UserID,
-			TotalAmount: o.TotalAmount,
-			Status:      string(o.Status),
-			CreatedAt:   o.CreatedAt,
+			ID:                  o.ID,
+			PublicID:            o.PublicID,
+			UserID:              o.UserID,
+			TotalAmount:         o.TotalAmount,
+			Status:              string(o.Status),
+			PaymentReviewReason: o.PaymentReviewReason,
+			CreatedAt:           o.CreatedAt,
 		})
 	}
 
@@ -181,9 +389,9 @@ func (s *orderService) AdminListOrders(status *string, startDate *time.Time, end
 		TotalPages: totalPages,
 		TotalCount: int(totalCount),
 	}
-	avg := 0.0
+	var avg money.Amount
 	if totalCount > 0 {
-		avg = totalRevenue / float64(totalCount)
+		avg = totalRevenue.Div(totalCount)
 	}
 	resp.Meta.Stats = dto.StatsMeta{
 		TotalRevenue:      totalRevenue,
@@ -201,37 +409,8 @@ func (s *orderService) GetOrdersByUser(userID string) ([]dto.OrderResponse, erro
 	}
 
 	resp := make([]dto.OrderResponse, 0, len(orders))
-	for _, o := range orders {
-		items := make([]dto.OrderItemResponse, len(o.Items))
-		for i, it := range o.Items {
-			item := dto.OrderItemResponse{
-				ProductSlug:     it.ProductSlug,
-				ProductName:     it.ProductName,
-				ProductImageURL: it.ProductImageURL,
-				Quantity:        it.Quantity,
-				PriceAtPurchase: it.PriceAtPurchase,
-				Subtotal:        it.Subtotal,
-			}
-			items[i] = item
-		}
-
-		resp = append(resp, dto.OrderResponse{
-			PublicID:                  o.PublicID,
-			TotalAmount:               o.TotalAmount,
-			Status:                    string(o.Status),
-			ShippingAddress:           o.ShippingAddress,
-			PaymentMethod:             string(o.PaymentMethod),
-			ShippingPrice:             o.ShippingPrice,
-			ShippingCarrier:           o.ShippingCarrier,
-			ShippingServiceCode:       o.ShippingServiceCode,
-			ShippingEstimatedDelivery: o.ShippingEstimatedDelivery,
-			ShippingTracking:          o.ShippingTracking,
-			ShippingStatus:            o.ShippingStatus,
-			Items:                     items,
-			ItemCount:                 len(items),
-			CreatedAt:                 o.CreatedAt,
-			UpdatedAt:                 o.UpdatedAt,
-		})
+	for i := range orders {
+		resp = append(resp, toOrderResponse(&orders[i]))
 	}
 
 	if resp == nil {
@@ -239,3 +418,596 @@ func (s *orderService) GetOrdersByUser(userID string) ([]dto.OrderResponse, erro
 	}
 	return resp, nil
 }
+
+// UpdateOrderStatus moves an order through its fulfillment lifecycle on behalf of an admin.
+// Cancelling settles payments like a customer cancellation: open intents are withdrawn before the
+// stock is released and any captured payment is refunded afterwards.
+func (s *orderService) UpdateOrderStatus(ctx context.Context, publicID string, req *dto.UpdateOrderStatusRequest, adminPublicID string) (*dto.OrderResponse, error) {
+	if req == nil {
+		return nil, apperror.NewCodeMessage("invalid_request", "missing payload")
+	}
+
+	order, err := s.orderRepo.FindByPublicIDWithItems(publicID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, apperror.NewCodeMessage("order_not_found", "order not found")
+	}
+
+	target := model.OrderStatus(req.Status)
+	if err := ValidateTransition(order.Status, target); err != nil {
+		return nil, err
+	}
+
+	tracking := strings.TrimSpace(req.TrackingNumber)
+	if target == model.OrderStatusShipped && tracking == "" {
+		return nil, apperror.NewCodeMessage("tracking_number_required", "tracking number is required to ship an order")
+	}
+
+	shippingStatus := ""
+	switch target {
+	case model.OrderStatusShipped, model.OrderStatusDelivered:
+		shippingStatus = string(target)
+	}
+
+	var updateErr error
+	if target == model.OrderStatusCancelled {
+		if s.payments != nil && order.Status == model.OrderStatusPending {
+			if _, err := s.payments.CancelOrderIntents(ctx, publicID, "admin_cancelled", "order cancelled by admin"); err != nil {
+				return nil, err
+			}
+		}
+		updateErr = s.orderRepo.CancelAndReleaseStock(publicID, order.Status)
+	} else {
+		updateErr = s.orderRepo.UpdateFulfillmentStatus(publicID, order.Status, target, shippingStatus, tracking)
+	}
+	if err := updateErr; err != nil {
+		if errors.Is(err, repository.ErrOrderStatusConflict) {
+			return nil, apperror.NewDomain(err, "order_status_conflict", "order status changed concurrently")
+		}
+		return nil, err
+	}
+
+	previous := order.Status
+	order.Status = target
+	if shippingStatus != "" {
+		order.ShippingStatus = shippingStatus
+	}
+	if tracking != "" {
+		order.ShippingTracking = tracking
+	}
+	order.UpdatedAt = time.Now()
+
+	s.logStatusChange(order, previous, adminPublicID, req.Reason)
+
+	s.recordEvent(&model.OrderEvent{
+		OrderID:    order.ID,
+		Type:       model.OrderEventStatusChanged,
+		FromStatus: string(previous),
+		ToStatus:   string(target),
+		ActorType:  model.OrderEventActorAdmin,
+		ActorID:    &adminPublicID,
+		Note:       req.Reason,
+	})
+	if shippingStatus != "" {
+		s.recordEvent(&model.OrderEvent{
+			OrderID:   order.ID,
+			Type:      model.OrderEventShippingUpdated,
+			ToStatus:  shippingStatus,
+			ActorType: model.OrderEventActorAdmin,
+			ActorID:   &adminPublicID,
+			Metadata:  datatypes.JSONMap{"shipping_tracking": order.ShippingTracking},
+		})
+	}
+	if target == model.OrderStatusCancelled {
+		order.StockReserved = false
+		s.refundCancelledOrder(ctx, order, req.Reason, adminPublicID)
+	}
+
+	resp := toOrderResponse(order)
+	return &resp, nil
+}
+
+// GetOrderByPublicID returns an order with its timeline, only if it belongs to the given user.
+func (s *orderService) GetOrderByPublicID(userID string, publicID string) (*dto.OrderDetailResponse, error) {
+	order, err := s.orderRepo.FindByPublicIDWithItems(publicID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.UserID != userID {
+		return nil, apperror.NewCodeMessage("order_not_found", "order not found")
+	}
+
+	timeline, err := s.timeline(order.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OrderDetailResponse{
+		OrderResponse: toOrderResponse(order),
+		Timeline:      timeline,
+	}, nil
+}
+
+// TrackGuestOrder returns a guest order with its timeline when the email and lookup token match.
+// Any mismatch is reported as not found so the endpoint does not reveal which part was wrong.
+func (s *orderService) TrackGuestOrder(req *dto.TrackOrderRequest) (*dto.OrderDetailResponse, error) {
+	order, err := s.orderRepo.FindByGuestLookupTokenHash(auth.HashGuestLookupToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.GuestEmail == "" || !strings.EqualFold(order.GuestEmail, strings.TrimSpace(req.Email)) {
+		return nil, apperror.NewCodeMessage("order_not_found", "order not found")
+	}
+
+	timeline, err := s.timeline(order.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OrderDetailResponse{
+		OrderResponse: toOrderResponse(order),
+		Timeline:      timeline,
+	}, nil
+}
+
+// ClaimGuestOrders moves guest orders into the user's account. An order is only claimed when it was
+// placed with the account's email and the caller proves it is theirs, either from the same guest
+// session or with the order's lookup token.
+func (s *orderService) ClaimGuestOrders(userID string, guestID string, req *dto.ClaimGuestOrdersRequest) (*dto.ClaimGuestOrdersResponse, error) {
+	var tokenHashes []string
+	if req != nil {
+		for _, token := range req.Tokens {
+			if strings.TrimSpace(token) != "" {
+				tokenHashes = append(tokenHashes, auth.HashGuestLookupToken(token))
+			}
+		}
+	}
+	resp := &dto.ClaimGuestOrdersResponse{ClaimedOrders: []string{}}
+	if guestID == "" && len(tokenHashes) == 0 {
+		return resp, nil
+	}
+
+	user, err := s.userRepo.FindByPublicID(userID)
+	if err != nil || user == nil {
+		return nil, apperror.NewCodeMessage("unauthorized", "user not found")
+	}
+
+	claimed, err := s.orderRepo.ClaimGuestOrders(userID, user.Email, guestID, tokenHashes)
+	if err != nil {
+		return nil, err
+	}
+	if len(claimed) > 0 {
+		log.Printf("claimed %d guest orders for user %s", len(claimed), userID)
+		resp.ClaimedOrders = claimed
+	}
+	return resp, nil
+}
+
+// AdminGetOrder returns any order with its full timeline, including actor identifiers.
+func (s *orderService) AdminGetOrder(publicID string) (*dto.AdminOrderDetailResponse, error) {
+	order, err := s.orderRepo.FindByPublicIDWithItems(publicID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, apperror.NewCodeMessage("order_not_found", "order not found")
+	}
+
+	timeline, err := s.timeline(order.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AdminOrderDetailResponse{
+		OrderResponse:       toOrderResponse(order),
+		UserID:              order.UserID,
+		GuestEmail:          order.GuestEmail,
+		PaymentReviewReason: order.PaymentReviewReason,
+		PaymentReviewAt:     order.PaymentReviewAt,
+		Timeline:            timeline,
+	}, nil
+}
+
+// ReleaseExpiredReservation cancels an unpaid order whose reservation has lapsed and returns its stock.
+func (s *orderService) ReleaseExpiredReservation(publicID string) error {
+	order, err := s.orderRepo.FindByPublicIDWithItems(publicID)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return apperror.NewCodeMessage("order_not_found", "order not found")
+	}
+	if order.Status != model.OrderStatusPending || !order.StockReserved {
+		return apperror.NewCodeMessage("order_status_conflict", "order is no longer awaiting payment")
+	}
+	if order.ReservationExpiresAt == nil || order.ReservationExpiresAt.After(time.Now()) {
+		return apperror.NewCodeMessage("reservation_active", "order reservation has not expired")
+	}
+	return s.cancelUnpaid(order, "reservation expired")
+}
+
+// CancelUnpaidOrder cancels an order still awaiting payment on behalf of the system, e.g. when its boleto
+// went overdue, and returns any reserved stock. The note is kept on the order timeline.
+func (s *orderService) CancelUnpaidOrder(publicID string, note string) error {
+	order, err := s.orderRepo.FindByPublicIDWithItems(publicID)
+	if err != nil {
+		return err
+	}
+	if order == nil {
+		return apperror.NewCodeMessage("order_not_found", "order not found")
+	}
+	if order.Status != model.OrderStatusPending {
+		return apperror.NewCodeMessage("order_status_conflict", "order is no longer awaiting payment")
+	}
+	return s.cancelUnpaid(order, note)
+}
+
+// cancelUnpaid moves a pending order to cancelled, releasing its stock, and records a system event.
+func (s *orderService) cancelUnpaid(order *model.Order, note string) error {
+	if err := s.orderRepo.CancelAndReleaseStock(order.PublicID, model.OrderStatusPending); err != nil {
+		if errors.Is(err, repository.ErrOrderStatusConflict) {
+			return apperror.NewDomain(err, "order_status_conflict", "order status changed concurrently")
+		}
+		return err
+	}
+
+	s.recordEvent(&model.OrderEvent{
+		OrderID:    order.ID,
+		Type:       model.OrderEventStatusChanged,
+		FromStatus: string(model.OrderStatusPending),
+		ToStatus:   string(model.OrderStatusCancelled),
+		ActorType:  model.OrderEventActorSystem,
+		Note:       note,
+	})
+	return nil
+}
+
+// CancelOrder lets a customer cancel their own order while the configured rules allow it.
+// Open payment intents are withdrawn first, then the order is cancelled and its stock returned,
+// and finally any captured payment is refunded in full.
+func (s *orderService) CancelOrder(ctx context.Context, userID string, publicID string, reason string) (*dto.OrderCancellationResponse, error) {
+	order, err := s.orderRepo.FindByPublicIDWithItems(publicID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil || order.UserID != userID {
+		return nil, apperror.NewCodeMessage("order_not_found", "order not found")
+	}
+
+	window, allowed := s.cfg.CustomerCancelWindows[order.Status]
+	if !allowed {
+		return nil, apperror.NewCodeMessage("order_not_cancellable", "order can no longer be cancelled")
+	}
+	if window > 0 && time.Since(order.CreatedAt) > window {
+		return nil, apperror.NewCodeMessage("cancellation_window_expired", "cancellation window has expired")
+	}
+
+	if s.payments != nil && order.Status == model.OrderStatusPending {
+		if _, err := s.payments.CancelOrderIntents(ctx, publicID, "customer_cancelled", "order cancelled by customer"); err != nil {
+			return nil, err
+		}
+	}
+
+	previous := order.Status
+	if err := s.orderRepo.CancelAndReleaseStock(publicID, previous); err != nil {
+		if errors.Is(err, repository.ErrOrderStatusConflict) {
+			return nil, apperror.NewDomain(err, "order_status_conflict", "order status changed concurrently")
+		}
+		return nil, err
+	}
+	order.Status = model.OrderStatusCancelled
+	order.StockReserved = false
+	order.UpdatedAt = time.Now()
+
+	s.recordEvent(&model.OrderEvent{
+		OrderID:    order.ID,
+		Type:       model.OrderEventStatusChanged,
+		FromStatus: string(previous),
+		ToStatus:   string(order.Status),
+		ActorType:  model.OrderEventActorUser,
+		ActorID:    &userID,
+		Note:       reason,
+	})
+
+	refundStatus := RefundStatusNone
+	var refundedCents int64
+	if s.payments != nil {
+		outcome, err := s.payments.RefundOrder(ctx, publicID, RefundRequest{
+			Reason:    reason,
+			ActorType: model.OrderEventActorUser,
+			ActorID:   userID,
+		})
+		if outcome != nil {
+			refundedCents = outcome.RefundedCents
+		}
+		switch {
+		case err != nil:
+			// The order stays cancelled; the refund is left for an admin to settle.
+			log.Printf("order %s cancelled but refund failed: %v", publicID, err)
+			refundStatus = RefundStatusFailed
+		case refundedCents > 0:
+			refundStatus = RefundStatusRefunded
+		}
+	}
+	refundedAmount := money.FromCents(refundedCents)
+	if refundStatus != RefundStatusNone {
+		s.recordEvent(&model.OrderEvent{
+			OrderID:   order.ID,
+			Type:      model.OrderEventPaymentUpdated,
+			ToStatus:  refundStatus,
+			ActorType: model.OrderEventActorSystem,
+			Metadata:  datatypes.JSONMap{"refunded_cents": refundedCents},
+		})
+	}
+
+	s.logCustomerCancellation(order, previous, userID, reason, refundStatus, refundedCents)
+
+	if s.notifier != nil && s.userRepo != nil {
+		if user, err := s.userRepo.FindByPublicID(userID); err == nil && user != nil {
+			if err := s.notifier.SendOrderCancelled(user.Email, order, refundedAmount); err != nil {
+				log.Printf("order %s: failed to send cancellation email: %v", publicID, err)
+			}
+		}
+	}
+
+	return &dto.OrderCancellationResponse{
+		OrderResponse:  toOrderResponse(order),
+		RefundStatus:   refundStatus,
+		RefundedAmount: refundedAmount,
+	}, nil
+}
+
+// AdminRefundOrder returns all or part of an order's captured payments on behalf of an admin.
+func (s *orderService) AdminRefundOrder(ctx context.Context, publicID string, req *dto.AdminRefundRequest, adminPublicID string) (*dto.RefundResponse, error) {
+	if req == nil {
+		return nil, apperror.NewCodeMessage("invalid_request", "missing payload")
+	}
+	if s.payments == nil {
+		return nil, apperror.NewCodeMessage("provider_unavailable", "payment provider not configured")
+	}
+
+	order, err := s.orderRepo.FindByPublicIDWithItems(publicID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, apperror.NewCodeMessage("order_not_found", "order not found")
+	}
+
+	outcome, err := s.payments.RefundOrder(ctx, publicID, RefundRequest{
+		AmountCents: req.Amount.Cents(),
+		Reason:      req.Reason,
+		ActorType:   model.OrderEventActorAdmin,
+		ActorID:     adminPublicID,
+	})
+	// A refund spread over several payments may fail halfway; whatever was returned is still recorded.
+	if outcome != nil && outcome.RefundedCents > 0 {
+		s.recordRefund(order, outcome, req.Reason, adminPublicID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if outcome.RefundedCents == 0 {
+		return nil, apperror.NewCodeMessage("nothing_to_refund", "order has no captured payment to refund")
+	}
+
+	return &dto.RefundResponse{
+		OrderPublicID:   publicID,
+		Status:          string(refundedPaymentStatus(outcome)),
+		RefundedAmount:  money.FromCents(outcome.RefundedCents),
+		RemainingAmount: money.FromCents(outcome.RemainingCents),
+	}, nil
+}
+
+// refundCancelledOrder returns whatever was captured for an order an admin cancelled. A failed
+// refund leaves the order cancelled and is recorded on the timeline for an admin to settle.
+func (s *orderService) refundCancelledOrder(ctx context.Context, order *model.Order, reason string, adminPublicID string) {
+	if s.payments == nil {
+		return
+	}
+	outcome, err := s.payments.RefundOrder(ctx, order.PublicID, RefundRequest{
+		Reason:    reason,
+		ActorType: model.OrderEventActorAdmin,
+		ActorID:   adminPublicID,
+	})
+	if outcome != nil && outcome.RefundedCents > 0 {
+		s.recordRefund(order, outcome, reason, adminPublicID)
+	}
+	if err != nil {
+		log.Printf("order %s cancelled by admin but refund failed: %v", order.PublicID, err)
+		s.recordEvent(&model.OrderEvent{
+			OrderID:   order.ID,
+			Type:      model.OrderEventPaymentUpdated,
+			ToStatus:  RefundStatusFailed,
+			ActorType: model.OrderEventActorSystem,
+			Note:      err.Error(),
+		})
+	}
+}
+
+// recordRefund adds an admin refund to the order timeline and the audit log.
+func (s *orderService) recordRefund(order *model.Order, outcome *RefundOutcome, reason string, adminPublicID string) {
+	status := refundedPaymentStatus(outcome)
+	s.recordEvent(&model.OrderEvent{
+		OrderID:   order.ID,
+		Type:      model.OrderEventPaymentUpdated,
+		ToStatus:  string(status),
+		ActorType: model.OrderEventActorAdmin,
+		ActorID:   &adminPublicID,
+		Note:      reason,
+		Metadata: datatypes.JSONMap{
+			"refunded_cents":  outcome.RefundedCents,
+			"remaining_cents": outcome.RemainingCents,
+		},
+	})
+
+	if s.auditLogService == nil {
+		return
+	}
+	var actorID *uint
+	if s.userRepo != nil && adminPublicID != "" {
+		if admin, err := s.userRepo.FindByPublicID(adminPublicID); err == nil && admin != nil {
+			actorID = &admin.ID
+		}
+	}
+	details := map[string]interface{}{
+		"actor_type":      "admin",
+		"admin_public_id": adminPublicID,
+		"order_user_id":   order.UserID,
+		"reason":          reason,
+		"refunded_cents":  outcome.RefundedCents,
+	}
+	newValues := map[string]interface{}{"payment_status": status, "remaining_cents": outcome.RemainingCents}
+	s.auditLogService.LogOrderAction(actorID, order.PublicID, model.AuditActionOrderRefunded, details, nil, newValues)
+}
+
+// refundedPaymentStatus tells whether a refund left any captured money on the order.
+func refundedPaymentStatus(outcome *RefundOutcome) model.PaymentStatus {
+	if outcome.RemainingCents > 0 {
+		return model.PaymentStatusPartiallyRefunded
+	}
+	return model.PaymentStatusRefunded
+}
+
+// timeline loads the events of an order. Actor identifiers are only exposed to admins.
+func (s *orderService) timeline(orderID uint, includeActorID bool) ([]dto.OrderEventResponse, error) {
+	resp := []dto.OrderEventResponse{}
+	if s.eventRepo == nil {
+		return resp, nil
+	}
+
+	events, err := s.eventRepo.ListByOrderID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		item := dto.OrderEventResponse{
+			Type:       string(e.Type),
+			FromStatus: e.FromStatus,
+			ToStatus:   e.ToStatus,
+			Actor:      string(e.ActorType),
+			Note:       e.Note,
+			CreatedAt:  e.CreatedAt,
+		}
+		if includeActorID && e.ActorID != nil {
+			item.ActorID = *e.ActorID
+		}
+		resp = append(resp, item)
+	}
+	return resp, nil
+}
+
+// recordEvent appends an entry to the order history. Failures are logged and never block the caller.
+func (s *orderService) recordEvent(event *model.OrderEvent) {
+	if s.eventRepo == nil {
+		return
+	}
+	if err := s.eventRepo.Create(event); err != nil {
+		log.Printf("order events: failed to record %s for order %d: %v", event.Type, event.OrderID, err)
+	}
+}
+
+// logStatusChange records an audit entry for an admin-driven order status change.
+func (s *orderService) logStatusChange(order *model.Order, previous model.OrderStatus, adminPublicID string, reason string) {
+	if s.auditLogService == nil {
+		return
+	}
+
+	var actorID *uint
+	if s.userRepo != nil && adminPublicID != "" {
+		if admin, err := s.userRepo.FindByPublicID(adminPublicID); err == nil && admin != nil {
+			actorID = &admin.ID
+		}
+	}
+
+	details := map[string]interface{}{
+		"actor_type":      "admin",
+		"admin_public_id": adminPublicID,
+		"order_user_id":   order.UserID,
+	}
+	if reason != "" {
+		details["reason"] = reason
+	}
+	oldValues := map[string]interface{}{"status": previous}
+	newValues := map[string]interface{}{"status": order.Status}
+	if order.ShippingTracking != "" {
+		newValues["shipping_tracking"] = order.ShippingTracking
+	}
+
+	s.auditLogService.LogOrderAction(actorID, order.PublicID, model.AuditActionOrderStatusChanged, details, oldValues, newValues)
+}
+
+// logCustomerCancellation records an audit entry for a customer-initiated cancellation.
+func (s *orderService) logCustomerCancellation(order *model.Order, previous model.OrderStatus, userID string, reason string, refundStatus string, refundedCents int64) {
+	if s.auditLogService == nil {
+		return
+	}
+
+	var actorID *uint
+	if s.userRepo != nil {
+		if user, err := s.userRepo.FindByPublicID(userID); err == nil && user != nil {
+			actorID = &user.ID
+		}
+	}
+
+	details := map[string]interface{}{
+		"actor_type":     "user",
+		"refund_status":  refundStatus,
+		"refunded_cents": refundedCents,
+	}
+	if reason != "" {
+		details["reason"] = reason
+	}
+	oldValues := map[string]interface{}{"status": previous}
+	newValues := map[string]interface{}{"status": order.Status}
+
+	s.auditLogService.LogOrderAction(actorID, order.PublicID, model.AuditActionOrderCancelledByUser, details, oldValues, newValues)
+}
+
+// toOrderResponse maps an order model to its client-facing response.
+func toOrderResponse(o *model.Order) dto.OrderResponse {
+	items := make([]dto.OrderItemResponse, len(o.Items))
+	for i, it := range o.Items {
+		items[i] = dto.OrderItemResponse{
+			ProductSlug:     it.ProductSlug,
+			ProductName:     it.ProductName,
+			ProductImageURL: it.ProductImageURL,
+			VariantSKU:      it.VariantSKU,
+			SizeML:          it.VariantSizeML,
+			Concentration:   string(it.VariantConcentration),
+			Quantity:        it.Quantity,
+			PriceAtPurchase: it.PriceAtPurchase,
+			Subtotal:        it.Subtotal,
+		}
+	}
+
+	var shippingDetails *dto.PostalAddressResponse
+	if !o.ShippingDetails.IsZero() {
+		details := dto.PostalAddressResponseFromModel(o.ShippingDetails)
+		shippingDetails = &details
+	}
+
+	return dto.OrderResponse{
+		PublicID:                  o.PublicID,
+		TotalAmount:               o.TotalAmount,
+		Status:                    string(o.Status),
+		ShippingAddress:           o.ShippingAddress,
+		ShippingDetails:           shippingDetails,
+		PaymentMethod:             string(o.PaymentMethod),
+		ShippingPrice:             o.ShippingPrice,
+		DiscountAmount:            o.DiscountAmount,
+		PromotionCode:             o.PromotionCode,
+		ShippingCarrier:           o.ShippingCarrier,
+		ShippingServiceCode:       o.ShippingServiceCode,
+		ShippingEstimatedDelivery: o.ShippingEstimatedDelivery,
+		ShippingTracking:          o.ShippingTracking,
+		ShippingStatus:            o.ShippingStatus,
+		ReservationExpiresAt:      o.ReservationExpiresAt,
+		Items:                     items,
+		ItemCount:                 len(items),
+		CreatedAt:                 o.CreatedAt,
+		UpdatedAt:                 o.UpdatedAt,
+	}
+}
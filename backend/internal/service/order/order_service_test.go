@@ -3,28 +3,80 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/auth"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/leoferamos/aroma-sense/internal/notification"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+	promotionservice "github.com/leoferamos/aroma-sense/internal/service/promotion"
 	"github.com/stretchr/testify/assert"
 )
 
 type mockOrderRepo struct {
-	createErr        error
-	listOrders       []model.Order
-	listCount        int64
-	listRevenue      float64
-	listErr          error
-	findByUserErr    error
-	findByUserOrders []model.Order
+	createErr         error
+	listOrders        []model.Order
+	listCount         int64
+	listRevenue       money.Amount
+	listErr           error
+	findByUserErr     error
+	findByUserOrders  []model.Order
+	findByPublicID    *model.Order
+	findByPublicIDErr error
+	fulfillmentErr    error
+	fulfillmentCalls  int
+	lastTracking      string
+	reservedCartID    uint
+	cancelErr         error
+	cancelCalls       int
+	created           *model.Order
+	byTokenHash       map[string]*model.Order
+	claimEmail        string
+	claimGuestID      string
+	claimTokenHashes  []string
+	claimed           []string
 }
 
 func (m *mockOrderRepo) Create(order *model.Order) error {
 	return m.createErr
 }
 
+func (m *mockOrderRepo) CreateWithStockReservation(order *model.Order, cartID uint) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.reservedCartID = cartID
+	m.created = order
+	order.StockReserved = true
+	return nil
+}
+
+func (m *mockOrderRepo) ReserveStock(publicID string) error {
+	return nil
+}
+
+func (m *mockOrderRepo) CancelAndReleaseStock(publicID string, from model.OrderStatus) error {
+	m.cancelCalls++
+	return m.cancelErr
+}
+
+func (m *mockOrderRepo) FindExpiredReservations(now time.Time, limit int) ([]model.Order, error) {
+	return nil, nil
+}
+
+func (m *mockOrderRepo) ExtendReservation(publicID string, until time.Time) error {
+	return nil
+}
+
+func (m *mockOrderRepo) FlagPaymentReview(publicID string, reason string, at time.Time) (bool, error) {
+	return false, nil
+}
+
 func (m *mockOrderRepo) FindByID(id uint) (*model.Order, error) {
 	return nil, nil
 }
@@ -34,10 +86,21 @@ func (m *mockOrderRepo) FindByUserID(userID string) ([]model.Order, error) {
 }
 
 func (m *mockOrderRepo) FindByPublicIDWithItems(publicID string) (*model.Order, error) {
-	return nil, nil
+	return m.findByPublicID, m.findByPublicIDErr
 }
 
-func (m *mockOrderRepo) ListOrders(status *string, startDate *time.Time, endDate *time.Time, page int, perPage int) ([]model.Order, int64, float64, error) {
+func (m *mockOrderRepo) FindByGuestLookupTokenHash(tokenHash string) (*model.Order, error) {
+	return m.byTokenHash[tokenHash], nil
+}
+
+func (m *mockOrderRepo) ClaimGuestOrders(userID string, email string, guestID string, tokenHashes []string) ([]string, error) {
+	m.claimEmail = email
+	m.claimGuestID = guestID
+	m.claimTokenHashes = tokenHashes
+	return m.claimed, nil
+}
+
+func (m *mockOrderRepo) ListOrders(status *string, startDate *time.Time, endDate *time.Time, page int, perPage int) ([]model.Order, int64, money.Amount, error) {
 	return m.listOrders, m.listCount, m.listRevenue, m.listErr
 }
 
@@ -49,10 +112,54 @@ func (m *mockOrderRepo) UpdateStatusByPublicID(publicID string, status model.Ord
 	return nil
 }
 
+func (m *mockOrderRepo) UpdateFulfillmentStatus(publicID string, from model.OrderStatus, to model.OrderStatus, shippingStatus string, tracking string) error {
+	m.fulfillmentCalls++
+	m.lastTracking = tracking
+	return m.fulfillmentErr
+}
+
+type mockOrderEventRepo struct {
+	events  []model.OrderEvent
+	listErr error
+}
+
+func (m *mockOrderEventRepo) Create(event *model.OrderEvent) error {
+	m.events = append(m.events, *event)
+	return nil
+}
+
+func (m *mockOrderEventRepo) ListByOrderID(orderID uint) ([]model.OrderEvent, error) {
+	return m.events, m.listErr
+}
+
+type mockOrderPayments struct {
+	cancelIntentsErr error
+	cancelCalls      int
+	cancelReason     string
+	refundedCents    int64
+	remainingCents   int64
+	refundErr        error
+	refundCalls      int
+	lastRefund       RefundRequest
+}
+
+func (m *mockOrderPayments) CancelOrderIntents(ctx context.Context, orderPublicID string, reason string, message string) ([]string, error) {
+	m.cancelCalls++
+	m.cancelReason = reason
+	return nil, m.cancelIntentsErr
+}
+
+func (m *mockOrderPayments) RefundOrder(ctx context.Context, orderPublicID string, req RefundRequest) (*RefundOutcome, error) {
+	m.refundCalls++
+	m.lastRefund = req
+	return &RefundOutcome{RefundedCents: m.refundedCents, RemainingCents: m.remainingCents}, m.refundErr
+}
+
 type mockCartRepo struct {
 	findByUserCart *model.Cart
 	findByUserErr  error
 	clearErr       error
+	clearCalls     int
 }
 
 func (m *mockCartRepo) Create(cart *model.Cart) error {
@@ -88,9 +195,26 @@ func (m *mockCartRepo) DeleteCartItem(itemID uint) error {
 }
 
 func (m *mockCartRepo) ClearCartItems(cartID uint) error {
+	m.clearCalls++
 	return m.clearErr
 }
 
+func (m *mockCartRepo) MergeGuestCart(guestCartID uint, items []model.CartItem) error {
+	return nil
+}
+
+func (m *mockCartRepo) ReconcileItems(updated []model.CartItem, removedIDs []uint) error {
+	return nil
+}
+
+func (m *mockCartRepo) SetPromotionCode(cartID uint, code string) error {
+	return nil
+}
+
+func (m *mockCartRepo) DeleteStaleGuestCarts(cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
 type mockProductRepo struct {
 	findByIDProduct model.Product
 	findByIDErr     error
@@ -124,6 +248,18 @@ func (m *mockProductRepo) SearchProductsByGender(ctx context.Context, query stri
 	return nil, 0, nil
 }
 
+func (m *mockProductRepo) FilterProducts(ctx context.Context, query string, filter dto.ProductFilter, limit int, offset int, sort string) ([]model.Product, int, error) {
+	return nil, 0, nil
+}
+
+func (m *mockProductRepo) FacetCounts(ctx context.Context, query string, filter dto.ProductFilter) (dto.ProductFacets, error) {
+	return dto.ProductFacets{}, nil
+}
+
+func (m *mockProductRepo) Suggest(ctx context.Context, term string, limit int) (dto.SuggestResponse, error) {
+	return dto.SuggestResponse{}, nil
+}
+
 func (m *mockProductRepo) Update(product *model.Product) error {
 	return nil
 }
@@ -132,7 +268,39 @@ func (m *mockProductRepo) Delete(id uint) error {
 	return nil
 }
 
-func (m *mockProductRepo) DecrementStock(productID uint, quantity int) error {
+func (m *mockProductRepo) DecrementStock(variantID uint, quantity int) error {
+	return nil
+}
+
+func (m *mockProductRepo) FindVariantBySKU(sku string) (*model.ProductVariant, error) {
+	return nil, nil
+}
+
+func (m *mockProductRepo) CreateVariant(variant *model.ProductVariant) error {
+	return nil
+}
+
+func (m *mockProductRepo) UpdateVariant(variant *model.ProductVariant, fields []string) error {
+	return nil
+}
+
+func (m *mockProductRepo) DeleteVariant(variant *model.ProductVariant) error {
+	return nil
+}
+
+func (m *mockProductRepo) CreateImage(image *model.ProductImage) error {
+	return nil
+}
+
+func (m *mockProductRepo) UpdateImage(image *model.ProductImage) error {
+	return nil
+}
+
+func (m *mockProductRepo) DeleteImage(image *model.ProductImage) error {
+	return nil
+}
+
+func (m *mockProductRepo) ReorderImages(productID uint, imageIDs []uint) error {
 	return nil
 }
 
@@ -159,13 +327,93 @@ func (m *mockProductRepo) FindSimilarProductsByEmbeddingAndGender(ctx context.Co
 type mockShippingSvc struct {
 	calculateOptions []dto.ShippingOption
 	calculateErr     error
+	lastCEP          string
 }
 
 func (m *mockShippingSvc) CalculateOptions(ctx context.Context, userID string, cep string) ([]dto.ShippingOption, error) {
+	m.lastCEP = cep
 	return m.calculateOptions, m.calculateErr
 }
 
+type mockAddressRepo struct {
+	addresses []model.Address
+}
+
+func (m *mockAddressRepo) Create(address *model.Address) error { return nil }
+func (m *mockAddressRepo) Update(address *model.Address) error { return nil }
+func (m *mockAddressRepo) Delete(address *model.Address) error { return nil }
+func (m *mockAddressRepo) FindByPublicID(userID string, publicID string) (*model.Address, error) {
+	for i := range m.addresses {
+		if m.addresses[i].UserID == userID && m.addresses[i].PublicID == publicID {
+			return &m.addresses[i], nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockAddressRepo) ListByUser(userID string) ([]model.Address, error) { return m.addresses, nil }
+func (m *mockAddressRepo) CountByUser(userID string) (int64, error) {
+	return int64(len(m.addresses)), nil
+}
+
+type mockAuditLogService struct {
+	orderActions []model.AuditAction
+}
+
+func (m *mockAuditLogService) LogUserAction(actorID *uint, userID *uint, action model.AuditAction, details map[string]interface{}) error {
+	return nil
+}
+func (m *mockAuditLogService) LogUserUpdate(actorID uint, userID uint, oldUser, newUser *model.User) error {
+	return nil
+}
+func (m *mockAuditLogService) LogAdminAction(adminID uint, userID uint, action model.AuditAction, details map[string]interface{}) error {
+	return nil
+}
+func (m *mockAuditLogService) LogSystemAction(action model.AuditAction, resource, resourceID string, details map[string]interface{}) error {
+	return nil
+}
+func (m *mockAuditLogService) LogSystemAlert(action model.AuditAction, resource, resourceID, severity string, details map[string]interface{}) error {
+	return nil
+}
+func (m *mockAuditLogService) LogOrderAction(actorID *uint, orderPublicID string, action model.AuditAction, details map[string]interface{}, oldValues, newValues map[string]interface{}) error {
+	m.orderActions = append(m.orderActions, action)
+	return nil
+}
+func (m *mockAuditLogService) LogDataAccess(userID uint, resource string, resourceID string) error {
+	return nil
+}
+func (m *mockAuditLogService) LogDeletionAction(actorID *uint, userID uint, action model.AuditAction, details map[string]interface{}) error {
+	return nil
+}
+func (m *mockAuditLogService) ListAuditLogs(filter *model.AuditLogFilter) ([]*model.AuditLog, int64, error) {
+	return nil, 0, nil
+}
+func (m *mockAuditLogService) GetAuditLogByID(id uint) (*model.AuditLog, error) { return nil, nil }
+func (m *mockAuditLogService) GetUserAuditLogs(userID uint, limit, offset int) ([]*model.AuditLog, int64, error) {
+	return nil, 0, nil
+}
+func (m *mockAuditLogService) GetResourceAuditLogs(resource, resourceID string) ([]*model.AuditLog, error) {
+	return nil, nil
+}
+func (m *mockAuditLogService) GetAuditSummary(startDate, endDate *time.Time) (*model.AuditLogSummary, error) {
+	return nil, nil
+}
+func (m *mockAuditLogService) CleanupOldLogs(retentionDays int) error { return nil }
+func (m *mockAuditLogService) ConvertAuditLogToResponse(auditLog *model.AuditLog) dto.AuditLogResponse {
+	return dto.AuditLogResponse{}
+}
+func (m *mockAuditLogService) ConvertAuditLogToResponseDetailed(auditLog *model.AuditLog) dto.AuditLogResponse {
+	return dto.AuditLogResponse{}
+}
+func (m *mockAuditLogService) ConvertAuditLogsToResponse(auditLogs []*model.AuditLog) []dto.AuditLogResponse {
+	return nil
+}
+func (m *mockAuditLogService) ConvertAuditLogSummaryToResponse(summary *model.AuditLogSummary) dto.AuditLogSummaryResponse {
+	return dto.AuditLogSummaryResponse{}
+}
+
 // --- Test helpers ---
+func uintPtr(v uint) *uint { return &v }
+
 func createTestCart() *model.Cart {
 	return &model.Cart{
 		ID:     1,
@@ -174,7 +422,8 @@ func createTestCart() *model.Cart {
 			{
 				ID:        1,
 				CartID:    1,
-				ProductID: 1,
+				ProductID: uintPtr(1),
+				VariantID: uintPtr(1),
 				Quantity:  2,
 			},
 		},
@@ -186,9 +435,12 @@ func createTestProduct() model.Product {
 		ID:            1,
 		Name:          "Test Product",
 		Slug:          "test-product",
-		Price:         10.0,
+		Price:         money.FromCents(1000),
 		StockQuantity: 10,
 		ImageURL:      "http://example.com/image.jpg",
+		Variants: []model.ProductVariant{
+			{ID: 1, ProductID: 1, SKU: "TEST-EDP-100", SizeML: 100, Concentration: model.ConcentrationEDP, Price: money.FromCents(1000), StockQuantity: 10},
+		},
 	}
 }
 
@@ -198,11 +450,11 @@ func createTestOrder() model.Order {
 		ID:                        1,
 		PublicID:                  "order123",
 		UserID:                    "user123",
-		TotalAmount:               20.0,
+		TotalAmount:               money.FromCents(2000),
 		Status:                    model.OrderStatusPending,
 		ShippingAddress:           "Test Address",
 		PaymentMethod:             model.PaymentMethodCreditCard,
-		ShippingPrice:             5.0,
+		ShippingPrice:             money.FromCents(500),
 		ShippingCarrier:           "Test Carrier",
 		ShippingServiceCode:       "standard",
 		ShippingEstimatedDelivery: &now,
@@ -213,8 +465,8 @@ func createTestOrder() model.Order {
 				ProductName:     "Test Product",
 				ProductImageURL: "http://example.com/image.jpg",
 				Quantity:        2,
-				PriceAtPurchase: 10.0,
-				Subtotal:        20.0,
+				PriceAtPurchase: money.FromCents(1000),
+				Subtotal:        money.FromCents(2000),
 			},
 		},
 		CreatedAt: now,
@@ -222,6 +474,32 @@ func createTestOrder() model.Order {
 	}
 }
 
+type mockPromotionService struct {
+	discount *promotionservice.Discount
+	err      error
+	customer promotionservice.Customer
+	lines    []promotionservice.Line
+	shipping money.Amount
+}
+
+func (m *mockPromotionService) Evaluate(code string, customer promotionservice.Customer, lines []promotionservice.Line, shipping money.Amount) (*promotionservice.Discount, error) {
+	m.customer, m.lines, m.shipping = customer, lines, shipping
+	return m.discount, m.err
+}
+func (m *mockPromotionService) CreatePromotion(req *dto.PromotionRequest, adminPublicID string) (*dto.PromotionResponse, error) {
+	return nil, nil
+}
+func (m *mockPromotionService) UpdatePromotion(id uint, req *dto.PromotionRequest, adminPublicID string) (*dto.PromotionResponse, error) {
+	return nil, nil
+}
+func (m *mockPromotionService) DeletePromotion(id uint, adminPublicID string) error { return nil }
+func (m *mockPromotionService) GetPromotion(id uint) (*dto.PromotionResponse, error) {
+	return nil, nil
+}
+func (m *mockPromotionService) ListPromotions(page int, perPage int) (*dto.PromotionListResponse, error) {
+	return nil, nil
+}
+
 // --- Tests ---
 func TestCreateOrderFromCart(t *testing.T) {
 	cart := createTestCart()
@@ -230,17 +508,26 @@ func TestCreateOrderFromCart(t *testing.T) {
 		{
 			Carrier:       "Test Carrier",
 			ServiceCode:   "standard",
-			Price:         5.0,
+			Price:         money.FromCents(500),
 			EstimatedDays: 3,
 		},
 	}
 
 	t.Run("success", func(t *testing.T) {
+		repo := &mockOrderRepo{}
 		svc := NewOrderService(
-			&mockOrderRepo{},
+			repo,
+			&mockOrderEventRepo{},
 			&mockCartRepo{findByUserCart: cart},
 			&mockProductRepo{findByIDProduct: product},
+			nil,
+			nil,
 			&mockShippingSvc{calculateOptions: shippingOptions},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
 		)
 
 		req := &dto.CreateOrderFromCartRequest{
@@ -255,16 +542,32 @@ func TestCreateOrderFromCart(t *testing.T) {
 		resp, err := svc.CreateOrderFromCart("user123", req)
 		assert.NoError(t, err)
 		assert.NotNil(t, resp)
-		assert.Equal(t, 25.0, resp.TotalAmount) // 20 + 5 shipping
+		assert.Equal(t, money.FromCents(2500), resp.TotalAmount) // 20 + 5 shipping
 		assert.Equal(t, "Test Carrier", resp.ShippingCarrier)
+		if assert.Len(t, repo.created.Items, 1) {
+			item := repo.created.Items[0]
+			assert.Equal(t, uint(1), *item.VariantID)
+			assert.Equal(t, "TEST-EDP-100", item.VariantSKU)
+			assert.Equal(t, 100, item.VariantSizeML)
+			assert.Equal(t, model.ConcentrationEDP, item.VariantConcentration)
+		}
+		assert.Equal(t, "TEST-EDP-100", resp.Items[0].VariantSKU)
 	})
 
 	t.Run("empty cart", func(t *testing.T) {
 		svc := NewOrderService(
 			&mockOrderRepo{},
+			&mockOrderEventRepo{},
 			&mockCartRepo{findByUserErr: errors.New("not found")},
 			&mockProductRepo{},
+			nil,
+			nil,
 			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
 		)
 
 		req := &dto.CreateOrderFromCartRequest{
@@ -279,13 +582,21 @@ func TestCreateOrderFromCart(t *testing.T) {
 
 	t.Run("insufficient stock", func(t *testing.T) {
 		productLowStock := createTestProduct()
-		productLowStock.StockQuantity = 1 // Less than cart quantity of 2
+		productLowStock.Variants[0].StockQuantity = 1 // Less than cart quantity of 2
 
 		svc := NewOrderService(
 			&mockOrderRepo{},
+			&mockOrderEventRepo{},
 			&mockCartRepo{findByUserCart: cart},
 			&mockProductRepo{findByIDProduct: productLowStock},
+			nil,
+			nil,
 			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
 		)
 
 		req := &dto.CreateOrderFromCartRequest{
@@ -301,9 +612,17 @@ func TestCreateOrderFromCart(t *testing.T) {
 	t.Run("invalid shipping selection", func(t *testing.T) {
 		svc := NewOrderService(
 			&mockOrderRepo{},
+			&mockOrderEventRepo{},
 			&mockCartRepo{findByUserCart: cart},
 			&mockProductRepo{findByIDProduct: product},
+			nil,
+			nil,
 			&mockShippingSvc{calculateOptions: shippingOptions},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
 		)
 
 		req := &dto.CreateOrderFromCartRequest{
@@ -323,9 +642,17 @@ func TestCreateOrderFromCart(t *testing.T) {
 	t.Run("create order error", func(t *testing.T) {
 		svc := NewOrderService(
 			&mockOrderRepo{createErr: errors.New("db error")},
+			&mockOrderEventRepo{},
 			&mockCartRepo{findByUserCart: cart},
 			&mockProductRepo{findByIDProduct: product},
+			nil,
+			nil,
 			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
 		)
 
 		req := &dto.CreateOrderFromCartRequest{
@@ -338,12 +665,20 @@ func TestCreateOrderFromCart(t *testing.T) {
 		assert.Nil(t, resp)
 	})
 
-	t.Run("clear cart error", func(t *testing.T) {
+	t.Run("reservation fails on insufficient stock", func(t *testing.T) {
 		svc := NewOrderService(
-			&mockOrderRepo{},
-			&mockCartRepo{findByUserCart: cart, clearErr: errors.New("clear error")},
+			&mockOrderRepo{createErr: fmt.Errorf("%w: product 1", repository.ErrInsufficientStock)},
+			&mockOrderEventRepo{},
+			&mockCartRepo{findByUserCart: cart},
 			&mockProductRepo{findByIDProduct: product},
+			nil,
+			nil,
 			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
 		)
 
 		req := &dto.CreateOrderFromCartRequest{
@@ -352,8 +687,204 @@ func TestCreateOrderFromCart(t *testing.T) {
 		}
 
 		resp, err := svc.CreateOrderFromCart("user123", req)
-		assert.Error(t, err)
 		assert.Nil(t, resp)
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "insufficient_stock", de.Code)
+	})
+
+	t.Run("cart is cleared inside the reservation transaction", func(t *testing.T) {
+		orderRepo := &mockOrderRepo{}
+		cartRepo := &mockCartRepo{findByUserCart: cart}
+		svc := NewOrderService(
+			orderRepo,
+			&mockOrderEventRepo{},
+			cartRepo,
+			&mockProductRepo{findByIDProduct: product},
+			nil,
+			nil,
+			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
+		)
+
+		req := &dto.CreateOrderFromCartRequest{
+			ShippingAddress: "12345-000",
+			PaymentMethod:   string(model.PaymentMethodCreditCard),
+		}
+
+		resp, err := svc.CreateOrderFromCart("user123", req)
+		assert.NoError(t, err)
+		assert.NotNil(t, resp)
+		assert.Equal(t, cart.ID, orderRepo.reservedCartID)
+		assert.Equal(t, 0, cartRepo.clearCalls)
+	})
+
+	t.Run("applies the cart coupon to the order", func(t *testing.T) {
+		couponCart := createTestCart()
+		couponCart.PromotionCode = "FRETE10"
+		orderRepo := &mockOrderRepo{}
+		promotions := &mockPromotionService{discount: &promotionservice.Discount{
+			Promotion: &model.Promotion{ID: 7, Code: "FRETE10"},
+			Items:     money.FromCents(200),
+			Shipping:  money.FromCents(500),
+		}}
+		svc := NewOrderService(orderRepo, &mockOrderEventRepo{}, &mockCartRepo{findByUserCart: couponCart}, &mockProductRepo{findByIDProduct: product}, nil, nil, &mockShippingSvc{calculateOptions: shippingOptions}, nil, nil, nil, promotions, Config{})
+
+		resp, err := svc.CreateOrderFromCart("user123", &dto.CreateOrderFromCartRequest{
+			ShippingAddress:   "12345-000",
+			PaymentMethod:     string(model.PaymentMethodCreditCard),
+			ShippingSelection: &dto.ShippingSelection{Carrier: "Test Carrier", ServiceCode: "standard"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, money.FromCents(1800), resp.TotalAmount) // 20 + 5 shipping - 2 - 5
+		assert.Equal(t, money.FromCents(700), resp.DiscountAmount)
+		assert.Equal(t, "FRETE10", resp.PromotionCode)
+		assert.Equal(t, uint(7), *orderRepo.created.PromotionID)
+		assert.Equal(t, "user123", promotions.customer.UserID)
+		assert.Equal(t, money.FromCents(500), promotions.shipping)
+		assert.Equal(t, []promotionservice.Line{{ProductID: 1, UnitPrice: money.FromCents(1000), Quantity: 2}}, promotions.lines)
+	})
+
+	t.Run("invalid coupon blocks checkout", func(t *testing.T) {
+		couponCart := createTestCart()
+		couponCart.PromotionCode = "EXPIRED"
+		orderRepo := &mockOrderRepo{}
+		promotions := &mockPromotionService{err: apperror.NewCodeMessage("promotion_not_active", "promotion is not active")}
+		svc := NewOrderService(orderRepo, &mockOrderEventRepo{}, &mockCartRepo{findByUserCart: couponCart}, &mockProductRepo{findByIDProduct: product}, nil, nil, &mockShippingSvc{}, nil, nil, nil, promotions, Config{})
+
+		_, err := svc.CreateOrderFromCart("user123", &dto.CreateOrderFromCartRequest{ShippingAddress: "12345-000", PaymentMethod: string(model.PaymentMethodCreditCard)})
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "promotion_not_active", de.Code)
+		assert.Nil(t, orderRepo.created)
+	})
+
+	t.Run("coupon used up while checking out", func(t *testing.T) {
+		couponCart := createTestCart()
+		couponCart.PromotionCode = "LAST"
+		promotions := &mockPromotionService{discount: &promotionservice.Discount{Promotion: &model.Promotion{ID: 3, Code: "LAST"}, Items: money.FromCents(100)}}
+		svc := NewOrderService(&mockOrderRepo{createErr: repository.ErrPromotionUsageLimit}, &mockOrderEventRepo{}, &mockCartRepo{findByUserCart: couponCart}, &mockProductRepo{findByIDProduct: product}, nil, nil, &mockShippingSvc{}, nil, nil, nil, promotions, Config{})
+
+		_, err := svc.CreateOrderFromCart("user123", &dto.CreateOrderFromCartRequest{ShippingAddress: "12345-000", PaymentMethod: string(model.PaymentMethodCreditCard)})
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "promotion_usage_limit_reached", de.Code)
+	})
+
+	t.Run("reservation expires after configured ttl", func(t *testing.T) {
+		svc := NewOrderService(
+			&mockOrderRepo{},
+			&mockOrderEventRepo{},
+			&mockCartRepo{findByUserCart: cart},
+			&mockProductRepo{findByIDProduct: product},
+			nil,
+			nil,
+			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{ReservationTTL: 10 * time.Minute},
+		)
+
+		req := &dto.CreateOrderFromCartRequest{
+			ShippingAddress: "12345-000",
+			PaymentMethod:   string(model.PaymentMethodCreditCard),
+		}
+
+		before := time.Now()
+		resp, err := svc.CreateOrderFromCart("user123", req)
+		assert.NoError(t, err)
+		if assert.NotNil(t, resp.ReservationExpiresAt) {
+			assert.WithinDuration(t, before.Add(10*time.Minute), *resp.ReservationExpiresAt, 5*time.Second)
+		}
+	})
+
+	t.Run("saved address is snapshotted onto the order", func(t *testing.T) {
+		addresses := &mockAddressRepo{addresses: []model.Address{{
+			PublicID: "addr-1",
+			UserID:   "user123",
+			PostalAddress: model.PostalAddress{
+				Recipient:    "Maria Silva",
+				CEP:          "01310100",
+				Street:       "Avenida Paulista",
+				Number:       "1578",
+				Complement:   "Apto 42",
+				Neighborhood: "Bela Vista",
+				City:         "São Paulo",
+				UF:           "SP",
+			},
+		}}}
+		shippingSvc := &mockShippingSvc{calculateOptions: shippingOptions}
+		svc := NewOrderService(
+			&mockOrderRepo{},
+			&mockOrderEventRepo{},
+			&mockCartRepo{findByUserCart: cart},
+			&mockProductRepo{findByIDProduct: product},
+			nil,
+			addresses,
+			shippingSvc,
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
+		)
+
+		req := &dto.CreateOrderFromCartRequest{
+			AddressID:     "addr-1",
+			PaymentMethod: string(model.PaymentMethodPix),
+			ShippingSelection: &dto.ShippingSelection{
+				Carrier:     "Test Carrier",
+				ServiceCode: "standard",
+			},
+		}
+
+		resp, err := svc.CreateOrderFromCart("user123", req)
+		assert.NoError(t, err)
+		assert.Equal(t, "01310100", shippingSvc.lastCEP)
+		assert.Equal(t, "Avenida Paulista, 1578, Apto 42 - Bela Vista, São Paulo - SP, 01310-100", resp.ShippingAddress)
+		if assert.NotNil(t, resp.ShippingDetails) {
+			assert.Equal(t, "Maria Silva", resp.ShippingDetails.Recipient)
+			assert.Equal(t, "01310-100", resp.ShippingDetails.CEP)
+			assert.Equal(t, "SP", resp.ShippingDetails.UF)
+		}
+
+		// Editing the address book afterwards does not touch the order
+		addresses.addresses[0].Street = "Rua Augusta"
+		assert.Equal(t, "Avenida Paulista", resp.ShippingDetails.Street)
+	})
+
+	t.Run("address of another user", func(t *testing.T) {
+		svc := NewOrderService(
+			&mockOrderRepo{},
+			&mockOrderEventRepo{},
+			&mockCartRepo{findByUserCart: cart},
+			&mockProductRepo{findByIDProduct: product},
+			nil,
+			&mockAddressRepo{addresses: []model.Address{{PublicID: "addr-1", UserID: "someone-else"}}},
+			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
+		)
+
+		req := &dto.CreateOrderFromCartRequest{
+			AddressID:     "addr-1",
+			PaymentMethod: string(model.PaymentMethodPix),
+		}
+
+		_, err := svc.CreateOrderFromCart("user123", req)
+		var de *apperror.DomainError
+		if assert.ErrorAs(t, err, &de) {
+			assert.Equal(t, "address_not_found", de.Code)
+		}
 	})
 }
 
@@ -362,10 +893,18 @@ func TestAdminListOrders(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		svc := NewOrderService(
-			&mockOrderRepo{listOrders: orders, listCount: 1, listRevenue: 20.0},
+			&mockOrderRepo{listOrders: orders, listCount: 1, listRevenue: money.FromCents(2000)},
+			&mockOrderEventRepo{},
 			&mockCartRepo{},
 			&mockProductRepo{},
+			nil,
+			nil,
 			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
 		)
 
 		resp, err := svc.AdminListOrders(nil, nil, nil, 1, 10)
@@ -373,16 +912,24 @@ func TestAdminListOrders(t *testing.T) {
 		assert.NotNil(t, resp)
 		assert.Len(t, resp.Orders, 1)
 		assert.Equal(t, 1, resp.Meta.Pagination.TotalCount)
-		assert.Equal(t, 20.0, resp.Meta.Stats.TotalRevenue)
-		assert.Equal(t, 20.0, resp.Meta.Stats.AverageOrderValue)
+		assert.Equal(t, money.FromCents(2000), resp.Meta.Stats.TotalRevenue)
+		assert.Equal(t, money.FromCents(2000), resp.Meta.Stats.AverageOrderValue)
 	})
 
 	t.Run("error", func(t *testing.T) {
 		svc := NewOrderService(
 			&mockOrderRepo{listErr: errors.New("db error")},
+			&mockOrderEventRepo{},
 			&mockCartRepo{},
 			&mockProductRepo{},
+			nil,
+			nil,
 			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
 		)
 
 		resp, err := svc.AdminListOrders(nil, nil, nil, 1, 10)
@@ -397,9 +944,17 @@ func TestGetOrdersByUser(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		svc := NewOrderService(
 			&mockOrderRepo{findByUserOrders: orders},
+			&mockOrderEventRepo{},
 			&mockCartRepo{},
 			&mockProductRepo{},
+			nil,
+			nil,
 			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
 		)
 
 		resp, err := svc.GetOrdersByUser("user123")
@@ -411,9 +966,17 @@ func TestGetOrdersByUser(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		svc := NewOrderService(
 			&mockOrderRepo{findByUserErr: errors.New("db error")},
+			&mockOrderEventRepo{},
 			&mockCartRepo{},
 			&mockProductRepo{},
+			nil,
+			nil,
 			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
 		)
 
 		resp, err := svc.GetOrdersByUser("user123")
@@ -424,9 +987,17 @@ func TestGetOrdersByUser(t *testing.T) {
 	t.Run("empty result", func(t *testing.T) {
 		svc := NewOrderService(
 			&mockOrderRepo{findByUserOrders: []model.Order{}},
+			&mockOrderEventRepo{},
 			&mockCartRepo{},
 			&mockProductRepo{},
+			nil,
+			nil,
 			&mockShippingSvc{},
+			nil,
+			nil,
+			nil,
+			nil,
+			Config{},
 		)
 
 		resp, err := svc.GetOrdersByUser("user123")
@@ -435,3 +1006,607 @@ func TestGetOrdersByUser(t *testing.T) {
 		assert.Len(t, resp, 0)
 	})
 }
+
+func TestUpdateOrderStatus(t *testing.T) {
+	events := &mockOrderEventRepo{}
+	newSvc := func(repo *mockOrderRepo, audit *mockAuditLogService) OrderService {
+		return NewOrderService(repo, events, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, audit, nil, nil, nil, Config{})
+	}
+	withPayments := func(repo *mockOrderRepo, payments *mockOrderPayments, audit *mockAuditLogService) OrderService {
+		return NewOrderService(repo, &mockOrderEventRepo{}, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, audit, payments, nil, nil, Config{})
+	}
+	orderWithStatus := func(status model.OrderStatus) *model.Order {
+		o := createTestOrder()
+		o.Status = status
+		return &o
+	}
+
+	t.Run("processing to shipped with tracking", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusProcessing)}
+		audit := &mockAuditLogService{}
+		svc := newSvc(repo, audit)
+
+		resp, err := svc.UpdateOrderStatus(context.Background(), "order123", &dto.UpdateOrderStatusRequest{Status: "shipped", TrackingNumber: " BR123 "}, "admin-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "shipped", resp.Status)
+		assert.Equal(t, "BR123", resp.ShippingTracking)
+		assert.Equal(t, "BR123", repo.lastTracking)
+		assert.Equal(t, []model.AuditAction{model.AuditActionOrderStatusChanged}, audit.orderActions)
+		if assert.Len(t, events.events, 2) {
+			assert.Equal(t, model.OrderEventStatusChanged, events.events[0].Type)
+			assert.Equal(t, "processing", events.events[0].FromStatus)
+			assert.Equal(t, "shipped", events.events[0].ToStatus)
+			assert.Equal(t, model.OrderEventActorAdmin, events.events[0].ActorType)
+			assert.Equal(t, model.OrderEventShippingUpdated, events.events[1].Type)
+		}
+	})
+
+	t.Run("shipped requires tracking number", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusProcessing)}
+		svc := newSvc(repo, &mockAuditLogService{})
+
+		resp, err := svc.UpdateOrderStatus(context.Background(), "order123", &dto.UpdateOrderStatusRequest{Status: "shipped"}, "admin-1")
+		assert.Nil(t, resp)
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "tracking_number_required", de.Code)
+		assert.Equal(t, 0, repo.fulfillmentCalls)
+	})
+
+	t.Run("illegal transition rejected", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusPending)}
+		audit := &mockAuditLogService{}
+		svc := newSvc(repo, audit)
+
+		resp, err := svc.UpdateOrderStatus(context.Background(), "order123", &dto.UpdateOrderStatusRequest{Status: "delivered"}, "admin-1")
+		assert.Nil(t, resp)
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "invalid_status_transition", de.Code)
+		assert.Empty(t, audit.orderActions)
+	})
+
+	t.Run("terminal status cannot change", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusCancelled)}
+		svc := newSvc(repo, &mockAuditLogService{})
+
+		_, err := svc.UpdateOrderStatus(context.Background(), "order123", &dto.UpdateOrderStatusRequest{Status: "processing"}, "admin-1")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "invalid_status_transition", de.Code)
+	})
+
+	t.Run("cancellation releases reserved stock", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusProcessing)}
+		svc := newSvc(repo, &mockAuditLogService{})
+
+		resp, err := svc.UpdateOrderStatus(context.Background(), "order123", &dto.UpdateOrderStatusRequest{Status: "cancelled"}, "admin-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "cancelled", resp.Status)
+		assert.Equal(t, 1, repo.cancelCalls)
+		assert.Equal(t, 0, repo.fulfillmentCalls)
+	})
+
+	t.Run("cancelling a pending order withdraws its intents", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusPending)}
+		payments := &mockOrderPayments{}
+		svc := withPayments(repo, payments, &mockAuditLogService{})
+
+		_, err := svc.UpdateOrderStatus(context.Background(), "order123", &dto.UpdateOrderStatusRequest{Status: "cancelled"}, "admin-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, payments.cancelCalls)
+		assert.Equal(t, "admin_cancelled", payments.cancelReason)
+		assert.Equal(t, 1, repo.cancelCalls)
+	})
+
+	t.Run("intent in flight blocks the cancellation", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusPending)}
+		payments := &mockOrderPayments{cancelIntentsErr: apperror.NewCodeMessage("payment_in_progress", "payment is already being processed")}
+		svc := withPayments(repo, payments, &mockAuditLogService{})
+
+		_, err := svc.UpdateOrderStatus(context.Background(), "order123", &dto.UpdateOrderStatusRequest{Status: "cancelled"}, "admin-1")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "payment_in_progress", de.Code)
+		assert.Equal(t, 0, repo.cancelCalls)
+	})
+
+	t.Run("cancelling a paid order refunds it", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusProcessing)}
+		payments := &mockOrderPayments{refundedCents: 2500}
+		audit := &mockAuditLogService{}
+		svc := withPayments(repo, payments, audit)
+
+		_, err := svc.UpdateOrderStatus(context.Background(), "order123", &dto.UpdateOrderStatusRequest{Status: "cancelled", Reason: "out of stock"}, "admin-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, payments.cancelCalls)
+		assert.Equal(t, 1, payments.refundCalls)
+		assert.Equal(t, model.OrderEventActorAdmin, payments.lastRefund.ActorType)
+		assert.Equal(t, "admin-1", payments.lastRefund.ActorID)
+		assert.Equal(t, []model.AuditAction{model.AuditActionOrderStatusChanged, model.AuditActionOrderRefunded}, audit.orderActions)
+	})
+
+	t.Run("refund failure keeps the cancellation", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusProcessing)}
+		payments := &mockOrderPayments{refundErr: errors.New("gateway down")}
+		svc := withPayments(repo, payments, &mockAuditLogService{})
+
+		resp, err := svc.UpdateOrderStatus(context.Background(), "order123", &dto.UpdateOrderStatusRequest{Status: "cancelled"}, "admin-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "cancelled", resp.Status)
+		assert.Equal(t, 1, repo.cancelCalls)
+	})
+
+	t.Run("order not found", func(t *testing.T) {
+		svc := newSvc(&mockOrderRepo{}, &mockAuditLogService{})
+
+		_, err := svc.UpdateOrderStatus(context.Background(), "missing", &dto.UpdateOrderStatusRequest{Status: "processing"}, "admin-1")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "order_not_found", de.Code)
+	})
+
+	t.Run("concurrent change", func(t *testing.T) {
+		repo := &mockOrderRepo{
+			findByPublicID: orderWithStatus(model.OrderStatusPending),
+			fulfillmentErr: repository.ErrOrderStatusConflict,
+		}
+		svc := newSvc(repo, &mockAuditLogService{})
+
+		_, err := svc.UpdateOrderStatus(context.Background(), "order123", &dto.UpdateOrderStatusRequest{Status: "processing"}, "admin-1")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "order_status_conflict", de.Code)
+	})
+}
+
+func TestCanTransition(t *testing.T) {
+	assert.True(t, CanTransition(model.OrderStatusPending, model.OrderStatusProcessing))
+	assert.True(t, CanTransition(model.OrderStatusProcessing, model.OrderStatusShipped))
+	assert.True(t, CanTransition(model.OrderStatusShipped, model.OrderStatusDelivered))
+	assert.True(t, CanTransition(model.OrderStatusPending, model.OrderStatusCancelled))
+	assert.True(t, CanTransition(model.OrderStatusProcessing, model.OrderStatusCancelled))
+	assert.False(t, CanTransition(model.OrderStatusShipped, model.OrderStatusCancelled))
+	assert.False(t, CanTransition(model.OrderStatusDelivered, model.OrderStatusPending))
+	assert.False(t, CanTransition(model.OrderStatusPending, model.OrderStatusPending))
+}
+
+func TestGetOrderTimeline(t *testing.T) {
+	adminID := "admin-1"
+	userID := "user123"
+	events := []model.OrderEvent{
+		{OrderID: 1, Type: model.OrderEventCreated, ToStatus: "pending", ActorType: model.OrderEventActorUser, ActorID: &userID},
+		{OrderID: 1, Type: model.OrderEventStatusChanged, FromStatus: "pending", ToStatus: "cancelled", ActorType: model.OrderEventActorAdmin, ActorID: &adminID},
+	}
+	order := createTestOrder()
+
+	newSvc := func(repo *mockOrderRepo) OrderService {
+		return NewOrderService(repo, &mockOrderEventRepo{events: events}, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, Config{})
+	}
+
+	t.Run("owner sees timeline without actor ids", func(t *testing.T) {
+		svc := newSvc(&mockOrderRepo{findByPublicID: &order})
+
+		resp, err := svc.GetOrderByPublicID("user123", "order123")
+		assert.NoError(t, err)
+		assert.Equal(t, "order123", resp.PublicID)
+		if assert.Len(t, resp.Timeline, 2) {
+			assert.Equal(t, "admin", resp.Timeline[1].Actor)
+			assert.Empty(t, resp.Timeline[1].ActorID)
+		}
+	})
+
+	t.Run("other users get not found", func(t *testing.T) {
+		svc := newSvc(&mockOrderRepo{findByPublicID: &order})
+
+		resp, err := svc.GetOrderByPublicID("someone-else", "order123")
+		assert.Nil(t, resp)
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "order_not_found", de.Code)
+	})
+
+	t.Run("admin sees actor ids", func(t *testing.T) {
+		svc := newSvc(&mockOrderRepo{findByPublicID: &order})
+
+		resp, err := svc.AdminGetOrder("order123")
+		assert.NoError(t, err)
+		assert.Equal(t, "user123", resp.UserID)
+		if assert.Len(t, resp.Timeline, 2) {
+			assert.Equal(t, adminID, resp.Timeline[1].ActorID)
+		}
+	})
+
+	t.Run("admin order not found", func(t *testing.T) {
+		svc := newSvc(&mockOrderRepo{})
+
+		_, err := svc.AdminGetOrder("missing")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "order_not_found", de.Code)
+	})
+}
+
+func TestReleaseExpiredReservation(t *testing.T) {
+	expiredOrder := func() *model.Order {
+		o := createTestOrder()
+		o.StockReserved = true
+		expired := time.Now().Add(-time.Minute)
+		o.ReservationExpiresAt = &expired
+		return &o
+	}
+
+	t.Run("cancels order and records system event", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: expiredOrder()}
+		events := &mockOrderEventRepo{}
+		svc := NewOrderService(repo, events, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, Config{})
+
+		err := svc.ReleaseExpiredReservation("order123")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, repo.cancelCalls)
+		if assert.Len(t, events.events, 1) {
+			assert.Equal(t, model.OrderEventActorSystem, events.events[0].ActorType)
+			assert.Equal(t, string(model.OrderStatusCancelled), events.events[0].ToStatus)
+		}
+	})
+
+	t.Run("reservation still active", func(t *testing.T) {
+		o := expiredOrder()
+		future := time.Now().Add(time.Hour)
+		o.ReservationExpiresAt = &future
+		repo := &mockOrderRepo{findByPublicID: o}
+		svc := NewOrderService(repo, &mockOrderEventRepo{}, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, Config{})
+
+		err := svc.ReleaseExpiredReservation("order123")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "reservation_active", de.Code)
+		assert.Equal(t, 0, repo.cancelCalls)
+	})
+
+	t.Run("order already paid", func(t *testing.T) {
+		o := expiredOrder()
+		o.Status = model.OrderStatusProcessing
+		repo := &mockOrderRepo{findByPublicID: o}
+		svc := NewOrderService(repo, &mockOrderEventRepo{}, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, Config{})
+
+		err := svc.ReleaseExpiredReservation("order123")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "order_status_conflict", de.Code)
+		assert.Equal(t, 0, repo.cancelCalls)
+	})
+
+	t.Run("paid concurrently", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: expiredOrder(), cancelErr: repository.ErrOrderStatusConflict}
+		events := &mockOrderEventRepo{}
+		svc := NewOrderService(repo, events, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, Config{})
+
+		err := svc.ReleaseExpiredReservation("order123")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "order_status_conflict", de.Code)
+		assert.Empty(t, events.events)
+	})
+}
+
+func TestCancelUnpaidOrder(t *testing.T) {
+	t.Run("cancels pending order with note", func(t *testing.T) {
+		o := createTestOrder()
+		o.StockReserved = true
+		repo := &mockOrderRepo{findByPublicID: &o}
+		events := &mockOrderEventRepo{}
+		svc := NewOrderService(repo, events, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, Config{})
+
+		err := svc.CancelUnpaidOrder("order123", "boleto overdue")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, repo.cancelCalls)
+		if assert.Len(t, events.events, 1) {
+			assert.Equal(t, "boleto overdue", events.events[0].Note)
+			assert.Equal(t, model.OrderEventActorSystem, events.events[0].ActorType)
+		}
+	})
+
+	t.Run("paid order is left alone", func(t *testing.T) {
+		o := createTestOrder()
+		o.Status = model.OrderStatusProcessing
+		repo := &mockOrderRepo{findByPublicID: &o}
+		svc := NewOrderService(repo, &mockOrderEventRepo{}, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, Config{})
+
+		err := svc.CancelUnpaidOrder("order123", "boleto overdue")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "order_status_conflict", de.Code)
+		assert.Equal(t, 0, repo.cancelCalls)
+	})
+}
+
+func TestCancelOrder(t *testing.T) {
+	orderWithStatus := func(status model.OrderStatus) *model.Order {
+		o := createTestOrder()
+		o.Status = status
+		o.StockReserved = true
+		o.CreatedAt = time.Now().Add(-time.Hour)
+		return &o
+	}
+	newSvc := func(repo *mockOrderRepo, payments *mockOrderPayments, audit *mockAuditLogService) OrderService {
+		return NewOrderService(repo, &mockOrderEventRepo{}, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, audit, payments, nil, nil, Config{})
+	}
+	domainCode := func(t *testing.T, err error) string {
+		var de *apperror.DomainError
+		if assert.ErrorAs(t, err, &de) {
+			return de.Code
+		}
+		return ""
+	}
+
+	t.Run("pending order withdraws intents and restocks", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusPending)}
+		payments := &mockOrderPayments{}
+		audit := &mockAuditLogService{}
+		svc := newSvc(repo, payments, audit)
+
+		resp, err := svc.CancelOrder(context.Background(), "user123", "order123", "changed my mind")
+		assert.NoError(t, err)
+		assert.Equal(t, "cancelled", resp.Status)
+		assert.Equal(t, RefundStatusNone, resp.RefundStatus)
+		assert.Equal(t, 1, payments.cancelCalls)
+		assert.Equal(t, "customer_cancelled", payments.cancelReason)
+		assert.Equal(t, 1, repo.cancelCalls)
+		assert.Equal(t, []model.AuditAction{model.AuditActionOrderCancelledByUser}, audit.orderActions)
+	})
+
+	t.Run("paid order is refunded", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusProcessing)}
+		payments := &mockOrderPayments{refundedCents: 2500}
+		svc := newSvc(repo, payments, &mockAuditLogService{})
+
+		resp, err := svc.CancelOrder(context.Background(), "user123", "order123", "")
+		assert.NoError(t, err)
+		assert.Equal(t, RefundStatusRefunded, resp.RefundStatus)
+		assert.Equal(t, money.FromCents(2500), resp.RefundedAmount)
+		assert.Equal(t, 0, payments.cancelCalls)
+		assert.Equal(t, 1, payments.refundCalls)
+	})
+
+	t.Run("refund failure keeps the cancellation", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusProcessing)}
+		payments := &mockOrderPayments{refundErr: errors.New("gateway down")}
+		svc := newSvc(repo, payments, &mockAuditLogService{})
+
+		resp, err := svc.CancelOrder(context.Background(), "user123", "order123", "")
+		assert.NoError(t, err)
+		assert.Equal(t, "cancelled", resp.Status)
+		assert.Equal(t, RefundStatusFailed, resp.RefundStatus)
+	})
+
+	t.Run("shipped order cannot be cancelled", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusShipped)}
+		svc := newSvc(repo, &mockOrderPayments{}, &mockAuditLogService{})
+
+		_, err := svc.CancelOrder(context.Background(), "user123", "order123", "")
+		assert.Equal(t, "order_not_cancellable", domainCode(t, err))
+		assert.Equal(t, 0, repo.cancelCalls)
+	})
+
+	t.Run("window expired", func(t *testing.T) {
+		o := orderWithStatus(model.OrderStatusProcessing)
+		o.CreatedAt = time.Now().Add(-48 * time.Hour)
+		repo := &mockOrderRepo{findByPublicID: o}
+		svc := newSvc(repo, &mockOrderPayments{}, &mockAuditLogService{})
+
+		_, err := svc.CancelOrder(context.Background(), "user123", "order123", "")
+		assert.Equal(t, "cancellation_window_expired", domainCode(t, err))
+	})
+
+	t.Run("configured rules apply", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusProcessing)}
+		cfg := Config{CustomerCancelWindows: ParseCancelRules("pending=0")}
+		svc := NewOrderService(repo, &mockOrderEventRepo{}, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, cfg)
+
+		_, err := svc.CancelOrder(context.Background(), "user123", "order123", "")
+		assert.Equal(t, "order_not_cancellable", domainCode(t, err))
+	})
+
+	t.Run("payment already in flight", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusPending)}
+		payments := &mockOrderPayments{cancelIntentsErr: apperror.NewCodeMessage("payment_in_progress", "payment is already being processed")}
+		svc := newSvc(repo, payments, &mockAuditLogService{})
+
+		_, err := svc.CancelOrder(context.Background(), "user123", "order123", "")
+		assert.Equal(t, "payment_in_progress", domainCode(t, err))
+		assert.Equal(t, 0, repo.cancelCalls)
+	})
+
+	t.Run("other users get not found", func(t *testing.T) {
+		repo := &mockOrderRepo{findByPublicID: orderWithStatus(model.OrderStatusPending)}
+		svc := newSvc(repo, &mockOrderPayments{}, &mockAuditLogService{})
+
+		_, err := svc.CancelOrder(context.Background(), "someone-else", "order123", "")
+		assert.Equal(t, "order_not_found", domainCode(t, err))
+	})
+}
+
+func TestParseCancelRules(t *testing.T) {
+	rules := ParseCancelRules("pending=0, processing=2h, shipped=1h, bogus")
+	assert.Equal(t, map[model.OrderStatus]time.Duration{
+		model.OrderStatusPending:    0,
+		model.OrderStatusProcessing: 2 * time.Hour,
+	}, rules)
+
+	assert.Empty(t, ParseCancelRules("none"))
+}
+
+func TestAdminRefundOrder(t *testing.T) {
+	order := createTestOrder()
+	order.Status = model.OrderStatusDelivered
+	newSvc := func(repo *mockOrderRepo, payments *mockOrderPayments, events *mockOrderEventRepo, audit *mockAuditLogService) OrderService {
+		return NewOrderService(repo, events, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, audit, payments, nil, nil, Config{})
+	}
+
+	t.Run("partial refund is recorded with reason and admin", func(t *testing.T) {
+		payments := &mockOrderPayments{refundedCents: 1000, remainingCents: 1500}
+		events := &mockOrderEventRepo{}
+		audit := &mockAuditLogService{}
+		svc := newSvc(&mockOrderRepo{findByPublicID: &order}, payments, events, audit)
+
+		resp, err := svc.AdminRefundOrder(context.Background(), "order123", &dto.AdminRefundRequest{Amount: money.FromCents(1000), Reason: "damaged"}, "admin-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "partially_refunded", resp.Status)
+		assert.Equal(t, money.FromCents(1000), resp.RefundedAmount)
+		assert.Equal(t, money.FromCents(1500), resp.RemainingAmount)
+		assert.Equal(t, int64(1000), payments.lastRefund.AmountCents)
+		assert.Equal(t, model.OrderEventActorAdmin, payments.lastRefund.ActorType)
+		assert.Equal(t, "admin-1", payments.lastRefund.ActorID)
+		if assert.Len(t, events.events, 1) {
+			assert.Equal(t, "damaged", events.events[0].Note)
+		}
+		assert.Equal(t, []model.AuditAction{model.AuditActionOrderRefunded}, audit.orderActions)
+	})
+
+	t.Run("omitted amount refunds everything", func(t *testing.T) {
+		payments := &mockOrderPayments{refundedCents: 2500}
+		svc := newSvc(&mockOrderRepo{findByPublicID: &order}, payments, &mockOrderEventRepo{}, &mockAuditLogService{})
+
+		resp, err := svc.AdminRefundOrder(context.Background(), "order123", &dto.AdminRefundRequest{Reason: "returned"}, "admin-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "refunded", resp.Status)
+		assert.Equal(t, int64(0), payments.lastRefund.AmountCents)
+	})
+
+	t.Run("nothing captured", func(t *testing.T) {
+		svc := newSvc(&mockOrderRepo{findByPublicID: &order}, &mockOrderPayments{}, &mockOrderEventRepo{}, &mockAuditLogService{})
+
+		_, err := svc.AdminRefundOrder(context.Background(), "order123", &dto.AdminRefundRequest{Reason: "returned"}, "admin-1")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "nothing_to_refund", de.Code)
+	})
+
+	t.Run("order not found", func(t *testing.T) {
+		svc := newSvc(&mockOrderRepo{}, &mockOrderPayments{}, &mockOrderEventRepo{}, &mockAuditLogService{})
+
+		_, err := svc.AdminRefundOrder(context.Background(), "missing", &dto.AdminRefundRequest{Reason: "returned"}, "admin-1")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "order_not_found", de.Code)
+	})
+}
+
+// guestConfirmationNotifier records guest order confirmations; other notifications are not expected.
+type guestConfirmationNotifier struct {
+	notification.NotificationService
+	to    string
+	token string
+}
+
+func (m *guestConfirmationNotifier) SendGuestOrderConfirmation(to string, order *model.Order, lookupToken string) error {
+	m.to = to
+	m.token = lookupToken
+	return nil
+}
+
+func TestCreateGuestOrderFromCart(t *testing.T) {
+	guestCart := createTestCart()
+	guestCart.UserID = ""
+	guestCart.GuestID = "guest123"
+	newSvc := func(repo *mockOrderRepo, events *mockOrderEventRepo) OrderService {
+		return NewOrderService(repo, events, &mockCartRepo{findByUserCart: guestCart}, &mockProductRepo{findByIDProduct: createTestProduct()}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, Config{})
+	}
+
+	t.Run("success returns a lookup token and stores only its hash", func(t *testing.T) {
+		repo := &mockOrderRepo{}
+		events := &mockOrderEventRepo{}
+		notifier := &guestConfirmationNotifier{}
+		svc := NewOrderService(repo, events, &mockCartRepo{findByUserCart: guestCart}, &mockProductRepo{findByIDProduct: createTestProduct()}, nil, nil, &mockShippingSvc{}, nil, nil, notifier, nil, Config{})
+
+		resp, err := svc.CreateGuestOrderFromCart("guest123", &dto.CreateGuestOrderRequest{
+			Email: " Guest@Example.com ",
+			Address: &dto.AddressRequest{
+				Recipient:    "Ana",
+				CEP:          "01310-100",
+				Street:       "Av. Paulista",
+				Number:       "1000",
+				Neighborhood: "Bela Vista",
+				City:         "São Paulo",
+				UF:           "sp",
+			},
+			PaymentMethod: string(model.PaymentMethodPix),
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, resp.LookupToken)
+		assert.Equal(t, "guest@example.com", resp.GuestEmail)
+		assert.Equal(t, money.FromCents(2000), resp.TotalAmount)
+
+		assert.Equal(t, "", repo.created.UserID)
+		assert.Equal(t, "guest123", repo.created.GuestID)
+		assert.Equal(t, auth.HashGuestLookupToken(resp.LookupToken), repo.created.GuestLookupTokenHash)
+		assert.Equal(t, "01310100", repo.created.ShippingDetails.CEP)
+		assert.Equal(t, "SP", repo.created.ShippingDetails.UF)
+		assert.Equal(t, uint(1), repo.reservedCartID)
+		assert.Equal(t, "guest@example.com", notifier.to)
+		assert.Equal(t, resp.LookupToken, notifier.token)
+	})
+
+	t.Run("missing address", func(t *testing.T) {
+		svc := newSvc(&mockOrderRepo{}, &mockOrderEventRepo{})
+
+		_, err := svc.CreateGuestOrderFromCart("guest123", &dto.CreateGuestOrderRequest{
+			Email:         "guest@example.com",
+			PaymentMethod: string(model.PaymentMethodPix),
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("empty cart", func(t *testing.T) {
+		svc := NewOrderService(&mockOrderRepo{}, &mockOrderEventRepo{}, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, Config{})
+
+		_, err := svc.CreateGuestOrderFromCart("guest123", &dto.CreateGuestOrderRequest{
+			Email:           "guest@example.com",
+			ShippingAddress: "Rua A, 1, São Paulo - SP, 01310-100",
+			PaymentMethod:   string(model.PaymentMethodPix),
+		})
+		var de *apperror.DomainError
+		if assert.ErrorAs(t, err, &de) {
+			assert.Equal(t, "cart_empty", de.Code)
+		}
+	})
+}
+
+func TestTrackGuestOrder(t *testing.T) {
+	order := createTestOrder()
+	order.UserID = ""
+	order.GuestID = "guest123"
+	order.GuestEmail = "guest@example.com"
+	repo := &mockOrderRepo{byTokenHash: map[string]*model.Order{auth.HashGuestLookupToken("secret-token"): &order}}
+	svc := NewOrderService(repo, &mockOrderEventRepo{}, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, Config{})
+
+	t.Run("matching email and token", func(t *testing.T) {
+		resp, err := svc.TrackGuestOrder(&dto.TrackOrderRequest{Email: "GUEST@example.com", Token: "secret-token"})
+		assert.NoError(t, err)
+		assert.Equal(t, "order123", resp.PublicID)
+	})
+
+	for name, req := range map[string]*dto.TrackOrderRequest{
+		"wrong email": {Email: "other@example.com", Token: "secret-token"},
+		"wrong token": {Email: "guest@example.com", Token: "guessed"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.TrackGuestOrder(req)
+			var de *apperror.DomainError
+			if assert.ErrorAs(t, err, &de) {
+				assert.Equal(t, "order_not_found", de.Code)
+			}
+		})
+	}
+}
+
+func TestClaimGuestOrders_NothingToClaim(t *testing.T) {
+	repo := &mockOrderRepo{}
+	svc := NewOrderService(repo, &mockOrderEventRepo{}, &mockCartRepo{}, &mockProductRepo{}, nil, nil, &mockShippingSvc{}, nil, nil, nil, nil, Config{})
+
+	resp, err := svc.ClaimGuestOrders("user123", "", &dto.ClaimGuestOrdersRequest{Tokens: []string{" "}})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.ClaimedOrders)
+	assert.Nil(t, repo.claimTokenHashes)
+}
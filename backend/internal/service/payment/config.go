@@ -0,0 +1,61 @@
+package service
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/envconfig"
+)
+
+const (
+	// DefaultReconcileAfter is how long a payment may wait for its webhook before the gateway is asked directly.
+	DefaultReconcileAfter = 30 * time.Minute
+	// DefaultReconcileInterval is how often stale payments are reconciled.
+	DefaultReconcileInterval = 15 * time.Minute
+	// DefaultReconcileProviders lists the gateways that can be asked for an intent's status.
+	DefaultReconcileProviders = "stripe"
+)
+
+// Config holds tunable payment behaviour.
+type Config struct {
+	ReconcileAfter     time.Duration
+	ReconcileInterval  time.Duration
+	ReconcileProviders []string
+}
+
+// LoadConfigFromEnv reads payment settings from the environment, falling back to defaults.
+// Durations use Go syntax, e.g. PAYMENT_RECONCILE_AFTER=1h; PAYMENT_RECONCILE_PROVIDERS is a
+// comma-separated list of gateway names.
+func LoadConfigFromEnv() Config {
+	cfg := Config{
+		ReconcileAfter:     envconfig.Duration("PAYMENT_RECONCILE_AFTER", DefaultReconcileAfter),
+		ReconcileInterval:  envconfig.Duration("PAYMENT_RECONCILE_INTERVAL", DefaultReconcileInterval),
+		ReconcileProviders: parseList(os.Getenv("PAYMENT_RECONCILE_PROVIDERS")),
+	}
+	return cfg.withDefaults()
+}
+
+// withDefaults fills unset or invalid values.
+func (c Config) withDefaults() Config {
+	if c.ReconcileAfter <= 0 {
+		c.ReconcileAfter = DefaultReconcileAfter
+	}
+	if c.ReconcileInterval <= 0 {
+		c.ReconcileInterval = DefaultReconcileInterval
+	}
+	if len(c.ReconcileProviders) == 0 {
+		c.ReconcileProviders = parseList(DefaultReconcileProviders)
+	}
+	return c
+}
+
+func parseList(raw string) []string {
+	var out []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -2,12 +2,20 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/leoferamos/aroma-sense/internal/apperror"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/leoferamos/aroma-sense/internal/notification"
 	"github.com/leoferamos/aroma-sense/internal/repository"
+	logservice "github.com/leoferamos/aroma-sense/internal/service/log"
+	orderservice "github.com/leoferamos/aroma-sense/internal/service/order"
+	promotionservice "github.com/leoferamos/aroma-sense/internal/service/promotion"
 	shippingservice "github.com/leoferamos/aroma-sense/internal/service/shipping"
 	"gorm.io/datatypes"
 )
@@ -16,8 +24,30 @@ import (
 type PaymentProvider interface {
 	CreatePaymentIntent(ctx context.Context, params PaymentIntentParams) (*PaymentIntentResult, error)
 	ParseWebhook(payload []byte, signature string) (*PaymentWebhookPayload, error)
+	CancelPaymentIntent(ctx context.Context, intentID string) error
+	RefundPayment(ctx context.Context, params RefundParams) (*RefundResult, error)
+	// GetPaymentIntent asks the gateway for the intent's current state, in webhook form.
+	GetPaymentIntent(ctx context.Context, intentID string) (*PaymentWebhookPayload, error)
 }
 
+// WebhookSimulator is implemented by in-process gateways that can produce their own signed events,
+// so payment outcomes can be exercised without a real gateway.
+type WebhookSimulator interface {
+	SimulateWebhook(intentID string, outcome string) (payload []byte, signature string, err error)
+}
+
+// ErrIntentNotCancelable is returned by providers when an intent has progressed too far to be canceled.
+var ErrIntentNotCancelable = errors.New("payment intent can no longer be canceled")
+
+// ErrIntentLookupUnsupported is returned by providers whose gateway cannot be queried for an intent's status.
+var ErrIntentLookupUnsupported = errors.New("payment intent status lookup not supported")
+
+// ErrWebhookSignature is wrapped by providers when a webhook fails signature verification.
+var ErrWebhookSignature = errors.New("webhook signature verification failed")
+
+// ErrWebhookEventIgnored is wrapped by providers for authentic events of types we do not act on.
+var ErrWebhookEventIgnored = errors.New("webhook event type not handled")
+
 // PaymentIntentParams are the normalized params sent to the provider.
 type PaymentIntentParams struct {
 	Amount        int64
@@ -30,57 +60,131 @@ type PaymentIntentParams struct {
 type PaymentIntentResult struct {
 	ID           string
 	ClientSecret string
+	// Pix charges are paid with a BR Code instead of a client secret.
+	PixCode      string
+	PixQRCodePNG []byte
+	// Boleto charges are paid with the digitable line or barcode by the due date.
+	BoletoBarcode       string
+	BoletoDigitableLine string
+	DueDate             *time.Time
+	// ExpiresAt is when the charge stops accepting payment.
+	ExpiresAt *time.Time
+	// Metadata holds provider details to keep with the payment.
+	Metadata map[string]string
+}
+
+// RefundParams describes a refund against a captured intent.
+type RefundParams struct {
+	IntentID string
+	Amount   int64
+	Reason   string
+	Metadata map[string]string
+}
+
+// RefundResult is the provider's view of an issued refund.
+type RefundResult struct {
+	ID     string
+	Status string
+	Amount int64
 }
 
 // PaymentWebhookPayload is a normalized view of provider webhook events.
+// EventID identifies the notification at the provider and is used to discard redeliveries.
 type PaymentWebhookPayload struct {
+	EventID       string
+	EventType     string
 	IntentID      string
 	Status        string
 	Amount        int64
 	Currency      string
 	CustomerEmail string
 	Metadata      map[string]string
+	// RefundedAmount is the total refunded so far, reported on refund events.
+	RefundedAmount int64
 }
 
 type PaymentService interface {
 	CreateIntent(ctx context.Context, userID string, req *dto.CreatePaymentIntentRequest) (*PaymentIntentResult, error)
-	HandleWebhook(ctx context.Context, payload []byte, signature string) (*PaymentWebhookPayload, error)
+	CreateGuestIntent(ctx context.Context, guestID string, req *dto.CreatePaymentIntentRequest) (*PaymentIntentResult, error)
+	HandleWebhook(ctx context.Context, provider string, payload []byte, signature string) (*PaymentWebhookPayload, error)
+	CancelOrderIntents(ctx context.Context, orderPublicID string, reason string, message string) ([]string, error)
+	RefundOrder(ctx context.Context, orderPublicID string, req orderservice.RefundRequest) (*orderservice.RefundOutcome, error)
+	ExpireIntent(ctx context.Context, intentID string, reason string) error
+	GetBoleto(ctx context.Context, userID string, intentID string) (*model.Payment, error)
+	ProcessWebhookEvent(ctx context.Context, id uint) error
+	ListWebhookEvents(status string, page int, perPage int) (*dto.AdminWebhookEventsResponse, error)
+	ReplayWebhookEvent(ctx context.Context, id uint) (*dto.WebhookEventResponse, error)
+	ReconcileIntent(ctx context.Context, intentID string) (model.PaymentStatus, error)
+	DiscrepancyReport(from, to time.Time) (*DiscrepancyReport, error)
+	SimulatePayment(ctx context.Context, intentID string, outcome string) (*PaymentWebhookPayload, error)
 }
 
 type paymentService struct {
 	cartRepo    repository.CartRepository
 	productRepo repository.ProductRepository
 	orderRepo   repository.OrderRepository
+	eventRepo   repository.OrderEventRepository
 	paymentRepo repository.PaymentRepository
+	refundRepo  repository.RefundRepository
+	webhookRepo repository.WebhookEventRepository
 	shippingSvc shippingservice.ShippingService
-	provider    PaymentProvider
+	auditLog    logservice.AuditLogService
+	notifier    notification.NotificationService
+	promotions  promotionservice.PromotionService
+	providers   *Providers
 }
 
-func NewPaymentService(cartRepo repository.CartRepository, productRepo repository.ProductRepository, orderRepo repository.OrderRepository, paymentRepo repository.PaymentRepository, shippingSvc shippingservice.ShippingService, provider PaymentProvider) PaymentService {
-	return &paymentService{cartRepo: cartRepo, productRepo: productRepo, orderRepo: orderRepo, paymentRepo: paymentRepo, shippingSvc: shippingSvc, provider: provider}
+func NewPaymentService(cartRepo repository.CartRepository, productRepo repository.ProductRepository, orderRepo repository.OrderRepository, eventRepo repository.OrderEventRepository, paymentRepo repository.PaymentRepository, refundRepo repository.RefundRepository, webhookRepo repository.WebhookEventRepository, shippingSvc shippingservice.ShippingService, auditLog logservice.AuditLogService, notifier notification.NotificationService, promotions promotionservice.PromotionService, providers *Providers) PaymentService {
+	return &paymentService{cartRepo: cartRepo, productRepo: productRepo, orderRepo: orderRepo, eventRepo: eventRepo, paymentRepo: paymentRepo, refundRepo: refundRepo, webhookRepo: webhookRepo, shippingSvc: shippingSvc, auditLog: auditLog, notifier: notifier, promotions: promotions, providers: providers}
 }
 
 // CreateIntent calculates totals from the user's cart and shipping selection, then delegates to the provider.
 func (s *paymentService) CreateIntent(ctx context.Context, userID string, req *dto.CreatePaymentIntentRequest) (*PaymentIntentResult, error) {
+	return s.createIntent(ctx, userID, false, req)
+}
+
+// CreateGuestIntent is CreateIntent for a guest session. Guests have no account email, so the
+// customer email is taken from the guest order or must be sent with the request.
+func (s *paymentService) CreateGuestIntent(ctx context.Context, guestID string, req *dto.CreatePaymentIntentRequest) (*PaymentIntentResult, error) {
+	return s.createIntent(ctx, guestID, true, req)
+}
+
+// createIntent starts a payment for the cart or order of userID, which is a guest session ID when guest is set.
+func (s *paymentService) createIntent(ctx context.Context, userID string, guest bool, req *dto.CreatePaymentIntentRequest) (*PaymentIntentResult, error) {
 	if req == nil {
 		return nil, apperror.NewCodeMessage("invalid_request", "missing payload")
 	}
 
-	var amount int64
-	metadata := map[string]string{
-		"user_id": userID,
+	amount := money.New(0, money.BRL)
+	var order *model.Order
+	method := model.PaymentMethod(req.PaymentMethod)
+	customerEmail := req.CustomerEmail
+	metadata := map[string]string{}
+	if guest {
+		metadata["guest_id"] = userID
+	} else {
+		metadata["user_id"] = userID
 	}
 
 	// If an order already exists, use its totals and metadata.
 	if req.OrderPublicID != "" {
-		order, err := s.orderRepo.FindByPublicIDWithItems(req.OrderPublicID)
+		var err error
+		order, err = s.orderRepo.FindByPublicIDWithItems(req.OrderPublicID)
 		if err != nil {
 			return nil, err
 		}
-		if order == nil || order.UserID != userID {
+		if order == nil || !order.OwnedBy(userID) {
 			return nil, apperror.NewCodeMessage("invalid_request", "order not found")
 		}
-		amount = int64(order.TotalAmount * 100)
+		// Cancelled or expired orders have given their stock back and must not be paid again.
+		if order.Status != model.OrderStatusPending || !order.StockReserved {
+			return nil, apperror.NewCodeMessage("order_not_payable", "order is no longer awaiting payment")
+		}
+		if customerEmail == "" {
+			customerEmail = order.GuestEmail
+		}
+		amount = order.Total()
+		method = order.PaymentMethod
 		metadata["order_public_id"] = req.OrderPublicID
 		if order.ShippingAddress != "" {
 			metadata["shipping_address"] = order.ShippingAddress
@@ -98,18 +202,28 @@ func (s *paymentService) CreateIntent(ctx context.Context, userID string, req *d
 		}
 
 		// Compute subtotal and validate stock existence.
-		total := 0.0
+		var total money.Amount
+		var lines []promotionservice.Line
 		for _, item := range cart.Items {
-			product, err := s.productRepo.FindByID(item.ProductID)
+			if item.ProductID == nil || item.VariantID == nil {
+				return nil, apperror.NewDomain(fmt.Errorf("cart item %d: product was removed", item.ID), "product_not_found", "product not found")
+			}
+			product, err := s.productRepo.FindByID(*item.ProductID)
 			if err != nil {
-				return nil, apperror.NewDomain(fmt.Errorf("product not found: %d", item.ProductID), "product_not_found", "product not found")
+				return nil, apperror.NewDomain(fmt.Errorf("product not found: %d", *item.ProductID), "product_not_found", "product not found")
+			}
+			variant := product.Variant(*item.VariantID)
+			if variant == nil {
+				return nil, apperror.NewDomain(fmt.Errorf("variant not found: %d", *item.VariantID), "product_not_found", "product not found")
 			}
-			if product.StockQuantity < item.Quantity {
-				return nil, apperror.NewDomain(fmt.Errorf("insufficient stock for product: %s", product.Name), "insufficient_stock", "insufficient stock")
+			if variant.StockQuantity < item.Quantity {
+				return nil, apperror.NewDomain(fmt.Errorf("insufficient stock for product: %s (%s)", product.Name, variant.SKU), "insufficient_stock", "insufficient stock")
 			}
-			total += float64(item.Quantity) * product.Price
+			total += variant.Price.Mul(item.Quantity)
+			lines = append(lines, promotionservice.Line{ProductID: product.ID, Brand: product.Brand, Category: product.Category, UnitPrice: variant.Price, Quantity: item.Quantity})
 		}
 
+		var shippingPrice money.Amount
 		if req.ShippingSelection != nil {
 			if s.shippingSvc == nil {
 				return nil, apperror.NewCodeMessage("provider_unavailable", "shipping provider not configured")
@@ -128,43 +242,75 @@ func (s *paymentService) CreateIntent(ctx context.Context, userID string, req *d
 			if matched == nil {
 				return nil, apperror.NewCodeMessage("invalid_shipping_selection", "invalid shipping selection")
 			}
+			shippingPrice = matched.Price
 			total += matched.Price
 			metadata["shipping_carrier"] = req.ShippingSelection.Carrier
 			metadata["shipping_service_code"] = req.ShippingSelection.ServiceCode
 		}
 
-		amount = int64(total * 100) // convert to cents
+		// Apply the cart's coupon the same way checkout will
+		if cart.PromotionCode != "" && s.promotions != nil {
+			customer := promotionservice.Customer{UserID: metadata["user_id"], Email: customerEmail}
+			discount, err := s.promotions.Evaluate(cart.PromotionCode, customer, lines, shippingPrice)
+			if err != nil {
+				return nil, err
+			}
+			total -= discount.Total()
+			metadata["promotion_code"] = discount.Promotion.Code
+		}
+
+		amount.Amount = total
 		if req.ShippingAddress != "" {
 			metadata["shipping_address"] = req.ShippingAddress
 		}
 		metadata["order_hint"] = "cart"
 	}
 
-	if amount <= 0 {
+	if amount.Amount <= 0 {
 		return nil, apperror.NewCodeMessage("invalid_amount", "amount must be positive")
 	}
+	if guest && customerEmail == "" {
+		return nil, apperror.NewCodeMessage("invalid_request", "customer email is required for guest checkout")
+	}
+
+	if method == "" {
+		method = model.PaymentMethodCreditCard
+	}
+	providerName, provider := s.providers.ForMethod(method)
+	if provider == nil {
+		return nil, apperror.NewCodeMessage("payment_method_unavailable", "payment method not available")
+	}
+	metadata["payment_method"] = string(method)
 
 	params := PaymentIntentParams{
-		Amount:        amount,
-		Currency:      "brl",
-		CustomerEmail: req.CustomerEmail,
+		Amount:        amount.Amount.Cents(),
+		Currency:      string(amount.Currency),
+		CustomerEmail: customerEmail,
 		Metadata:      metadata,
 	}
 
-	result, err := s.provider.CreatePaymentIntent(ctx, params)
+	result, err := provider.CreatePaymentIntent(ctx, params)
 	if err != nil {
 		return nil, err
 	}
 
 	if s.paymentRepo != nil {
+		for k, v := range result.Metadata {
+			metadata[k] = v
+		}
 		payment := &model.Payment{
-			IntentID:    result.ID,
-			Provider:    "stripe",
-			UserID:      userID,
-			AmountCents: amount,
-			Currency:    params.Currency,
-			Status:      model.PaymentStatusPending,
-			Metadata:    toJSONMap(metadata),
+			IntentID:            result.ID,
+			Provider:            providerName,
+			UserID:              metadata["user_id"],
+			GuestID:             metadata["guest_id"],
+			AmountCents:         params.Amount,
+			Currency:            params.Currency,
+			Status:              model.PaymentStatusPending,
+			Metadata:            toJSONMap(metadata),
+			ExpiresAt:           result.ExpiresAt,
+			DueDate:             result.DueDate,
+			BoletoBarcode:       result.BoletoBarcode,
+			BoletoDigitableLine: result.BoletoDigitableLine,
 		}
 		if v, ok := metadata["order_public_id"]; ok && v != "" {
 			payment.OrderPublicID = &v
@@ -174,60 +320,114 @@ func (s *paymentService) CreateIntent(ctx context.Context, userID string, req *d
 		}
 	}
 
+	// Charges payable for longer than the order's reservation (e.g. boletos) keep the stock held until they lapse.
+	if order != nil && result.ExpiresAt != nil && order.ReservationExpiresAt != nil && result.ExpiresAt.After(*order.ReservationExpiresAt) {
+		if err := s.orderRepo.ExtendReservation(order.PublicID, *result.ExpiresAt); err != nil {
+			log.Printf("payments: failed to extend reservation of order %s: %v", order.PublicID, err)
+		}
+	}
+
 	return result, nil
 }
 
 // HandleWebhook validates provider signature and returns the normalized payload.
-func (s *paymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) (*PaymentWebhookPayload, error) {
-	if s.provider == nil {
+// An empty provider name selects the default gateway.
+//
+// Verified events are stored in the webhook inbox before they are applied, so a redelivered event
+// is acknowledged without being applied twice and a failed one is retried by the webhook retry job.
+// Events of types we do not handle are stored as ignored and return a nil payload.
+func (s *paymentService) HandleWebhook(ctx context.Context, providerName string, payload []byte, signature string) (*PaymentWebhookPayload, error) {
+	provider := s.providers.ByName(providerName)
+	if provider == nil {
 		return nil, apperror.NewCodeMessage("provider_unavailable", "payment provider not configured")
 	}
+	if providerName == "" {
+		providerName = s.providers.DefaultName()
+	}
+
+	normalized, err := provider.ParseWebhook(payload, signature)
+	if s.webhookRepo == nil {
+		if err != nil {
+			return nil, apperror.NewDomain(err, "invalid_webhook", "invalid webhook payload")
+		}
+		if err := s.applyWebhook(ctx, providerName, normalized); err != nil {
+			return nil, err
+		}
+		return normalized, nil
+	}
 
-	normalized, err := s.provider.ParseWebhook(payload, signature)
 	if err != nil {
-		return nil, apperror.NewDomain(err, "invalid_webhook", "invalid webhook payload")
+		return nil, s.storeUnprocessable(providerName, payload, err)
 	}
 
+	event, created, err := s.storeWebhookEvent(providerName, payload, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		log.Printf("payment webhook: duplicate %s event %s ignored", providerName, event.EventID)
+		return normalized, nil
+	}
+
+	// The event is safely stored, so a failure here is left to the retry job instead of
+	// making the gateway redeliver it.
+	if err := s.processEvent(ctx, event); err != nil {
+		log.Printf("payment webhook: %s event %s failed, will retry: %v", providerName, event.EventID, err)
+	}
+	return normalized, nil
+}
+
+// applyWebhook moves the payment and its order to the state reported by the gateway. It is safe to
+// run again for the same event: every step checks the current state before changing it.
+func (s *paymentService) applyWebhook(ctx context.Context, providerName string, normalized *PaymentWebhookPayload) error {
 	status := toPaymentStatus(normalized.Status)
 	metadata := normalized.Metadata
 
 	if s.paymentRepo == nil {
-		return normalized, nil
+		return nil
 	}
 
 	payment, err := s.paymentRepo.FindByIntentID(normalized.IntentID)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	orderPublicID := metadata["order_public_id"]
+	paymentChanged := false
 
 	if payment == nil {
-		userID := metadata["user_id"]
 		p := &model.Payment{
 			IntentID:    normalized.IntentID,
-			Provider:    "stripe",
-			UserID:      userID,
+			Provider:    providerName,
+			UserID:      metadata["user_id"],
+			GuestID:     metadata["guest_id"],
 			AmountCents: normalized.Amount,
 			Currency:    normalized.Currency,
 			Status:      status,
 			Metadata:    toJSONMap(metadata),
 		}
+		if status.IsRefunded() {
+			p.RefundedCents = min(normalized.RefundedAmount, normalized.Amount)
+		}
 		if orderPublicID != "" {
 			p.OrderPublicID = &orderPublicID
 		}
 		if err := s.paymentRepo.Create(p); err != nil {
-			return nil, err
+			return err
 		}
 		payment = p
+		paymentChanged = true
 	} else {
 		if orderPublicID != "" && payment.OrderPublicID == nil {
 			if err := s.paymentRepo.AttachOrderPublicID(payment.IntentID, orderPublicID); err == nil {
 				payment.OrderPublicID = &orderPublicID
 			}
 		}
-		// Idempotent update.
-		if payment.Status != status {
+		if status == model.PaymentStatusSucceeded && payment.ExpiresAt != nil && payment.ExpiresAt.Before(time.Now()) {
+			log.Printf("payment webhook: intent %s was paid after it expired at %s", payment.IntentID, payment.ExpiresAt.Format(time.RFC3339))
+		}
+		// Idempotent update. Refunds are final, so later charge events must not revive the payment.
+		if payment.Status != status && (!payment.Status.IsRefunded() || status.IsRefunded()) {
 			errCode := ""
 			errMsg := ""
 			if status == model.PaymentStatusFailed || status == model.PaymentStatusCanceled {
@@ -235,9 +435,17 @@ func (s *paymentService) HandleWebhook(ctx context.Context, payload []byte, sign
 				errMsg = "provider reported failure"
 			}
 			if err := s.paymentRepo.UpdateStatusByIntentID(payment.IntentID, status, errCode, errMsg); err != nil {
-				return nil, err
+				return err
 			}
 			payment.Status = status
+			paymentChanged = true
+		}
+		// Refunds issued at the gateway, such as from its dashboard, only reach the store here.
+		if status.IsRefunded() && normalized.RefundedAmount > payment.RefundedCents {
+			if err := s.paymentRepo.SyncProviderRefund(payment.IntentID, normalized.RefundedAmount); err != nil {
+				return err
+			}
+			payment.RefundedCents = min(normalized.RefundedAmount, payment.AmountCents)
 		}
 	}
 
@@ -249,32 +457,336 @@ func (s *paymentService) HandleWebhook(ctx context.Context, payload []byte, sign
 		if target != "" {
 			order, err := s.orderRepo.FindByPublicIDWithItems(target)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if order == nil {
-				return normalized, nil
+				return nil
+			}
+			if paymentChanged {
+				s.recordEvent(&model.OrderEvent{
+					OrderID:   order.ID,
+					Type:      model.OrderEventPaymentUpdated,
+					ToStatus:  string(status),
+					ActorType: model.OrderEventActorWebhook,
+					Metadata: datatypes.JSONMap{
+						"intent_id":    normalized.IntentID,
+						"amount_cents": normalized.Amount,
+						"currency":     normalized.Currency,
+					},
+				})
 			}
+
+			var next model.OrderStatus
 			switch status {
 			case model.PaymentStatusSucceeded:
-				if order.Status == model.OrderStatusPending {
-					for _, item := range order.Items {
-						if err := s.productRepo.DecrementStock(item.ProductID, item.Quantity); err != nil {
-							return nil, err
+				// Orders created before checkout-time reservation still need their stock taken.
+				if order.Status == model.OrderStatusPending && !order.StockReserved {
+					if err := s.orderRepo.ReserveStock(target); err != nil {
+						if !errors.Is(err, repository.ErrInsufficientStock) {
+							return err
 						}
+						log.Printf("payment webhook: order %s paid but stock is insufficient: %v", target, err)
 					}
 				}
-				if err := s.orderRepo.UpdateStatusByPublicID(target, model.OrderStatusProcessing); err != nil {
-					return nil, err
+				next = model.OrderStatusProcessing
+				// A payment that does not cover the order exactly, or that arrives for an order that is
+				// no longer awaiting payment, is held for an operator to resolve.
+				if order.Status != model.OrderStatusPending && paymentChanged {
+					s.flagForReview(order, model.PaymentReviewOrderNotPayable, normalized)
+				}
+				if order.Status == model.OrderStatusPending {
+					if reason := paymentMismatch(order, normalized); reason != "" {
+						s.flagForReview(order, reason, normalized)
+						next = ""
+					} else if order.PaymentReviewReason != "" {
+						next = ""
+					}
 				}
 			case model.PaymentStatusFailed, model.PaymentStatusCanceled:
-				if err := s.orderRepo.UpdateStatusByPublicID(target, model.OrderStatusCancelled); err != nil {
-					return nil, err
+				next = model.OrderStatusCancelled
+			}
+			if next != "" && orderservice.CanTransition(order.Status, next) {
+				var err error
+				if next == model.OrderStatusCancelled {
+					err = s.orderRepo.CancelAndReleaseStock(target, order.Status)
+				} else {
+					err = s.orderRepo.UpdateStatusByPublicID(target, next)
+				}
+				switch {
+				case err == nil:
+					s.recordEvent(&model.OrderEvent{
+						OrderID:    order.ID,
+						Type:       model.OrderEventStatusChanged,
+						FromStatus: string(order.Status),
+						ToStatus:   string(next),
+						ActorType:  model.OrderEventActorWebhook,
+						Note:       "payment " + string(status),
+					})
+				case !errors.Is(err, repository.ErrOrderStatusConflict):
+					return err
 				}
 			}
 		}
 	}
 
-	return normalized, nil
+	return nil
+}
+
+// SimulatePayment has the payment's gateway produce a signed event for the outcome and delivers it
+// through HandleWebhook, like a real notification. Only in-process gateways support this.
+func (s *paymentService) SimulatePayment(ctx context.Context, intentID string, outcome string) (*PaymentWebhookPayload, error) {
+	if s.paymentRepo == nil {
+		return nil, apperror.NewCodeMessage("payment_not_found", "payment not found")
+	}
+	payment, err := s.paymentRepo.FindByIntentID(intentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, apperror.NewCodeMessage("payment_not_found", "payment not found")
+	}
+	simulator, ok := s.providers.ByName(payment.Provider).(WebhookSimulator)
+	if !ok {
+		return nil, apperror.NewCodeMessage("simulation_unsupported", "payment provider cannot simulate events")
+	}
+
+	// The fake gateway keeps intents in memory, so intents from before a restart cannot be simulated.
+	payload, signature, err := simulator.SimulateWebhook(payment.IntentID, outcome)
+	if err != nil {
+		return nil, apperror.NewDomain(err, "simulation_failed", "payment event could not be simulated")
+	}
+	return s.HandleWebhook(ctx, payment.Provider, payload, signature)
+}
+
+// CancelOrderIntents cancels every still-open intent of an order and returns the canceled intent IDs.
+// It returns ErrIntentNotCancelable when an intent is already being settled, so callers can leave the order alone.
+func (s *paymentService) CancelOrderIntents(ctx context.Context, orderPublicID string, reason string, message string) ([]string, error) {
+	if s.paymentRepo == nil {
+		return nil, nil
+	}
+
+	payments, err := s.paymentRepo.FindByOrderPublicID(orderPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	var canceled []string
+	for _, p := range payments {
+		if p.Status == model.PaymentStatusSucceeded || p.Status == model.PaymentStatusProcessing {
+			return canceled, paymentInProgress(fmt.Errorf("intent %s is %s: %w", p.IntentID, p.Status, ErrIntentNotCancelable))
+		}
+		if p.Status != model.PaymentStatusPending {
+			continue
+		}
+
+		provider := s.providers.ByName(p.Provider)
+		if provider == nil {
+			return canceled, apperror.NewCodeMessage("provider_unavailable", "payment provider not configured")
+		}
+		if err := provider.CancelPaymentIntent(ctx, p.IntentID); err != nil {
+			if errors.Is(err, ErrIntentNotCancelable) {
+				return canceled, paymentInProgress(err)
+			}
+			return canceled, err
+		}
+		if err := s.paymentRepo.UpdateStatusByIntentID(p.IntentID, model.PaymentStatusCanceled, reason, message); err != nil {
+			return canceled, err
+		}
+		canceled = append(canceled, p.IntentID)
+	}
+	return canceled, nil
+}
+
+// ExpireIntent withdraws a single unpaid intent whose payment deadline passed and records the reason.
+// Intents that are already being settled return ErrIntentNotCancelable; closed ones are left alone.
+func (s *paymentService) ExpireIntent(ctx context.Context, intentID string, reason string) error {
+	if s.paymentRepo == nil {
+		return nil
+	}
+	p, err := s.paymentRepo.FindByIntentID(intentID)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return apperror.NewCodeMessage("payment_not_found", "payment not found")
+	}
+	switch p.Status {
+	case model.PaymentStatusPending:
+	case model.PaymentStatusProcessing, model.PaymentStatusSucceeded:
+		return paymentInProgress(fmt.Errorf("intent %s is %s: %w", p.IntentID, p.Status, ErrIntentNotCancelable))
+	default:
+		return nil
+	}
+
+	provider := s.providers.ByName(p.Provider)
+	if provider == nil {
+		return apperror.NewCodeMessage("provider_unavailable", "payment provider not configured")
+	}
+	if err := provider.CancelPaymentIntent(ctx, p.IntentID); err != nil {
+		if errors.Is(err, ErrIntentNotCancelable) {
+			return paymentInProgress(err)
+		}
+		return err
+	}
+	return s.paymentRepo.UpdateStatusByIntentID(p.IntentID, model.PaymentStatusCanceled, reason, "payment deadline passed")
+}
+
+// GetBoleto returns a boleto payment owned by the user or guest session, for rendering.
+func (s *paymentService) GetBoleto(ctx context.Context, userID string, intentID string) (*model.Payment, error) {
+	if s.paymentRepo == nil {
+		return nil, apperror.NewCodeMessage("boleto_not_found", "boleto not found")
+	}
+	p, err := s.paymentRepo.FindByIntentID(intentID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || !p.OwnedBy(userID) || p.BoletoBarcode == "" {
+		return nil, apperror.NewCodeMessage("boleto_not_found", "boleto not found")
+	}
+	return p, nil
+}
+
+// RefundOrder returns captured money for an order, spreading the amount over its payments oldest first.
+// Each payment touched gets its own refund record. A zero amount refunds everything still captured.
+func (s *paymentService) RefundOrder(ctx context.Context, orderPublicID string, req orderservice.RefundRequest) (*orderservice.RefundOutcome, error) {
+	outcome := &orderservice.RefundOutcome{}
+	if s.paymentRepo == nil {
+		return outcome, nil
+	}
+	if req.AmountCents < 0 {
+		return nil, apperror.NewCodeMessage("invalid_amount", "amount must be positive")
+	}
+
+	payments, err := s.paymentRepo.FindByOrderPublicID(orderPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	var refundable int64
+	for i := range payments {
+		refundable += payments[i].RefundableCents()
+	}
+	amount := req.AmountCents
+	if amount == 0 {
+		amount = refundable
+	}
+	if amount > refundable {
+		return nil, apperror.NewCodeMessage("refund_exceeds_captured", "refund amount exceeds the captured amount")
+	}
+
+	left := amount
+	for i := range payments {
+		if left == 0 {
+			break
+		}
+		part := min(left, payments[i].RefundableCents())
+		if part == 0 {
+			continue
+		}
+		if err := s.refundPayment(ctx, &payments[i], part, req); err != nil {
+			outcome.RemainingCents = refundable - outcome.RefundedCents
+			return outcome, err
+		}
+		outcome.RefundedCents += part
+		left -= part
+	}
+	outcome.RemainingCents = refundable - outcome.RefundedCents
+	return outcome, nil
+}
+
+// refundPayment issues a single refund at the provider and books it against the payment.
+func (s *paymentService) refundPayment(ctx context.Context, p *model.Payment, amount int64, req orderservice.RefundRequest) error {
+	orderPublicID := ""
+	if p.OrderPublicID != nil {
+		orderPublicID = *p.OrderPublicID
+	}
+	refund := &model.Refund{
+		PaymentID:     p.ID,
+		IntentID:      p.IntentID,
+		OrderPublicID: orderPublicID,
+		AmountCents:   amount,
+		Currency:      p.Currency,
+		Status:        model.RefundStatusPending,
+		Reason:        req.Reason,
+		ActorType:     req.ActorType,
+	}
+	if req.ActorID != "" {
+		actorID := req.ActorID
+		refund.ActorID = &actorID
+	}
+	provider := s.providers.ByName(p.Provider)
+	if provider == nil {
+		return apperror.NewCodeMessage("provider_unavailable", "payment provider not configured")
+	}
+	if s.refundRepo != nil {
+		if err := s.refundRepo.Create(refund); err != nil {
+			return err
+		}
+	}
+
+	// The provider is called while the payment row is locked, so a concurrent refund that
+	// would exceed the captured amount is rejected before it reaches the gateway.
+	var result *RefundResult
+	var providerErr error
+	err := s.paymentRepo.ApplyRefund(p.IntentID, amount, func() error {
+		result, providerErr = provider.RefundPayment(ctx, RefundParams{
+			IntentID: p.IntentID,
+			Amount:   amount,
+			Reason:   req.Reason,
+			Metadata: map[string]string{"order_public_id": orderPublicID},
+		})
+		return providerErr
+	})
+	if result == nil {
+		if err == nil {
+			err = errors.New("refund not issued")
+		}
+		s.markRefundFailed(refund, err)
+		switch {
+		case errors.Is(err, repository.ErrRefundExceedsPayment):
+			return apperror.NewDomain(err, "refund_exceeds_captured", "refund amount exceeds the captured amount")
+		case providerErr != nil:
+			return apperror.NewDomain(err, "refund_failed", "payment provider rejected the refund")
+		}
+		return err
+	}
+
+	// From here the money has left at the provider, so the refund is recorded as succeeded
+	// even if booking it against the payment failed.
+	if s.refundRepo != nil {
+		if markErr := s.refundRepo.MarkSucceeded(refund.ID, result.ID); markErr != nil {
+			log.Printf("refunds: failed to mark refund %d as succeeded: %v", refund.ID, markErr)
+		}
+	}
+	if err != nil {
+		log.Printf("refunds: refund %d issued as %s but not booked on payment %s: %v", refund.ID, result.ID, p.IntentID, err)
+	}
+	return err
+}
+
+// markRefundFailed records why a refund was not issued. Failures are logged and never mask the cause.
+func (s *paymentService) markRefundFailed(refund *model.Refund, cause error) {
+	if s.refundRepo == nil {
+		return
+	}
+	if err := s.refundRepo.MarkFailed(refund.ID, cause.Error()); err != nil {
+		log.Printf("refunds: failed to mark refund %d as failed: %v", refund.ID, err)
+	}
+}
+
+// paymentInProgress marks errors for intents that are already being settled and cannot be withdrawn.
+func paymentInProgress(err error) error {
+	return apperror.NewDomain(err, "payment_in_progress", "payment is already being processed")
+}
+
+// recordEvent appends an entry to the order history. Failures are logged and never block the webhook.
+func (s *paymentService) recordEvent(event *model.OrderEvent) {
+	if s.eventRepo == nil {
+		return
+	}
+	if err := s.eventRepo.Create(event); err != nil {
+		log.Printf("order events: failed to record %s for order %d: %v", event.Type, event.OrderID, err)
+	}
 }
 
 func toPaymentStatus(raw string) model.PaymentStatus {
@@ -287,6 +799,10 @@ func toPaymentStatus(raw string) model.PaymentStatus {
 		return model.PaymentStatusCanceled
 	case "processing":
 		return model.PaymentStatusProcessing
+	case "refunded":
+		return model.PaymentStatusRefunded
+	case "partially_refunded":
+		return model.PaymentStatusPartiallyRefunded
 	default:
 		return model.PaymentStatusPending
 	}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"gorm.io/datatypes"
+)
+
+// paidMoney returns what the gateway reported as captured.
+func paidMoney(payment *PaymentWebhookPayload) money.Money {
+	return money.New(money.FromCents(payment.Amount), money.Normalize(payment.Currency))
+}
+
+// paymentMismatch compares a successful payment with the order it pays for and returns the
+// review reason, or "" when the payment covers the order exactly.
+func paymentMismatch(order *model.Order, payment *PaymentWebhookPayload) string {
+	expected := order.Total()
+	paid := paidMoney(payment)
+	if !paid.SameCurrency(expected) {
+		return model.PaymentReviewCurrencyMismatch
+	}
+	switch {
+	case paid.Amount < expected.Amount:
+		return model.PaymentReviewUnderpaid
+	case paid.Amount > expected.Amount:
+		return model.PaymentReviewOverpaid
+	}
+	return ""
+}
+
+// flagForReview holds the order for manual review and alerts operators. Only the first flag
+// is recorded, so replayed or retried webhooks do not repeat the alert.
+func (s *paymentService) flagForReview(order *model.Order, reason string, payment *PaymentWebhookPayload) {
+	flagged, err := s.orderRepo.FlagPaymentReview(order.PublicID, reason, time.Now())
+	if err != nil {
+		log.Printf("payment webhook: failed to flag order %s for review: %v", order.PublicID, err)
+		return
+	}
+	order.PaymentReviewReason = reason
+	if !flagged {
+		return
+	}
+
+	expected := order.Total()
+	paid := paidMoney(payment)
+	log.Printf("payment webhook: order %s held for review (%s): paid %s, expected %s", order.PublicID, reason, paid, expected)
+
+	s.recordEvent(&model.OrderEvent{
+		OrderID:   order.ID,
+		Type:      model.OrderEventPaymentReview,
+		ActorType: model.OrderEventActorWebhook,
+		Note:      reason,
+		Metadata: datatypes.JSONMap{
+			"intent_id":             payment.IntentID,
+			"amount_cents":          payment.Amount,
+			"currency":              payment.Currency,
+			"expected_amount_cents": expected.Amount.Cents(),
+			"expected_currency":     string(expected.Currency),
+		},
+	})
+
+	if s.auditLog != nil {
+		details := map[string]interface{}{
+			"reason":                reason,
+			"order_status":          string(order.Status),
+			"intent_id":             payment.IntentID,
+			"amount_cents":          payment.Amount,
+			"currency":              payment.Currency,
+			"expected_amount_cents": expected.Amount.Cents(),
+			"expected_currency":     string(expected.Currency),
+		}
+		if err := s.auditLog.LogSystemAlert(model.AuditActionPaymentMismatch, "order", order.PublicID, model.AuditSeverityCritical, details); err != nil {
+			log.Printf("payment webhook: failed to audit mismatch on order %s: %v", order.PublicID, err)
+		}
+	}
+
+	if s.notifier != nil {
+		subject := fmt.Sprintf("Payment mismatch on order %s", order.PublicID)
+		message := fmt.Sprintf("Payment %s reported %s, but the order expects %s (%s). The order is held for manual review.",
+			payment.IntentID, paid, expected, reason)
+		if reason == model.PaymentReviewOrderNotPayable {
+			message = fmt.Sprintf("Payment %s captured %s, but the order is already %s. The payment needs a manual refund or the order must be restored.",
+				payment.IntentID, paid, order.Status)
+		}
+		if err := s.notifier.SendAdminAlert(subject, message); err != nil {
+			log.Printf("payment webhook: failed to alert admins about order %s: %v", order.PublicID, err)
+		}
+	}
+}
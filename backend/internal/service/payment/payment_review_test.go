@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockOrderRepo struct {
+	order    *model.Order
+	statuses []model.OrderStatus
+	flags    int
+}
+
+func (m *mockOrderRepo) Create(order *model.Order) error { return nil }
+func (m *mockOrderRepo) CreateWithStockReservation(order *model.Order, cartID uint) error {
+	return nil
+}
+func (m *mockOrderRepo) ReserveStock(publicID string) error { return nil }
+func (m *mockOrderRepo) CancelAndReleaseStock(publicID string, from model.OrderStatus) error {
+	m.statuses = append(m.statuses, model.OrderStatusCancelled)
+	return nil
+}
+func (m *mockOrderRepo) FindExpiredReservations(now time.Time, limit int) ([]model.Order, error) {
+	return nil, nil
+}
+func (m *mockOrderRepo) ExtendReservation(publicID string, until time.Time) error { return nil }
+func (m *mockOrderRepo) FlagPaymentReview(publicID string, reason string, at time.Time) (bool, error) {
+	if m.order.PaymentReviewReason != "" {
+		return false, nil
+	}
+	m.order.PaymentReviewReason = reason
+	m.order.PaymentReviewAt = &at
+	m.flags++
+	return true, nil
+}
+func (m *mockOrderRepo) FindByID(id uint) (*model.Order, error)            { return m.order, nil }
+func (m *mockOrderRepo) FindByUserID(userID string) ([]model.Order, error) { return nil, nil }
+func (m *mockOrderRepo) FindByPublicIDWithItems(publicID string) (*model.Order, error) {
+	copied := *m.order
+	return &copied, nil
+}
+func (m *mockOrderRepo) FindByGuestLookupTokenHash(tokenHash string) (*model.Order, error) {
+	return nil, nil
+}
+func (m *mockOrderRepo) ClaimGuestOrders(userID string, email string, guestID string, tokenHashes []string) ([]string, error) {
+	return nil, nil
+}
+func (m *mockOrderRepo) ListOrders(status *string, startDate *time.Time, endDate *time.Time, page int, perPage int) ([]model.Order, int64, money.Amount, error) {
+	return nil, 0, 0, nil
+}
+func (m *mockOrderRepo) HasUserDeliveredOrderWithProduct(userID string, productID uint) (bool, error) {
+	return false, nil
+}
+func (m *mockOrderRepo) UpdateStatusByPublicID(publicID string, status model.OrderStatus) error {
+	m.statuses = append(m.statuses, status)
+	m.order.Status = status
+	return nil
+}
+func (m *mockOrderRepo) UpdateFulfillmentStatus(publicID string, from model.OrderStatus, to model.OrderStatus, shippingStatus string, tracking string) error {
+	return nil
+}
+
+func TestPaymentMismatch(t *testing.T) {
+	order := &model.Order{TotalAmount: money.FromCents(5000)}
+
+	cases := []struct {
+		name     string
+		amount   int64
+		currency string
+		want     string
+	}{
+		{"exact", 5000, "brl", ""},
+		{"currency case", 5000, "BRL", ""},
+		{"underpaid", 4999, "brl", model.PaymentReviewUnderpaid},
+		{"overpaid", 5001, "brl", model.PaymentReviewOverpaid},
+		{"other currency", 5000, "usd", model.PaymentReviewCurrencyMismatch},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := paymentMismatch(order, &PaymentWebhookPayload{Amount: tc.amount, Currency: tc.currency})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestApplyWebhook_PaymentReview(t *testing.T) {
+	newService := func(order *model.Order) (*paymentService, *mockOrderRepo) {
+		orders := &mockOrderRepo{order: order}
+		svc := NewPaymentService(nil, nil, orders, nil, &mockPaymentRepo{}, &mockRefundRepo{}, nil, nil, nil, nil, nil, NewProviders("stripe", &mockProvider{}))
+		return svc.(*paymentService), orders
+	}
+	pendingOrder := func() *model.Order {
+		return &model.Order{ID: 1, PublicID: "order-1", Status: model.OrderStatusPending, StockReserved: true, TotalAmount: money.FromCents(5000)}
+	}
+	webhook := func(amount int64) *PaymentWebhookPayload {
+		payload := succeededWebhook()
+		payload.Amount = amount
+		payload.Metadata = map[string]string{"user_id": "user-1", "order_public_id": "order-1"}
+		return payload
+	}
+
+	t.Run("matching payment moves the order to processing", func(t *testing.T) {
+		svc, orders := newService(pendingOrder())
+
+		err := svc.applyWebhook(context.Background(), "stripe", webhook(5000))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.OrderStatus{model.OrderStatusProcessing}, orders.statuses)
+		assert.Empty(t, orders.order.PaymentReviewReason)
+	})
+
+	t.Run("underpayment holds the order for review", func(t *testing.T) {
+		svc, orders := newService(pendingOrder())
+
+		err := svc.applyWebhook(context.Background(), "stripe", webhook(4000))
+
+		assert.NoError(t, err)
+		assert.Empty(t, orders.statuses)
+		assert.Equal(t, model.PaymentReviewUnderpaid, orders.order.PaymentReviewReason)
+		assert.Equal(t, model.OrderStatusPending, orders.order.Status)
+	})
+
+	t.Run("overpayment is flagged only once across redeliveries", func(t *testing.T) {
+		svc, orders := newService(pendingOrder())
+
+		assert.NoError(t, svc.applyWebhook(context.Background(), "stripe", webhook(6000)))
+		assert.NoError(t, svc.applyWebhook(context.Background(), "stripe", webhook(6000)))
+
+		assert.Equal(t, 1, orders.flags)
+		assert.Empty(t, orders.statuses)
+		assert.Equal(t, model.PaymentReviewOverpaid, orders.order.PaymentReviewReason)
+	})
+
+	t.Run("payment for a cancelled order is flagged", func(t *testing.T) {
+		order := pendingOrder()
+		order.Status = model.OrderStatusCancelled
+		order.StockReserved = false
+		svc, orders := newService(order)
+
+		err := svc.applyWebhook(context.Background(), "stripe", webhook(5000))
+
+		assert.NoError(t, err)
+		assert.Empty(t, orders.statuses)
+		assert.Equal(t, 1, orders.flags)
+		assert.Equal(t, model.PaymentReviewOrderNotPayable, orders.order.PaymentReviewReason)
+	})
+
+	t.Run("redelivered success for a paid order is not flagged", func(t *testing.T) {
+		svc, orders := newService(pendingOrder())
+
+		assert.NoError(t, svc.applyWebhook(context.Background(), "stripe", webhook(5000)))
+		assert.NoError(t, svc.applyWebhook(context.Background(), "stripe", webhook(5000)))
+
+		assert.Equal(t, 0, orders.flags)
+		assert.Equal(t, []model.OrderStatus{model.OrderStatusProcessing}, orders.statuses)
+	})
+
+	t.Run("flagged order is not fulfilled by a later matching event", func(t *testing.T) {
+		order := pendingOrder()
+		order.PaymentReviewReason = model.PaymentReviewCurrencyMismatch
+		svc, orders := newService(order)
+
+		err := svc.applyWebhook(context.Background(), "stripe", webhook(5000))
+
+		assert.NoError(t, err)
+		assert.Empty(t, orders.statuses)
+	})
+}
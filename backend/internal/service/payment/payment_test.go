@@ -0,0 +1,464 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+	orderservice "github.com/leoferamos/aroma-sense/internal/service/order"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPaymentRepo struct {
+	payments []model.Payment
+	applied  map[string]int64
+	findErr  error
+	totals   []repository.OrderPaymentTotals
+}
+
+func (m *mockPaymentRepo) Create(payment *model.Payment) error {
+	m.payments = append(m.payments, *payment)
+	return nil
+}
+
+func (m *mockPaymentRepo) FindByIntentID(intentID string) (*model.Payment, error) {
+	if m.findErr != nil {
+		return nil, m.findErr
+	}
+	for i := range m.payments {
+		if m.payments[i].IntentID == intentID {
+			p := m.payments[i]
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockPaymentRepo) UpdateStatusByIntentID(intentID string, status model.PaymentStatus, errorCode, errorMessage string) error {
+	for i := range m.payments {
+		if m.payments[i].IntentID == intentID {
+			m.payments[i].Status = status
+			m.payments[i].ErrorCode = errorCode
+		}
+	}
+	return nil
+}
+
+func (m *mockPaymentRepo) AttachOrderPublicID(intentID string, orderPublicID string) error {
+	return nil
+}
+
+func (m *mockPaymentRepo) FindByOrderPublicID(orderPublicID string) ([]model.Payment, error) {
+	return m.payments, nil
+}
+
+func (m *mockPaymentRepo) ApplyRefund(intentID string, amountCents int64, issue func() error) error {
+	if m.applied == nil {
+		m.applied = map[string]int64{}
+	}
+	for i := range m.payments {
+		if m.payments[i].IntentID != intentID {
+			continue
+		}
+		if m.payments[i].RefundedCents+amountCents > m.payments[i].AmountCents {
+			return repository.ErrRefundExceedsPayment
+		}
+		if err := issue(); err != nil {
+			return err
+		}
+		m.payments[i].RefundedCents += amountCents
+		m.applied[intentID] += amountCents
+	}
+	return nil
+}
+
+func (m *mockPaymentRepo) SyncProviderRefund(intentID string, refundedCents int64) error {
+	for i := range m.payments {
+		if m.payments[i].IntentID == intentID {
+			m.payments[i].RefundedCents = max(m.payments[i].RefundedCents, min(refundedCents, m.payments[i].AmountCents))
+		}
+	}
+	return nil
+}
+
+func (m *mockPaymentRepo) FindExpiredPending(provider string, now time.Time, limit int) ([]model.Payment, error) {
+	return nil, nil
+}
+
+func (m *mockPaymentRepo) FindStaleOpen(providers []string, createdBefore time.Time, limit int) ([]model.Payment, error) {
+	return nil, nil
+}
+
+func (m *mockPaymentRepo) OrderTotals(from, to time.Time) ([]repository.OrderPaymentTotals, error) {
+	return m.totals, nil
+}
+
+type mockRefundRepo struct {
+	refunds   []model.Refund
+	succeeded []uint
+	failed    []uint
+}
+
+func (m *mockRefundRepo) Create(refund *model.Refund) error {
+	refund.ID = uint(len(m.refunds) + 1)
+	m.refunds = append(m.refunds, *refund)
+	return nil
+}
+
+func (m *mockRefundRepo) MarkSucceeded(id uint, providerRefundID string) error {
+	m.succeeded = append(m.succeeded, id)
+	return nil
+}
+
+func (m *mockRefundRepo) MarkFailed(id uint, errorMessage string) error {
+	m.failed = append(m.failed, id)
+	return nil
+}
+
+func (m *mockRefundRepo) ListByOrderPublicID(orderPublicID string) ([]model.Refund, error) {
+	return m.refunds, nil
+}
+
+type mockProvider struct {
+	refundErr error
+	refunds   []RefundParams
+	canceled  []string
+	webhook   *PaymentWebhookPayload
+	parseErr  error
+	current   *PaymentWebhookPayload
+}
+
+func (m *mockProvider) CreatePaymentIntent(ctx context.Context, params PaymentIntentParams) (*PaymentIntentResult, error) {
+	return &PaymentIntentResult{ID: "pi_test", ClientSecret: "secret"}, nil
+}
+
+func (m *mockProvider) ParseWebhook(payload []byte, signature string) (*PaymentWebhookPayload, error) {
+	if m.parseErr != nil {
+		return nil, m.parseErr
+	}
+	if m.webhook == nil {
+		return nil, errors.New("not implemented")
+	}
+	return m.webhook, nil
+}
+
+func (m *mockProvider) CancelPaymentIntent(ctx context.Context, intentID string) error {
+	m.canceled = append(m.canceled, intentID)
+	return nil
+}
+
+func (m *mockProvider) RefundPayment(ctx context.Context, params RefundParams) (*RefundResult, error) {
+	if m.refundErr != nil {
+		return nil, m.refundErr
+	}
+	m.refunds = append(m.refunds, params)
+	return &RefundResult{ID: "re_" + params.IntentID, Status: "succeeded", Amount: params.Amount}, nil
+}
+
+func (m *mockProvider) GetPaymentIntent(ctx context.Context, intentID string) (*PaymentWebhookPayload, error) {
+	if m.current == nil {
+		return nil, ErrIntentLookupUnsupported
+	}
+	return m.current, nil
+}
+
+func capturedPayments() []model.Payment {
+	orderID := "order-1"
+	return []model.Payment{
+		{ID: 1, IntentID: "pi_1", OrderPublicID: &orderID, AmountCents: 3000, Currency: "brl", Status: model.PaymentStatusSucceeded},
+		{ID: 2, IntentID: "pi_2", OrderPublicID: &orderID, AmountCents: 2000, Currency: "brl", Status: model.PaymentStatusFailed},
+		{ID: 3, IntentID: "pi_3", OrderPublicID: &orderID, AmountCents: 5000, RefundedCents: 1000, Currency: "brl", Status: model.PaymentStatusPartiallyRefunded},
+	}
+}
+
+func TestRefundOrder(t *testing.T) {
+	newSvc := func(payments *mockPaymentRepo, refunds *mockRefundRepo, provider *mockProvider) PaymentService {
+		return NewPaymentService(nil, nil, nil, nil, payments, refunds, nil, nil, nil, nil, nil, NewProviders("stripe", provider))
+	}
+	adminRefund := func(amount int64) orderservice.RefundRequest {
+		return orderservice.RefundRequest{AmountCents: amount, Reason: "damaged", ActorType: model.OrderEventActorAdmin, ActorID: "admin-1"}
+	}
+
+	t.Run("partial refund spans payments oldest first", func(t *testing.T) {
+		payments := &mockPaymentRepo{payments: capturedPayments()}
+		refunds := &mockRefundRepo{}
+		provider := &mockProvider{}
+		svc := newSvc(payments, refunds, provider)
+
+		outcome, err := svc.RefundOrder(context.Background(), "order-1", adminRefund(4000))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4000), outcome.RefundedCents)
+		assert.Equal(t, int64(3000), outcome.RemainingCents)
+		assert.Equal(t, map[string]int64{"pi_1": 3000, "pi_3": 1000}, payments.applied)
+		if assert.Len(t, refunds.refunds, 2) {
+			assert.Equal(t, "damaged", refunds.refunds[0].Reason)
+			assert.Equal(t, "admin-1", *refunds.refunds[0].ActorID)
+		}
+		assert.Equal(t, []uint{1, 2}, refunds.succeeded)
+	})
+
+	t.Run("zero amount refunds everything captured", func(t *testing.T) {
+		payments := &mockPaymentRepo{payments: capturedPayments()}
+		svc := newSvc(payments, &mockRefundRepo{}, &mockProvider{})
+
+		outcome, err := svc.RefundOrder(context.Background(), "order-1", adminRefund(0))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7000), outcome.RefundedCents)
+		assert.Equal(t, int64(0), outcome.RemainingCents)
+	})
+
+	t.Run("amount above captured is rejected", func(t *testing.T) {
+		provider := &mockProvider{}
+		svc := newSvc(&mockPaymentRepo{payments: capturedPayments()}, &mockRefundRepo{}, provider)
+
+		_, err := svc.RefundOrder(context.Background(), "order-1", adminRefund(7001))
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "refund_exceeds_captured", de.Code)
+		assert.Empty(t, provider.refunds)
+	})
+
+	t.Run("provider failure is recorded", func(t *testing.T) {
+		payments := &mockPaymentRepo{payments: capturedPayments()}
+		refunds := &mockRefundRepo{}
+		svc := newSvc(payments, refunds, &mockProvider{refundErr: errors.New("gateway down")})
+
+		outcome, err := svc.RefundOrder(context.Background(), "order-1", adminRefund(1000))
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "refund_failed", de.Code)
+		assert.Equal(t, int64(0), outcome.RefundedCents)
+		assert.Equal(t, []uint{1}, refunds.failed)
+		assert.Empty(t, payments.applied)
+	})
+
+	t.Run("refund that lost a race never reaches the provider", func(t *testing.T) {
+		current := capturedPayments()
+		current[0].RefundedCents = 3000
+		payments := &stalePaymentRepo{mockPaymentRepo: &mockPaymentRepo{payments: current}, snapshot: capturedPayments()}
+		refunds := &mockRefundRepo{}
+		provider := &mockProvider{}
+		svc := NewPaymentService(nil, nil, nil, nil, payments, refunds, nil, nil, nil, nil, nil, NewProviders("stripe", provider))
+
+		_, err := svc.RefundOrder(context.Background(), "order-1", adminRefund(3000))
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "refund_exceeds_captured", de.Code)
+		assert.Empty(t, provider.refunds)
+		assert.Equal(t, []uint{1}, refunds.failed)
+	})
+}
+
+// stalePaymentRepo lists payments as they were before a concurrent refund was booked.
+type stalePaymentRepo struct {
+	*mockPaymentRepo
+	snapshot []model.Payment
+}
+
+func (m *stalePaymentRepo) FindByOrderPublicID(orderPublicID string) ([]model.Payment, error) {
+	return m.snapshot, nil
+}
+
+func TestHandleWebhook_RefundBooking(t *testing.T) {
+	provider := &mockProvider{webhook: &PaymentWebhookPayload{IntentID: "pi_1", Status: "partially_refunded", Amount: 3000, RefundedAmount: 1200}}
+	payments := &mockPaymentRepo{payments: []model.Payment{{IntentID: "pi_1", Provider: "stripe", AmountCents: 3000, Status: model.PaymentStatusSucceeded}}}
+	svc := NewPaymentService(nil, nil, nil, nil, payments, &mockRefundRepo{}, nil, nil, nil, nil, nil, NewProviders("stripe", provider))
+
+	_, err := svc.HandleWebhook(context.Background(), "", []byte(`{}`), "sig")
+	assert.NoError(t, err)
+	assert.Equal(t, model.PaymentStatusPartiallyRefunded, payments.payments[0].Status)
+	assert.Equal(t, int64(1200), payments.payments[0].RefundedCents)
+
+	// A late event with a smaller total must not undo what was already booked.
+	provider.webhook = &PaymentWebhookPayload{IntentID: "pi_1", Status: "partially_refunded", Amount: 3000, RefundedAmount: 1000}
+	_, err = svc.HandleWebhook(context.Background(), "", []byte(`{}`), "sig")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1200), payments.payments[0].RefundedCents)
+}
+
+func TestCreateIntent_OrderStatus(t *testing.T) {
+	newService := func(order *model.Order) (PaymentService, *mockPaymentRepo) {
+		payments := &mockPaymentRepo{}
+		return NewPaymentService(nil, nil, &mockOrderRepo{order: order}, nil, payments, &mockRefundRepo{}, nil, nil, nil, nil, nil, NewProviders("stripe", &mockProvider{})), payments
+	}
+	order := func(status model.OrderStatus, reserved bool) *model.Order {
+		return &model.Order{ID: 1, PublicID: "order-1", UserID: "user-1", Status: status, StockReserved: reserved, TotalAmount: money.FromCents(5000)}
+	}
+	req := &dto.CreatePaymentIntentRequest{OrderPublicID: "order-1"}
+
+	t.Run("pending order with reserved stock can be paid", func(t *testing.T) {
+		svc, payments := newService(order(model.OrderStatusPending, true))
+
+		res, err := svc.CreateIntent(context.Background(), "user-1", req)
+		assert.NoError(t, err)
+		assert.Equal(t, "pi_test", res.ID)
+		assert.Len(t, payments.payments, 1)
+	})
+
+	for name, o := range map[string]*model.Order{
+		"cancelled order":      order(model.OrderStatusCancelled, false),
+		"order already paid":   order(model.OrderStatusProcessing, true),
+		"reservation released": order(model.OrderStatusPending, false),
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, payments := newService(o)
+
+			_, err := svc.CreateIntent(context.Background(), "user-1", req)
+			var de *apperror.DomainError
+			assert.ErrorAs(t, err, &de)
+			assert.Equal(t, "order_not_payable", de.Code)
+			assert.Empty(t, payments.payments)
+		})
+	}
+}
+
+func TestProviderRouting(t *testing.T) {
+	orderID := "order-1"
+
+	t.Run("webhook is parsed by the named gateway", func(t *testing.T) {
+		stripe := &mockProvider{}
+		pix := &mockProvider{webhook: &PaymentWebhookPayload{IntentID: "TX1", Status: "succeeded", Amount: 4990, Currency: "brl"}}
+		payments := &mockPaymentRepo{payments: []model.Payment{{IntentID: "TX1", Provider: "pix", AmountCents: 4990, Status: model.PaymentStatusPending}}}
+		svc := NewPaymentService(nil, nil, nil, nil, payments, &mockRefundRepo{}, nil, nil, nil, nil, nil, NewProviders("stripe", stripe).WithMethod(model.PaymentMethodPix, "pix", pix))
+
+		res, err := svc.HandleWebhook(context.Background(), "pix", []byte(`{}`), "sig")
+		assert.NoError(t, err)
+		assert.Equal(t, "TX1", res.IntentID)
+		assert.Equal(t, model.PaymentStatusSucceeded, payments.payments[0].Status)
+
+		_, err = svc.HandleWebhook(context.Background(), "", []byte(`{}`), "sig")
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "invalid_webhook", de.Code)
+
+		_, err = svc.HandleWebhook(context.Background(), "boleto", []byte(`{}`), "sig")
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "provider_unavailable", de.Code)
+	})
+
+	t.Run("cancel goes to the gateway that created the intent", func(t *testing.T) {
+		stripe := &mockProvider{}
+		pix := &mockProvider{}
+		payments := &mockPaymentRepo{payments: []model.Payment{
+			{IntentID: "pi_1", Provider: "stripe", OrderPublicID: &orderID, Status: model.PaymentStatusPending},
+			{IntentID: "TX1", Provider: "pix", OrderPublicID: &orderID, Status: model.PaymentStatusPending},
+		}}
+		svc := NewPaymentService(nil, nil, nil, nil, payments, &mockRefundRepo{}, nil, nil, nil, nil, nil, NewProviders("stripe", stripe).WithMethod(model.PaymentMethodPix, "pix", pix))
+
+		canceled, err := svc.CancelOrderIntents(context.Background(), orderID, "customer_cancelled", "order cancelled by customer")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"pi_1", "TX1"}, canceled)
+		assert.Equal(t, "customer_cancelled", payments.payments[0].ErrorCode)
+		assert.Equal(t, []string{"pi_1"}, stripe.canceled)
+		assert.Equal(t, []string{"TX1"}, pix.canceled)
+	})
+
+	t.Run("method without gateway falls back to the default", func(t *testing.T) {
+		stripe := &mockProvider{}
+		providers := NewProviders("stripe", stripe)
+		name, provider := providers.ForMethod(model.PaymentMethodBoleto)
+		assert.Equal(t, "stripe", name)
+		assert.Same(t, stripe, provider)
+
+		name, provider = NewProviders("stripe", nil).ForMethod(model.PaymentMethodCreditCard)
+		assert.Empty(t, name)
+		assert.Nil(t, provider)
+	})
+}
+
+func TestExpireIntent(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	newSvc := func(payments *mockPaymentRepo, provider *mockProvider) PaymentService {
+		return NewPaymentService(nil, nil, nil, nil, payments, &mockRefundRepo{}, nil, nil, nil, nil, nil, NewProviders("stripe", nil).WithMethod(model.PaymentMethodBoleto, "boleto", provider))
+	}
+
+	t.Run("pending boleto is canceled with reason", func(t *testing.T) {
+		payments := &mockPaymentRepo{payments: []model.Payment{{IntentID: "123", Provider: "boleto", Status: model.PaymentStatusPending, ExpiresAt: &expired}}}
+		provider := &mockProvider{}
+
+		err := newSvc(payments, provider).ExpireIntent(context.Background(), "123", "boleto_overdue")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"123"}, provider.canceled)
+		assert.Equal(t, model.PaymentStatusCanceled, payments.payments[0].Status)
+		assert.Equal(t, "boleto_overdue", payments.payments[0].ErrorCode)
+	})
+
+	t.Run("settled boleto is not canceled", func(t *testing.T) {
+		payments := &mockPaymentRepo{payments: []model.Payment{{IntentID: "123", Provider: "boleto", Status: model.PaymentStatusSucceeded}}}
+		provider := &mockProvider{}
+
+		err := newSvc(payments, provider).ExpireIntent(context.Background(), "123", "boleto_overdue")
+		assert.ErrorIs(t, err, ErrIntentNotCancelable)
+		assert.Empty(t, provider.canceled)
+	})
+
+	t.Run("already canceled is a no-op", func(t *testing.T) {
+		payments := &mockPaymentRepo{payments: []model.Payment{{IntentID: "123", Provider: "boleto", Status: model.PaymentStatusCanceled}}}
+		provider := &mockProvider{}
+
+		err := newSvc(payments, provider).ExpireIntent(context.Background(), "123", "boleto_overdue")
+		assert.NoError(t, err)
+		assert.Empty(t, provider.canceled)
+	})
+}
+
+func TestGetBoleto(t *testing.T) {
+	payments := &mockPaymentRepo{payments: []model.Payment{
+		{IntentID: "123", Provider: "boleto", UserID: "user-1", BoletoBarcode: "00193373700000001000500940144816060680935031"},
+		{IntentID: "pi_1", Provider: "stripe", UserID: "user-1"},
+	}}
+	svc := NewPaymentService(nil, nil, nil, nil, payments, &mockRefundRepo{}, nil, nil, nil, nil, nil, NewProviders("stripe", &mockProvider{}))
+
+	p, err := svc.GetBoleto(context.Background(), "user-1", "123")
+	assert.NoError(t, err)
+	assert.Equal(t, "123", p.IntentID)
+
+	for _, tc := range []struct{ user, intent string }{{"user-2", "123"}, {"user-1", "pi_1"}, {"user-1", "missing"}} {
+		_, err := svc.GetBoleto(context.Background(), tc.user, tc.intent)
+		var de *apperror.DomainError
+		assert.ErrorAs(t, err, &de)
+		assert.Equal(t, "boleto_not_found", de.Code)
+	}
+}
+
+type simulatingProvider struct {
+	mockProvider
+	outcome string
+}
+
+func (m *simulatingProvider) SimulateWebhook(intentID string, outcome string) ([]byte, string, error) {
+	m.outcome = outcome
+	m.webhook = &PaymentWebhookPayload{EventID: "evt_" + intentID, IntentID: intentID, Status: outcome, Amount: 5000, Currency: "brl"}
+	return []byte(`{}`), "sig", nil
+}
+
+func TestSimulatePayment(t *testing.T) {
+	payments := &mockPaymentRepo{payments: []model.Payment{
+		{IntentID: "pi_fake_1", Provider: "fake", AmountCents: 5000, Currency: "brl", Status: model.PaymentStatusPending},
+		{IntentID: "pi_real_1", Provider: "stripe", AmountCents: 5000, Currency: "brl", Status: model.PaymentStatusPending},
+	}}
+	simulator := &simulatingProvider{}
+	providers := NewProviders("fake", simulator).WithMethod(model.PaymentMethodDebitCard, "stripe", &mockProvider{})
+	svc := NewPaymentService(nil, nil, nil, nil, payments, &mockRefundRepo{}, nil, nil, nil, nil, nil, providers)
+
+	res, err := svc.SimulatePayment(context.Background(), "pi_fake_1", "succeeded")
+	assert.NoError(t, err)
+	assert.Equal(t, "succeeded", simulator.outcome)
+	assert.Equal(t, "evt_pi_fake_1", res.EventID)
+	assert.Equal(t, model.PaymentStatusSucceeded, payments.payments[0].Status)
+
+	_, err = svc.SimulatePayment(context.Background(), "pi_real_1", "succeeded")
+	var de *apperror.DomainError
+	assert.True(t, errors.As(err, &de))
+	assert.Equal(t, "simulation_unsupported", de.Code)
+
+	_, err = svc.SimulatePayment(context.Background(), "pi_missing", "succeeded")
+	assert.True(t, errors.As(err, &de))
+	assert.Equal(t, "payment_not_found", de.Code)
+}
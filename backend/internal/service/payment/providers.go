@@ -0,0 +1,71 @@
+package service
+
+import "github.com/leoferamos/aroma-sense/internal/model"
+
+// Providers routes payments to the gateway that handles them. The default gateway serves every
+// payment method without a dedicated one; payments remember the gateway name they were created with.
+type Providers struct {
+	defaultName string
+	named       map[string]PaymentProvider
+	methods     map[model.PaymentMethod]string
+}
+
+// NewProviders returns a registry with the given default gateway. The default may be nil when
+// only method-specific gateways are configured.
+func NewProviders(defaultName string, provider PaymentProvider) *Providers {
+	p := &Providers{
+		named:   map[string]PaymentProvider{},
+		methods: map[model.PaymentMethod]string{},
+	}
+	if provider != nil {
+		p.defaultName = defaultName
+		p.named[defaultName] = provider
+	}
+	return p
+}
+
+// WithMethod registers a gateway dedicated to a payment method.
+func (p *Providers) WithMethod(method model.PaymentMethod, name string, provider PaymentProvider) *Providers {
+	if provider == nil {
+		return p
+	}
+	p.named[name] = provider
+	p.methods[method] = name
+	return p
+}
+
+// Empty reports whether no gateway is configured at all.
+func (p *Providers) Empty() bool {
+	return p == nil || len(p.named) == 0
+}
+
+// ForMethod returns the gateway for a payment method and the name to store on the payment.
+func (p *Providers) ForMethod(method model.PaymentMethod) (string, PaymentProvider) {
+	if p == nil {
+		return "", nil
+	}
+	name, ok := p.methods[method]
+	if !ok {
+		name = p.defaultName
+	}
+	return name, p.named[name]
+}
+
+// DefaultName is the name of the default gateway, or empty when there is none.
+func (p *Providers) DefaultName() string {
+	if p == nil {
+		return ""
+	}
+	return p.defaultName
+}
+
+// ByName returns a gateway by the name stored on payments. An empty name means the default gateway.
+func (p *Providers) ByName(name string) PaymentProvider {
+	if p == nil {
+		return nil
+	}
+	if name == "" {
+		name = p.defaultName
+	}
+	return p.named[name]
+}
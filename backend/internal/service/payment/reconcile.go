@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/model"
+)
+
+// DiscrepancyKind classifies a mismatch between an order and its payments.
+type DiscrepancyKind string
+
+const (
+	// DiscrepancyAmountMismatch: the captured amount differs from the order total.
+	DiscrepancyAmountMismatch DiscrepancyKind = "amount_mismatch"
+	// DiscrepancyPaidWithoutCapture: the order moved past payment but no captured payment backs it.
+	DiscrepancyPaidWithoutCapture DiscrepancyKind = "paid_without_capture"
+	// DiscrepancyCapturedOnUnpaidOrder: money was kept for an order that is still pending or was cancelled.
+	DiscrepancyCapturedOnUnpaidOrder DiscrepancyKind = "captured_on_unpaid_order"
+)
+
+// Discrepancy is one order whose payments do not add up.
+type Discrepancy struct {
+	OrderPublicID string          `json:"order_public_id"`
+	OrderStatus   string          `json:"order_status"`
+	Kind          DiscrepancyKind `json:"kind"`
+	ExpectedCents int64           `json:"expected_cents"`
+	CapturedCents int64           `json:"captured_cents"`
+	RefundedCents int64           `json:"refunded_cents"`
+}
+
+// DiscrepancyReport compares the payments of the orders created in [From, To) against the order totals.
+type DiscrepancyReport struct {
+	From          time.Time     `json:"from"`
+	To            time.Time     `json:"to"`
+	OrdersChecked int           `json:"orders_checked"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
+
+// ReconcileIntent asks the gateway for the current state of an intent whose webhook may have been lost,
+// and applies it exactly as the webhook would have. It returns the payment's resulting status.
+func (s *paymentService) ReconcileIntent(ctx context.Context, intentID string) (model.PaymentStatus, error) {
+	if s.paymentRepo == nil {
+		return "", apperror.NewCodeMessage("payment_not_found", "payment not found")
+	}
+	payment, err := s.paymentRepo.FindByIntentID(intentID)
+	if err != nil {
+		return "", err
+	}
+	if payment == nil {
+		return "", apperror.NewCodeMessage("payment_not_found", "payment not found")
+	}
+	provider := s.providers.ByName(payment.Provider)
+	if provider == nil {
+		return payment.Status, apperror.NewCodeMessage("provider_unavailable", "payment provider not configured")
+	}
+
+	current, err := provider.GetPaymentIntent(ctx, payment.IntentID)
+	if err != nil {
+		return payment.Status, err
+	}
+	status := toPaymentStatus(current.Status)
+	if status == payment.Status {
+		return status, nil
+	}
+
+	current.IntentID = payment.IntentID
+	if err := s.applyWebhook(ctx, payment.Provider, current); err != nil {
+		return payment.Status, err
+	}
+	return status, nil
+}
+
+// DiscrepancyReport lists the orders created in [from, to) whose payments do not match the order.
+func (s *paymentService) DiscrepancyReport(from, to time.Time) (*DiscrepancyReport, error) {
+	report := &DiscrepancyReport{From: from, To: to, Discrepancies: []Discrepancy{}}
+	if s.paymentRepo == nil {
+		return report, nil
+	}
+	totals, err := s.paymentRepo.OrderTotals(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report.OrdersChecked = len(totals)
+	for _, t := range totals {
+		expected := t.TotalAmount.Cents()
+		kept := t.CapturedCents - t.RefundedCents
+
+		var kind DiscrepancyKind
+		switch t.OrderStatus {
+		case model.OrderStatusPending, model.OrderStatusCancelled:
+			if kept > 0 {
+				kind = DiscrepancyCapturedOnUnpaidOrder
+			}
+		default:
+			switch {
+			case t.CapturedCents == 0:
+				kind = DiscrepancyPaidWithoutCapture
+			case t.CapturedCents != expected:
+				kind = DiscrepancyAmountMismatch
+			}
+		}
+		if kind == "" {
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, Discrepancy{
+			OrderPublicID: t.OrderPublicID,
+			OrderStatus:   string(t.OrderStatus),
+			Kind:          kind,
+			ExpectedCents: expected,
+			CapturedCents: t.CapturedCents,
+			RefundedCents: t.RefundedCents,
+		})
+	}
+	return report, nil
+}
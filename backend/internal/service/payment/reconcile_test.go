@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileIntent(t *testing.T) {
+	setup := func(provider *mockProvider) (PaymentService, *mockPaymentRepo) {
+		payments := &mockPaymentRepo{payments: []model.Payment{
+			{IntentID: "pi_1", Provider: "stripe", AmountCents: 5000, Currency: "brl", Status: model.PaymentStatusPending},
+		}}
+		return NewPaymentService(nil, nil, nil, nil, payments, &mockRefundRepo{}, nil, nil, nil, nil, nil, NewProviders("stripe", provider)), payments
+	}
+
+	t.Run("applies the gateway state", func(t *testing.T) {
+		svc, payments := setup(&mockProvider{current: &PaymentWebhookPayload{IntentID: "pi_1", Status: "succeeded", Amount: 5000, Currency: "brl"}})
+
+		status, err := svc.ReconcileIntent(context.Background(), "pi_1")
+		assert.NoError(t, err)
+		assert.Equal(t, model.PaymentStatusSucceeded, status)
+		assert.Equal(t, model.PaymentStatusSucceeded, payments.payments[0].Status)
+	})
+
+	t.Run("unchanged intent", func(t *testing.T) {
+		svc, payments := setup(&mockProvider{current: &PaymentWebhookPayload{IntentID: "pi_1", Status: "requires_payment_method"}})
+
+		status, err := svc.ReconcileIntent(context.Background(), "pi_1")
+		assert.NoError(t, err)
+		assert.Equal(t, model.PaymentStatusPending, status)
+		assert.Equal(t, model.PaymentStatusPending, payments.payments[0].Status)
+	})
+
+	t.Run("gateway without lookup", func(t *testing.T) {
+		svc, _ := setup(&mockProvider{})
+
+		status, err := svc.ReconcileIntent(context.Background(), "pi_1")
+		assert.ErrorIs(t, err, ErrIntentLookupUnsupported)
+		assert.Equal(t, model.PaymentStatusPending, status)
+	})
+}
+
+func TestDiscrepancyReport(t *testing.T) {
+	payments := &mockPaymentRepo{totals: []repository.OrderPaymentTotals{
+		{OrderPublicID: "ok", OrderStatus: model.OrderStatusProcessing, TotalAmount: money.FromCents(12990), CapturedCents: 12990, PaymentCount: 1},
+		{OrderPublicID: "short", OrderStatus: model.OrderStatusShipped, TotalAmount: money.FromCents(12990), CapturedCents: 12000, PaymentCount: 1},
+		{OrderPublicID: "unbacked", OrderStatus: model.OrderStatusDelivered, TotalAmount: money.FromCents(5000), PaymentCount: 1},
+		{OrderPublicID: "stuck", OrderStatus: model.OrderStatusPending, TotalAmount: money.FromCents(8000), CapturedCents: 8000, PaymentCount: 1},
+		{OrderPublicID: "refunded", OrderStatus: model.OrderStatusCancelled, TotalAmount: money.FromCents(8000), CapturedCents: 8000, RefundedCents: 8000, PaymentCount: 1},
+		{OrderPublicID: "unpaid", OrderStatus: model.OrderStatusPending, TotalAmount: money.FromCents(8000)},
+	}}
+	svc := NewPaymentService(nil, nil, nil, nil, payments, &mockRefundRepo{}, nil, nil, nil, nil, nil, NewProviders("stripe", &mockProvider{}))
+
+	from := time.Date(2025, 12, 18, 0, 0, 0, 0, time.UTC)
+	report, err := svc.DiscrepancyReport(from, from.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, report.OrdersChecked)
+
+	kinds := map[string]DiscrepancyKind{}
+	for _, d := range report.Discrepancies {
+		kinds[d.OrderPublicID] = d.Kind
+	}
+	assert.Equal(t, map[string]DiscrepancyKind{
+		"short":    DiscrepancyAmountMismatch,
+		"unbacked": DiscrepancyPaidWithoutCapture,
+		"stuck":    DiscrepancyCapturedOnUnpaidOrder,
+	}, kinds)
+}
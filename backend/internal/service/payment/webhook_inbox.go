@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+)
+
+const (
+	// WebhookStaleAfter is how long an event may stay claimed before it is assumed abandoned by a crashed worker.
+	WebhookStaleAfter = 5 * time.Minute
+	// maxWebhookAttempts stops automatic retries; the event then waits for an admin replay.
+	maxWebhookAttempts = 10
+	webhookRetryBase   = time.Minute
+	webhookRetryMax    = time.Hour
+)
+
+// storeWebhookEvent records a verified event in the inbox. It reports false when the provider
+// already delivered an event with the same ID, returning the stored copy instead.
+func (s *paymentService) storeWebhookEvent(providerName string, payload []byte, normalized *PaymentWebhookPayload) (*model.WebhookEvent, bool, error) {
+	encoded, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, false, err
+	}
+	eventID := normalized.EventID
+	if eventID == "" {
+		eventID = payloadDigest(payload)
+	}
+
+	event := &model.WebhookEvent{
+		Provider:       providerName,
+		EventID:        eventID,
+		EventType:      normalized.EventType,
+		Payload:        string(payload),
+		Normalized:     encoded,
+		SignatureValid: true,
+		Status:         model.WebhookEventPending,
+	}
+	created, err := s.webhookRepo.CreateIfAbsent(event)
+	if err != nil {
+		return nil, false, err
+	}
+	return event, created, nil
+}
+
+// storeUnprocessable keeps a notification the provider could not parse, for inspection, and returns
+// the error for the caller. Events of unhandled types are acknowledged instead.
+func (s *paymentService) storeUnprocessable(providerName string, payload []byte, parseErr error) error {
+	status := model.WebhookEventRejected
+	if errors.Is(parseErr, ErrWebhookEventIgnored) {
+		status = model.WebhookEventIgnored
+	}
+	event := &model.WebhookEvent{
+		Provider:       providerName,
+		EventID:        payloadDigest(payload),
+		Payload:        string(payload),
+		SignatureValid: !errors.Is(parseErr, ErrWebhookSignature),
+		Status:         status,
+		LastError:      parseErr.Error(),
+	}
+	if _, err := s.webhookRepo.CreateIfAbsent(event); err != nil {
+		log.Printf("payment webhook: failed to store %s %s event: %v", status, providerName, err)
+	}
+
+	if status == model.WebhookEventIgnored {
+		return nil
+	}
+	return apperror.NewDomain(parseErr, "invalid_webhook", "invalid webhook payload")
+}
+
+// ProcessWebhookEvent applies a stored event that is pending or due for a retry.
+func (s *paymentService) ProcessWebhookEvent(ctx context.Context, id uint) error {
+	event, err := s.findWebhookEvent(id)
+	if err != nil {
+		return err
+	}
+	return s.processEvent(ctx, event)
+}
+
+// ListWebhookEvents returns inbox entries in the given status, or every entry when status is empty.
+func (s *paymentService) ListWebhookEvents(status string, page int, perPage int) (*dto.AdminWebhookEventsResponse, error) {
+	if s.webhookRepo == nil {
+		return nil, apperror.NewCodeMessage("provider_unavailable", "payment provider not configured")
+	}
+	events, totalCount, err := s.webhookRepo.List(status, page, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.WebhookEventResponse, 0, len(events))
+	for i := range events {
+		items = append(items, toWebhookEventResponse(&events[i]))
+	}
+
+	totalPages := 0
+	if perPage > 0 {
+		totalPages = int((totalCount + int64(perPage) - 1) / int64(perPage))
+	}
+
+	resp := &dto.AdminWebhookEventsResponse{Events: items}
+	resp.Meta.Pagination = dto.PaginationMeta{
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages,
+		TotalCount: int(totalCount),
+	}
+	return resp, nil
+}
+
+// ReplayWebhookEvent processes a failed event again right away, whether or not it still has
+// automatic retries left, and returns the event as it stands afterwards.
+func (s *paymentService) ReplayWebhookEvent(ctx context.Context, id uint) (*dto.WebhookEventResponse, error) {
+	event, err := s.findWebhookEvent(id)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status != model.WebhookEventFailed {
+		return nil, apperror.NewCodeMessage("webhook_event_not_replayable", "only failed webhook events can be replayed")
+	}
+
+	if err := s.processEvent(ctx, event); err != nil {
+		log.Printf("payment webhook: replay of %s event %s failed: %v", event.Provider, event.EventID, err)
+	}
+
+	updated, err := s.webhookRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	resp := toWebhookEventResponse(updated)
+	return &resp, nil
+}
+
+func (s *paymentService) findWebhookEvent(id uint) (*model.WebhookEvent, error) {
+	if s.webhookRepo == nil {
+		return nil, apperror.NewCodeMessage("provider_unavailable", "payment provider not configured")
+	}
+	event, err := s.webhookRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, apperror.NewCodeMessage("webhook_event_not_found", "webhook event not found")
+	}
+	return event, nil
+}
+
+// processEvent claims the event and applies it. Losing the claim is not an error: another worker
+// is handling the event or has already done so.
+func (s *paymentService) processEvent(ctx context.Context, event *model.WebhookEvent) error {
+	now := time.Now()
+	claimed, err := s.webhookRepo.Claim(event.ID, now.Add(-WebhookStaleAfter))
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+	attempts := event.Attempts + 1
+
+	var normalized PaymentWebhookPayload
+	if err := json.Unmarshal(event.Normalized, &normalized); err != nil {
+		err = fmt.Errorf("decode stored webhook event: %w", err)
+		if markErr := s.webhookRepo.MarkFailed(event.ID, err.Error(), nil); markErr != nil {
+			log.Printf("payment webhook: failed to mark event %d failed: %v", event.ID, markErr)
+		}
+		return err
+	}
+
+	if err := s.applyWebhook(ctx, event.Provider, &normalized); err != nil {
+		if markErr := s.webhookRepo.MarkFailed(event.ID, err.Error(), nextWebhookAttempt(attempts, now)); markErr != nil {
+			log.Printf("payment webhook: failed to mark event %d failed: %v", event.ID, markErr)
+		}
+		return err
+	}
+	return s.webhookRepo.MarkProcessed(event.ID)
+}
+
+// nextWebhookAttempt backs off exponentially from one minute up to an hour between attempts,
+// and returns nil once the event has used up its automatic retries.
+func nextWebhookAttempt(attempts int, now time.Time) *time.Time {
+	if attempts >= maxWebhookAttempts {
+		return nil
+	}
+	next := now.Add(min(webhookRetryBase<<(attempts-1), webhookRetryMax))
+	return &next
+}
+
+// payloadDigest identifies notifications that carry no event ID of their own.
+func payloadDigest(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func toWebhookEventResponse(e *model.WebhookEvent) dto.WebhookEventResponse {
+	return dto.WebhookEventResponse{
+		ID:             e.ID,
+		Provider:       e.Provider,
+		EventID:        e.EventID,
+		EventType:      e.EventType,
+		Status:         string(e.Status),
+		SignatureValid: e.SignatureValid,
+		Attempts:       e.Attempts,
+		LastError:      e.LastError,
+		Payload:        e.Payload,
+		NextAttemptAt:  e.NextAttemptAt,
+		ProcessedAt:    e.ProcessedAt,
+		CreatedAt:      e.CreatedAt,
+		UpdatedAt:      e.UpdatedAt,
+	}
+}
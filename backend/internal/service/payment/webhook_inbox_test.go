@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockWebhookRepo struct {
+	events []model.WebhookEvent
+}
+
+func (m *mockWebhookRepo) CreateIfAbsent(event *model.WebhookEvent) (bool, error) {
+	for _, e := range m.events {
+		if e.Provider == event.Provider && e.EventID == event.EventID {
+			return false, nil
+		}
+	}
+	event.ID = uint(len(m.events) + 1)
+	m.events = append(m.events, *event)
+	return true, nil
+}
+
+func (m *mockWebhookRepo) FindByID(id uint) (*model.WebhookEvent, error) {
+	for i := range m.events {
+		if m.events[i].ID == id {
+			e := m.events[i]
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockWebhookRepo) Claim(id uint, staleBefore time.Time) (bool, error) {
+	e := m.find(id)
+	if e == nil || (e.Status != model.WebhookEventPending && e.Status != model.WebhookEventFailed) {
+		return false, nil
+	}
+	e.Status = model.WebhookEventProcessing
+	e.Attempts++
+	return true, nil
+}
+
+func (m *mockWebhookRepo) MarkProcessed(id uint) error {
+	e := m.find(id)
+	now := time.Now()
+	e.Status = model.WebhookEventProcessed
+	e.LastError = ""
+	e.NextAttemptAt = nil
+	e.ProcessedAt = &now
+	return nil
+}
+
+func (m *mockWebhookRepo) MarkFailed(id uint, errorMessage string, nextAttemptAt *time.Time) error {
+	e := m.find(id)
+	e.Status = model.WebhookEventFailed
+	e.LastError = errorMessage
+	e.NextAttemptAt = nextAttemptAt
+	return nil
+}
+
+func (m *mockWebhookRepo) FindDue(now time.Time, staleBefore time.Time, limit int) ([]model.WebhookEvent, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookRepo) List(status string, page int, perPage int) ([]model.WebhookEvent, int64, error) {
+	var out []model.WebhookEvent
+	for _, e := range m.events {
+		if status == "" || string(e.Status) == status {
+			out = append(out, e)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
+func (m *mockWebhookRepo) find(id uint) *model.WebhookEvent {
+	for i := range m.events {
+		if m.events[i].ID == id {
+			return &m.events[i]
+		}
+	}
+	return nil
+}
+
+func succeededWebhook() *PaymentWebhookPayload {
+	return &PaymentWebhookPayload{
+		EventID:   "evt_1",
+		EventType: "payment_intent.succeeded",
+		IntentID:  "pi_1",
+		Status:    "succeeded",
+		Amount:    5000,
+		Currency:  "brl",
+		Metadata:  map[string]string{"user_id": "user-1"},
+	}
+}
+
+func TestHandleWebhook_Inbox(t *testing.T) {
+	setup := func(provider *mockProvider) (PaymentService, *mockPaymentRepo, *mockWebhookRepo) {
+		payments := &mockPaymentRepo{}
+		webhooks := &mockWebhookRepo{}
+		return NewPaymentService(nil, nil, nil, nil, payments, &mockRefundRepo{}, webhooks, nil, nil, nil, nil, NewProviders("stripe", provider)), payments, webhooks
+	}
+
+	t.Run("stores and applies each event once", func(t *testing.T) {
+		svc, payments, webhooks := setup(&mockProvider{webhook: succeededWebhook()})
+
+		_, err := svc.HandleWebhook(context.Background(), "", []byte(`{"id":"evt_1"}`), "sig")
+		assert.NoError(t, err)
+		_, err = svc.HandleWebhook(context.Background(), "", []byte(`{"id":"evt_1"}`), "sig")
+		assert.NoError(t, err)
+
+		assert.Len(t, payments.payments, 1)
+		assert.Equal(t, model.PaymentStatusSucceeded, payments.payments[0].Status)
+		assert.Len(t, webhooks.events, 1)
+		assert.Equal(t, "stripe", webhooks.events[0].Provider)
+		assert.Equal(t, model.WebhookEventProcessed, webhooks.events[0].Status)
+		assert.True(t, webhooks.events[0].SignatureValid)
+		assert.Equal(t, 1, webhooks.events[0].Attempts)
+	})
+
+	t.Run("failed processing is acknowledged and retried", func(t *testing.T) {
+		svc, payments, webhooks := setup(&mockProvider{webhook: succeededWebhook()})
+		payments.findErr = errors.New("connection reset")
+
+		_, err := svc.HandleWebhook(context.Background(), "", []byte(`{"id":"evt_1"}`), "sig")
+		assert.NoError(t, err)
+		assert.Equal(t, model.WebhookEventFailed, webhooks.events[0].Status)
+		assert.Equal(t, "connection reset", webhooks.events[0].LastError)
+		assert.NotNil(t, webhooks.events[0].NextAttemptAt)
+
+		payments.findErr = nil
+		assert.NoError(t, svc.ProcessWebhookEvent(context.Background(), webhooks.events[0].ID))
+		assert.Equal(t, model.WebhookEventProcessed, webhooks.events[0].Status)
+		assert.Equal(t, 2, webhooks.events[0].Attempts)
+		assert.Len(t, payments.payments, 1)
+	})
+
+	t.Run("bad signature is stored as rejected", func(t *testing.T) {
+		svc, payments, webhooks := setup(&mockProvider{parseErr: fmt.Errorf("stripe webhook: %w", ErrWebhookSignature)})
+
+		_, err := svc.HandleWebhook(context.Background(), "", []byte(`{"id":"evt_forged"}`), "bad")
+		var de *apperror.DomainError
+		assert.True(t, errors.As(err, &de))
+		assert.Equal(t, "invalid_webhook", de.Code)
+
+		assert.Empty(t, payments.payments)
+		assert.Len(t, webhooks.events, 1)
+		assert.Equal(t, model.WebhookEventRejected, webhooks.events[0].Status)
+		assert.False(t, webhooks.events[0].SignatureValid)
+		assert.Contains(t, webhooks.events[0].EventID, "sha256:")
+	})
+
+	t.Run("unhandled event types are acknowledged", func(t *testing.T) {
+		svc, _, webhooks := setup(&mockProvider{parseErr: fmt.Errorf("stripe webhook event customer.created: %w", ErrWebhookEventIgnored)})
+
+		res, err := svc.HandleWebhook(context.Background(), "", []byte(`{"id":"evt_2"}`), "sig")
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, model.WebhookEventIgnored, webhooks.events[0].Status)
+		assert.True(t, webhooks.events[0].SignatureValid)
+	})
+}
+
+func TestReplayWebhookEvent(t *testing.T) {
+	payments := &mockPaymentRepo{findErr: errors.New("connection reset")}
+	webhooks := &mockWebhookRepo{}
+	svc := NewPaymentService(nil, nil, nil, nil, payments, &mockRefundRepo{}, webhooks, nil, nil, nil, nil, NewProviders("stripe", &mockProvider{webhook: succeededWebhook()}))
+
+	_, err := svc.HandleWebhook(context.Background(), "", []byte(`{"id":"evt_1"}`), "sig")
+	assert.NoError(t, err)
+	id := webhooks.events[0].ID
+
+	payments.findErr = nil
+	res, err := svc.ReplayWebhookEvent(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, "processed", res.Status)
+	assert.Equal(t, 2, res.Attempts)
+
+	_, err = svc.ReplayWebhookEvent(context.Background(), id)
+	var de *apperror.DomainError
+	assert.True(t, errors.As(err, &de))
+	assert.Equal(t, "webhook_event_not_replayable", de.Code)
+
+	_, err = svc.ReplayWebhookEvent(context.Background(), 99)
+	assert.True(t, errors.As(err, &de))
+	assert.Equal(t, "webhook_event_not_found", de.Code)
+}
+
+func TestNextWebhookAttempt(t *testing.T) {
+	now := time.Date(2025, 12, 18, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, now.Add(time.Minute), *nextWebhookAttempt(1, now))
+	assert.Equal(t, now.Add(8*time.Minute), *nextWebhookAttempt(4, now))
+	assert.Equal(t, now.Add(time.Hour), *nextWebhookAttempt(9, now))
+	assert.Nil(t, nextWebhookAttempt(maxWebhookAttempts, now))
+}
@@ -7,11 +7,16 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
+	"github.com/leoferamos/aroma-sense/internal/apperror"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/integrations/ai/embeddings"
+	"github.com/leoferamos/aroma-sense/internal/model"
 	"github.com/leoferamos/aroma-sense/internal/repository"
 	"github.com/leoferamos/aroma-sense/internal/storage"
 	"github.com/leoferamos/aroma-sense/internal/utils"
@@ -24,70 +29,65 @@ type ProductService interface {
 	GetProductBySlug(ctx context.Context, slug string) (dto.ProductResponse, error)
 	GetProductIDBySlug(ctx context.Context, slug string) (uint, error)
 	GetLatestProducts(ctx context.Context, page int, limit int) ([]dto.ProductResponse, int, error)
-	SearchProducts(ctx context.Context, query string, page int, limit int, sort string) ([]dto.ProductResponse, int, error)
+	SearchProducts(ctx context.Context, query string, filter dto.ProductFilter, page int, limit int, sort string) (dto.ProductListResponse, error)
 	AdminListProducts(ctx context.Context, page int, limit int) ([]dto.ProductResponse, int, error)
 	UpdateProduct(ctx context.Context, id uint, input dto.UpdateProductRequest) error
 	DeleteProduct(ctx context.Context, id uint) error
+	AddVariant(ctx context.Context, productID uint, input dto.ProductVariantRequest) (dto.ProductVariantResponse, error)
+	UpdateVariant(ctx context.Context, productID uint, sku string, input dto.UpdateProductVariantRequest) (dto.ProductVariantResponse, error)
+	DeleteVariant(ctx context.Context, productID uint, sku string) error
+	AddImage(ctx context.Context, productID uint, input dto.ProductImageFormDTO, file dto.FileUpload) (dto.ProductImageResponse, error)
+	ReplaceImage(ctx context.Context, productID uint, imageID uint, input dto.ProductImageFormDTO, file dto.FileUpload) (dto.ProductImageResponse, error)
+	UpdateImage(ctx context.Context, productID uint, imageID uint, input dto.UpdateProductImageRequest) (dto.ProductImageResponse, error)
+	ReorderImages(ctx context.Context, productID uint, input dto.ReorderProductImagesRequest) ([]dto.ProductImageResponse, error)
+	DeleteImage(ctx context.Context, productID uint, imageID uint) error
+	Suggest(ctx context.Context, query string, limit int) (dto.SuggestResponse, error)
+}
+
+type suggestCacheEntry struct {
+	resp    dto.SuggestResponse
+	staleAt time.Time
 }
 
 type productService struct {
 	repo       repository.ProductRepository
 	storage    storage.ImageStorage
 	embeddings embeddings.Provider
+
+	suggestMu    sync.RWMutex
+	suggestCache map[string]suggestCacheEntry
+	suggestTTL   time.Duration
 }
 
 func NewProductService(repo repository.ProductRepository, storage storage.ImageStorage, embProvider embeddings.Provider) ProductService {
-	return &productService{repo: repo, storage: storage, embeddings: embProvider}
+	return &productService{
+		repo:         repo,
+		storage:      storage,
+		embeddings:   embProvider,
+		suggestCache: make(map[string]suggestCacheEntry),
+		suggestTTL:   time.Minute,
+	}
 }
 
 func (s *productService) CreateProduct(ctx context.Context, input dto.ProductFormDTO, file dto.FileUpload) error {
-	// Validate the file upload
-	if err := file.Validate(); err != nil {
-		return err
-	}
-
-	// Read first 512 bytes to detect actual content type
-	buf := make([]byte, 512)
-	n, err := file.Content.Read(buf)
-	if err != nil && err != io.EOF {
-		return fmt.Errorf("failed to read image: %w", err)
-	}
-	detectedType := http.DetectContentType(buf[:n])
-
-	// Verify the detected type matches the provided content type
-	if detectedType != file.ContentType {
-		return fmt.Errorf("content type mismatch: detected %s, provided %s", detectedType, file.ContentType)
+	if input.SKU != "" {
+		if err := s.ensureSKUAvailable(input.SKU); err != nil {
+			return err
+		}
 	}
 
-	// Generate a unique name for the image
-	uuidStr := uuid.New().String()
-	var ext string
-	switch file.ContentType {
-	case "image/jpeg":
-		ext = ".jpg"
-	case "image/png":
-		ext = ".png"
-	default:
-		ext = ""
-	}
-	imageName := fmt.Sprintf("product-%s%s", uuidStr, ext)
-
-	combinedReader := io.MultiReader(
-		bytes.NewReader(buf[:n]),
-		file.Content,
-	)
-
-	// Upload the image and thumbnail to storage
-	origURL, thumbURL, err := s.storage.UploadImageWithThumbnail(ctx, imageName, combinedReader, file.Size, file.ContentType, 256, 256)
+	origURL, thumbURL, err := s.uploadImage(ctx, file)
 	if err != nil {
-		return fmt.Errorf("failed to upload image: %w", err)
+		return err
 	}
 
 	// Call the repository to save to database
 	productID, err := s.repo.Create(input, origURL, thumbURL)
 	if err != nil {
+		s.deleteStoredImage(ctx, origURL, thumbURL)
 		return err
 	}
+	s.clearSuggestCache()
 
 	// Generate embedding asynchronously
 	go func() {
@@ -165,6 +165,8 @@ func (s *productService) GetProductByID(ctx context.Context, id uint) (dto.Produ
 		NotesBase:     product.NotesBase,
 		Category:      product.Category,
 		StockQuantity: product.StockQuantity,
+		Variants:      dto.ProductVariantResponsesFromModel(product.Variants),
+		Images:        dto.ProductImageResponsesFromModel(product.Images),
 		CreatedAt:     product.CreatedAt,
 		UpdatedAt:     product.UpdatedAt,
 	}, nil
@@ -197,6 +199,8 @@ func (s *productService) GetProductBySlug(ctx context.Context, slug string) (dto
 		NotesBase:     product.NotesBase,
 		Category:      product.Category,
 		StockQuantity: product.StockQuantity,
+		Variants:      dto.ProductVariantResponsesFromModel(product.Variants),
+		Images:        dto.ProductImageResponsesFromModel(product.Images),
 		CreatedAt:     product.CreatedAt,
 		UpdatedAt:     product.UpdatedAt,
 	}, nil
@@ -254,6 +258,8 @@ func (s *productService) GetLatestProducts(ctx context.Context, page int, limit
 			NotesBase:     p.NotesBase,
 			Category:      p.Category,
 			StockQuantity: p.StockQuantity,
+			Variants:      dto.ProductVariantResponsesFromModel(p.Variants),
+			Images:        dto.ProductImageResponsesFromModel(p.Images),
 			CreatedAt:     p.CreatedAt,
 		})
 	}
@@ -278,21 +284,12 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, input dto.U
 		product.Brand = *input.Brand
 		brandChanged = true
 	}
-	if input.Weight != nil {
-		product.Weight = *input.Weight
-	}
 	if input.Description != nil {
 		product.Description = *input.Description
 	}
-	if input.Price != nil {
-		product.Price = *input.Price
-	}
 	if input.Category != nil {
 		product.Category = *input.Category
 	}
-	if input.StockQuantity != nil {
-		product.StockQuantity = *input.StockQuantity
-	}
 	if input.Accords != nil {
 		product.Accords = *input.Accords
 	}
@@ -331,7 +328,30 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, input dto.U
 		}
 	}
 
-	return s.repo.Update(&product)
+	if err := s.repo.Update(&product); err != nil {
+		return err
+	}
+	s.clearSuggestCache()
+
+	// Weight, price and stock belong to the default variant
+	if (input.Weight != nil || input.Price != nil || input.StockQuantity != nil) && len(product.Variants) > 0 {
+		variant := product.Variants[0]
+		var fields []string
+		if input.Weight != nil {
+			variant.Weight = *input.Weight
+			fields = append(fields, "Weight")
+		}
+		if input.Price != nil {
+			variant.Price = *input.Price
+			fields = append(fields, "Price")
+		}
+		if input.StockQuantity != nil {
+			variant.StockQuantity = *input.StockQuantity
+			fields = append(fields, "StockQuantity")
+		}
+		return s.repo.UpdateVariant(&variant, fields)
+	}
+	return nil
 }
 
 // DeleteProduct removes a product by its ID
@@ -342,31 +362,23 @@ func (s *productService) DeleteProduct(ctx context.Context, id uint) error {
 	}
 
 	// Delete images from storage first
-	if product.ImageURL != "" {
-		// Extract image name from URL
-		imageName := extractImageNameFromURL(product.ImageURL)
-		if imageName != "" {
-			if err := s.storage.DeleteImage(ctx, imageName); err != nil {
-				fmt.Printf("Warning: failed to delete image %s: %v\n", imageName, err)
-			}
-		}
+	for _, image := range product.Images {
+		s.deleteStoredImage(ctx, image.ImageURL, image.ThumbnailURL)
 	}
-
-	// Also delete thumbnail if it exists and is different from main image
-	if product.ThumbnailURL != "" && product.ThumbnailURL != product.ImageURL {
-		thumbName := extractImageNameFromURL(product.ThumbnailURL)
-		if thumbName != "" {
-			if err := s.storage.DeleteImage(ctx, thumbName); err != nil {
-				fmt.Printf("Warning: failed to delete thumbnail %s: %v\n", thumbName, err)
-			}
-		}
+	if len(product.Images) == 0 {
+		s.deleteStoredImage(ctx, product.ImageURL, product.ThumbnailURL)
 	}
 
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.clearSuggestCache()
+	return nil
 }
 
-// SearchProducts performs a product search with pagination and sorting.
-func (s *productService) SearchProducts(ctx context.Context, query string, page int, limit int, sort string) ([]dto.ProductResponse, int, error) {
+// SearchProducts performs a product search combined with the structured filters, with pagination,
+// sorting and facet counts.
+func (s *productService) SearchProducts(ctx context.Context, query string, filter dto.ProductFilter, page int, limit int, sort string) (dto.ProductListResponse, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -381,9 +393,13 @@ func (s *productService) SearchProducts(ctx context.Context, query string, page
 
 	offset := (page - 1) * limit
 
-	products, total, err := s.repo.SearchProducts(ctx, query, limit, offset, sort)
+	products, total, err := s.repo.FilterProducts(ctx, query, filter, limit, offset, sort)
+	if err != nil {
+		return dto.ProductListResponse{}, fmt.Errorf("failed to search products: %w", err)
+	}
+	facets, err := s.repo.FacetCounts(ctx, query, filter)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to search products: %w", err)
+		return dto.ProductListResponse{}, fmt.Errorf("failed to count facets: %w", err)
 	}
 
 	var resp []dto.ProductResponse
@@ -408,12 +424,20 @@ func (s *productService) SearchProducts(ctx context.Context, query string, page
 			NotesBase:     p.NotesBase,
 			Category:      p.Category,
 			StockQuantity: p.StockQuantity,
+			Variants:      dto.ProductVariantResponsesFromModel(p.Variants),
+			Images:        dto.ProductImageResponsesFromModel(p.Images),
 			CreatedAt:     p.CreatedAt,
 			UpdatedAt:     p.UpdatedAt,
 		})
 	}
 
-	return resp, total, nil
+	return dto.ProductListResponse{
+		Items:  resp,
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+		Facets: &facets,
+	}, nil
 }
 
 // AdminListProducts returns all products with IDs for admin management
@@ -460,6 +484,8 @@ func (s *productService) AdminListProducts(ctx context.Context, page int, limit
 			NotesBase:     p.NotesBase,
 			Category:      p.Category,
 			StockQuantity: p.StockQuantity,
+			Variants:      dto.ProductVariantResponsesFromModel(p.Variants),
+			Images:        dto.ProductImageResponsesFromModel(p.Images),
 			CreatedAt:     p.CreatedAt,
 			UpdatedAt:     p.UpdatedAt,
 		})
@@ -468,6 +494,416 @@ func (s *productService) AdminListProducts(ctx context.Context, page int, limit
 	return resp, total, nil
 }
 
+// AddVariant adds a size or concentration to a product
+func (s *productService) AddVariant(ctx context.Context, productID uint, input dto.ProductVariantRequest) (dto.ProductVariantResponse, error) {
+	product, err := s.repo.FindByID(productID)
+	if err != nil {
+		return dto.ProductVariantResponse{}, apperror.NewDomain(fmt.Errorf("product not found: %w", err), "product_not_found", "product not found")
+	}
+	if err := s.ensureSKUAvailable(input.SKU); err != nil {
+		return dto.ProductVariantResponse{}, err
+	}
+
+	variant := model.ProductVariant{
+		ProductID:     product.ID,
+		SKU:           input.SKU,
+		SizeML:        input.SizeML,
+		Concentration: model.Concentration(input.Concentration),
+		Price:         input.Price,
+		StockQuantity: input.StockQuantity,
+		Weight:        input.Weight,
+		Position:      input.Position,
+	}
+	if input.Barcode != "" {
+		variant.Barcode = &input.Barcode
+	}
+	if err := s.repo.CreateVariant(&variant); err != nil {
+		return dto.ProductVariantResponse{}, fmt.Errorf("failed to add variant: %w", err)
+	}
+	return dto.ProductVariantResponseFromModel(variant), nil
+}
+
+// UpdateVariant updates the size, concentration, price, stock or weight of a product variant
+func (s *productService) UpdateVariant(ctx context.Context, productID uint, sku string, input dto.UpdateProductVariantRequest) (dto.ProductVariantResponse, error) {
+	_, variant, err := s.findVariant(productID, sku)
+	if err != nil {
+		return dto.ProductVariantResponse{}, err
+	}
+
+	// Only the fields present in the request are written; the loaded stock may already be stale.
+	var fields []string
+	if input.SizeML != nil {
+		variant.SizeML = *input.SizeML
+		fields = append(fields, "SizeML")
+	}
+	if input.Concentration != nil {
+		variant.Concentration = model.Concentration(*input.Concentration)
+		fields = append(fields, "Concentration")
+	}
+	if input.Price != nil {
+		variant.Price = *input.Price
+		fields = append(fields, "Price")
+	}
+	if input.StockQuantity != nil {
+		variant.StockQuantity = *input.StockQuantity
+		fields = append(fields, "StockQuantity")
+	}
+	if input.Weight != nil {
+		variant.Weight = *input.Weight
+		fields = append(fields, "Weight")
+	}
+	if input.Barcode != nil {
+		if *input.Barcode == "" {
+			variant.Barcode = nil
+		} else {
+			variant.Barcode = input.Barcode
+		}
+		fields = append(fields, "Barcode")
+	}
+	if input.Position != nil {
+		variant.Position = *input.Position
+		fields = append(fields, "Position")
+	}
+
+	if err := s.repo.UpdateVariant(&variant, fields); err != nil {
+		return dto.ProductVariantResponse{}, fmt.Errorf("failed to update variant: %w", err)
+	}
+	if fresh, err := s.repo.FindVariantBySKU(variant.SKU); err == nil && fresh != nil {
+		variant = *fresh
+	}
+	return dto.ProductVariantResponseFromModel(variant), nil
+}
+
+// DeleteVariant removes a variant from a product. A product always keeps at least one variant.
+func (s *productService) DeleteVariant(ctx context.Context, productID uint, sku string) error {
+	product, variant, err := s.findVariant(productID, sku)
+	if err != nil {
+		return err
+	}
+	if len(product.Variants) == 1 {
+		return apperror.NewCodeMessage("last_variant", "a product must keep at least one variant")
+	}
+	return s.repo.DeleteVariant(&variant)
+}
+
+// findVariant returns a product and one of its variants by SKU
+func (s *productService) findVariant(productID uint, sku string) (model.Product, model.ProductVariant, error) {
+	product, err := s.repo.FindByID(productID)
+	if err != nil {
+		return model.Product{}, model.ProductVariant{}, apperror.NewDomain(fmt.Errorf("product not found: %w", err), "product_not_found", "product not found")
+	}
+	for _, v := range product.Variants {
+		if v.SKU == sku {
+			return product, v, nil
+		}
+	}
+	return model.Product{}, model.ProductVariant{}, apperror.NewCodeMessage("variant_not_found", "variant not found")
+}
+
+// ensureSKUAvailable rejects SKUs already used by any product variant
+func (s *productService) ensureSKUAvailable(sku string) error {
+	existing, err := s.repo.FindVariantBySKU(sku)
+	if err != nil {
+		return fmt.Errorf("failed to check sku: %w", err)
+	}
+	if existing != nil {
+		return apperror.NewCodeMessage("variant_sku_taken", "sku already exists")
+	}
+	return nil
+}
+
+// AddImage uploads an image to a product's gallery. The first image of a gallery is always primary.
+func (s *productService) AddImage(ctx context.Context, productID uint, input dto.ProductImageFormDTO, file dto.FileUpload) (dto.ProductImageResponse, error) {
+	product, err := s.repo.FindByID(productID)
+	if err != nil {
+		return dto.ProductImageResponse{}, apperror.NewDomain(fmt.Errorf("product not found: %w", err), "product_not_found", "product not found")
+	}
+
+	origURL, thumbURL, err := s.uploadImage(ctx, file)
+	if err != nil {
+		return dto.ProductImageResponse{}, err
+	}
+
+	image := model.ProductImage{
+		ProductID:    product.ID,
+		ImageURL:     origURL,
+		ThumbnailURL: thumbURL,
+		AltText:      input.AltText,
+		Position:     nextImagePosition(product.Images),
+		IsPrimary:    input.IsPrimary || len(product.Images) == 0,
+	}
+	if err := s.repo.CreateImage(&image); err != nil {
+		s.deleteStoredImage(ctx, origURL, thumbURL)
+		return dto.ProductImageResponse{}, fmt.Errorf("failed to add image: %w", err)
+	}
+	return dto.ProductImageResponseFromModel(image), nil
+}
+
+// ReplaceImage uploads a new file for a gallery image, keeping its position, and removes the old
+// file from storage. A non-empty alt text replaces the current one.
+func (s *productService) ReplaceImage(ctx context.Context, productID uint, imageID uint, input dto.ProductImageFormDTO, file dto.FileUpload) (dto.ProductImageResponse, error) {
+	_, image, err := s.findImage(productID, imageID)
+	if err != nil {
+		return dto.ProductImageResponse{}, err
+	}
+
+	origURL, thumbURL, err := s.uploadImage(ctx, file)
+	if err != nil {
+		return dto.ProductImageResponse{}, err
+	}
+
+	oldURL, oldThumbURL := image.ImageURL, image.ThumbnailURL
+	image.ImageURL = origURL
+	image.ThumbnailURL = thumbURL
+	if input.AltText != "" {
+		image.AltText = input.AltText
+	}
+	if input.IsPrimary {
+		image.IsPrimary = true
+	}
+	if err := s.repo.UpdateImage(&image); err != nil {
+		s.deleteStoredImage(ctx, origURL, thumbURL)
+		return dto.ProductImageResponse{}, fmt.Errorf("failed to replace image: %w", err)
+	}
+	s.deleteStoredImage(ctx, oldURL, oldThumbURL)
+	return dto.ProductImageResponseFromModel(image), nil
+}
+
+// UpdateImage changes the alt text of a gallery image or makes it the primary image
+func (s *productService) UpdateImage(ctx context.Context, productID uint, imageID uint, input dto.UpdateProductImageRequest) (dto.ProductImageResponse, error) {
+	_, image, err := s.findImage(productID, imageID)
+	if err != nil {
+		return dto.ProductImageResponse{}, err
+	}
+
+	if input.AltText != nil {
+		image.AltText = *input.AltText
+	}
+	if input.IsPrimary != nil {
+		// The primary flag moves to another image rather than being cleared
+		if !*input.IsPrimary && image.IsPrimary {
+			return dto.ProductImageResponse{}, apperror.NewCodeMessage("primary_image_required", "set another image as primary instead")
+		}
+		image.IsPrimary = *input.IsPrimary
+	}
+
+	if err := s.repo.UpdateImage(&image); err != nil {
+		return dto.ProductImageResponse{}, fmt.Errorf("failed to update image: %w", err)
+	}
+	return dto.ProductImageResponseFromModel(image), nil
+}
+
+// ReorderImages sets the display order of a product gallery. Every image must be listed once.
+func (s *productService) ReorderImages(ctx context.Context, productID uint, input dto.ReorderProductImagesRequest) ([]dto.ProductImageResponse, error) {
+	product, err := s.repo.FindByID(productID)
+	if err != nil {
+		return nil, apperror.NewDomain(fmt.Errorf("product not found: %w", err), "product_not_found", "product not found")
+	}
+
+	if len(input.ImageIDs) != len(product.Images) {
+		return nil, apperror.NewCodeMessage("invalid_image_order", "every gallery image must be listed once")
+	}
+	seen := make(map[uint]bool, len(input.ImageIDs))
+	for _, id := range input.ImageIDs {
+		if product.Image(id) == nil || seen[id] {
+			return nil, apperror.NewCodeMessage("invalid_image_order", "every gallery image must be listed once")
+		}
+		seen[id] = true
+	}
+
+	if err := s.repo.ReorderImages(product.ID, input.ImageIDs); err != nil {
+		return nil, fmt.Errorf("failed to reorder images: %w", err)
+	}
+
+	images := make([]model.ProductImage, len(input.ImageIDs))
+	for position, id := range input.ImageIDs {
+		images[position] = *product.Image(id)
+		images[position].Position = position
+	}
+	return dto.ProductImageResponsesFromModel(images), nil
+}
+
+// DeleteImage removes a gallery image and its files. A product always keeps at least one image.
+func (s *productService) DeleteImage(ctx context.Context, productID uint, imageID uint) error {
+	product, image, err := s.findImage(productID, imageID)
+	if err != nil {
+		return err
+	}
+	if len(product.Images) == 1 {
+		return apperror.NewCodeMessage("last_image", "a product must keep at least one image")
+	}
+	if err := s.repo.DeleteImage(&image); err != nil {
+		return fmt.Errorf("failed to delete image: %w", err)
+	}
+	s.deleteStoredImage(ctx, image.ImageURL, image.ThumbnailURL)
+	return nil
+}
+
+// findImage returns a product and one of its gallery images
+func (s *productService) findImage(productID uint, imageID uint) (model.Product, model.ProductImage, error) {
+	product, err := s.repo.FindByID(productID)
+	if err != nil {
+		return model.Product{}, model.ProductImage{}, apperror.NewDomain(fmt.Errorf("product not found: %w", err), "product_not_found", "product not found")
+	}
+	image := product.Image(imageID)
+	if image == nil {
+		return model.Product{}, model.ProductImage{}, apperror.NewCodeMessage("image_not_found", "image not found")
+	}
+	return product, *image, nil
+}
+
+// nextImagePosition places a new image after the last image of the gallery
+func nextImagePosition(images []model.ProductImage) int {
+	next := 0
+	for _, img := range images {
+		if img.Position >= next {
+			next = img.Position + 1
+		}
+	}
+	return next
+}
+
+// uploadImage validates an image upload and stores it together with its thumbnail
+func (s *productService) uploadImage(ctx context.Context, file dto.FileUpload) (string, string, error) {
+	// Validate the file upload
+	if err := file.Validate(); err != nil {
+		return "", "", apperror.NewDomain(err, "invalid_image", err.Error())
+	}
+
+	// Read first 512 bytes to detect actual content type
+	buf := make([]byte, 512)
+	n, err := file.Content.Read(buf)
+	if err != nil && err != io.EOF {
+		return "", "", fmt.Errorf("failed to read image: %w", err)
+	}
+	detectedType := http.DetectContentType(buf[:n])
+
+	// Verify the detected type matches the provided content type
+	if detectedType != file.ContentType {
+		err := fmt.Errorf("content type mismatch: detected %s, provided %s", detectedType, file.ContentType)
+		return "", "", apperror.NewDomain(err, "invalid_image", err.Error())
+	}
+
+	// Generate a unique name for the image
+	uuidStr := uuid.New().String()
+	var ext string
+	switch file.ContentType {
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/png":
+		ext = ".png"
+	default:
+		ext = ""
+	}
+	imageName := fmt.Sprintf("product-%s%s", uuidStr, ext)
+
+	combinedReader := io.MultiReader(
+		bytes.NewReader(buf[:n]),
+		file.Content,
+	)
+
+	// Upload the image and thumbnail to storage
+	origURL, thumbURL, err := s.storage.UploadImageWithThumbnail(ctx, imageName, combinedReader, file.Size, file.ContentType, 256, 256)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to upload image: %w", err)
+	}
+	return origURL, thumbURL, nil
+}
+
+// deleteStoredImage removes an image and its thumbnail from storage. Failures are only logged so a
+// leftover file never blocks the catalog change.
+func (s *productService) deleteStoredImage(ctx context.Context, imageURL string, thumbnailURL string) {
+	if imageName := extractImageNameFromURL(imageURL); imageName != "" {
+		if err := s.storage.DeleteImage(ctx, imageName); err != nil {
+			fmt.Printf("Warning: failed to delete image %s: %v\n", imageName, err)
+		}
+	}
+	// The thumbnail may be the main image itself
+	if thumbnailURL == "" || thumbnailURL == imageURL {
+		return
+	}
+	if thumbName := extractImageNameFromURL(thumbnailURL); thumbName != "" {
+		if err := s.storage.DeleteImage(ctx, thumbName); err != nil {
+			fmt.Printf("Warning: failed to delete thumbnail %s: %v\n", thumbName, err)
+		}
+	}
+}
+
+const (
+	// minSuggestLength is the shortest term worth suggesting for
+	minSuggestLength = 2
+	// maxSuggestLength bounds the term sent to the database
+	maxSuggestLength = 64
+	// maxSuggestLimit bounds the suggestions per group
+	maxSuggestLimit = 10
+	// maxSuggestCacheEntries bounds the memory held by the suggestion cache
+	maxSuggestCacheEntries = 1000
+)
+
+// Suggest returns typeahead suggestions for a partial search term: product names, brands, accords
+// and notes, at most limit per group. Results are cached briefly since every keystroke asks again.
+func (s *productService) Suggest(ctx context.Context, query string, limit int) (dto.SuggestResponse, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+
+	term := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if utf8.RuneCountInString(term) < minSuggestLength {
+		return emptySuggestResponse(), nil
+	}
+	if utf8.RuneCountInString(term) > maxSuggestLength {
+		term = string([]rune(term)[:maxSuggestLength])
+	}
+
+	key := fmt.Sprintf("%d:%s", limit, term)
+	s.suggestMu.RLock()
+	entry, ok := s.suggestCache[key]
+	s.suggestMu.RUnlock()
+	if ok && time.Now().Before(entry.staleAt) {
+		return entry.resp, nil
+	}
+
+	resp, err := s.repo.Suggest(ctx, term, limit)
+	if err != nil {
+		return dto.SuggestResponse{}, fmt.Errorf("failed to suggest products: %w", err)
+	}
+
+	s.suggestMu.Lock()
+	if len(s.suggestCache) >= maxSuggestCacheEntries {
+		now := time.Now()
+		for k, e := range s.suggestCache {
+			if !now.Before(e.staleAt) {
+				delete(s.suggestCache, k)
+			}
+		}
+		if len(s.suggestCache) >= maxSuggestCacheEntries {
+			s.suggestCache = make(map[string]suggestCacheEntry)
+		}
+	}
+	s.suggestCache[key] = suggestCacheEntry{resp: resp, staleAt: time.Now().Add(s.suggestTTL)}
+	s.suggestMu.Unlock()
+	return resp, nil
+}
+
+// clearSuggestCache drops cached suggestions after a catalog change
+func (s *productService) clearSuggestCache() {
+	s.suggestMu.Lock()
+	s.suggestCache = make(map[string]suggestCacheEntry)
+	s.suggestMu.Unlock()
+}
+
+func emptySuggestResponse() dto.SuggestResponse {
+	return dto.SuggestResponse{
+		Products: []dto.ProductSuggestion{},
+		Brands:   []string{},
+		Accords:  []string{},
+		Notes:    []string{},
+	}
+}
+
 // extractImageNameFromURL extracts the image name from a Supabase storage URL
 func extractImageNameFromURL(imageURL string) string {
 	// URL format: https://domain.com/storage/v1/object/public/bucket/image-name.jpg
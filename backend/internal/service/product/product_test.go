@@ -1,9 +1,15 @@
 package service
 
 import (
+	"context"
 	"testing"
 
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/leoferamos/aroma-sense/internal/repository"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExtractImageNameFromURL(t *testing.T) {
@@ -51,3 +57,137 @@ func TestExtractImageNameFromURL(t *testing.T) {
 		})
 	}
 }
+
+func TestNextImagePosition(t *testing.T) {
+	tests := []struct {
+		name     string
+		images   []model.ProductImage
+		expected int
+	}{
+		{
+			name:     "Empty gallery",
+			images:   nil,
+			expected: 0,
+		},
+		{
+			name:     "Ordered gallery",
+			images:   []model.ProductImage{{Position: 0}, {Position: 1}, {Position: 2}},
+			expected: 3,
+		},
+		{
+			name:     "Gallery with gaps",
+			images:   []model.ProductImage{{Position: 4}, {Position: 0}},
+			expected: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nextImagePosition(tt.images))
+		})
+	}
+}
+
+// suggestRepo counts Suggest calls; other repository methods are not used by these tests
+type suggestRepo struct {
+	repository.ProductRepository
+	calls []string
+}
+
+func (r *suggestRepo) Suggest(ctx context.Context, term string, limit int) (dto.SuggestResponse, error) {
+	r.calls = append(r.calls, term)
+	return dto.SuggestResponse{Brands: []string{"Dior"}}, nil
+}
+
+func TestSuggest(t *testing.T) {
+	t.Run("Short term skips the database", func(t *testing.T) {
+		repo := &suggestRepo{}
+		svc := NewProductService(repo, nil, nil)
+
+		resp, err := svc.Suggest(context.Background(), " d ", 5)
+
+		require.NoError(t, err)
+		assert.Empty(t, repo.calls)
+		assert.NotNil(t, resp.Products)
+	})
+
+	t.Run("Normalized term is cached", func(t *testing.T) {
+		repo := &suggestRepo{}
+		svc := NewProductService(repo, nil, nil)
+
+		first, err := svc.Suggest(context.Background(), "Dio", 5)
+		require.NoError(t, err)
+		second, err := svc.Suggest(context.Background(), "  dio ", 5)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"dio"}, repo.calls)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("Limit is part of the cache key and capped", func(t *testing.T) {
+		repo := &suggestRepo{}
+		svc := NewProductService(repo, nil, nil)
+
+		_, _ = svc.Suggest(context.Background(), "dio", 5)
+		_, _ = svc.Suggest(context.Background(), "dio", 50)
+		_, _ = svc.Suggest(context.Background(), "dio", 10)
+
+		assert.Len(t, repo.calls, 2)
+	})
+}
+
+// variantRepo records which variant fields are written; other repository methods are not used by these tests
+type variantRepo struct {
+	repository.ProductRepository
+	product model.Product
+	fields  []string
+}
+
+func (r *variantRepo) FindByID(id uint) (model.Product, error) { return r.product, nil }
+func (r *variantRepo) Update(product *model.Product) error     { return nil }
+func (r *variantRepo) FindVariantBySKU(sku string) (*model.ProductVariant, error) {
+	return nil, nil
+}
+func (r *variantRepo) UpdateVariant(variant *model.ProductVariant, fields []string) error {
+	r.fields = fields
+	return nil
+}
+
+func TestUpdateVariantFields(t *testing.T) {
+	newRepo := func() *variantRepo {
+		return &variantRepo{product: model.Product{ID: 1, Variants: []model.ProductVariant{{ID: 7, ProductID: 1, SKU: "DIOR-100", StockQuantity: 5}}}}
+	}
+	price := money.FromCents(89990)
+	stock := 12
+
+	t.Run("Price change leaves stock alone", func(t *testing.T) {
+		repo := newRepo()
+		svc := NewProductService(repo, nil, nil)
+
+		_, err := svc.UpdateVariant(context.Background(), 1, "DIOR-100", dto.UpdateProductVariantRequest{Price: &price})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Price"}, repo.fields)
+	})
+
+	t.Run("Stock is written when supplied", func(t *testing.T) {
+		repo := newRepo()
+		svc := NewProductService(repo, nil, nil)
+
+		resp, err := svc.UpdateVariant(context.Background(), 1, "DIOR-100", dto.UpdateProductVariantRequest{Price: &price, StockQuantity: &stock})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Price", "StockQuantity"}, repo.fields)
+		assert.Equal(t, 12, resp.StockQuantity)
+	})
+
+	t.Run("Product price update only touches the default variant's price", func(t *testing.T) {
+		repo := newRepo()
+		svc := NewProductService(repo, nil, nil)
+
+		err := svc.UpdateProduct(context.Background(), 1, dto.UpdateProductRequest{Price: &price})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Price"}, repo.fields)
+	})
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+	logservice "github.com/leoferamos/aroma-sense/internal/service/log"
+)
+
+var promotionCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,64}$`)
+
+// Line is a cart or order line a promotion is evaluated against
+type Line struct {
+	ProductID uint
+	Brand     string
+	Category  string
+	UnitPrice money.Amount
+	Quantity  int
+}
+
+// Customer identifies who redeems a promotion, for per-customer usage limits. Guests are
+// identified by email, and may be anonymous while they have not entered one yet.
+type Customer struct {
+	UserID string
+	Email  string
+}
+
+// Discount is what a promotion takes off a purchase
+type Discount struct {
+	Promotion *model.Promotion
+	Items     money.Amount
+	Shipping  money.Amount
+}
+
+// Total returns the whole discount, items and shipping together
+func (d *Discount) Total() money.Amount {
+	if d == nil {
+		return 0
+	}
+	return d.Items + d.Shipping
+}
+
+// PromotionService manages promotions and evaluates coupon codes
+type PromotionService interface {
+	Evaluate(code string, customer Customer, lines []Line, shipping money.Amount) (*Discount, error)
+	CreatePromotion(req *dto.PromotionRequest, adminPublicID string) (*dto.PromotionResponse, error)
+	UpdatePromotion(id uint, req *dto.PromotionRequest, adminPublicID string) (*dto.PromotionResponse, error)
+	DeletePromotion(id uint, adminPublicID string) error
+	GetPromotion(id uint) (*dto.PromotionResponse, error)
+	ListPromotions(page int, perPage int) (*dto.PromotionListResponse, error)
+}
+
+type promotionService struct {
+	repo            repository.PromotionRepository
+	auditLogService logservice.AuditLogService
+	now             func() time.Time
+}
+
+// NewPromotionService creates a new instance of PromotionService
+func NewPromotionService(repo repository.PromotionRepository, auditLogService logservice.AuditLogService) PromotionService {
+	return &promotionService{repo: repo, auditLogService: auditLogService, now: time.Now}
+}
+
+// Evaluate checks a coupon code against a purchase and computes its discount. Item discounts only
+// cover lines in the promotion's brand and category scope, and never exceed what those lines cost.
+// The minimum subtotal is checked against every line. Usage limits are checked here so shoppers
+// learn early; they are enforced again when the order is placed.
+func (s *promotionService) Evaluate(code string, customer Customer, lines []Line, shipping money.Amount) (*Discount, error) {
+	code = model.NormalizePromotionCode(code)
+	if code == "" {
+		return nil, apperror.NewCodeMessage("promotion_not_found", "promotion not found")
+	}
+	promotion, err := s.repo.FindByCode(code)
+	if err != nil {
+		return nil, apperror.NewDomain(err, "internal_error", "failed to load promotion")
+	}
+	if promotion == nil {
+		return nil, apperror.NewCodeMessage("promotion_not_found", "promotion not found")
+	}
+	if !promotion.ActiveAt(s.now()) {
+		return nil, apperror.NewCodeMessage("promotion_not_active", "promotion is not active")
+	}
+	if promotion.MaxUses != nil && promotion.UsedCount >= *promotion.MaxUses {
+		return nil, apperror.NewCodeMessage("promotion_usage_limit_reached", "promotion usage limit reached")
+	}
+	if promotion.MaxUsesPerUser != nil && (customer.UserID != "" || customer.Email != "") {
+		used, err := s.repo.CountRedemptions(promotion.ID, customer.UserID, customer.Email)
+		if err != nil {
+			return nil, apperror.NewDomain(err, "internal_error", "failed to count promotion redemptions")
+		}
+		if used >= int64(*promotion.MaxUsesPerUser) {
+			return nil, apperror.NewCodeMessage("promotion_usage_limit_reached", "promotion usage limit reached")
+		}
+	}
+
+	var subtotal, eligibleSubtotal money.Amount
+	var eligible []Line
+	for _, line := range lines {
+		lineTotal := line.UnitPrice.Mul(line.Quantity)
+		subtotal += lineTotal
+		if promotion.Matches(line.Brand, line.Category) {
+			eligibleSubtotal += lineTotal
+			eligible = append(eligible, line)
+		}
+	}
+	if subtotal < promotion.MinSubtotal {
+		return nil, apperror.NewCodeMessage("promotion_min_subtotal_not_met", fmt.Sprintf("promotion requires a subtotal of at least %s", promotion.MinSubtotal))
+	}
+	if len(eligible) == 0 {
+		return nil, apperror.NewCodeMessage("promotion_not_applicable", "promotion does not apply to any item in the cart")
+	}
+
+	discount := &Discount{Promotion: promotion}
+	switch promotion.Type {
+	case model.PromotionTypePercentage:
+		discount.Items = eligibleSubtotal.Mul(promotion.PercentOff).Div(100)
+	case model.PromotionTypeFixedAmount:
+		discount.Items = promotion.AmountOff
+	case model.PromotionTypeFreeShipping:
+		discount.Shipping = shipping
+	case model.PromotionTypeBuyXGetY:
+		discount.Items = freeUnitsValue(eligible, promotion.BuyQuantity, promotion.GetQuantity)
+		if discount.Items == 0 {
+			return nil, apperror.NewCodeMessage("promotion_not_applicable", fmt.Sprintf("add %d eligible items to use this promotion", promotion.BuyQuantity+promotion.GetQuantity))
+		}
+	}
+	if discount.Items > eligibleSubtotal {
+		discount.Items = eligibleSubtotal
+	}
+	return discount, nil
+}
+
+// freeUnitsValue prices a buy X get Y deal. Eligible units are grouped from the most expensive
+// down, and the cheapest getQty units of every full group of buyQty+getQty are free.
+func freeUnitsValue(lines []Line, buyQty int, getQty int) money.Amount {
+	groupSize := buyQty + getQty
+	if buyQty <= 0 || getQty <= 0 {
+		return 0
+	}
+
+	var units []money.Amount
+	for _, line := range lines {
+		for i := 0; i < line.Quantity; i++ {
+			units = append(units, line.UnitPrice)
+		}
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i] > units[j] })
+
+	var free money.Amount
+	for start := 0; start+groupSize <= len(units); start += groupSize {
+		for _, price := range units[start+buyQty : start+groupSize] {
+			free += price
+		}
+	}
+	return free
+}
+
+// CreatePromotion validates and stores a new promotion
+func (s *promotionService) CreatePromotion(req *dto.PromotionRequest, adminPublicID string) (*dto.PromotionResponse, error) {
+	promotion := &model.Promotion{Active: true}
+	if err := applyPromotionRequest(promotion, req); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByCode(promotion.Code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, apperror.NewCodeMessage("promotion_code_taken", "promotion code already exists")
+	}
+
+	if err := s.repo.Create(promotion); err != nil {
+		return nil, err
+	}
+	s.audit(model.AuditActionPromotionCreated, promotion, adminPublicID)
+
+	resp := dto.PromotionResponseFromModel(promotion)
+	return &resp, nil
+}
+
+// UpdatePromotion replaces the settings of a promotion. Its usage count is kept.
+func (s *promotionService) UpdatePromotion(id uint, req *dto.PromotionRequest, adminPublicID string) (*dto.PromotionResponse, error) {
+	promotion, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	previousCode := promotion.Code
+	if err := applyPromotionRequest(promotion, req); err != nil {
+		return nil, err
+	}
+
+	if promotion.Code != previousCode {
+		existing, err := s.repo.FindByCode(promotion.Code)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, apperror.NewCodeMessage("promotion_code_taken", "promotion code already exists")
+		}
+	}
+
+	if err := s.repo.Update(promotion); err != nil {
+		return nil, err
+	}
+	s.audit(model.AuditActionPromotionUpdated, promotion, adminPublicID)
+
+	resp := dto.PromotionResponseFromModel(promotion)
+	return &resp, nil
+}
+
+// DeletePromotion removes a promotion that was never redeemed. Redeemed promotions stay referenced
+// by their orders and can only be deactivated.
+func (s *promotionService) DeletePromotion(id uint, adminPublicID string) error {
+	promotion, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	if promotion.UsedCount > 0 {
+		return apperror.NewCodeMessage("promotion_in_use", "promotion has been redeemed; deactivate it instead")
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.audit(model.AuditActionPromotionDeleted, promotion, adminPublicID)
+	return nil
+}
+
+// GetPromotion returns a promotion by ID
+func (s *promotionService) GetPromotion(id uint) (*dto.PromotionResponse, error) {
+	promotion, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	resp := dto.PromotionResponseFromModel(promotion)
+	return &resp, nil
+}
+
+// ListPromotions returns a page of promotions, newest first
+func (s *promotionService) ListPromotions(page int, perPage int) (*dto.PromotionListResponse, error) {
+	promotions, totalCount, err := s.repo.List(page, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.PromotionResponse, 0, len(promotions))
+	for i := range promotions {
+		items = append(items, dto.PromotionResponseFromModel(&promotions[i]))
+	}
+
+	totalPages := 0
+	if perPage > 0 {
+		totalPages = int((totalCount + int64(perPage) - 1) / int64(perPage))
+	}
+
+	resp := &dto.PromotionListResponse{Promotions: items}
+	resp.Meta.Pagination = dto.PaginationMeta{
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages,
+		TotalCount: int(totalCount),
+	}
+	return resp, nil
+}
+
+func (s *promotionService) find(id uint) (*model.Promotion, error) {
+	promotion, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if promotion == nil {
+		return nil, apperror.NewCodeMessage("promotion_not_found", "promotion not found")
+	}
+	return promotion, nil
+}
+
+func (s *promotionService) audit(action model.AuditAction, promotion *model.Promotion, adminPublicID string) {
+	if s.auditLogService == nil {
+		return
+	}
+	details := map[string]interface{}{
+		"admin_public_id": adminPublicID,
+		"code":            promotion.Code,
+		"type":            string(promotion.Type),
+	}
+	if err := s.auditLogService.LogSystemAction(action, "promotion", fmt.Sprint(promotion.ID), details); err != nil {
+		log.Printf("failed to audit %s for promotion %d: %v", action, promotion.ID, err)
+	}
+}
+
+// applyPromotionRequest validates a request and copies it onto the promotion. Settings that do not
+// belong to the promotion type are cleared.
+func applyPromotionRequest(promotion *model.Promotion, req *dto.PromotionRequest) error {
+	code := model.NormalizePromotionCode(req.Code)
+	if !promotionCodePattern.MatchString(code) {
+		return apperror.NewCodeMessage("invalid_promotion", "code must be 3 to 64 letters, digits, '-' or '_'")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return apperror.NewCodeMessage("invalid_promotion", "name is required")
+	}
+	if req.AmountOff < 0 || req.MinSubtotal < 0 {
+		return apperror.NewCodeMessage("invalid_promotion", "amounts cannot be negative")
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return apperror.NewCodeMessage("invalid_promotion", "ends_at must be after starts_at")
+	}
+	if (req.MaxUses != nil && *req.MaxUses < 1) || (req.MaxUsesPerUser != nil && *req.MaxUsesPerUser < 1) {
+		return apperror.NewCodeMessage("invalid_promotion", "usage limits must be at least 1")
+	}
+	if req.MaxUses != nil && *req.MaxUses < promotion.UsedCount {
+		return apperror.NewCodeMessage("invalid_promotion", "max_uses is below the number of redemptions")
+	}
+
+	promotion.PercentOff, promotion.AmountOff, promotion.BuyQuantity, promotion.GetQuantity = 0, 0, 0, 0
+	switch model.PromotionType(req.Type) {
+	case model.PromotionTypePercentage:
+		if req.PercentOff < 1 || req.PercentOff > 100 {
+			return apperror.NewCodeMessage("invalid_promotion", "percent_off must be between 1 and 100")
+		}
+		promotion.PercentOff = req.PercentOff
+	case model.PromotionTypeFixedAmount:
+		if req.AmountOff <= 0 {
+			return apperror.NewCodeMessage("invalid_promotion", "amount_off must be positive")
+		}
+		promotion.AmountOff = req.AmountOff
+	case model.PromotionTypeFreeShipping:
+	case model.PromotionTypeBuyXGetY:
+		if req.BuyQuantity < 1 || req.GetQuantity < 1 {
+			return apperror.NewCodeMessage("invalid_promotion", "buy_quantity and get_quantity must be at least 1")
+		}
+		promotion.BuyQuantity, promotion.GetQuantity = req.BuyQuantity, req.GetQuantity
+	default:
+		return apperror.NewCodeMessage("invalid_promotion", "unknown promotion type")
+	}
+
+	promotion.Code = code
+	promotion.Name = name
+	promotion.Description = strings.TrimSpace(req.Description)
+	promotion.Type = model.PromotionType(req.Type)
+	promotion.Brand = strings.TrimSpace(req.Brand)
+	promotion.Category = strings.TrimSpace(req.Category)
+	promotion.MinSubtotal = req.MinSubtotal
+	promotion.MaxUses = req.MaxUses
+	promotion.MaxUsesPerUser = req.MaxUsesPerUser
+	promotion.StartsAt = req.StartsAt
+	promotion.EndsAt = req.EndsAt
+	if req.Active != nil {
+		promotion.Active = *req.Active
+	}
+	return nil
+}
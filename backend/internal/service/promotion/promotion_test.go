@@ -0,0 +1,221 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPromotionRepo struct {
+	promotions  map[uint]*model.Promotion
+	redemptions int64
+	created     *model.Promotion
+	updated     *model.Promotion
+	deletedID   uint
+}
+
+func (m *mockPromotionRepo) Create(promotion *model.Promotion) error {
+	promotion.ID = 99
+	m.created = promotion
+	return nil
+}
+func (m *mockPromotionRepo) Update(promotion *model.Promotion) error {
+	m.updated = promotion
+	return nil
+}
+func (m *mockPromotionRepo) Delete(id uint) error {
+	m.deletedID = id
+	return nil
+}
+func (m *mockPromotionRepo) FindByID(id uint) (*model.Promotion, error) {
+	if p, ok := m.promotions[id]; ok {
+		copied := *p
+		return &copied, nil
+	}
+	return nil, nil
+}
+func (m *mockPromotionRepo) FindByCode(code string) (*model.Promotion, error) {
+	for _, p := range m.promotions {
+		if p.Code == code {
+			copied := *p
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockPromotionRepo) List(page int, perPage int) ([]model.Promotion, int64, error) {
+	return nil, 0, nil
+}
+func (m *mockPromotionRepo) CountRedemptions(promotionID uint, userID string, guestEmail string) (int64, error) {
+	return m.redemptions, nil
+}
+
+func intPtr(v int) *int { return &v }
+
+func errorCode(err error) string {
+	if de, ok := err.(*apperror.DomainError); ok {
+		return de.Code
+	}
+	return ""
+}
+
+func newTestService(promotions ...model.Promotion) (*promotionService, *mockPromotionRepo) {
+	repo := &mockPromotionRepo{promotions: map[uint]*model.Promotion{}}
+	for i := range promotions {
+		p := promotions[i]
+		repo.promotions[p.ID] = &p
+	}
+	svc := NewPromotionService(repo, nil).(*promotionService)
+	svc.now = func() time.Time { return time.Date(2025, 12, 26, 12, 0, 0, 0, time.UTC) }
+	return svc, repo
+}
+
+func testLines() []Line {
+	return []Line{
+		{ProductID: 1, Brand: "Dior", Category: "Perfume", UnitPrice: money.FromCents(10000), Quantity: 2},
+		{ProductID: 2, Brand: "Natura", Category: "Body", UnitPrice: money.FromCents(3000), Quantity: 1},
+	}
+}
+
+func TestEvaluate_RuleTypes(t *testing.T) {
+	t.Run("percentage off the eligible brand only", func(t *testing.T) {
+		svc, _ := newTestService(model.Promotion{ID: 1, Code: "DIOR10", Type: model.PromotionTypePercentage, PercentOff: 10, Brand: "dior", Active: true})
+
+		discount, err := svc.Evaluate(" dior10 ", Customer{UserID: "user-1"}, testLines(), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, money.FromCents(2000), discount.Items)
+		assert.Equal(t, money.Amount(0), discount.Shipping)
+	})
+
+	t.Run("fixed amount is capped at the eligible subtotal", func(t *testing.T) {
+		svc, _ := newTestService(model.Promotion{ID: 1, Code: "BODY50", Type: model.PromotionTypeFixedAmount, AmountOff: money.FromCents(5000), Category: "Body", Active: true})
+
+		discount, err := svc.Evaluate("BODY50", Customer{}, testLines(), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, money.FromCents(3000), discount.Items)
+	})
+
+	t.Run("free shipping waives the shipping price", func(t *testing.T) {
+		svc, _ := newTestService(model.Promotion{ID: 1, Code: "FRETE", Type: model.PromotionTypeFreeShipping, Active: true})
+
+		discount, err := svc.Evaluate("FRETE", Customer{}, testLines(), money.FromCents(1590))
+		assert.NoError(t, err)
+		assert.Equal(t, money.Amount(0), discount.Items)
+		assert.Equal(t, money.FromCents(1590), discount.Total())
+	})
+
+	t.Run("buy x get y gives the cheapest units of each group", func(t *testing.T) {
+		svc, _ := newTestService(model.Promotion{ID: 1, Code: "LEVE3", Type: model.PromotionTypeBuyXGetY, BuyQuantity: 2, GetQuantity: 1, Active: true})
+
+		discount, err := svc.Evaluate("LEVE3", Customer{}, testLines(), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, money.FromCents(3000), discount.Items)
+
+		_, err = svc.Evaluate("LEVE3", Customer{}, testLines()[:1], 0)
+		assert.Equal(t, "promotion_not_applicable", errorCode(err))
+	})
+}
+
+func TestEvaluate_Rejections(t *testing.T) {
+	past := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		promotion model.Promotion
+		code      string
+		want      string
+	}{
+		{"unknown code", model.Promotion{ID: 1, Code: "OTHER", Type: model.PromotionTypeFreeShipping, Active: true}, "MISSING", "promotion_not_found"},
+		{"inactive", model.Promotion{ID: 1, Code: "OFF", Type: model.PromotionTypeFreeShipping}, "OFF", "promotion_not_active"},
+		{"expired", model.Promotion{ID: 1, Code: "OLD", Type: model.PromotionTypeFreeShipping, Active: true, EndsAt: &past}, "OLD", "promotion_not_active"},
+		{"used up", model.Promotion{ID: 1, Code: "GONE", Type: model.PromotionTypeFreeShipping, Active: true, MaxUses: intPtr(5), UsedCount: 5}, "GONE", "promotion_usage_limit_reached"},
+		{"below minimum subtotal", model.Promotion{ID: 1, Code: "BIG", Type: model.PromotionTypeFreeShipping, Active: true, MinSubtotal: money.FromCents(30000)}, "BIG", "promotion_min_subtotal_not_met"},
+		{"no eligible item", model.Promotion{ID: 1, Code: "CHANEL", Type: model.PromotionTypePercentage, PercentOff: 10, Brand: "Chanel", Active: true}, "CHANEL", "promotion_not_applicable"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc, _ := newTestService(tc.promotion)
+			_, err := svc.Evaluate(tc.code, Customer{UserID: "user-1"}, testLines(), 0)
+			assert.Equal(t, tc.want, errorCode(err))
+		})
+	}
+
+	t.Run("per customer limit", func(t *testing.T) {
+		svc, repo := newTestService(model.Promotion{ID: 1, Code: "ONCE", Type: model.PromotionTypeFreeShipping, Active: true, MaxUsesPerUser: intPtr(1)})
+		repo.redemptions = 1
+
+		_, err := svc.Evaluate("ONCE", Customer{Email: "guest@example.com"}, testLines(), 0)
+		assert.Equal(t, "promotion_usage_limit_reached", errorCode(err))
+
+		// Anonymous guests are only checked once they give an email
+		_, err = svc.Evaluate("ONCE", Customer{}, testLines(), 0)
+		assert.NoError(t, err)
+	})
+}
+
+func TestCreatePromotion(t *testing.T) {
+	t.Run("normalizes the code and clears settings of other types", func(t *testing.T) {
+		svc, repo := newTestService()
+
+		resp, err := svc.CreatePromotion(&dto.PromotionRequest{Code: " welcome10 ", Name: "Welcome", Type: "percentage", PercentOff: 10, AmountOff: money.FromCents(500)}, "admin-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "WELCOME10", resp.Code)
+		assert.True(t, resp.Active)
+		assert.Equal(t, money.Amount(0), repo.created.AmountOff)
+	})
+
+	t.Run("rejects a taken code", func(t *testing.T) {
+		svc, _ := newTestService(model.Promotion{ID: 1, Code: "WELCOME10"})
+
+		_, err := svc.CreatePromotion(&dto.PromotionRequest{Code: "welcome10", Name: "Welcome", Type: "free_shipping"}, "admin-1")
+		assert.Equal(t, "promotion_code_taken", errorCode(err))
+	})
+
+	t.Run("validates the rule", func(t *testing.T) {
+		start := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+		end := start.Add(-time.Hour)
+		invalid := []dto.PromotionRequest{
+			{Code: "NO SPACES", Name: "x", Type: "free_shipping"},
+			{Code: "PCT", Name: "x", Type: "percentage"},
+			{Code: "FIXED", Name: "x", Type: "fixed_amount"},
+			{Code: "BXGY", Name: "x", Type: "buy_x_get_y", BuyQuantity: 2},
+			{Code: "WINDOW", Name: "x", Type: "free_shipping", StartsAt: &start, EndsAt: &end},
+			{Code: "LIMIT", Name: "x", Type: "free_shipping", MaxUsesPerUser: intPtr(0)},
+		}
+		for i := range invalid {
+			svc, _ := newTestService()
+			_, err := svc.CreatePromotion(&invalid[i], "admin-1")
+			assert.Equal(t, "invalid_promotion", errorCode(err), invalid[i].Code)
+		}
+	})
+}
+
+func TestUpdatePromotion_KeepsUsage(t *testing.T) {
+	svc, repo := newTestService(model.Promotion{ID: 1, Code: "SUMMER", Type: model.PromotionTypeFreeShipping, Active: true, UsedCount: 7})
+
+	active := false
+	resp, err := svc.UpdatePromotion(1, &dto.PromotionRequest{Code: "SUMMER", Name: "Summer", Type: "free_shipping", Active: &active}, "admin-1")
+	assert.NoError(t, err)
+	assert.False(t, resp.Active)
+	assert.Equal(t, 7, repo.updated.UsedCount)
+
+	_, err = svc.UpdatePromotion(1, &dto.PromotionRequest{Code: "SUMMER", Name: "Summer", Type: "free_shipping", MaxUses: intPtr(3)}, "admin-1")
+	assert.Equal(t, "invalid_promotion", errorCode(err))
+}
+
+func TestDeletePromotion(t *testing.T) {
+	svc, repo := newTestService(
+		model.Promotion{ID: 1, Code: "USED", UsedCount: 1},
+		model.Promotion{ID: 2, Code: "FRESH"},
+	)
+
+	assert.Equal(t, "promotion_in_use", errorCode(svc.DeletePromotion(1, "admin-1")))
+	assert.NoError(t, svc.DeletePromotion(2, "admin-1"))
+	assert.Equal(t, uint(2), repo.deletedID)
+	assert.Equal(t, "promotion_not_found", errorCode(svc.DeletePromotion(3, "admin-1")))
+}
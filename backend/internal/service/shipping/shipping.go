@@ -6,6 +6,7 @@ import (
 	"github.com/leoferamos/aroma-sense/internal/apperror"
 	"github.com/leoferamos/aroma-sense/internal/dto"
 	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
 	"github.com/leoferamos/aroma-sense/internal/repository"
 	"github.com/leoferamos/aroma-sense/internal/validation"
 )
@@ -50,17 +51,17 @@ func (s *shippingService) CalculateOptions(ctx context.Context, userID string, p
 
 	// Aggregate weight and derive a single parcel.
 	var totalWeightKg float64
-	var insuredValue float64
+	var insuredValue money.Amount
 	for _, it := range cart.Items {
-		if it.Product != nil {
-			w := it.Product.Weight
+		if it.Variant != nil {
+			w := it.Variant.Weight
 			if w > 50 {
 				w = w / 1000.0
 			}
 			totalWeightKg += w * float64(it.Quantity)
-			insuredValue += it.Price * float64(it.Quantity)
+			insuredValue += it.Price.Mul(it.Quantity)
 		} else {
-			insuredValue += it.Price * float64(it.Quantity)
+			insuredValue += it.Price.Mul(it.Quantity)
 		}
 	}
 	if totalWeightKg <= 0 {
@@ -71,7 +72,7 @@ func (s *shippingService) CalculateOptions(ctx context.Context, userID string, p
 	if s.provider == nil {
 		return nil, apperror.NewCodeMessage("provider_unavailable", "shipping provider not configured")
 	}
-	quotes, err := s.provider.GetQuotes(ctx, userID, s.originCEP, destCEP, []model.Parcel{parcel}, insuredValue)
+	quotes, err := s.provider.GetQuotes(ctx, userID, s.originCEP, destCEP, []model.Parcel{parcel}, insuredValue.Float64())
 	if err != nil {
 		return nil, err
 	}
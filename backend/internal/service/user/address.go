@@ -0,0 +1,153 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+	"github.com/leoferamos/aroma-sense/internal/validation"
+)
+
+// MaxAddressesPerUser caps the size of an address book
+const MaxAddressesPerUser = 20
+
+// AddressService manages the authenticated user's address book
+type AddressService interface {
+	ListAddresses(userID string) ([]dto.AddressResponse, error)
+	GetAddress(userID string, publicID string) (*dto.AddressResponse, error)
+	CreateAddress(userID string, req *dto.AddressRequest) (*dto.AddressResponse, error)
+	UpdateAddress(userID string, publicID string, req *dto.AddressRequest) (*dto.AddressResponse, error)
+	DeleteAddress(userID string, publicID string) error
+}
+
+type addressService struct {
+	repo repository.AddressRepository
+}
+
+func NewAddressService(repo repository.AddressRepository) AddressService {
+	return &addressService{repo: repo}
+}
+
+// ListAddresses returns the user's addresses, default first
+func (s *addressService) ListAddresses(userID string) ([]dto.AddressResponse, error) {
+	if userID == "" {
+		return nil, apperror.NewCodeMessage("unauthenticated", "unauthenticated")
+	}
+	addresses, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.AddressResponse, len(addresses))
+	for i := range addresses {
+		resp[i] = dto.AddressResponseFromModel(&addresses[i])
+	}
+	return resp, nil
+}
+
+// GetAddress returns one of the user's addresses
+func (s *addressService) GetAddress(userID string, publicID string) (*dto.AddressResponse, error) {
+	address, err := s.find(userID, publicID)
+	if err != nil {
+		return nil, err
+	}
+	resp := dto.AddressResponseFromModel(address)
+	return &resp, nil
+}
+
+// CreateAddress adds an address to the book. The first address always becomes the default
+func (s *addressService) CreateAddress(userID string, req *dto.AddressRequest) (*dto.AddressResponse, error) {
+	if userID == "" {
+		return nil, apperror.NewCodeMessage("unauthenticated", "unauthenticated")
+	}
+	postal, err := NormalizePostalAddress(req)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.repo.CountByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxAddressesPerUser {
+		return nil, apperror.NewCodeMessage("address_limit_reached", "address book is full")
+	}
+
+	address := &model.Address{
+		UserID:        userID,
+		PostalAddress: postal,
+		IsDefault:     req.IsDefault || count == 0,
+	}
+	if err := s.repo.Create(address); err != nil {
+		return nil, err
+	}
+	resp := dto.AddressResponseFromModel(address)
+	return &resp, nil
+}
+
+// UpdateAddress replaces the fields of an address. The default can be moved to it but not removed from it
+func (s *addressService) UpdateAddress(userID string, publicID string, req *dto.AddressRequest) (*dto.AddressResponse, error) {
+	address, err := s.find(userID, publicID)
+	if err != nil {
+		return nil, err
+	}
+	postal, err := NormalizePostalAddress(req)
+	if err != nil {
+		return nil, err
+	}
+
+	address.PostalAddress = postal
+	address.IsDefault = address.IsDefault || req.IsDefault
+	if err := s.repo.Update(address); err != nil {
+		return nil, err
+	}
+	resp := dto.AddressResponseFromModel(address)
+	return &resp, nil
+}
+
+// DeleteAddress removes an address from the book. Orders keep their own copy of the address
+func (s *addressService) DeleteAddress(userID string, publicID string) error {
+	address, err := s.find(userID, publicID)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(address)
+}
+
+func (s *addressService) find(userID string, publicID string) (*model.Address, error) {
+	if userID == "" {
+		return nil, apperror.NewCodeMessage("unauthenticated", "unauthenticated")
+	}
+	address, err := s.repo.FindByPublicID(userID, publicID)
+	if err != nil {
+		return nil, err
+	}
+	if address == nil {
+		return nil, apperror.NewCodeMessage("address_not_found", "address not found")
+	}
+	return address, nil
+}
+
+// NormalizePostalAddress trims the fields of an address request, keeps only the CEP digits and upper-cases the UF
+func NormalizePostalAddress(req *dto.AddressRequest) (model.PostalAddress, error) {
+	if !validation.IsValidCEP(req.CEP) {
+		return model.PostalAddress{}, apperror.NewCodeMessage("invalid_postal_code", "invalid postal code")
+	}
+	if !validation.IsValidUF(req.UF) {
+		return model.PostalAddress{}, apperror.NewCodeMessage("invalid_uf", "invalid federative unit")
+	}
+	postal := model.PostalAddress{
+		Recipient:    strings.TrimSpace(req.Recipient),
+		CEP:          validation.NormalizeCEP(req.CEP),
+		Street:       strings.TrimSpace(req.Street),
+		Number:       strings.TrimSpace(req.Number),
+		Complement:   strings.TrimSpace(req.Complement),
+		Neighborhood: strings.TrimSpace(req.Neighborhood),
+		City:         strings.TrimSpace(req.City),
+		UF:           strings.ToUpper(strings.TrimSpace(req.UF)),
+	}
+	if postal.Recipient == "" || postal.Street == "" || postal.Number == "" || postal.Neighborhood == "" || postal.City == "" {
+		return model.PostalAddress{}, apperror.NewCodeMessage("invalid_address", "address is incomplete")
+	}
+	return postal, nil
+}
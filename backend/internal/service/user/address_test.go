@@ -0,0 +1,149 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockAddressRepo struct {
+	addresses []model.Address
+	created   *model.Address
+	updated   *model.Address
+	deleted   *model.Address
+}
+
+func (m *mockAddressRepo) Create(address *model.Address) error {
+	address.PublicID = "addr-new"
+	m.created = address
+	return nil
+}
+
+func (m *mockAddressRepo) Update(address *model.Address) error {
+	m.updated = address
+	return nil
+}
+
+func (m *mockAddressRepo) Delete(address *model.Address) error {
+	m.deleted = address
+	return nil
+}
+
+func (m *mockAddressRepo) FindByPublicID(userID string, publicID string) (*model.Address, error) {
+	for i := range m.addresses {
+		if m.addresses[i].UserID == userID && m.addresses[i].PublicID == publicID {
+			a := m.addresses[i]
+			return &a, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockAddressRepo) ListByUser(userID string) ([]model.Address, error) {
+	return m.addresses, nil
+}
+
+func (m *mockAddressRepo) CountByUser(userID string) (int64, error) {
+	return int64(len(m.addresses)), nil
+}
+
+func validAddressRequest() *dto.AddressRequest {
+	return &dto.AddressRequest{
+		Recipient:    " Maria Silva ",
+		CEP:          "01310-100",
+		Street:       "Avenida Paulista",
+		Number:       "1578",
+		Neighborhood: "Bela Vista",
+		City:         "São Paulo",
+		UF:           "sp",
+	}
+}
+
+func assertDomainCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var de *apperror.DomainError
+	if assert.ErrorAs(t, err, &de) {
+		assert.Equal(t, code, de.Code)
+	}
+}
+
+func TestCreateAddress(t *testing.T) {
+	t.Run("first address becomes default and is normalized", func(t *testing.T) {
+		repo := &mockAddressRepo{}
+		svc := NewAddressService(repo)
+
+		resp, err := svc.CreateAddress("user-1", validAddressRequest())
+		assert.NoError(t, err)
+		assert.True(t, resp.IsDefault)
+		assert.Equal(t, "01310-100", resp.CEP)
+		assert.Equal(t, "01310100", repo.created.CEP)
+		assert.Equal(t, "SP", repo.created.UF)
+		assert.Equal(t, "Maria Silva", repo.created.Recipient)
+		assert.Equal(t, "user-1", repo.created.UserID)
+	})
+
+	t.Run("later addresses are not default unless asked", func(t *testing.T) {
+		repo := &mockAddressRepo{addresses: []model.Address{{PublicID: "addr-1", UserID: "user-1", IsDefault: true}}}
+		svc := NewAddressService(repo)
+
+		resp, err := svc.CreateAddress("user-1", validAddressRequest())
+		assert.NoError(t, err)
+		assert.False(t, resp.IsDefault)
+	})
+
+	t.Run("invalid CEP and UF", func(t *testing.T) {
+		svc := NewAddressService(&mockAddressRepo{})
+
+		req := validAddressRequest()
+		req.CEP = "1310-100"
+		_, err := svc.CreateAddress("user-1", req)
+		assertDomainCode(t, err, "invalid_postal_code")
+
+		req = validAddressRequest()
+		req.UF = "XX"
+		_, err = svc.CreateAddress("user-1", req)
+		assertDomainCode(t, err, "invalid_uf")
+	})
+
+	t.Run("address book is full", func(t *testing.T) {
+		repo := &mockAddressRepo{addresses: make([]model.Address, MaxAddressesPerUser)}
+		svc := NewAddressService(repo)
+
+		_, err := svc.CreateAddress("user-1", validAddressRequest())
+		assertDomainCode(t, err, "address_limit_reached")
+		assert.Nil(t, repo.created)
+	})
+}
+
+func TestUpdateAddress(t *testing.T) {
+	t.Run("default flag is kept", func(t *testing.T) {
+		repo := &mockAddressRepo{addresses: []model.Address{{PublicID: "addr-1", UserID: "user-1", IsDefault: true}}}
+		svc := NewAddressService(repo)
+
+		resp, err := svc.UpdateAddress("user-1", "addr-1", validAddressRequest())
+		assert.NoError(t, err)
+		assert.True(t, resp.IsDefault)
+		assert.Equal(t, "Avenida Paulista", repo.updated.Street)
+	})
+
+	t.Run("another user's address is not found", func(t *testing.T) {
+		repo := &mockAddressRepo{addresses: []model.Address{{PublicID: "addr-1", UserID: "user-2"}}}
+		svc := NewAddressService(repo)
+
+		_, err := svc.UpdateAddress("user-1", "addr-1", validAddressRequest())
+		assertDomainCode(t, err, "address_not_found")
+		assert.Nil(t, repo.updated)
+	})
+}
+
+func TestDeleteAddress(t *testing.T) {
+	repo := &mockAddressRepo{addresses: []model.Address{{ID: 7, PublicID: "addr-1", UserID: "user-1"}}}
+	svc := NewAddressService(repo)
+
+	assert.NoError(t, svc.DeleteAddress("user-1", "addr-1"))
+	assert.Equal(t, uint(7), repo.deleted.ID)
+	assertDomainCode(t, svc.DeleteAddress("user-1", "addr-9"), "address_not_found")
+}
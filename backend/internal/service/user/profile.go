@@ -2,6 +2,7 @@ package service
 
 import (
 	"strings"
+	"time"
 
 	"github.com/leoferamos/aroma-sense/internal/apperror"
 	"github.com/leoferamos/aroma-sense/internal/model"
@@ -15,6 +16,7 @@ import (
 type UserProfileService interface {
 	GetByPublicID(publicID string) (*model.User, error)
 	UpdateDisplayName(publicID string, displayName string) (*model.User, error)
+	UpdateMarketingConsent(publicID string, optIn bool) (*model.User, error)
 	SetPasswordHash(publicID string, hashedPassword string) error
 	ChangePassword(publicID string, currentPassword string, newPassword string) error
 }
@@ -70,6 +72,40 @@ func (s *userProfileService) UpdateDisplayName(publicID string, displayName stri
 	return user, nil
 }
 
+// UpdateMarketingConsent records whether the user agrees to receive marketing emails
+func (s *userProfileService) UpdateMarketingConsent(publicID string, optIn bool) (*model.User, error) {
+	if publicID == "" {
+		return nil, apperror.NewCodeMessage("unauthenticated", "unauthenticated")
+	}
+	user, err := s.repo.FindByPublicID(publicID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MarketingOptIn == optIn {
+		return user, nil
+	}
+
+	oldUser := *user
+
+	// Keep when consent was given, as proof of opt-in
+	user.MarketingOptIn = optIn
+	if optIn {
+		now := time.Now()
+		user.MarketingOptInAt = &now
+	} else {
+		user.MarketingOptInAt = nil
+	}
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+
+	if s.auditLogService != nil {
+		s.auditLogService.LogUserUpdate(user.ID, user.ID, &oldUser, user)
+	}
+
+	return user, nil
+}
+
 // SetPasswordHash updates a user's password hash (low-level method)
 func (s *userProfileService) SetPasswordHash(publicID string, hashedPassword string) error {
 	user, err := s.repo.FindByPublicID(publicID)
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/envconfig"
+)
+
+const (
+	// DefaultAlertLimit is how many wishlist alert emails a user can get per window.
+	DefaultAlertLimit = 1
+	// DefaultAlertWindow is the period DefaultAlertLimit applies to.
+	DefaultAlertWindow = 24 * time.Hour
+)
+
+// Config holds tunable wishlist alert behaviour.
+type Config struct {
+	AlertLimit  int
+	AlertWindow time.Duration
+}
+
+// LoadConfigFromEnv reads wishlist settings from the environment, falling back to defaults.
+// WISHLIST_ALERT_LIMIT is a count and WISHLIST_ALERT_WINDOW a Go duration, e.g. 12h.
+func LoadConfigFromEnv() Config {
+	cfg := Config{
+		AlertLimit:  envconfig.Count("WISHLIST_ALERT_LIMIT", DefaultAlertLimit),
+		AlertWindow: envconfig.Duration("WISHLIST_ALERT_WINDOW", DefaultAlertWindow),
+	}
+	return cfg.withDefaults()
+}
+
+// withDefaults fills unset or invalid values.
+func (c Config) withDefaults() Config {
+	if c.AlertLimit <= 0 {
+		c.AlertLimit = DefaultAlertLimit
+	}
+	if c.AlertWindow <= 0 {
+		c.AlertWindow = DefaultAlertWindow
+	}
+	return c
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/leoferamos/aroma-sense/internal/notification"
+	"github.com/leoferamos/aroma-sense/internal/rate"
+	"github.com/leoferamos/aroma-sense/internal/repository"
+	"gorm.io/gorm"
+)
+
+// MaxWishlistItems caps the size of a wishlist
+const MaxWishlistItems = 100
+
+// alertBatchSize caps how many changed wishlist entries are handled per run
+const alertBatchSize = 500
+
+// WishlistService manages the authenticated user's wishlist and alerts users about saved products
+type WishlistService interface {
+	GetWishlist(userID string) (*dto.WishlistResponse, error)
+	AddItem(userID string, productSlug string) (*dto.WishlistItemResponse, bool, error)
+	RemoveItem(userID string, productSlug string) error
+	ProcessAlerts() (int, error)
+}
+
+type wishlistService struct {
+	repo     repository.WishlistRepository
+	products repository.ProductRepository
+	notifier notification.NotificationService
+	limiter  rate.RateLimiter
+	cfg      Config
+}
+
+func NewWishlistService(repo repository.WishlistRepository, products repository.ProductRepository, notifier notification.NotificationService, limiter rate.RateLimiter, cfg Config) WishlistService {
+	return &wishlistService{
+		repo:     repo,
+		products: products,
+		notifier: notifier,
+		limiter:  limiter,
+		cfg:      cfg.withDefaults(),
+	}
+}
+
+// GetWishlist returns the user's saved products, most recent first
+func (s *wishlistService) GetWishlist(userID string) (*dto.WishlistResponse, error) {
+	if userID == "" {
+		return nil, apperror.NewCodeMessage("unauthenticated", "unauthenticated")
+	}
+	items, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.WishlistResponse{Items: make([]dto.WishlistItemResponse, 0, len(items))}
+	for _, item := range items {
+		if item.Product == nil {
+			continue
+		}
+		resp.Items = append(resp.Items, dto.WishlistItemResponseFromModel(item))
+	}
+	resp.Total = len(resp.Items)
+	return resp, nil
+}
+
+// AddItem saves a product at its current price. Saving a product twice keeps the original entry;
+// the returned flag reports whether a new entry was created.
+func (s *wishlistService) AddItem(userID string, productSlug string) (*dto.WishlistItemResponse, bool, error) {
+	if userID == "" {
+		return nil, false, apperror.NewCodeMessage("unauthenticated", "unauthenticated")
+	}
+	product, err := s.findProduct(productSlug)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := s.repo.Find(userID, product.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		existing.Product = product
+		resp := dto.WishlistItemResponseFromModel(*existing)
+		return &resp, false, nil
+	}
+
+	count, err := s.repo.CountByUser(userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if count >= MaxWishlistItems {
+		return nil, false, apperror.NewCodeMessage("wishlist_limit_reached", "wishlist is full")
+	}
+
+	item := &model.WishlistItem{
+		UserID:     userID,
+		ProductID:  product.ID,
+		SavedPrice: product.Price,
+		AlertPrice: product.Price,
+		InStock:    product.StockQuantity > 0,
+	}
+	created, err := s.repo.CreateIfAbsent(item)
+	if err != nil {
+		return nil, false, err
+	}
+	if !created {
+		// Saved concurrently by another request
+		existing, err := s.repo.Find(userID, product.ID)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing != nil {
+			item = existing
+		}
+	}
+	item.Product = product
+	resp := dto.WishlistItemResponseFromModel(*item)
+	return &resp, created, nil
+}
+
+// RemoveItem removes a product from the user's wishlist
+func (s *wishlistService) RemoveItem(userID string, productSlug string) error {
+	if userID == "" {
+		return apperror.NewCodeMessage("unauthenticated", "unauthenticated")
+	}
+	product, err := s.findProduct(productSlug)
+	if err != nil {
+		return err
+	}
+	removed, err := s.repo.Delete(userID, product.ID)
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return apperror.NewCodeMessage("wishlist_item_not_found", "product is not in the wishlist")
+	}
+	return nil
+}
+
+// ProcessAlerts emails users whose saved products dropped in price or came back in stock and returns
+// how many emails were sent. A user gets at most cfg.AlertLimit emails per cfg.AlertWindow; alerts held
+// back by the limit stay pending and are skipped until the user's window resets, so they do not crowd
+// out other users' alerts.
+func (s *wishlistService) ProcessAlerts() (int, error) {
+	items, err := s.repo.FindChanged(time.Now(), alertBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var users []string
+	byUser := map[string][]model.WishlistItem{}
+	for _, item := range items {
+		if _, ok := byUser[item.UserID]; !ok {
+			users = append(users, item.UserID)
+		}
+		byUser[item.UserID] = append(byUser[item.UserID], item)
+	}
+
+	sent := 0
+	for _, userID := range users {
+		if s.alertUser(userID, byUser[userID]) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// alertUser sends one email covering all of a user's alerts and records what was announced. Changes
+// that need no alert, like a product selling out, are recorded right away.
+func (s *wishlistService) alertUser(userID string, items []model.WishlistItem) bool {
+	var alerts []model.WishlistAlert
+	var pending []model.WishlistItem
+	for _, item := range items {
+		if item.Product == nil {
+			continue
+		}
+		inStock := item.Product.StockQuantity > 0
+		priceDropped := inStock && item.Product.Price < item.AlertPrice
+		backInStock := inStock && !item.InStock
+		if priceDropped || backInStock {
+			alerts = append(alerts, model.WishlistAlert{
+				Product:      *item.Product,
+				SavedPrice:   item.SavedPrice,
+				PriceDropped: priceDropped,
+				BackInStock:  backInStock,
+			})
+			pending = append(pending, item)
+			continue
+		}
+
+		alertPrice := item.AlertPrice
+		if item.Product.Price > alertPrice {
+			alertPrice = rearmedAlertPrice(item)
+		}
+		if err := s.repo.UpdateAlertState(item.ID, alertPrice, inStock); err != nil {
+			log.Printf("wishlist item %d: failed to update alert state: %v", item.ID, err)
+		}
+	}
+	if len(alerts) == 0 || items[0].User == nil {
+		return false
+	}
+
+	if s.limiter != nil {
+		allowed, _, resetAt, err := s.limiter.Allow(context.Background(), "wishlist_alert:"+userID, s.cfg.AlertLimit, s.cfg.AlertWindow)
+		if err != nil {
+			return false
+		}
+		if !allowed {
+			s.deferAlerts(userID, pending, resetAt)
+			return false
+		}
+	}
+	if err := s.notifier.SendWishlistAlert(items[0].User.Email, alerts); err != nil {
+		log.Printf("wishlist alert for user %s failed: %v", userID, err)
+		return false
+	}
+
+	for _, item := range pending {
+		alertPrice := item.Product.Price
+		if alertPrice > item.AlertPrice {
+			alertPrice = rearmedAlertPrice(item)
+		}
+		if err := s.repo.UpdateAlertState(item.ID, alertPrice, true); err != nil {
+			log.Printf("wishlist item %d: failed to update alert state: %v", item.ID, err)
+		}
+	}
+	return true
+}
+
+// deferAlerts keeps rate limited alerts out of the next runs until the user's window resets
+func (s *wishlistService) deferAlerts(userID string, pending []model.WishlistItem, resetAt time.Time) {
+	if now := time.Now(); !resetAt.After(now) {
+		resetAt = now.Add(s.cfg.AlertWindow)
+	}
+	ids := make([]uint, 0, len(pending))
+	for _, item := range pending {
+		ids = append(ids, item.ID)
+	}
+	if err := s.repo.DeferAlerts(ids, resetAt); err != nil {
+		log.Printf("wishlist alerts for user %s: failed to defer: %v", userID, err)
+	}
+}
+
+// rearmedAlertPrice is the alert price after the product's price went up: the next drop below the
+// current price is announced again, but never above the price the product was saved at
+func rearmedAlertPrice(item model.WishlistItem) money.Amount {
+	if item.Product.Price < item.SavedPrice {
+		return item.Product.Price
+	}
+	return item.SavedPrice
+}
+
+func (s *wishlistService) findProduct(slug string) (*model.Product, error) {
+	product, err := s.products.FindBySlug(slug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NewCodeMessage("product_not_found", "product not found")
+		}
+		return nil, err
+	}
+	return &product, nil
+}
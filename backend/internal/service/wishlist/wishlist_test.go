@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leoferamos/aroma-sense/internal/apperror"
+	"github.com/leoferamos/aroma-sense/internal/dto"
+	"github.com/leoferamos/aroma-sense/internal/model"
+	"github.com/leoferamos/aroma-sense/internal/money"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type mockWishlistRepo struct {
+	items    []model.WishlistItem
+	changed  []model.WishlistItem
+	created  *model.WishlistItem
+	states   map[uint]alertState
+	deferred map[uint]time.Time
+}
+
+type alertState struct {
+	price   money.Amount
+	inStock bool
+}
+
+func (m *mockWishlistRepo) ListByUser(userID string) ([]model.WishlistItem, error) {
+	return m.items, nil
+}
+func (m *mockWishlistRepo) Find(userID string, productID uint) (*model.WishlistItem, error) {
+	for i := range m.items {
+		if m.items[i].UserID == userID && m.items[i].ProductID == productID {
+			item := m.items[i]
+			return &item, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockWishlistRepo) CountByUser(userID string) (int64, error) {
+	return int64(len(m.items)), nil
+}
+func (m *mockWishlistRepo) CreateIfAbsent(item *model.WishlistItem) (bool, error) {
+	m.created = item
+	m.items = append(m.items, *item)
+	return true, nil
+}
+func (m *mockWishlistRepo) Delete(userID string, productID uint) (int64, error) {
+	for i := range m.items {
+		if m.items[i].UserID == userID && m.items[i].ProductID == productID {
+			m.items = append(m.items[:i], m.items[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+func (m *mockWishlistRepo) FindChanged(now time.Time, limit int) ([]model.WishlistItem, error) {
+	return m.changed, nil
+}
+func (m *mockWishlistRepo) DeferAlerts(ids []uint, until time.Time) error {
+	for _, id := range ids {
+		m.deferred[id] = until
+	}
+	return nil
+}
+func (m *mockWishlistRepo) UpdateAlertState(id uint, alertPrice money.Amount, inStock bool) error {
+	m.states[id] = alertState{price: alertPrice, inStock: inStock}
+	return nil
+}
+
+type mockProductRepo struct {
+	products map[string]model.Product
+}
+
+func (m *mockProductRepo) Create(input dto.ProductFormDTO, imageURL string, thumbnailURL string) (uint, error) {
+	return 0, nil
+}
+func (m *mockProductRepo) FindAll(limit int) ([]model.Product, error) { return nil, nil }
+func (m *mockProductRepo) FindAllPaginated(limit int, offset int) ([]model.Product, int, error) {
+	return nil, 0, nil
+}
+func (m *mockProductRepo) FindByID(id uint) (model.Product, error) { return model.Product{}, nil }
+func (m *mockProductRepo) FindBySlug(slug string) (model.Product, error) {
+	if p, ok := m.products[slug]; ok {
+		return p, nil
+	}
+	return model.Product{}, gorm.ErrRecordNotFound
+}
+func (m *mockProductRepo) SearchProducts(ctx context.Context, query string, limit int, offset int, sort string) ([]model.Product, int, error) {
+	return nil, 0, nil
+}
+func (m *mockProductRepo) SearchProductsByGender(ctx context.Context, query string, limit int, offset int, sort string, gender string) ([]model.Product, int, error) {
+	return nil, 0, nil
+}
+func (m *mockProductRepo) FilterProducts(ctx context.Context, query string, filter dto.ProductFilter, limit int, offset int, sort string) ([]model.Product, int, error) {
+	return nil, 0, nil
+}
+func (m *mockProductRepo) FacetCounts(ctx context.Context, query string, filter dto.ProductFilter) (dto.ProductFacets, error) {
+	return dto.ProductFacets{}, nil
+}
+func (m *mockProductRepo) Suggest(ctx context.Context, term string, limit int) (dto.SuggestResponse, error) {
+	return dto.SuggestResponse{}, nil
+}
+func (m *mockProductRepo) Update(product *model.Product) error               { return nil }
+func (m *mockProductRepo) Delete(id uint) error                              { return nil }
+func (m *mockProductRepo) DecrementStock(variantID uint, quantity int) error { return nil }
+func (m *mockProductRepo) FindVariantBySKU(sku string) (*model.ProductVariant, error) {
+	return nil, nil
+}
+func (m *mockProductRepo) CreateVariant(variant *model.ProductVariant) error { return nil }
+func (m *mockProductRepo) UpdateVariant(variant *model.ProductVariant, fields []string) error {
+	return nil
+}
+func (m *mockProductRepo) DeleteVariant(variant *model.ProductVariant) error        { return nil }
+func (m *mockProductRepo) CreateImage(image *model.ProductImage) error              { return nil }
+func (m *mockProductRepo) UpdateImage(image *model.ProductImage) error              { return nil }
+func (m *mockProductRepo) DeleteImage(image *model.ProductImage) error              { return nil }
+func (m *mockProductRepo) ReorderImages(productID uint, imageIDs []uint) error      { return nil }
+func (m *mockProductRepo) EnsureUniqueSlug(base string) (string, error)             { return "", nil }
+func (m *mockProductRepo) UpsertProductEmbedding(productID uint, e []float32) error { return nil }
+func (m *mockProductRepo) HasProductEmbedding(productID uint) (bool, error)         { return false, nil }
+func (m *mockProductRepo) FindSimilarProductsByEmbedding(ctx context.Context, embedding []float32, limit int) ([]model.Product, error) {
+	return nil, nil
+}
+func (m *mockProductRepo) FindSimilarProductsByEmbeddingAndGender(ctx context.Context, embedding []float32, limit int, gender string) ([]model.Product, error) {
+	return nil, nil
+}
+
+type mockNotifier struct {
+	alerts map[string][]model.WishlistAlert
+}
+
+func (m *mockNotifier) SendPasswordResetCode(to, code string) error           { return nil }
+func (m *mockNotifier) SendWelcomeEmail(to, name string) error                { return nil }
+func (m *mockNotifier) SendOrderConfirmation(to string, o *model.Order) error { return nil }
+func (m *mockNotifier) SendGuestOrderConfirmation(to string, o *model.Order, lookupToken string) error {
+	return nil
+}
+func (m *mockNotifier) SendOrderCancelled(to string, o *model.Order, refundedAmount money.Amount) error {
+	return nil
+}
+func (m *mockNotifier) SendAccountDeactivated(to, reason string, deadline string) error { return nil }
+func (m *mockNotifier) SendContestationReceived(to string) error                        { return nil }
+func (m *mockNotifier) SendContestationResult(to string, approved bool, reason string) error {
+	return nil
+}
+func (m *mockNotifier) SendDeletionRequested(to string, cancelLink string) error { return nil }
+func (m *mockNotifier) SendDeletionAutoConfirmed(to string) error                { return nil }
+func (m *mockNotifier) SendDeletionCancelled(to string) error                    { return nil }
+func (m *mockNotifier) SendDataAnonymized(to string) error                       { return nil }
+func (m *mockNotifier) SendPromotional(to, subject, htmlBody string) error       { return nil }
+func (m *mockNotifier) SendAbandonedCartReminder(to string, items []model.CartItem) error {
+	return nil
+}
+func (m *mockNotifier) SendWishlistAlert(to string, alerts []model.WishlistAlert) error {
+	m.alerts[to] = append(m.alerts[to], alerts...)
+	return nil
+}
+func (m *mockNotifier) SendAdminAlert(subject, message string) error { return nil }
+
+type stubLimiter struct {
+	allowed bool
+	resetAt time.Time
+	buckets []string
+}
+
+func (l *stubLimiter) Allow(ctx context.Context, bucket string, limit int, window time.Duration) (bool, int, time.Time, error) {
+	l.buckets = append(l.buckets, bucket)
+	return l.allowed, 0, l.resetAt, nil
+}
+
+func errorCode(err error) string {
+	if de, ok := err.(*apperror.DomainError); ok {
+		return de.Code
+	}
+	return ""
+}
+
+func newTestService(products ...model.Product) (*wishlistService, *mockWishlistRepo, *mockNotifier, *stubLimiter) {
+	repo := &mockWishlistRepo{states: map[uint]alertState{}, deferred: map[uint]time.Time{}}
+	productRepo := &mockProductRepo{products: map[string]model.Product{}}
+	for _, p := range products {
+		productRepo.products[p.Slug] = p
+	}
+	notifier := &mockNotifier{alerts: map[string][]model.WishlistAlert{}}
+	limiter := &stubLimiter{allowed: true}
+	svc := NewWishlistService(repo, productRepo, notifier, limiter, Config{}).(*wishlistService)
+	return svc, repo, notifier, limiter
+}
+
+func TestAddItem(t *testing.T) {
+	sauvage := model.Product{ID: 1, Slug: "sauvage", Name: "Sauvage", Price: money.FromCents(59990), StockQuantity: 0}
+
+	t.Run("saves the current price and stock", func(t *testing.T) {
+		svc, repo, _, _ := newTestService(sauvage)
+
+		resp, created, err := svc.AddItem("user-1", "sauvage")
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, money.FromCents(59990), repo.created.SavedPrice)
+		assert.Equal(t, money.FromCents(59990), repo.created.AlertPrice)
+		assert.False(t, repo.created.InStock)
+		assert.Equal(t, "sauvage", resp.ProductSlug)
+		assert.False(t, resp.InStock)
+	})
+
+	t.Run("keeps the original entry", func(t *testing.T) {
+		svc, repo, _, _ := newTestService(sauvage)
+		repo.items = []model.WishlistItem{{UserID: "user-1", ProductID: 1, SavedPrice: money.FromCents(69990)}}
+
+		resp, created, err := svc.AddItem("user-1", "sauvage")
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Nil(t, repo.created)
+		assert.Equal(t, money.FromCents(69990), resp.SavedPrice)
+		assert.True(t, resp.PriceDropped)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		svc, _, _, _ := newTestService()
+
+		_, _, err := svc.AddItem("user-1", "missing")
+		assert.Equal(t, "product_not_found", errorCode(err))
+	})
+
+	t.Run("full wishlist", func(t *testing.T) {
+		svc, repo, _, _ := newTestService(sauvage)
+		repo.items = make([]model.WishlistItem, MaxWishlistItems)
+
+		_, _, err := svc.AddItem("user-1", "sauvage")
+		assert.Equal(t, "wishlist_limit_reached", errorCode(err))
+	})
+}
+
+func TestRemoveItem(t *testing.T) {
+	svc, repo, _, _ := newTestService(model.Product{ID: 1, Slug: "sauvage"})
+	repo.items = []model.WishlistItem{{UserID: "user-1", ProductID: 1}}
+
+	assert.NoError(t, svc.RemoveItem("user-1", "sauvage"))
+	assert.Equal(t, "wishlist_item_not_found", errorCode(svc.RemoveItem("user-1", "sauvage")))
+}
+
+func TestProcessAlerts(t *testing.T) {
+	ana := &model.User{PublicID: "user-1", Email: "ana@example.com"}
+	changed := func() []model.WishlistItem {
+		return []model.WishlistItem{
+			// Price dropped below the saved price
+			{ID: 1, UserID: "user-1", User: ana, SavedPrice: money.FromCents(50000), AlertPrice: money.FromCents(50000), InStock: true,
+				Product: &model.Product{Slug: "sauvage", Price: money.FromCents(42000), StockQuantity: 3}},
+			// Back in stock
+			{ID: 2, UserID: "user-1", User: ana, SavedPrice: money.FromCents(30000), AlertPrice: money.FromCents(30000), InStock: false,
+				Product: &model.Product{Slug: "kaiak", Price: money.FromCents(30000), StockQuantity: 5}},
+			// Sold out: recorded without an alert
+			{ID: 3, UserID: "user-1", User: ana, SavedPrice: money.FromCents(20000), AlertPrice: money.FromCents(20000), InStock: true,
+				Product: &model.Product{Slug: "malbec", Price: money.FromCents(20000), StockQuantity: 0}},
+			// Price went back up after an alert: the alert re-arms at the new price
+			{ID: 4, UserID: "user-1", User: ana, SavedPrice: money.FromCents(40000), AlertPrice: money.FromCents(30000), InStock: true,
+				Product: &model.Product{Slug: "egeo", Price: money.FromCents(35000), StockQuantity: 2}},
+		}
+	}
+
+	t.Run("sends one email per user and records the alerts", func(t *testing.T) {
+		svc, repo, notifier, limiter := newTestService()
+		repo.changed = changed()
+
+		sent, err := svc.ProcessAlerts()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		assert.Equal(t, []string{"wishlist_alert:user-1"}, limiter.buckets)
+
+		alerts := notifier.alerts["ana@example.com"]
+		if assert.Len(t, alerts, 2) {
+			assert.True(t, alerts[0].PriceDropped)
+			assert.False(t, alerts[0].BackInStock)
+			assert.True(t, alerts[1].BackInStock)
+			assert.False(t, alerts[1].PriceDropped)
+		}
+		assert.Equal(t, alertState{price: money.FromCents(42000), inStock: true}, repo.states[1])
+		assert.Equal(t, alertState{price: money.FromCents(30000), inStock: true}, repo.states[2])
+		assert.Equal(t, alertState{price: money.FromCents(20000), inStock: false}, repo.states[3])
+		assert.Equal(t, alertState{price: money.FromCents(35000), inStock: true}, repo.states[4])
+	})
+
+	t.Run("rate limited alerts stay pending", func(t *testing.T) {
+		svc, repo, notifier, limiter := newTestService()
+		repo.changed = changed()
+		limiter.allowed = false
+		limiter.resetAt = time.Now().Add(30 * time.Minute)
+
+		sent, err := svc.ProcessAlerts()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, sent)
+		assert.Empty(t, notifier.alerts)
+		assert.NotContains(t, repo.states, uint(1))
+		assert.NotContains(t, repo.states, uint(2))
+		// Changes that need no alert are still recorded
+		assert.Contains(t, repo.states, uint(3))
+		// Held back alerts are skipped until the user's window resets
+		assert.Equal(t, map[uint]time.Time{1: limiter.resetAt, 2: limiter.resetAt}, repo.deferred)
+	})
+
+	t.Run("deferral falls back to the alert window", func(t *testing.T) {
+		svc, repo, _, limiter := newTestService()
+		svc.cfg.AlertWindow = time.Hour
+		repo.changed = changed()
+		limiter.allowed = false
+
+		_, err := svc.ProcessAlerts()
+		assert.NoError(t, err)
+		if assert.Contains(t, repo.deferred, uint(1)) {
+			assert.WithinDuration(t, time.Now().Add(time.Hour), repo.deferred[1], time.Minute)
+		}
+	})
+}
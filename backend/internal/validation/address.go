@@ -0,0 +1,25 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+)
+
+var cepFormat = regexp.MustCompile(`^[0-9]{5}-?[0-9]{3}$`)
+
+var brazilianUFs = map[string]struct{}{
+	"AC": {}, "AL": {}, "AP": {}, "AM": {}, "BA": {}, "CE": {}, "DF": {}, "ES": {}, "GO": {},
+	"MA": {}, "MT": {}, "MS": {}, "MG": {}, "PA": {}, "PB": {}, "PR": {}, "PE": {}, "PI": {},
+	"RJ": {}, "RN": {}, "RS": {}, "RO": {}, "RR": {}, "SC": {}, "SP": {}, "SE": {}, "TO": {},
+}
+
+// IsValidUF reports whether s is one of the 27 Brazilian federative units (case-insensitive).
+func IsValidUF(s string) bool {
+	_, ok := brazilianUFs[strings.ToUpper(strings.TrimSpace(s))]
+	return ok
+}
+
+// IsValidCEP reports whether s holds exactly the 8 digits of a CEP, with or without the hyphen.
+func IsValidCEP(s string) bool {
+	return cepFormat.MatchString(strings.TrimSpace(s))
+}
@@ -0,0 +1,20 @@
+-- Create order_events table to keep the full history of an order
+CREATE TABLE IF NOT EXISTS order_events (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL,
+    type VARCHAR(30) NOT NULL,
+    from_status VARCHAR(30),
+    to_status VARCHAR(30),
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255),
+    note TEXT,
+    metadata JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT fk_order_events_order_id FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    CONSTRAINT check_order_event_type CHECK (type IN ('created', 'status_changed', 'payment_updated', 'shipping_updated')),
+    CONSTRAINT check_order_event_actor CHECK (actor_type IN ('user', 'admin', 'system', 'webhook'))
+);
+
+-- Timeline lookups are always per order, in chronological order
+CREATE INDEX IF NOT EXISTS idx_order_events_order_id_created_at ON order_events(order_id, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS check_products_stock_quantity;
+ALTER TABLE orders DROP COLUMN IF EXISTS stock_reserved;
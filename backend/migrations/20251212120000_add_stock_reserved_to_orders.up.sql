@@ -0,0 +1,6 @@
+-- Track whether stock for an order was reserved at checkout time
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS stock_reserved BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Prevent stock from ever going negative
+ALTER TABLE products DROP CONSTRAINT IF EXISTS check_products_stock_quantity;
+ALTER TABLE products ADD CONSTRAINT check_products_stock_quantity CHECK (stock_quantity >= 0);
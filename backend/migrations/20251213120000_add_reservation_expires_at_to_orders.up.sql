@@ -0,0 +1,6 @@
+-- Deadline after which an unpaid order's stock reservation is released
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS reservation_expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_orders_reservation_expires_at
+    ON orders (reservation_expires_at)
+    WHERE status = 'pending' AND stock_reserved = TRUE;
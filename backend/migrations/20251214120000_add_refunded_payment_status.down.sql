@@ -0,0 +1,2 @@
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS check_payment_status;
+ALTER TABLE payments ADD CONSTRAINT check_payment_status CHECK (status IN ('pending','processing','succeeded','failed','canceled'));
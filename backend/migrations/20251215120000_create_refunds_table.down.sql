@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS refunds;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS check_payment_refunded_cents;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS check_payment_status;
+ALTER TABLE payments ADD CONSTRAINT check_payment_status CHECK (status IN ('pending','processing','succeeded','failed','canceled','refunded'));
+ALTER TABLE payments DROP COLUMN IF EXISTS refunded_cents;
@@ -0,0 +1,31 @@
+-- Track how much of each payment has been returned
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refunded_cents BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS check_payment_status;
+ALTER TABLE payments ADD CONSTRAINT check_payment_status CHECK (status IN ('pending','processing','succeeded','failed','canceled','refunded','partially_refunded'));
+ALTER TABLE payments ADD CONSTRAINT check_payment_refunded_cents CHECK (refunded_cents >= 0 AND refunded_cents <= amount_cents);
+
+-- Create refunds table with one row per refund issued at the gateway
+CREATE TABLE IF NOT EXISTS refunds (
+    id SERIAL PRIMARY KEY,
+    payment_id INTEGER NOT NULL,
+    intent_id VARCHAR(255) NOT NULL,
+    order_public_id UUID NOT NULL,
+    provider_refund_id VARCHAR(255),
+    amount_cents BIGINT NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    reason TEXT,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255),
+    error_message TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT fk_refunds_payment_id FOREIGN KEY (payment_id) REFERENCES payments(id) ON DELETE CASCADE,
+    CONSTRAINT check_refund_status CHECK (status IN ('pending','succeeded','failed')),
+    CONSTRAINT check_refund_amount CHECK (amount_cents > 0),
+    CONSTRAINT check_refund_actor CHECK (actor_type IN ('user', 'admin', 'system', 'webhook'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_payment_id ON refunds(payment_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_order_public_id ON refunds(order_public_id);
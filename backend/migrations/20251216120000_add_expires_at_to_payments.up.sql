@@ -0,0 +1,2 @@
+-- Deadline after which an unpaid charge (e.g. a Pix BR Code) can no longer be paid
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_payments_pending_expires_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS boleto_digitable_line;
+ALTER TABLE payments DROP COLUMN IF EXISTS boleto_barcode;
+ALTER TABLE payments DROP COLUMN IF EXISTS due_date;
@@ -0,0 +1,8 @@
+-- Boleto charges: printable codes and the due date shown to the customer
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS due_date DATE;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS boleto_barcode VARCHAR(44);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS boleto_digitable_line VARCHAR(60);
+
+CREATE INDEX IF NOT EXISTS idx_payments_pending_expires_at
+    ON payments (provider, expires_at)
+    WHERE status = 'pending' AND expires_at IS NOT NULL;
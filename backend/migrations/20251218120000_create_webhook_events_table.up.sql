@@ -0,0 +1,23 @@
+-- Inbox of gateway notifications, kept as received and processed at most once per event
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(100),
+    payload TEXT NOT NULL,
+    normalized JSONB,
+    signature_valid BOOLEAN NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ,
+    processed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT uq_webhook_events_provider_event UNIQUE (provider, event_id),
+    CONSTRAINT check_webhook_event_status CHECK (status IN ('pending','processing','processed','failed','rejected','ignored'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_status ON webhook_events(status);
+CREATE INDEX IF NOT EXISTS idx_webhook_events_next_attempt_at ON webhook_events(next_attempt_at) WHERE status = 'failed';
@@ -0,0 +1,4 @@
+-- Reconciliation looks up payments still waiting for a webhook, per gateway, oldest first
+CREATE INDEX IF NOT EXISTS idx_payments_open_created_at
+    ON payments (provider, created_at)
+    WHERE status IN ('pending', 'processing');
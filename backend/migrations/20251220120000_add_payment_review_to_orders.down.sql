@@ -0,0 +1,8 @@
+DELETE FROM order_events WHERE type = 'payment_review';
+ALTER TABLE order_events DROP CONSTRAINT IF EXISTS check_order_event_type;
+ALTER TABLE order_events ADD CONSTRAINT check_order_event_type
+    CHECK (type IN ('created', 'status_changed', 'payment_updated', 'shipping_updated'));
+
+DROP INDEX IF EXISTS idx_orders_payment_review;
+ALTER TABLE orders DROP COLUMN IF EXISTS payment_review_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS payment_review_reason;
@@ -0,0 +1,11 @@
+-- Orders whose payment does not match the order total are held for manual review
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_review_reason VARCHAR(50);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_review_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_orders_payment_review
+    ON orders (payment_review_at)
+    WHERE payment_review_reason IS NOT NULL;
+
+ALTER TABLE order_events DROP CONSTRAINT IF EXISTS check_order_event_type;
+ALTER TABLE order_events ADD CONSTRAINT check_order_event_type
+    CHECK (type IN ('created', 'status_changed', 'payment_updated', 'shipping_updated', 'payment_review'));
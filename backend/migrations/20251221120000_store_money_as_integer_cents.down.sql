@@ -0,0 +1,33 @@
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS price_at_purchase DECIMAL(10,2);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS subtotal DECIMAL(10,2);
+UPDATE order_items SET
+    price_at_purchase = price_at_purchase_cents / 100.0,
+    subtotal = subtotal_cents / 100.0;
+ALTER TABLE order_items ALTER COLUMN price_at_purchase SET NOT NULL;
+ALTER TABLE order_items ALTER COLUMN subtotal SET NOT NULL;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price_at_purchase_cents;
+ALTER TABLE order_items DROP COLUMN IF EXISTS subtotal_cents;
+ALTER TABLE order_items ADD CONSTRAINT positive_price CHECK (price_at_purchase >= 0);
+ALTER TABLE order_items ADD CONSTRAINT positive_subtotal CHECK (subtotal >= 0);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total_amount DECIMAL(10,2);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_price DECIMAL(10,2) NOT NULL DEFAULT 0;
+UPDATE orders SET
+    total_amount = total_amount_cents / 100.0,
+    shipping_price = shipping_price_cents / 100.0;
+ALTER TABLE orders ALTER COLUMN total_amount SET NOT NULL;
+ALTER TABLE orders DROP COLUMN IF EXISTS total_amount_cents;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_price_cents;
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+ALTER TABLE orders ADD CONSTRAINT check_total_amount CHECK (total_amount >= 0);
+
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS price DECIMAL(10,2);
+UPDATE cart_items SET price = price_cents / 100.0;
+ALTER TABLE cart_items ALTER COLUMN price SET NOT NULL;
+ALTER TABLE cart_items DROP COLUMN IF EXISTS price_cents;
+ALTER TABLE cart_items ADD CONSTRAINT positive_price CHECK (price > 0);
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price FLOAT;
+UPDATE products SET price = price_cents / 100.0;
+ALTER TABLE products ALTER COLUMN price SET NOT NULL;
+ALTER TABLE products DROP COLUMN IF EXISTS price_cents;
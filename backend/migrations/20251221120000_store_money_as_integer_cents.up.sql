@@ -0,0 +1,36 @@
+-- Store monetary values as integer cents instead of FLOAT/DECIMAL so totals never drift.
+-- Existing values are converted with half-up rounding to the nearest cent.
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS price_cents BIGINT;
+UPDATE products SET price_cents = ROUND(price::numeric * 100)::bigint;
+ALTER TABLE products ALTER COLUMN price_cents SET NOT NULL;
+ALTER TABLE products DROP COLUMN IF EXISTS price;
+
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS price_cents BIGINT;
+UPDATE cart_items SET price_cents = ROUND(price * 100)::bigint;
+ALTER TABLE cart_items ALTER COLUMN price_cents SET NOT NULL;
+ALTER TABLE cart_items DROP COLUMN IF EXISTS price;
+ALTER TABLE cart_items ADD CONSTRAINT positive_price CHECK (price_cents > 0);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total_amount_cents BIGINT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS shipping_price_cents BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'brl';
+UPDATE orders SET
+    total_amount_cents = ROUND(total_amount * 100)::bigint,
+    shipping_price_cents = ROUND(shipping_price * 100)::bigint;
+ALTER TABLE orders ALTER COLUMN total_amount_cents SET NOT NULL;
+ALTER TABLE orders DROP COLUMN IF EXISTS total_amount;
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_price;
+ALTER TABLE orders ADD CONSTRAINT check_total_amount CHECK (total_amount_cents >= 0);
+
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS price_at_purchase_cents BIGINT;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS subtotal_cents BIGINT;
+UPDATE order_items SET
+    price_at_purchase_cents = ROUND(price_at_purchase * 100)::bigint,
+    subtotal_cents = ROUND(subtotal * 100)::bigint;
+ALTER TABLE order_items ALTER COLUMN price_at_purchase_cents SET NOT NULL;
+ALTER TABLE order_items ALTER COLUMN subtotal_cents SET NOT NULL;
+ALTER TABLE order_items DROP COLUMN IF EXISTS price_at_purchase;
+ALTER TABLE order_items DROP COLUMN IF EXISTS subtotal;
+ALTER TABLE order_items ADD CONSTRAINT positive_price CHECK (price_at_purchase_cents >= 0);
+ALTER TABLE order_items ADD CONSTRAINT positive_subtotal CHECK (subtotal_cents >= 0);
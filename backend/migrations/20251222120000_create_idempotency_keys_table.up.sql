@@ -0,0 +1,22 @@
+-- Responses to requests sent with an Idempotency-Key, replayed when the client retries
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    path VARCHAR(255) NOT NULL,
+    fingerprint VARCHAR(80) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'in_flight',
+    response_status INTEGER,
+    response_content_type VARCHAR(100),
+    response_body BYTEA,
+    locked_until TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT uq_idempotency_keys_user_key UNIQUE (user_id, key),
+    CONSTRAINT check_idempotency_key_status CHECK (status IN ('in_flight', 'completed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
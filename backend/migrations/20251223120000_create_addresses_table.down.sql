@@ -0,0 +1,11 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS shipping_recipient,
+    DROP COLUMN IF EXISTS shipping_cep,
+    DROP COLUMN IF EXISTS shipping_street,
+    DROP COLUMN IF EXISTS shipping_number,
+    DROP COLUMN IF EXISTS shipping_complement,
+    DROP COLUMN IF EXISTS shipping_neighborhood,
+    DROP COLUMN IF EXISTS shipping_city,
+    DROP COLUMN IF EXISTS shipping_uf;
+
+DROP TABLE IF EXISTS addresses;
@@ -0,0 +1,39 @@
+-- Address book: structured Brazilian addresses per user
+CREATE TABLE IF NOT EXISTS addresses (
+    id SERIAL PRIMARY KEY,
+    public_id UUID NOT NULL DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    recipient VARCHAR(120) NOT NULL,
+    cep VARCHAR(8) NOT NULL,
+    street VARCHAR(255) NOT NULL,
+    number VARCHAR(20) NOT NULL,
+    complement VARCHAR(120) NOT NULL DEFAULT '',
+    neighborhood VARCHAR(120) NOT NULL,
+    city VARCHAR(120) NOT NULL,
+    uf VARCHAR(2) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    -- Constraints
+    CONSTRAINT uq_addresses_public_id UNIQUE (public_id),
+    CONSTRAINT fk_addresses_user_id FOREIGN KEY (user_id) REFERENCES users(public_id) ON DELETE CASCADE,
+    CONSTRAINT check_addresses_cep CHECK (cep ~ '^[0-9]{8}$'),
+    CONSTRAINT check_addresses_uf CHECK (uf ~ '^[A-Z]{2}$')
+);
+
+CREATE INDEX IF NOT EXISTS idx_addresses_user_id ON addresses(user_id);
+
+-- At most one default address per user
+CREATE UNIQUE INDEX IF NOT EXISTS uq_addresses_user_default ON addresses(user_id) WHERE is_default;
+
+-- Structured snapshot of the delivery address on orders
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS shipping_recipient VARCHAR(120) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS shipping_cep VARCHAR(8) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS shipping_street VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS shipping_number VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS shipping_complement VARCHAR(120) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS shipping_neighborhood VARCHAR(120) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS shipping_city VARCHAR(120) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS shipping_uf VARCHAR(2) NOT NULL DEFAULT '';
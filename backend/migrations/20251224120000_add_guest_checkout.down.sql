@@ -0,0 +1,26 @@
+-- Guest rows cannot be kept once user_id is mandatory again
+DELETE FROM payments WHERE user_id IS NULL;
+DELETE FROM orders WHERE user_id IS NULL;
+DELETE FROM carts WHERE user_id IS NULL;
+
+DROP INDEX IF EXISTS idx_payments_guest_id;
+ALTER TABLE payments
+    DROP CONSTRAINT IF EXISTS check_payments_owner,
+    DROP COLUMN IF EXISTS guest_id,
+    ALTER COLUMN user_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_orders_guest_email;
+DROP INDEX IF EXISTS idx_orders_guest_id;
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS check_orders_owner,
+    DROP CONSTRAINT IF EXISTS uq_orders_guest_lookup_token_hash,
+    DROP COLUMN IF EXISTS guest_lookup_token_hash,
+    DROP COLUMN IF EXISTS guest_email,
+    DROP COLUMN IF EXISTS guest_id,
+    ALTER COLUMN user_id SET NOT NULL;
+
+ALTER TABLE carts
+    DROP CONSTRAINT IF EXISTS check_carts_owner,
+    DROP CONSTRAINT IF EXISTS uq_carts_guest_id,
+    DROP COLUMN IF EXISTS guest_id,
+    ALTER COLUMN user_id SET NOT NULL;
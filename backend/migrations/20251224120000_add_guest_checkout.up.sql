@@ -0,0 +1,27 @@
+-- Guest checkout: carts, orders and payments can belong to a guest session instead of a user
+
+ALTER TABLE carts
+    ALTER COLUMN user_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS guest_id UUID,
+    ADD CONSTRAINT uq_carts_guest_id UNIQUE (guest_id),
+    ADD CONSTRAINT check_carts_owner CHECK ((user_id IS NULL) <> (guest_id IS NULL));
+
+ALTER TABLE orders
+    ALTER COLUMN user_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS guest_id UUID,
+    ADD COLUMN IF NOT EXISTS guest_email VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS guest_lookup_token_hash VARCHAR(64),
+    ADD CONSTRAINT uq_orders_guest_lookup_token_hash UNIQUE (guest_lookup_token_hash),
+    ADD CONSTRAINT check_orders_owner CHECK (
+        user_id IS NOT NULL OR (guest_email IS NOT NULL AND guest_lookup_token_hash IS NOT NULL)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_orders_guest_id ON orders(guest_id) WHERE guest_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_guest_email ON orders(LOWER(guest_email)) WHERE guest_email IS NOT NULL;
+
+ALTER TABLE payments
+    ALTER COLUMN user_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS guest_id UUID,
+    ADD CONSTRAINT check_payments_owner CHECK (user_id IS NOT NULL OR guest_id IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS idx_payments_guest_id ON payments(guest_id) WHERE guest_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_cart_items_cart_updated_at;
+DROP INDEX IF EXISTS idx_carts_guest_updated_at;
@@ -0,0 +1,3 @@
+-- Lets the guest cart cleanup job find abandoned guest carts without scanning user carts
+CREATE INDEX IF NOT EXISTS idx_carts_guest_updated_at ON carts(updated_at) WHERE guest_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_cart_items_cart_updated_at ON cart_items(cart_id, updated_at);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_orders_promotion_id;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS promotion_code,
+    DROP COLUMN IF EXISTS promotion_id,
+    DROP COLUMN IF EXISTS discount_cents;
+
+ALTER TABLE carts DROP COLUMN IF EXISTS promotion_code;
+
+DROP TABLE IF EXISTS promotion_redemptions;
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,53 @@
+-- Coupon promotions and the orders that redeemed them
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL,
+    name VARCHAR(128) NOT NULL,
+    description TEXT,
+    type VARCHAR(20) NOT NULL,
+    percent_off INTEGER NOT NULL DEFAULT 0,
+    amount_off_cents BIGINT NOT NULL DEFAULT 0,
+    buy_quantity INTEGER NOT NULL DEFAULT 0,
+    get_quantity INTEGER NOT NULL DEFAULT 0,
+    brand VARCHAR(64),
+    category VARCHAR(64),
+    min_subtotal_cents BIGINT NOT NULL DEFAULT 0,
+    max_uses INTEGER,
+    max_uses_per_user INTEGER,
+    used_count INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT uq_promotions_code UNIQUE (code),
+    CONSTRAINT check_promotions_type CHECK (type IN ('percentage', 'fixed_amount', 'free_shipping', 'buy_x_get_y')),
+    CONSTRAINT check_promotions_percent_off CHECK (percent_off BETWEEN 0 AND 100),
+    CONSTRAINT check_promotions_amounts CHECK (amount_off_cents >= 0 AND min_subtotal_cents >= 0),
+    CONSTRAINT check_promotions_used_count CHECK (max_uses IS NULL OR used_count <= max_uses)
+);
+
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id SERIAL PRIMARY KEY,
+    promotion_id INTEGER NOT NULL REFERENCES promotions(id) ON DELETE RESTRICT,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    user_id UUID,
+    guest_email VARCHAR(255),
+    discount_cents BIGINT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT uq_promotion_redemptions_order UNIQUE (order_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_promotion_user ON promotion_redemptions(promotion_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_promotion_guest_email ON promotion_redemptions(promotion_id, LOWER(guest_email));
+
+ALTER TABLE carts ADD COLUMN IF NOT EXISTS promotion_code VARCHAR(64);
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS discount_cents BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS promotion_id INTEGER REFERENCES promotions(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS promotion_code VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_orders_promotion_id ON orders(promotion_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS cart_reminders;
+
+ALTER TABLE users DROP COLUMN IF EXISTS marketing_opt_in_at;
+ALTER TABLE users DROP COLUMN IF EXISTS marketing_opt_in;
@@ -0,0 +1,22 @@
+-- Marketing email consent, required before abandoned cart reminders are sent
+ALTER TABLE users ADD COLUMN IF NOT EXISTS marketing_opt_in BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS marketing_opt_in_at TIMESTAMPTZ;
+
+-- One row per abandoned cart reminder sent. cart_activity_at is the cart's last activity when the
+-- reminder went out, so each abandonment gets its own sequence of reminders.
+CREATE TABLE IF NOT EXISTS cart_reminders (
+    id SERIAL PRIMARY KEY,
+    cart_id INTEGER NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    cart_activity_at TIMESTAMPTZ NOT NULL,
+    sequence INTEGER NOT NULL,
+    item_count INTEGER NOT NULL DEFAULT 0,
+    cart_value_cents BIGINT NOT NULL DEFAULT 0,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT uq_cart_reminders_sequence UNIQUE (cart_id, cart_activity_at, sequence),
+    CONSTRAINT check_cart_reminders_sequence CHECK (sequence > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cart_reminders_sent_at ON cart_reminders(sent_at);
+CREATE INDEX IF NOT EXISTS idx_cart_reminders_user_id ON cart_reminders(user_id);
@@ -0,0 +1,17 @@
+-- Products users saved for later. alert_price_cents is the price the last price-drop alert was
+-- sent for (the saved price until then) and in_stock the stock state last seen by the alert job.
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    saved_price_cents BIGINT NOT NULL,
+    alert_price_cents BIGINT NOT NULL,
+    in_stock BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT uq_wishlist_items_user_product UNIQUE (user_id, product_id),
+    CONSTRAINT check_wishlist_items_prices CHECK (saved_price_cents >= 0 AND alert_price_cents >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_product_id ON wishlist_items(product_id);
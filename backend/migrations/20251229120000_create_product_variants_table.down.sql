@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_order_items_variant_id;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_concentration;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_size_ml;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_sku;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+
+-- Lines for extra variants of the same product cannot survive the old one-line-per-product constraint
+DELETE FROM cart_items ci USING cart_items other
+WHERE ci.cart_id = other.cart_id AND ci.product_id = other.product_id AND ci.id > other.id;
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS unique_cart_variant;
+ALTER TABLE cart_items DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE cart_items ADD CONSTRAINT unique_cart_product UNIQUE (cart_id, product_id);
+
+DROP TABLE IF EXISTS product_variants;
@@ -0,0 +1,56 @@
+-- Purchasable versions of a product (size and concentration). Each variant carries its own SKU,
+-- price, stock and weight; reviews and embeddings stay on the product. products.price_cents,
+-- stock_quantity and weight are kept as aggregates of the variants: the lowest price, the total
+-- stock and the weight of the default (first) variant.
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(64) NOT NULL,
+    size_ml INTEGER NOT NULL DEFAULT 0,
+    concentration VARCHAR(16) NOT NULL,
+    price_cents BIGINT NOT NULL,
+    stock_quantity INTEGER NOT NULL DEFAULT 0,
+    weight FLOAT NOT NULL,
+    barcode VARCHAR(32),
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+
+    CONSTRAINT uq_product_variants_sku UNIQUE (sku),
+    CONSTRAINT check_product_variants_concentration CHECK (concentration IN ('edt', 'edp', 'parfum')),
+    CONSTRAINT check_product_variants_values CHECK (size_ml >= 0 AND price_cents > 0 AND stock_quantity >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_variants_product_id ON product_variants(product_id, position);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_variants_barcode ON product_variants(barcode) WHERE barcode IS NOT NULL;
+
+-- Every existing product becomes its own default variant. The size is unknown (0) until an admin sets it.
+INSERT INTO product_variants (product_id, sku, concentration, price_cents, stock_quantity, weight, created_at, updated_at)
+SELECT id,
+       'AS-' || LPAD(id::text, 6, '0'),
+       CASE
+           WHEN category ILIKE '%toilette%' THEN 'edt'
+           WHEN category ILIKE 'parfum' OR category ILIKE '%extrait%' THEN 'parfum'
+           ELSE 'edp'
+       END,
+       price_cents, stock_quantity, weight, created_at, updated_at
+FROM products;
+
+-- Cart lines hold a variant; the same product may be in a cart once per variant.
+ALTER TABLE cart_items ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE;
+UPDATE cart_items ci SET variant_id = pv.id FROM product_variants pv WHERE pv.product_id = ci.product_id;
+ALTER TABLE cart_items ALTER COLUMN variant_id SET NOT NULL;
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS unique_cart_product;
+ALTER TABLE cart_items ADD CONSTRAINT unique_cart_variant UNIQUE (cart_id, variant_id);
+
+-- Order items keep a snapshot of the variant, so removing a variant does not rewrite order history.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_sku VARCHAR(64);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_size_ml INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_concentration VARCHAR(16);
+UPDATE order_items oi SET
+    variant_id = pv.id,
+    variant_sku = pv.sku,
+    variant_concentration = pv.concentration
+FROM product_variants pv WHERE pv.product_id = oi.product_id;
+CREATE INDEX IF NOT EXISTS idx_order_items_variant_id ON order_items(variant_id);
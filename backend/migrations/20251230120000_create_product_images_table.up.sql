@@ -0,0 +1,22 @@
+-- Image gallery of a product. products.image_url and thumbnail_url keep mirroring the primary
+-- image so listings, carts and wishlists keep reading a single image.
+CREATE TABLE IF NOT EXISTS product_images (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    image_url VARCHAR(256) NOT NULL,
+    thumbnail_url VARCHAR(256) NOT NULL DEFAULT '',
+    alt_text VARCHAR(255) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_images_product_id ON product_images(product_id, position);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_images_primary ON product_images(product_id) WHERE is_primary;
+
+-- The existing product image becomes the primary image of the gallery
+INSERT INTO product_images (product_id, image_url, thumbnail_url, alt_text, position, is_primary, created_at, updated_at)
+SELECT id, image_url, COALESCE(thumbnail_url, ''), LEFT(brand || ' ' || name, 255), 0, TRUE, created_at, updated_at
+FROM products
+WHERE image_url IS NOT NULL AND image_url <> '';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_products_price_cents;
+DROP INDEX IF EXISTS idx_products_category;
+DROP INDEX IF EXISTS idx_products_gender;
+DROP INDEX IF EXISTS idx_products_brand;
+DROP INDEX IF EXISTS idx_products_seasons;
+DROP INDEX IF EXISTS idx_products_occasions;
+DROP INDEX IF EXISTS idx_products_accords;
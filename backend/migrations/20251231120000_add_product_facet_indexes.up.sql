@@ -0,0 +1,9 @@
+-- Indexes backing the catalog filters: GIN for the array overlap (&&) filters, B-tree for the
+-- single-value and price filters.
+CREATE INDEX IF NOT EXISTS idx_products_accords ON products USING GIN(accords);
+CREATE INDEX IF NOT EXISTS idx_products_occasions ON products USING GIN(occasions);
+CREATE INDEX IF NOT EXISTS idx_products_seasons ON products USING GIN(seasons);
+CREATE INDEX IF NOT EXISTS idx_products_brand ON products(brand);
+CREATE INDEX IF NOT EXISTS idx_products_gender ON products(gender);
+CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
+CREATE INDEX IF NOT EXISTS idx_products_price_cents ON products(price_cents);
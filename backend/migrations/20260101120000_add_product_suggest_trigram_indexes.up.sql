@@ -0,0 +1,13 @@
+-- Typeahead suggestions rank product names, brands, accords and notes by prefix and trigram
+-- similarity over accent-insensitive text, reusing the unaccent extension of the search vector.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() is only STABLE because its dictionary can change; pinning the dictionary makes an
+-- IMMUTABLE wrapper that expression indexes accept.
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text AS $$
+  SELECT public.unaccent('public.unaccent'::regdictionary, $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (lower(immutable_unaccent(name)) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_products_brand_trgm ON products USING GIN (lower(immutable_unaccent(brand)) gin_trgm_ops);
@@ -0,0 +1,12 @@
+DELETE FROM cart_items WHERE product_id IS NULL OR variant_id IS NULL;
+
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS cart_items_variant_id_fkey;
+ALTER TABLE cart_items ADD CONSTRAINT cart_items_variant_id_fkey
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE CASCADE;
+
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS fk_cart_items_product_id;
+ALTER TABLE cart_items ADD CONSTRAINT fk_cart_items_product_id
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
+
+ALTER TABLE cart_items ALTER COLUMN variant_id SET NOT NULL;
+ALTER TABLE cart_items ALTER COLUMN product_id SET NOT NULL;
@@ -0,0 +1,12 @@
+-- Cart lines outlive their product or variant: deleting either clears the reference instead of
+-- the line, so the cart can report the line as removed until the shopper reconciles it.
+ALTER TABLE cart_items ALTER COLUMN product_id DROP NOT NULL;
+ALTER TABLE cart_items ALTER COLUMN variant_id DROP NOT NULL;
+
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS fk_cart_items_product_id;
+ALTER TABLE cart_items ADD CONSTRAINT fk_cart_items_product_id
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE SET NULL;
+
+ALTER TABLE cart_items DROP CONSTRAINT IF EXISTS cart_items_variant_id_fkey;
+ALTER TABLE cart_items ADD CONSTRAINT cart_items_variant_id_fkey
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE SET NULL;
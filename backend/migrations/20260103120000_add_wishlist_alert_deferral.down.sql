@@ -0,0 +1 @@
+ALTER TABLE wishlist_items DROP COLUMN IF EXISTS alert_deferred_until;
@@ -0,0 +1,3 @@
+-- Alerts held back by the per-user rate limit wait until alert_deferred_until, so the alert job
+-- skips them meanwhile instead of fetching the same entries on every run.
+ALTER TABLE wishlist_items ADD COLUMN IF NOT EXISTS alert_deferred_until TIMESTAMPTZ;